@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+	"go.uber.org/zap"
+)
+
+var log *zap.Logger
+
+func main() {
+	log = logger.New()
+	defer log.Sync()
+
+	log.Info("API Service running...")
+
+	config.Setup()
+
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
+	defer dbClient.Close()
+	log.Debug("Database connection established")
+
+	jetstreamClient, natsConn, err := nats.Connect("api-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
+	defer natsConn.Close()
+	log.Debug("NATS JetStream connection established")
+
+	compression, err := recordbatch.ParseCompression(config.RecordsCompression())
+	if err != nil {
+		log.Fatal("Invalid RECORDS_COMPRESSION", zap.Error(err))
+	}
+	maxBytes, err := recordbatch.MaxMessageBytes(context.Background(), jetstreamClient, config.RecordsStreamName(), natsConn.MaxPayload())
+	if err != nil {
+		log.Fatal("Error loading message limit", zap.Error(err))
+	}
+	// Submissions too large for a message are stored in the claim check
+	// bucket when there is one, and split otherwise.
+	var claims recordbatch.ObjectStore
+	if bucket := config.ClaimCheckBucket(); bucket != "" {
+		store, err := recordbatch.OpenClaims(context.Background(), jetstreamClient, bucket, config.ClaimCheckTTL())
+		if err != nil {
+			log.Fatal("Error opening claim check bucket", zap.Error(err))
+		}
+		claims = store
+	}
+	batches := recordbatch.NewPublisher(jetstreamClient, compression, maxBytes, claims)
+
+	// Signatures are only reported valid for keys certified by the root CA,
+	// whose certificate keys-service publishes.
+	root, err := loadRootCertificate(config.CACertFile(), rootCertificateWait)
+	if err != nil {
+		log.Fatal("Error loading the root certificate", zap.Error(err))
+	}
+
+	// Every request authenticates with an API key, which decides its tenant.
+	apiKeys := config.APIKeys()
+	if len(apiKeys) == 0 {
+		log.Fatal("API_KEYS not set")
+	}
+	for _, tenant := range apiKeys {
+		if err := types.ValidateTenant(tenant); err != nil {
+			log.Fatal("Invalid API_KEYS", zap.Error(err))
+		}
+	}
+
+	srv := newServer(dbClient, batches, signer.NewVerifier(root), apiKeys)
+	httpServer := &http.Server{
+		Addr:              config.APIAddr(),
+		Handler:           srv.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Records whose publish was lost after their commit are published again.
+	go srv.republish(ctx, config.APIRepublishAfter())
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), maxWait+5*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Error("Error shutting down HTTP server", zap.Error(err))
+		}
+	}()
+
+	log.Info("Listening", zap.String("addr", httpServer.Addr))
+	if err := httpServer.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal("HTTP server failed", zap.Error(err))
+	}
+	log.Info("API Service stopped")
+}
+
+// rootCertificateWait is how long api-service waits for keys-service, which
+// starts alongside it, to publish the root certificate on first start.
+const rootCertificateWait = time.Minute
+
+// loadRootCertificate loads the root certificate from path, waiting up to
+// wait for it to appear.
+func loadRootCertificate(path string, wait time.Duration) (*x509.Certificate, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		cert, err := signer.LoadCACertificate(path)
+		if !errors.Is(err, fs.ErrNotExist) || time.Now().Add(time.Second).After(deadline) {
+			return cert, err
+		}
+		log.Info("Root certificate not published yet, retrying in 1s", zap.String("path", path))
+		time.Sleep(time.Second)
+	}
+}
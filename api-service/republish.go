@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database/record"
+	"github.com/jurshsmith/vaultstream/types"
+	"go.uber.org/zap"
+)
+
+// republishInterval is how often api-service looks for records to republish.
+const republishInterval = time.Minute
+
+// republish publishes unsigned records again every republishInterval, until
+// ctx is done. A record qualifies once it has been unsigned for after, so
+// that records still queued are left alone.
+func (s *server) republish(ctx context.Context, after time.Duration) {
+	ticker := time.NewTicker(republishInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := s.republishUnsigned(ctx, time.Now().Add(-after))
+		if err != nil {
+			log.Error("Error republishing unsigned records", zap.Error(err))
+			continue
+		}
+		if n > 0 {
+			log.Info("Republished unsigned records", zap.Int("recordCount", n))
+		}
+	}
+}
+
+// republishUnsigned publishes up to maxBulkRecords unsigned records inserted
+// before cutoff, oldest first, on records.<tenant>.normal.api.<first id>: the
+// ones whose publish failed, or was cut short by a stop, after their commit.
+// signing-service skips records signed in the meantime, and the stream drops
+// a batch published again on the same subject within its duplicate window.
+func (s *server) republishUnsigned(ctx context.Context, cutoff time.Time) (int, error) {
+	dbRecords, err := s.db.Record.Query().
+		Where(record.Not(record.HasSignature()), record.InsertedAtLT(cutoff)).
+		Order(record.ByID()).
+		Limit(maxBulkRecords).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed querying unsigned records: %w", err)
+	}
+	var tenants []string
+	byTenant := make(map[string][]types.Record)
+	for _, rec := range dbRecords {
+		if _, ok := byTenant[rec.TenantID]; !ok {
+			tenants = append(tenants, rec.TenantID)
+		}
+		byTenant[rec.TenantID] = append(byTenant[rec.TenantID], types.Record{ID: rec.ID, TenantID: rec.TenantID, InsertedAt: rec.InsertedAt, Data: rec.Data})
+	}
+	n := 0
+	for _, tenant := range tenants {
+		prefix := fmt.Sprintf("records.%s.%s.api", tenant, types.PriorityNormal)
+		if _, err := s.batches.Publish(ctx, prefix, byTenant[tenant]); err != nil {
+			return n, fmt.Errorf("failed republishing records on %s: %w", prefix, err)
+		}
+		n += len(byTenant[tenant])
+	}
+	return n, nil
+}
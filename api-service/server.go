@@ -0,0 +1,424 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/record"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/export"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+	"go.uber.org/zap"
+)
+
+const (
+	maxBodySize    = 8 << 20 // bytes per request body
+	maxRecordData  = 64 << 10
+	maxBulkRecords = 1000
+	maxWait        = 30 * time.Second // upper bound of a status long-poll
+	pollInterval   = 250 * time.Millisecond
+
+	statusPending = "pending"
+	statusSigned  = "signed"
+
+	// bearerPrefix starts the Authorization header carrying an API key; a
+	// request acts for the tenant its key belongs to.
+	bearerPrefix = "Bearer "
+	// priorityHeader sets the priority lane submitted records are signed in:
+	// "high", "normal" (the default) or "low".
+	priorityHeader = "X-VaultStream-Priority"
+)
+
+// server serves the HTTP API on top of the records and signatures tables.
+// Submitted records are handed to signing-service on
+// records.<tenant>.<priority>.api.<first id>.
+type server struct {
+	db           *database.Client
+	batches      *recordbatch.Publisher
+	exporter     *export.Exporter
+	verifier     *signer.Verifier
+	apiKeys      map[string]string // tenant by apiKeyDigest of the key
+	pollInterval time.Duration
+}
+
+func newServer(db *database.Client, batches *recordbatch.Publisher, verifier *signer.Verifier, apiKeys map[string]string) *server {
+	return &server{
+		db:           db,
+		batches:      batches,
+		exporter:     export.New(db),
+		verifier:     verifier,
+		apiKeys:      apiKeys,
+		pollInterval: pollInterval,
+	}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /records", s.submitRecord)
+	mux.HandleFunc("POST /records/bulk", s.submitRecords)
+	mux.HandleFunc("GET /records/{id}", s.recordStatus)
+	mux.HandleFunc("GET /records/{id}/signature", s.fetchSignature)
+	mux.HandleFunc("POST /verify", s.verify)
+	return s.authenticate(mux)
+}
+
+// tenantContextKey holds the tenant of an authenticated request.
+type tenantContextKey struct{}
+
+// authenticate answers 401 to requests without a known API key, and passes
+// the others on acting for the tenant of their key.
+func (s *server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), bearerPrefix)
+		tenant, known := s.apiKeys[apiKeyDigest(key)]
+		if !ok || key == "" || !known {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="vaultstream"`)
+			writeError(w, http.StatusUnauthorized, "missing or unknown API key")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenant)))
+	})
+}
+
+// apiKeyDigest is the hex SHA-256 of an API key, which is all API_KEYS holds
+// of it.
+func apiKeyDigest(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(digest[:])
+}
+
+// submission is the body of a record submission; Data is base64 in JSON.
+type submission struct {
+	Data []byte `json:"data"`
+}
+
+type statusResponse struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// keyResponse is the public part of a signing key.
+type keyResponse struct {
+	ID               int      `json:"id"`
+	TenantID         string   `json:"tenant_id"`
+	Algorithm        string   `json:"algorithm"`
+	PublicKey        string   `json:"public_key"`
+	Certificate      string   `json:"certificate,omitempty"`
+	CertificateChain []string `json:"certificate_chain,omitempty"`
+}
+
+type signatureResponse struct {
+	Record    types.Record    `json:"record"`
+	Signature types.Signature `json:"signature"`
+	Key       keyResponse     `json:"key"`
+}
+
+type verifyRequest struct {
+	Record    types.Record    `json:"record"`
+	Signature types.Signature `json:"signature"`
+}
+
+type verifyResponse struct {
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// submitRecord stores a single record and queues it for signing.
+func (s *server) submitRecord(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantID(r)
+	var sub submission
+	if !decode(w, r, &sub) {
+		return
+	}
+	records, ok := s.submit(w, r, tenant, []submission{sub})
+	if !ok {
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/records/%d", records[0].ID))
+	writeJSON(w, http.StatusAccepted, records[0])
+}
+
+// submitRecords stores up to maxBulkRecords records and queues them for
+// signing as one batch.
+func (s *server) submitRecords(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantID(r)
+	var subs []submission
+	if !decode(w, r, &subs) {
+		return
+	}
+	if len(subs) == 0 || len(subs) > maxBulkRecords {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("expected between 1 and %d records", maxBulkRecords))
+		return
+	}
+	records, ok := s.submit(w, r, tenant, subs)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusAccepted, records)
+}
+
+// submit commits the records and then publishes them, so signing-service
+// never receives records it cannot see yet. Records whose publish fails are
+// accepted all the same: republishUnsigned publishes them again.
+func (s *server) submit(w http.ResponseWriter, r *http.Request, tenant string, subs []submission) ([]types.Record, bool) {
+	priority, ok := batchPriority(w, r)
+	if !ok {
+		return nil, false
+	}
+	for i, sub := range subs {
+		if len(sub.Data) > maxRecordData {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("record %d exceeds %d bytes", i, maxRecordData))
+			return nil, false
+		}
+	}
+	ctx := r.Context()
+
+	tx, err := s.db.Tx(ctx)
+	if err != nil {
+		s.internalError(w, "Error starting transaction", err)
+		return nil, false
+	}
+	defer tx.Rollback()
+
+	// Records are signed as published, so inserted_at must already have the
+	// microsecond precision PostgreSQL stores.
+	now := time.Now().Truncate(time.Microsecond)
+	dbRecords, err := tx.Record.MapCreateBulk(subs, func(c *database.RecordCreate, i int) {
+		c.SetTenantID(tenant).SetInsertedAt(now)
+		if subs[i].Data != nil {
+			c.SetData(subs[i].Data)
+		}
+	}).Save(ctx)
+	if err != nil {
+		s.internalError(w, "Error inserting records", err)
+		return nil, false
+	}
+	records := make([]types.Record, len(dbRecords))
+	for i, rec := range dbRecords {
+		records[i] = types.Record{ID: rec.ID, TenantID: rec.TenantID, InsertedAt: rec.InsertedAt, Data: rec.Data}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.internalError(w, "Error committing records", err)
+		return nil, false
+	}
+
+	prefix := fmt.Sprintf("records.%s.%s.api", tenant, priority)
+	parts, err := s.batches.Publish(ctx, prefix, records)
+	if err != nil {
+		log.Error("Error publishing records, leaving them to be republished", zap.String("subject", prefix), zap.Error(err))
+		return records, true
+	}
+	log.Debug("Records submitted", zap.String("subject", prefix), zap.Int("recordCount", len(records)), zap.Int("messages", len(parts)))
+	return records, true
+}
+
+// recordStatus reports whether a record is signed. With ?wait=<duration> it
+// long-polls until the record is signed or the wait, capped at maxWait, is over.
+func (s *server) recordStatus(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantID(r)
+	id, ok := recordID(w, r)
+	if !ok {
+		return
+	}
+	var wait time.Duration
+	if v := r.URL.Query().Get("wait"); v != "" {
+		var err error
+		if wait, err = time.ParseDuration(v); err != nil || wait < 0 {
+			writeError(w, http.StatusBadRequest, "invalid wait duration")
+			return
+		}
+		wait = min(wait, maxWait)
+	}
+	ctx := r.Context()
+
+	exists, err := s.db.Record.Query().Where(record.ID(id), record.TenantID(tenant)).Exist(ctx)
+	if err != nil {
+		s.internalError(w, "Error querying record", err)
+		return
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		signed, err := s.db.Signature.Query().Where(signature.RecordID(id)).Exist(ctx)
+		if err != nil {
+			s.internalError(w, "Error querying signature", err)
+			return
+		}
+		if signed {
+			writeJSON(w, http.StatusOK, statusResponse{ID: id, Status: statusSigned})
+			return
+		}
+		if !time.Now().Before(deadline) {
+			writeJSON(w, http.StatusOK, statusResponse{ID: id, Status: statusPending})
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// fetchSignature returns a record's signature with its key. ?format=jws or
+// ?format=cms returns the standard export instead of the JSON document.
+func (s *server) fetchSignature(w http.ResponseWriter, r *http.Request) {
+	tenant := tenantID(r)
+	id, ok := recordID(w, r)
+	if !ok {
+		return
+	}
+	signed, err := s.exporter.Load(r.Context(), id)
+	// Records of other tenants are reported as missing.
+	if database.IsNotFound(err) || (err == nil && signed.Record.TenantID != tenant) {
+		writeError(w, http.StatusNotFound, "record not found or not signed yet")
+		return
+	}
+	if err != nil {
+		s.internalError(w, "Error loading signature", err)
+		return
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		writeJSON(w, http.StatusOK, signatureResponse{
+			Record:    signed.Record,
+			Signature: signed.Signature,
+			Key: keyResponse{
+				ID:               signed.Key.ID,
+				TenantID:         signed.Key.TenantID,
+				Algorithm:        signed.Key.Algorithm,
+				PublicKey:        signed.Key.PublicKey,
+				Certificate:      signed.Key.Certificate,
+				CertificateChain: signed.Key.CertificateChain,
+			},
+		})
+	case "jws":
+		jws, err := signed.JWSJSON(false)
+		if err != nil {
+			s.internalError(w, "Error exporting JWS", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jose+json")
+		w.Write(jws)
+	case "cms":
+		cms, err := signed.CMS()
+		if err != nil {
+			s.internalError(w, "Error exporting CMS", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pkcs7-signature")
+		w.Write(cms)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown format %q", format))
+	}
+}
+
+// verify checks a supplied signature of a supplied record against the stored,
+// CA-certified key it names. An invalid signature is a 200 with valid false.
+func (s *server) verify(w http.ResponseWriter, r *http.Request) {
+	var req verifyRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	dbKey, err := s.db.Key.Get(r.Context(), req.Signature.KeyID)
+	if database.IsNotFound(err) {
+		writeJSON(w, http.StatusOK, verifyResponse{Error: fmt.Sprintf("unknown key %d", req.Signature.KeyID)})
+		return
+	}
+	if err != nil {
+		s.internalError(w, "Error loading key", err)
+		return
+	}
+
+	key := &types.Key{
+		ID:               dbKey.ID,
+		TenantID:         dbKey.TenantID,
+		Algorithm:        dbKey.Algorithm,
+		PublicKey:        dbKey.PublicKey,
+		Certificate:      dbKey.Certificate,
+		CertificateChain: dbKey.CertificateChain,
+	}
+	if err := s.verifier.Verify(key, req.Record, req.Signature); err != nil {
+		writeJSON(w, http.StatusOK, verifyResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, verifyResponse{Valid: true})
+}
+
+func (s *server) internalError(w http.ResponseWriter, msg string, err error) {
+	log.Error(msg, zap.Error(err))
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
+
+// tenantID returns the tenant the request acts for, that of its API key.
+func tenantID(r *http.Request) string {
+	return r.Context().Value(tenantContextKey{}).(string)
+}
+
+// batchPriority returns the priority to sign submitted records with,
+// answering 400 for an unknown one.
+func batchPriority(w http.ResponseWriter, r *http.Request) (string, bool) {
+	priority := r.Header.Get(priorityHeader)
+	if priority == "" {
+		return types.PriorityNormal, true
+	}
+	if err := types.ValidatePriority(priority); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", false
+	}
+	return priority, true
+}
+
+func recordID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid record id")
+		return 0, false
+	}
+	return id, true
+}
+
+// decode reads a JSON request body into v, answering 400 or 413 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		}
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("Error writing response", zap.Error(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/ledger"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop()
+}
+
+// fakePublisher records published messages instead of sending them to NATS.
+type fakePublisher struct {
+	mu       sync.Mutex
+	subjects []string
+	messages [][]byte
+	err      error
+}
+
+func (p *fakePublisher) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.subjects = append(p.subjects, msg.Subject)
+	p.messages = append(p.messages, msg.Data)
+	return &jetstream.PubAck{Stream: "test"}, nil
+}
+
+// batches publishes uncompressed record batches through p.
+func (p *fakePublisher) batches() *recordbatch.Publisher {
+	return recordbatch.NewPublisher(p, recordbatch.None, 1<<20, nil)
+}
+
+func do(t *testing.T, handler http.Handler, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	return doAs(t, handler, "", method, target, body)
+}
+
+// testAPIKey is the API key the tests use for tenant.
+func testAPIKey(tenant string) string {
+	return tenant + "-api-key"
+}
+
+// testAPIKeys are the API keys of the servers under test, one per tenant the
+// tests act for.
+var testAPIKeys = map[string]string{
+	apiKeyDigest(testAPIKey(types.DefaultTenant)): types.DefaultTenant,
+	apiKeyDigest(testAPIKey("acme")):              "acme",
+}
+
+// doAs sends a request with the API key of tenant, or of the default tenant
+// when empty.
+func doAs(t *testing.T, handler http.Handler, tenant, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	if tenant == "" {
+		tenant = types.DefaultTenant
+	}
+	header := http.Header{"Authorization": {bearerPrefix + testAPIKey(tenant)}}
+	return doWith(t, handler, header, method, target, body)
+}
+
+// doWith sends a request with the given headers, authenticated as the default
+// tenant unless they carry an Authorization header.
+func doWith(t *testing.T, handler http.Handler, header http.Header, method, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	if header.Values("Authorization") == nil {
+		header = header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Authorization", bearerPrefix+testAPIKey(types.DefaultTenant))
+	}
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for name, values := range header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestRejectsInvalidRequests covers the requests refused before the database is touched.
+func TestRejectsInvalidRequests(t *testing.T) {
+	handler := newServer(nil, (&fakePublisher{}).batches(), signer.NewVerifier(), testAPIKeys).routes()
+	tooMany := make([]submission, maxBulkRecords+1)
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		method     string
+		target     string
+		body       any
+		wantStatus int
+	}{
+		{name: "missing API key", header: http.Header{"Authorization": {""}}, method: http.MethodPost, target: "/records", body: submission{}, wantStatus: http.StatusUnauthorized},
+		{name: "unknown API key", header: http.Header{"Authorization": {bearerPrefix + "stolen"}}, method: http.MethodGet, target: "/records/1", wantStatus: http.StatusUnauthorized},
+		{name: "API key not bearer", header: http.Header{"Authorization": {testAPIKey(types.DefaultTenant)}}, method: http.MethodGet, target: "/records/1", wantStatus: http.StatusUnauthorized},
+		{name: "tenant header ignored", header: http.Header{"Authorization": {""}, "X-Vaultstream-Tenant": {"acme"}}, method: http.MethodGet, target: "/records/1", wantStatus: http.StatusUnauthorized},
+		{name: "unknown priority", header: http.Header{priorityHeader: {"urgent"}}, method: http.MethodPost, target: "/records/bulk", body: []submission{{}}, wantStatus: http.StatusBadRequest},
+		{name: "malformed record", method: http.MethodPost, target: "/records", body: "{", wantStatus: http.StatusBadRequest},
+		{name: "unknown field", method: http.MethodPost, target: "/records", body: `{"payload":"eA=="}`, wantStatus: http.StatusBadRequest},
+		{name: "data not base64", method: http.MethodPost, target: "/records", body: `{"data":"not base64!"}`, wantStatus: http.StatusBadRequest},
+		{name: "oversized record", method: http.MethodPost, target: "/records", body: submission{Data: make([]byte, maxRecordData+1)}, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "empty bulk", method: http.MethodPost, target: "/records/bulk", body: "[]", wantStatus: http.StatusBadRequest},
+		{name: "too many records", method: http.MethodPost, target: "/records/bulk", body: tooMany, wantStatus: http.StatusBadRequest},
+		{name: "invalid record id", method: http.MethodGet, target: "/records/abc", wantStatus: http.StatusBadRequest},
+		{name: "non-positive record id", method: http.MethodGet, target: "/records/0/signature", wantStatus: http.StatusBadRequest},
+		{name: "invalid wait", method: http.MethodGet, target: "/records/1?wait=soon", wantStatus: http.StatusBadRequest},
+		{name: "malformed verify", method: http.MethodPost, target: "/verify", body: "[]", wantStatus: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodDelete, target: "/records/1", wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doWith(t, handler, tt.header, tt.method, tt.target, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("%s %s status = %d, want %d (%s)", tt.method, tt.target, rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+}
+
+// ----------------------------
+// Integration Tests Using Actual Database Connection (ent)
+// ----------------------------
+
+// setupDB connects to the test database and empties the tables the API uses.
+func setupDB(t *testing.T) *database.Client {
+	t.Helper()
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		t.Skipf("Skipping integration test: %v", err)
+	}
+	t.Cleanup(func() { dbClient.Close() })
+	for _, table := range []string{"signatures", "ledger", "records", "keys"} {
+		if _, err := dbClient.Exec(context.Background(), "DELETE FROM "+table); err != nil {
+			t.Skipf("Skipping integration test: unable to clean %s table: %v", table, err)
+		}
+	}
+	return dbClient
+}
+
+// signStored plays signing-service for one record: it certifies a key, signs
+// the record and appends the signature to the ledger.
+func signStored(t *testing.T, dbClient *database.Client, ca *signer.CA, record types.Record) {
+	t.Helper()
+	ctx := context.Background()
+	backend := signer.NewMemoryBackend()
+	key, err := backend.GenerateKey(ctx, 1, signer.ES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := ca.Issue(key, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(certificate)
+	key.CertificateChain = ca.Chain()
+	if err := dbClient.Key.Create().
+		SetID(key.ID).
+		SetAlgorithm(key.Algorithm).
+		SetPublicKey(key.PublicKey).
+		SetCertificate(key.Certificate).
+		SetCertificateChain(key.CertificateChain).
+		Exec(ctx); err != nil {
+		t.Fatalf("failed saving key: %v", err)
+	}
+
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := keySigner.Sign(ctx, signer.ES256.Digest(signer.SigningInput(signer.ES256, key.ID, record)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := dbClient.Tx(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigs := []types.Signature{{RecordID: record.ID, KeyID: key.ID, Algorithm: string(signer.ES256), Value: base64.StdEncoding.EncodeToString(raw)}}
+	if _, err := ledger.Append(ctx, tx, sigs); err != nil {
+		tx.Rollback()
+		t.Fatalf("failed inserting signature: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSubmitSignFetchVerify walks a record through the whole API.
+func TestSubmitSignFetchVerify(t *testing.T) {
+	dbClient := setupDB(t)
+	ca, err := signer.OpenCA(filepath.Join(t.TempDir(), "root.json"), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	js := &fakePublisher{}
+	srv := newServer(dbClient, js.batches(), signer.NewVerifier(ca.Certificate()), testAPIKeys)
+	srv.pollInterval = 10 * time.Millisecond
+	handler := srv.routes()
+
+	// Submit two records in bulk; they are published as one batch.
+	rec := do(t, handler, http.MethodPost, "/records/bulk", []submission{{Data: []byte("first")}, {Data: []byte("second")}})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("bulk submit status = %d: %s", rec.Code, rec.Body)
+	}
+	var records []types.Record
+	if err := json.Unmarshal(rec.Body.Bytes(), &records); err != nil || len(records) != 2 {
+		t.Fatalf("bulk submit returned %s", rec.Body)
+	}
+	if len(js.subjects) != 1 || js.subjects[0] != fmt.Sprintf("records.default.normal.api.%d", records[0].ID) {
+		t.Errorf("published subjects = %v", js.subjects)
+	}
+	var published []types.Record
+	if err := json.Unmarshal(js.messages[0], &published); err != nil || len(published) != 2 || string(published[1].Data) != "second" {
+		t.Errorf("published batch = %s", js.messages[0])
+	}
+
+	// Urgent records go to the high priority lane.
+	rec = doWith(t, handler, http.Header{priorityHeader: {types.PriorityHigh}}, http.MethodPost, "/records", submission{Data: []byte("urgent")})
+	var urgent types.Record
+	if err := json.Unmarshal(rec.Body.Bytes(), &urgent); err != nil || rec.Code != http.StatusAccepted {
+		t.Fatalf("urgent submit status = %d: %s", rec.Code, rec.Body)
+	}
+	if want := fmt.Sprintf("records.default.high.api.%d", urgent.ID); js.subjects[len(js.subjects)-1] != want {
+		t.Errorf("published subjects = %v, want the last on %s", js.subjects, want)
+	}
+	record := records[0]
+
+	// Unsigned: status is pending and there is no signature yet.
+	var status statusResponse
+	rec = do(t, handler, http.MethodGet, fmt.Sprintf("/records/%d?wait=30ms", record.ID), nil)
+	json.Unmarshal(rec.Body.Bytes(), &status)
+	if status.Status != statusPending {
+		t.Errorf("status before signing = %s", rec.Body)
+	}
+	if rec := do(t, handler, http.MethodGet, fmt.Sprintf("/records/%d/signature", record.ID), nil); rec.Code != http.StatusNotFound {
+		t.Errorf("fetch before signing status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	// A long-poll returns as soon as the record is signed.
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- do(t, handler, http.MethodGet, fmt.Sprintf("/records/%d?wait=10s", record.ID), nil)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	signStored(t, dbClient, ca, record)
+	rec = <-done
+	json.Unmarshal(rec.Body.Bytes(), &status)
+	if status.Status != statusSigned {
+		t.Errorf("long-polled status = %s", rec.Body)
+	}
+
+	rec = do(t, handler, http.MethodGet, fmt.Sprintf("/records/%d/signature", record.ID), nil)
+	var signed signatureResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &signed); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("fetch signature status = %d: %s", rec.Code, rec.Body)
+	}
+	if signed.Key.ID != signed.Signature.KeyID || signed.Key.PublicKey == "" || string(signed.Record.Data) != "first" {
+		t.Errorf("fetched signature = %+v", signed)
+	}
+	// Other tenants cannot see the record.
+	for _, target := range []string{"/records/%d", "/records/%d/signature"} {
+		if rec := doAs(t, handler, "acme", http.MethodGet, fmt.Sprintf(target, record.ID), nil); rec.Code != http.StatusNotFound {
+			t.Errorf("GET %s as another tenant status = %d, want %d", fmt.Sprintf(target, record.ID), rec.Code, http.StatusNotFound)
+		}
+	}
+	for _, format := range []string{"jws", "cms"} {
+		if rec := do(t, handler, http.MethodGet, fmt.Sprintf("/records/%d/signature?format=%s", record.ID, format), nil); rec.Code != http.StatusOK {
+			t.Errorf("fetch %s status = %d: %s", format, rec.Code, rec.Body)
+		}
+	}
+
+	tampered := signed.Record
+	tampered.Data = []byte("forged")
+	tests := []struct {
+		name      string
+		record    types.Record
+		wantValid bool
+	}{
+		{name: "stored record", record: signed.Record, wantValid: true},
+		{name: "tampered record", record: tampered, wantValid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, handler, http.MethodPost, "/verify", verifyRequest{Record: tt.record, Signature: signed.Signature})
+			var result verifyResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("verify returned %s", rec.Body)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("verify valid = %v, want %v (%s)", result.Valid, tt.wantValid, result.Error)
+			}
+		})
+	}
+}
+
+// TestSubmitRepublishesWhenPublishFails makes sure records whose publish
+// failed after their commit are accepted and published again while unsigned.
+func TestSubmitRepublishesWhenPublishFails(t *testing.T) {
+	dbClient := setupDB(t)
+	ctx := context.Background()
+	js := &fakePublisher{err: context.DeadlineExceeded}
+	srv := newServer(dbClient, js.batches(), signer.NewVerifier(), testAPIKeys)
+	handler := srv.routes()
+
+	rec := doAs(t, handler, "acme", http.MethodPost, "/records", submission{Data: []byte("lost")})
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body)
+	}
+	var record types.Record
+	if err := json.Unmarshal(rec.Body.Bytes(), &record); err != nil {
+		t.Fatalf("submit returned %s", rec.Body)
+	}
+	if _, err := dbClient.Record.Get(ctx, record.ID); err != nil {
+		t.Fatalf("submitted record was not committed: %v", err)
+	}
+
+	// Records younger than the cutoff are still expected to be queued.
+	js.err = nil
+	if n, err := srv.republishUnsigned(ctx, record.InsertedAt); err != nil || n != 0 {
+		t.Errorf("republishUnsigned() before the cutoff = %d, %v, want 0", n, err)
+	}
+	n, err := srv.republishUnsigned(ctx, time.Now())
+	if err != nil || n != 1 {
+		t.Fatalf("republishUnsigned() = %d, %v, want 1", n, err)
+	}
+	if want := fmt.Sprintf("records.acme.normal.api.%d", record.ID); len(js.subjects) != 1 || js.subjects[0] != want {
+		t.Errorf("republished subjects = %v, want [%s]", js.subjects, want)
+	}
+	var published []types.Record
+	if err := json.Unmarshal(js.messages[0], &published); err != nil || len(published) != 1 || !published[0].InsertedAt.Equal(record.InsertedAt) {
+		t.Errorf("republished batch = %s, want record %d as submitted", js.messages[0], record.ID)
+	}
+}
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"encoding/hex"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -22,8 +24,55 @@ func Setup() {
 	}
 }
 
+// Stream is the configuration of a JetStream stream, read from
+// <KIND>_STREAM_* variables, e.g. RECORDS_STREAM_MAX_AGE=24h.
+type Stream struct {
+	Name      string        // <KIND>_STREAM_NAME
+	Retention string        // <KIND>_STREAM_RETENTION: limits, workqueue or interest
+	Storage   string        // <KIND>_STREAM_STORAGE: file or memory
+	Discard   string        // <KIND>_STREAM_DISCARD: old or new, when a limit is hit
+	MaxAge    time.Duration // <KIND>_STREAM_MAX_AGE, 0 keeps messages forever
+	MaxBytes  int64         // <KIND>_STREAM_MAX_BYTES, -1 for no limit
+	Replicas  int           // <KIND>_STREAM_REPLICAS
+}
+
+// RecordsStream holds records.> batches. Each batch is signed once, so it is
+// a work queue by default, removed once acknowledged.
+func RecordsStream() Stream {
+	return streamConfig("RECORDS", "vaultstream-records", "workqueue")
+}
+
+// KeysStream holds keys.>, leased and returned by signing-service.
+func KeysStream() Stream {
+	return streamConfig("KEYS", "vaultstream-keys", "limits")
+}
+
+// EventsStream holds signatures.> events.
+func EventsStream() Stream {
+	return streamConfig("EVENTS", "vaultstream-events", "limits")
+}
+
+func RecordsStreamName() string {
+	return RecordsStream().Name
+}
+func KeysStreamName() string {
+	return KeysStream().Name
+}
 func EventsStreamName() string {
-	return "vaultstream-streams"
+	return EventsStream().Name
+}
+
+func streamConfig(kind, name, retention string) Stream {
+	prefix := kind + "_STREAM_"
+	return Stream{
+		Name:      envOr(prefix+"NAME", name),
+		Retention: envOr(prefix+"RETENTION", retention),
+		Storage:   envOr(prefix+"STORAGE", "file"),
+		Discard:   envOr(prefix+"DISCARD", "old"),
+		MaxAge:    envDurationOr(prefix+"MAX_AGE", 0),
+		MaxBytes:  int64(envIntOr(prefix+"MAX_BYTES", -1)),
+		Replicas:  envIntOr(prefix+"REPLICAS", 1),
+	}
 }
 
 func DatabaseURL() string {
@@ -34,6 +83,48 @@ func DatabaseURL() string {
 	return url
 }
 
+// DevDatabaseURL is an empty database the migrate command replays the
+// migrations on to generate the next one.
+func DevDatabaseURL() string {
+	url := os.Getenv("DEV_DATABASE_URL")
+	if url == "" {
+		log.Fatal("DEV_DATABASE_URL not set")
+	}
+	return url
+}
+
+// DatabasePool configures the database driver and connection pool.
+type DatabasePool struct {
+	Driver           string        // DATABASE_DRIVER: postgres (lib/pq) or pgx
+	MaxOpenConns     int           // DATABASE_MAX_OPEN_CONNS
+	MaxIdleConns     int           // DATABASE_MAX_IDLE_CONNS, defaults to MaxOpenConns
+	ConnMaxLifetime  time.Duration // DATABASE_CONN_MAX_LIFETIME
+	ConnMaxIdleTime  time.Duration // DATABASE_CONN_MAX_IDLE_TIME
+	StatementTimeout time.Duration // DATABASE_STATEMENT_TIMEOUT, 0 for none
+	StartupTimeout   time.Duration // DATABASE_STARTUP_TIMEOUT for the first connection
+}
+
+// DatabasePoolFor returns the pool settings of a service running up to
+// concurrency queries at once, e.g. RECORDS_MAX_CONCURRENCY. Unless
+// DATABASE_MAX_OPEN_CONNS says otherwise, the pool has a connection for each
+// plus two for the service's own bookkeeping, or 10 when concurrency is 0.
+func DatabasePoolFor(concurrency int) DatabasePool {
+	maxOpen := 10
+	if concurrency > 0 {
+		maxOpen = concurrency + 2
+	}
+	maxOpen = envIntOr("DATABASE_MAX_OPEN_CONNS", maxOpen)
+	return DatabasePool{
+		Driver:           envOr("DATABASE_DRIVER", "postgres"),
+		MaxOpenConns:     maxOpen,
+		MaxIdleConns:     envIntOr("DATABASE_MAX_IDLE_CONNS", maxOpen),
+		ConnMaxLifetime:  envDurationOr("DATABASE_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime:  envDurationOr("DATABASE_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		StatementTimeout: envDurationOr("DATABASE_STATEMENT_TIMEOUT", 0),
+		StartupTimeout:   envDurationOr("DATABASE_STARTUP_TIMEOUT", time.Minute),
+	}
+}
+
 func VaultStreamNatsURL() string {
 	url := os.Getenv("VAULTSTREAM_NATS_URL")
 	if url == "" {
@@ -41,20 +132,59 @@ func VaultStreamNatsURL() string {
 	}
 	return url
 }
-func VaultStreamNatsPassword() string {
-	pwd := os.Getenv("VAULTSTREAM_NATS_PASSWORD")
-	if pwd == "" {
-		log.Fatal("VAULTSTREAM_NATS_PASSWORD not set")
+
+// NatsReconnect is how NATS connections wait for the server at startup and
+// reconnect after losing it.
+type NatsReconnect struct {
+	MaxReconnects  int           // VAULTSTREAM_NATS_MAX_RECONNECTS, -1 retries forever
+	Wait           time.Duration // VAULTSTREAM_NATS_RECONNECT_WAIT between attempts
+	Jitter         time.Duration // VAULTSTREAM_NATS_RECONNECT_JITTER added to Wait at random
+	StartupTimeout time.Duration // VAULTSTREAM_NATS_STARTUP_TIMEOUT for the first connection
+}
+
+func VaultStreamNatsReconnect() NatsReconnect {
+	return NatsReconnect{
+		MaxReconnects:  envIntOr("VAULTSTREAM_NATS_MAX_RECONNECTS", -1),
+		Wait:           envDurationOr("VAULTSTREAM_NATS_RECONNECT_WAIT", 2*time.Second),
+		Jitter:         envDurationOr("VAULTSTREAM_NATS_RECONNECT_JITTER", 500*time.Millisecond),
+		StartupTimeout: envDurationOr("VAULTSTREAM_NATS_STARTUP_TIMEOUT", time.Minute),
+	}
+}
+
+// NatsAuth is how a service authenticates to NATS. Each setting is read from
+// <SERVICE>_NATS_<SETTING>, e.g. KEYS_SERVICE_NATS_NKEY, falling back to
+// VAULTSTREAM_NATS_<SETTING>, so every service can have its own user.
+type NatsAuth struct {
+	Creds    string // CREDS: decentralized JWT credentials file
+	NKey     string // NKEY: file holding an NKey user seed
+	User     string // USER, used with PASSWORD; defaults to admin
+	Password string // PASSWORD
+	TLSCA    string // TLS_CA: CA certificate verifying the server
+	TLSCert  string // TLS_CERT: client certificate for mutual TLS
+	TLSKey   string // TLS_KEY: key of TLS_CERT
+}
+
+// VaultStreamNatsAuth returns the NATS credentials of service, e.g.
+// keys-service.
+func VaultStreamNatsAuth(service string) NatsAuth {
+	prefix := strings.ToUpper(strings.ReplaceAll(service, "-", "_")) + "_NATS_"
+	setting := func(name, fallback string) string {
+		return envOr(prefix+name, envOr("VAULTSTREAM_NATS_"+name, fallback))
+	}
+	return NatsAuth{
+		Creds:    setting("CREDS", ""),
+		NKey:     setting("NKEY", ""),
+		User:     setting("USER", "admin"),
+		Password: setting("PASSWORD", ""),
+		TLSCA:    setting("TLS_CA", ""),
+		TLSCert:  setting("TLS_CERT", ""),
+		TLSKey:   setting("TLS_KEY", ""),
 	}
-	return pwd
 }
 
 func TotalRecords() int {
 	return mustEnvInt("TOTAL_RECORDS")
 }
-func TotalRecordBatches() int {
-	return ceilDiv(TotalRecords(), RecordsBatchSize())
-}
 
 func KeysBucketName() string {
 	return "vaultstream-keys"
@@ -69,9 +199,111 @@ func TotalKeys() int {
 func RecordsMaxConcurrency() int {
 	return mustEnvInt("RECORDS_MAX_CONCURRENCY")
 }
+
+// RecordsBatchSize is the records per batch records-service starts with; it
+// adapts the size between RecordsMinBatchSize and RecordsMaxBatchSize from
+// how fast its batches get signed.
 func RecordsBatchSize() int {
 	return mustEnvInt("BATCH_SIZE")
 }
+func RecordsMinBatchSize() int {
+	return envIntOr("BATCH_MIN_SIZE", 1)
+}
+func RecordsMaxBatchSize() int {
+	return envIntOr("BATCH_MAX_SIZE", 1000)
+}
+
+// BatchTargetLatency is the longest records-service wants a batch to take
+// from being published to being signed and committed; it shrinks batches
+// that take longer and grows them otherwise.
+func BatchTargetLatency() time.Duration {
+	return envDurationOr("BATCH_TARGET_LATENCY", 2*time.Second)
+}
+
+// BatchTargetInsert is the longest records-service wants signing-service to
+// take committing one batch's signatures.
+func BatchTargetInsert() time.Duration {
+	return envDurationOr("BATCH_TARGET_INSERT", 500*time.Millisecond)
+}
+
+// RecordsMetricsAddr is the address records-service serves its metrics on,
+// at /debug/vars. Metrics are not served when it is empty.
+func RecordsMetricsAddr() string {
+	return os.Getenv("RECORDS_METRICS_ADDR")
+}
+
+// RecordsCompression is how published record batches are compressed:
+// "none", "s2" or "zstd".
+func RecordsCompression() string {
+	return envOr("RECORDS_COMPRESSION", "none")
+}
+
+// ClaimCheckBucket is the JetStream object store record batches too large
+// for a message are stored in, the message carrying only a reference to
+// them. Oversized batches are split instead when it is empty.
+func ClaimCheckBucket() string {
+	return os.Getenv("CLAIM_CHECK_BUCKET")
+}
+
+// ClaimCheckTTL is how long a stored batch is kept should signing-service
+// never get to delete it.
+func ClaimCheckTTL() time.Duration {
+	return envDurationOr("CLAIM_CHECK_TTL", 24*time.Hour)
+}
+
+// KeyAlgorithms lists the signature algorithms keys-service hands out, assigned
+// to keys round-robin, e.g. "ES256,EdDSA".
+func KeyAlgorithms() []string {
+	return strings.Split(envOr("KEY_ALGORITHMS", "ES256"), ",")
+}
+
+// TenantKeyAlgorithms lists the algorithms of the tenants whose keys do not
+// follow KEY_ALGORITHMS, e.g. "acme=EdDSA;globex=PS256,ES256".
+func TenantKeyAlgorithms() map[string][]string {
+	algorithms := make(map[string][]string)
+	s := os.Getenv("TENANT_KEY_ALGORITHMS")
+	if s == "" {
+		return algorithms
+	}
+	for _, pair := range strings.Split(s, ";") {
+		tenant, names, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || tenant == "" || names == "" {
+			log.Fatalf("invalid TENANT_KEY_ALGORITHMS entry %q, want tenant=<algorithm>,...", pair)
+		}
+		algorithms[tenant] = strings.Split(names, ",")
+	}
+	return algorithms
+}
+
+// Tenants lists the tenants the seeder spreads records over and keys-service
+// creates a key pool of TOTAL_KEYS keys for, e.g. "acme,globex".
+func Tenants() []string {
+	return strings.Split(envOr("TENANTS", "default"), ",")
+}
+
+// TenantWeights are the shares of signing capacity signing-service gives each
+// tenant with a backlog, e.g. "acme=3,globex=1". Unlisted tenants weigh 1.
+func TenantWeights() map[string]float64 {
+	return envFloatMap("TENANT_WEIGHTS")
+}
+
+// TenantRateLimits caps the signatures per second signing-service makes for a
+// tenant, e.g. "acme=500". Unlisted tenants are not limited.
+func TenantRateLimits() map[string]float64 {
+	return envFloatMap("TENANT_RATE_LIMITS")
+}
+
+// RecordsPriority is the priority records-service publishes its batches with:
+// "high", "normal" or "low".
+func RecordsPriority() string {
+	return envOr("RECORDS_PRIORITY", "normal")
+}
+
+// PriorityStarvationLimit is how many batches in a row signing-service takes
+// from higher priorities while a lower one has a batch waiting.
+func PriorityStarvationLimit() int {
+	return envIntOr("PRIORITY_STARVATION_LIMIT", 10)
+}
 
 func KeysMaxConcurrency() int {
 	return mustEnvInt("KEYS_MAX_CONCURRENCY")
@@ -81,8 +313,196 @@ func SignerMaxConcurrency() int {
 	return mustEnvInt("SIGNER_MAX_CONCURRENCY")
 }
 
-func ceilDiv(a, b int) int {
-	return (a + b - 1) / b
+// SignerBackend selects where private keys live: "memory", "keystore" or "pkcs11".
+func SignerBackend() string {
+	return envOr("SIGNER_BACKEND", "memory")
+}
+
+func KeystoreDir() string {
+	return envOr("KEYSTORE_DIR", "./keystore")
+}
+func KeystorePassphrase() string {
+	return mustEnv("KEYSTORE_PASSPHRASE")
+}
+
+// CAFile is the encrypted file holding the internal root CA that certifies signing keys.
+func CAFile() string {
+	return envOr("CA_FILE", "./ca/root.json")
+}
+func CAPassphrase() string {
+	return mustEnv("CA_PASSPHRASE")
+}
+
+// CACertFile is the PEM root certificate keys-service publishes, from which
+// api-service verifies key certificates without the CA key.
+func CACertFile() string {
+	return envOr("CA_CERT_FILE", "./ca/root.pem")
+}
+
+// SigningMode is how signing-service signs a batch: "record" signs every record,
+// "merkle" signs one Merkle tree head per batch and stores inclusion proofs.
+func SigningMode() string {
+	return envOr("SIGNING_MODE", "record")
+}
+
+// SignRequestSubject is the NATS subject prefix signing-service answers
+// synchronous sign requests on: a tenant's requests go to <prefix>.<tenant>.
+func SignRequestSubject() string {
+	return envOr("SIGN_REQUEST_SUBJECT", "sign.request")
+}
+
+// SigningMetricsAddr is the address signing-service serves its metrics on,
+// at /debug/vars. Metrics are not served when it is empty.
+func SigningMetricsAddr() string {
+	return os.Getenv("SIGNING_METRICS_ADDR")
+}
+
+// TSAURL is the RFC 3161 time-stamp authority signing-service asks to
+// timestamp every signature. Timestamping is skipped when it is empty.
+func TSAURL() string {
+	return os.Getenv("TSA_URL")
+}
+
+// APIAddr is the address api-service listens on.
+func APIAddr() string {
+	return envOr("API_ADDR", ":8080")
+}
+
+// APIRepublishAfter is how long a submitted record may stay unsigned before
+// api-service publishes it again, in case its first publish was lost.
+func APIRepublishAfter() time.Duration {
+	return envDurationOr("API_REPUBLISH_AFTER", 5*time.Minute)
+}
+
+// APIKeys maps the API keys api-service accepts, by the hex SHA-256 of each,
+// to the tenant it acts for, from API_KEYS, e.g. "acme=<sha256>,globex=<sha256>".
+// A tenant may have several keys.
+func APIKeys() map[string]string {
+	keys := make(map[string]string)
+	s := os.Getenv("API_KEYS")
+	if s == "" {
+		return keys
+	}
+	for _, pair := range strings.Split(s, ",") {
+		tenant, digest, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if b, err := hex.DecodeString(digest); !ok || tenant == "" || err != nil || len(b) != 32 {
+			log.Fatalf("invalid API_KEYS entry %q, want tenant=<hex SHA-256 of the key>", pair)
+		}
+		keys[strings.ToLower(digest)] = tenant
+	}
+	return keys
+}
+
+// WebhookMaxAttempts is how many times webhooks-service tries to deliver an
+// event to one webhook before giving up on it.
+func WebhookMaxAttempts() int {
+	return envIntOr("WEBHOOK_MAX_ATTEMPTS", 5)
+}
+
+// WebhookInitialBackoff is the delay before the second delivery attempt; it
+// doubles with every further attempt.
+func WebhookInitialBackoff() time.Duration {
+	return envDurationOr("WEBHOOK_INITIAL_BACKOFF", time.Second)
+}
+
+// ControllerInterval is how often controller-service samples the signing
+// consumers and publishes a scaling recommendation.
+func ControllerInterval() time.Duration {
+	return envDurationOr("CONTROLLER_INTERVAL", 15*time.Second)
+}
+
+// ControllerMetricsAddr is the address controller-service serves its metrics
+// on, at /debug/vars. Metrics are not served when it is empty.
+func ControllerMetricsAddr() string {
+	return os.Getenv("CONTROLLER_METRICS_ADDR")
+}
+
+// ControlSubject is the core NATS subject controller-service publishes its
+// scaling recommendations on.
+func ControlSubject() string {
+	return envOr("CONTROL_SUBJECT", "control.scaling")
+}
+
+// SigningReplicas is how many signing-service replicas currently run; the
+// controller scales its recommendation from it.
+func SigningReplicas() int {
+	return envIntOr("SIGNING_REPLICAS", 1)
+}
+
+// SigningMinReplicas and SigningMaxReplicas bound the recommended replicas.
+func SigningMinReplicas() int {
+	return envIntOr("SIGNING_MIN_REPLICAS", 1)
+}
+func SigningMaxReplicas() int {
+	return envIntOr("SIGNING_MAX_REPLICAS", 10)
+}
+
+// ControllerTargetDrain is how soon the recommended replicas should clear the
+// current records backlog on top of keeping up with new batches.
+func ControllerTargetDrain() time.Duration {
+	return envDurationOr("CONTROLLER_TARGET_DRAIN", time.Minute)
+}
+
+func PKCS11ModulePath() string {
+	return mustEnv("PKCS11_MODULE_PATH")
+}
+func PKCS11TokenLabel() string {
+	return mustEnv("PKCS11_TOKEN_LABEL")
+}
+func PKCS11Pin() string {
+	return mustEnv("PKCS11_PIN")
+}
+
+func envOr(key, fallback string) string {
+	if s := os.Getenv(key); s != "" {
+		return s
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) int {
+	if os.Getenv(key) == "" {
+		return fallback
+	}
+	return mustEnvInt(key)
+}
+
+func envDurationOr(key string, fallback time.Duration) time.Duration {
+	s := os.Getenv(key)
+	if s == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return d
+}
+
+// envFloatMap parses a "name=value,..." list of positive numbers.
+func envFloatMap(key string) map[string]float64 {
+	values := make(map[string]float64)
+	s := os.Getenv(key)
+	if s == "" {
+		return values
+	}
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		v, err := strconv.ParseFloat(value, 64)
+		if !ok || name == "" || err != nil || v <= 0 {
+			log.Fatalf("invalid %s entry %q, want name=<positive number>", key, pair)
+		}
+		values[name] = v
+	}
+	return values
+}
+
+func mustEnv(key string) string {
+	s := os.Getenv(key)
+	if s == "" {
+		log.Fatalf("%s not set", key)
+	}
+	return s
 }
 
 func mustEnvInt(key string) int {
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// keyTargetUtilization is the share of a tenant's keys the recommended pool
+// expects to be leased at once, leaving headroom for bursts.
+const keyTargetUtilization = 0.8
+
+var (
+	backlogVar         = new(expvar.Int)
+	arrivalRateVar     = new(expvar.Float)
+	throughputVar      = new(expvar.Float)
+	redeliveredVar     = new(expvar.Int)
+	desiredReplicasVar = new(expvar.Int)
+	recordsPendingVar  = new(expvar.Map)
+	keysLeasedVar      = new(expvar.Map)
+	desiredKeysVar     = new(expvar.Map)
+)
+
+func init() {
+	expvar.Publish("controller_records_backlog", backlogVar)
+	expvar.Publish("controller_arrival_rate", arrivalRateVar)
+	expvar.Publish("controller_throughput", throughputVar)
+	expvar.Publish("controller_redelivered", redeliveredVar)
+	expvar.Publish("controller_desired_replicas", desiredReplicasVar)
+	expvar.Publish("controller_records_pending", recordsPendingVar)
+	expvar.Publish("controller_keys_leased", keysLeasedVar)
+	expvar.Publish("controller_desired_keys", desiredKeysVar)
+}
+
+// consumerStats is the part of a consumer's info the controller scales on.
+type consumerStats struct {
+	pending     uint64 // matching messages not delivered yet
+	ackPending  uint64 // delivered, not acked yet
+	redelivered int
+}
+
+// snapshot is the state of the signing consumers at one point in time.
+type snapshot struct {
+	at        time.Time
+	records   map[string]consumerStats // by <tenant>.<priority>
+	keys      map[string]consumerStats // by tenant
+	committed uint64                   // records batches committed so far
+}
+
+// commits counts the records batches signing-service commits from their
+// signatures events. A batch is committed once however often it was
+// delivered, so unlike the consumers' ack floors the count leaves out
+// redeliveries.
+type commits struct {
+	batches atomic.Uint64
+}
+
+// handle receives a signatures event. Events of sign requests, which come
+// without timings, are not records batches and are ignored.
+func (c *commits) handle(msg *nats.Msg) {
+	var event types.SignaturesEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Warn("Error unmarshaling signatures event", zap.String("subject", msg.Subject), zap.Error(err))
+		return
+	}
+	if event.Timings != nil {
+		c.batches.Add(1)
+	}
+}
+
+// policy bounds and aims the recommendations.
+type policy struct {
+	currentReplicas int
+	minReplicas     int
+	maxReplicas     int
+	targetDrain     time.Duration
+}
+
+// consumerGetter is the part of jetstream.JetStream the controller needs.
+type consumerGetter interface {
+	Consumer(ctx context.Context, stream string, name string) (jetstream.Consumer, error)
+}
+
+// sample reads the records consumers of every tenant and priority, the keys
+// consumer of every tenant and the batches committed. Consumers
+// signing-service has not created yet are left out.
+func sample(ctx context.Context, js consumerGetter, tenants []string, committed *commits, now time.Time) (*snapshot, error) {
+	s := &snapshot{
+		at:        now,
+		records:   make(map[string]consumerStats),
+		keys:      make(map[string]consumerStats),
+		committed: committed.batches.Load(),
+	}
+	for _, tenant := range tenants {
+		for _, priority := range types.Priorities {
+			stats, ok, err := consumerInfo(ctx, js, config.RecordsStreamName(), fmt.Sprintf("signing-records-consumer-%s-%s", tenant, priority))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				s.records[tenant+"."+priority] = stats
+			}
+		}
+		stats, ok, err := consumerInfo(ctx, js, config.KeysStreamName(), "signing-keys-consumer-"+tenant)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.keys[tenant] = stats
+		}
+	}
+	return s, nil
+}
+
+func consumerInfo(ctx context.Context, js consumerGetter, stream, name string) (consumerStats, bool, error) {
+	consumer, err := js.Consumer(ctx, stream, name)
+	if errors.Is(err, jetstream.ErrConsumerNotFound) {
+		return consumerStats{}, false, nil
+	}
+	if err != nil {
+		return consumerStats{}, false, fmt.Errorf("failed loading consumer %s: %w", name, err)
+	}
+	info, err := consumer.Info(ctx)
+	if err != nil {
+		return consumerStats{}, false, fmt.Errorf("failed loading info of consumer %s: %w", name, err)
+	}
+	return consumerStats{
+		pending:     info.NumPending,
+		ackPending:  uint64(info.NumAckPending),
+		redelivered: info.NumRedelivered,
+	}, true, nil
+}
+
+// recommend compares two snapshots. Throughput is the rate batches were
+// committed between them, and the arrival rate that plus the growth of the
+// backlog. The replicas needed to keep up with arrivals and drain the backlog
+// within targetDrain are scaled from the current ones by the throughput they
+// achieved. Without a previous snapshot there are no rates, and the current
+// replicas are kept.
+func recommend(prev, cur *snapshot, p policy) types.ScalingRecommendation {
+	rec := types.ScalingRecommendation{
+		CurrentReplicas: p.currentReplicas,
+		DesiredReplicas: p.currentReplicas,
+		DesiredKeys:     make(map[string]int, len(cur.keys)),
+		SampledAt:       cur.at,
+	}
+	var pending uint64
+	for _, stats := range cur.records {
+		pending += stats.pending
+		rec.Backlog += stats.pending + stats.ackPending
+		rec.Redelivered += stats.redelivered
+	}
+
+	if prev != nil && cur.at.After(prev.at) {
+		elapsed := cur.at.Sub(prev.at).Seconds()
+		var committed, prevPending uint64
+		if cur.committed >= prev.committed {
+			committed = cur.committed - prev.committed
+		}
+		for lane := range cur.records {
+			prevPending += prev.records[lane].pending
+		}
+		rec.Throughput = float64(committed) / elapsed
+		rec.ArrivalRate = max(0, float64(committed)+float64(pending)-float64(prevPending)) / elapsed
+
+		required := rec.ArrivalRate + float64(rec.Backlog)/p.targetDrain.Seconds()
+		switch {
+		case rec.Backlog == 0 && rec.ArrivalRate == 0:
+			rec.DesiredReplicas = p.minReplicas
+		case rec.Throughput > 0:
+			rec.DesiredReplicas = int(math.Ceil(float64(p.currentReplicas) * required / rec.Throughput))
+		default:
+			// Work is waiting but nothing was committed: scale out one step.
+			rec.DesiredReplicas = p.currentReplicas + 1
+		}
+	}
+	rec.DesiredReplicas = min(max(rec.DesiredReplicas, p.minReplicas), p.maxReplicas)
+
+	// Every in-flight batch holds a key, so a tenant needs its leased keys
+	// scaled along with the replicas, at the target utilization.
+	for tenant, stats := range cur.keys {
+		scale := float64(rec.DesiredReplicas) / float64(max(p.currentReplicas, 1))
+		rec.DesiredKeys[tenant] = max(1, int(math.Ceil(float64(stats.ackPending)*scale/keyTargetUtilization)))
+	}
+	return rec
+}
+
+// report sets the controller's metrics from a snapshot and its recommendation.
+func report(cur *snapshot, rec types.ScalingRecommendation) {
+	backlogVar.Set(int64(rec.Backlog))
+	arrivalRateVar.Set(rec.ArrivalRate)
+	throughputVar.Set(rec.Throughput)
+	redeliveredVar.Set(int64(rec.Redelivered))
+	desiredReplicasVar.Set(int64(rec.DesiredReplicas))
+	for lane, stats := range cur.records {
+		pending := new(expvar.Int)
+		pending.Set(int64(stats.pending))
+		recordsPendingVar.Set(lane, pending)
+	}
+	for tenant, stats := range cur.keys {
+		leased := new(expvar.Int)
+		leased.Set(int64(stats.ackPending))
+		keysLeasedVar.Set(tenant, leased)
+	}
+	for tenant, n := range rec.DesiredKeys {
+		desired := new(expvar.Int)
+		desired.Set(int64(n))
+		desiredKeysVar.Set(tenant, desired)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop()
+}
+
+func TestRecommend(t *testing.T) {
+	start := time.Now()
+	p := policy{currentReplicas: 2, minReplicas: 1, maxReplicas: 10, targetDrain: time.Minute}
+	lane := func(pending, ackPending uint64) map[string]consumerStats {
+		return map[string]consumerStats{"acme.normal": {pending: pending, ackPending: ackPending}}
+	}
+	at := func(seconds int, records map[string]consumerStats, committed uint64) *snapshot {
+		return &snapshot{at: start.Add(time.Duration(seconds) * time.Second), records: records, committed: committed}
+	}
+
+	tests := []struct {
+		name            string
+		prev, cur       *snapshot
+		wantDesired     int
+		wantThroughput  float64
+		wantArrivalRate float64
+	}{
+		{name: "first sample", cur: at(0, lane(600, 4), 0), wantDesired: 2},
+		{name: "idle", prev: at(0, lane(0, 0), 50), cur: at(10, lane(0, 0), 50), wantDesired: 1},
+		// 10 batches/s arrive and are committed; nothing is waiting to drain.
+		{name: "keeping up", prev: at(0, lane(0, 0), 0), cur: at(10, lane(0, 0), 100), wantDesired: 2, wantThroughput: 10, wantArrivalRate: 10},
+		// 10/s arrive, 5/s are committed and 600 wait: 10 + 600/60 = 20/s needed, 4x the throughput.
+		{name: "falling behind", prev: at(0, lane(550, 0), 0), cur: at(10, lane(600, 0), 50), wantDesired: 8, wantThroughput: 5, wantArrivalRate: 10},
+		{name: "capped", prev: at(0, lane(0, 0), 0), cur: at(10, lane(60000, 0), 10), wantDesired: 10, wantThroughput: 1, wantArrivalRate: 6001},
+		{name: "stalled", prev: at(0, lane(100, 0), 20), cur: at(10, lane(100, 0), 20), wantDesired: 3},
+		{name: "restarted controller", prev: at(0, lane(0, 0), 500), cur: at(10, lane(100, 0), 0), wantDesired: 3, wantArrivalRate: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := recommend(tt.prev, tt.cur, p)
+			if rec.DesiredReplicas != tt.wantDesired || rec.Throughput != tt.wantThroughput || rec.ArrivalRate != tt.wantArrivalRate {
+				t.Errorf("recommend() = %d replicas, throughput %v, arrivals %v; want %d, %v, %v",
+					rec.DesiredReplicas, rec.Throughput, rec.ArrivalRate, tt.wantDesired, tt.wantThroughput, tt.wantArrivalRate)
+			}
+		})
+	}
+}
+
+// TestRecommendKeys verifies key pools are sized from the keys leased,
+// scaled with the replicas.
+func TestRecommendKeys(t *testing.T) {
+	start := time.Now()
+	p := policy{currentReplicas: 2, minReplicas: 1, maxReplicas: 10, targetDrain: time.Minute}
+	keys := map[string]consumerStats{
+		"acme":   {pending: 0, ackPending: 8},
+		"globex": {pending: 10, ackPending: 0},
+	}
+	prev := &snapshot{at: start, records: map[string]consumerStats{"acme.normal": {pending: 550}}, keys: keys}
+	cur := &snapshot{at: start.Add(10 * time.Second), records: map[string]consumerStats{"acme.normal": {pending: 600}}, keys: keys, committed: 50}
+
+	rec := recommend(prev, cur, p)
+	// 8 leased keys for 2 replicas become 32 for 8, at 80% utilization 40.
+	if rec.DesiredKeys["acme"] != 40 || rec.DesiredKeys["globex"] != 1 {
+		t.Errorf("DesiredKeys = %v, want acme 40 and globex 1", rec.DesiredKeys)
+	}
+}
+
+// fakeConsumer reports fixed info.
+type fakeConsumer struct {
+	jetstream.Consumer
+	info jetstream.ConsumerInfo
+}
+
+func (c *fakeConsumer) Info(context.Context) (*jetstream.ConsumerInfo, error) {
+	return &c.info, nil
+}
+
+// fakeJetStream serves the consumers it has.
+type fakeJetStream map[string]*fakeConsumer
+
+func (js fakeJetStream) Consumer(ctx context.Context, stream, name string) (jetstream.Consumer, error) {
+	if c, ok := js[name]; ok {
+		return c, nil
+	}
+	return nil, jetstream.ErrConsumerNotFound
+}
+
+// TestSample verifies consumers are read by tenant and priority, and missing
+// ones skipped.
+func TestSample(t *testing.T) {
+	js := fakeJetStream{
+		"signing-records-consumer-acme-high": {info: jetstream.ConsumerInfo{NumPending: 3, NumAckPending: 1, NumRedelivered: 1, AckFloor: jetstream.SequenceInfo{Consumer: 7}}},
+		"signing-keys-consumer-acme":         {info: jetstream.ConsumerInfo{NumPending: 4, NumAckPending: 2}},
+	}
+	committed := &commits{}
+	committed.batches.Store(5)
+	s, err := sample(context.Background(), js, []string{"acme", "globex"}, committed, time.Now())
+	if err != nil {
+		t.Fatalf("sample() returned an unexpected error: %v", err)
+	}
+	if s.committed != 5 {
+		t.Errorf("committed = %d, want 5", s.committed)
+	}
+	want := consumerStats{pending: 3, ackPending: 1, redelivered: 1}
+	if len(s.records) != 1 || s.records["acme.high"] != want {
+		t.Errorf("records = %+v, want acme.high %+v only", s.records, want)
+	}
+	if len(s.keys) != 1 || s.keys["acme"] != (consumerStats{pending: 4, ackPending: 2}) {
+		t.Errorf("keys = %+v, want acme only", s.keys)
+	}
+}
+
+// TestCommits verifies records batches are counted once per signatures
+// event, whatever their acks, and sign requests not at all.
+func TestCommits(t *testing.T) {
+	committed := &commits{}
+	events := []types.SignaturesEvent{
+		{TenantID: "acme", LedgerSeq: 1, Timings: &types.BatchTimings{Sign: time.Millisecond}},
+		{TenantID: "acme", LedgerSeq: 2},
+		{TenantID: "globex", LedgerSeq: 3, Timings: &types.BatchTimings{}},
+	}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("failed marshaling event: %v", err)
+		}
+		committed.handle(&nats.Msg{Subject: fmt.Sprintf("signatures.%s.%d", event.TenantID, event.LedgerSeq), Data: data})
+	}
+	committed.handle(&nats.Msg{Subject: "signatures.acme.4", Data: []byte("{")})
+	if got := committed.batches.Load(); got != 2 {
+		t.Errorf("batches = %d, want 2", got)
+	}
+}
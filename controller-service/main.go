@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/metrics"
+	"github.com/jurshsmith/vaultstream/nats"
+	"go.uber.org/zap"
+)
+
+var log *zap.Logger
+
+func main() {
+	log = logger.New()
+	defer log.Sync()
+
+	log.Info("Controller Service running...")
+
+	config.Setup()
+
+	jetstreamClient, natsConn, err := nats.Connect("controller-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
+	defer natsConn.Close()
+	log.Debug("NATS JetStream connection established")
+
+	p := policy{
+		currentReplicas: config.SigningReplicas(),
+		minReplicas:     config.SigningMinReplicas(),
+		maxReplicas:     config.SigningMaxReplicas(),
+		targetDrain:     config.ControllerTargetDrain(),
+	}
+	if p.minReplicas < 1 || p.maxReplicas < p.minReplicas || p.currentReplicas < 0 || p.targetDrain <= 0 {
+		log.Fatal("Invalid scaling policy",
+			zap.Int("currentReplicas", p.currentReplicas),
+			zap.Int("minReplicas", p.minReplicas),
+			zap.Int("maxReplicas", p.maxReplicas),
+			zap.Duration("targetDrain", p.targetDrain))
+	}
+
+	if addr := config.ControllerMetricsAddr(); addr != "" {
+		go func() {
+			if err := metrics.ListenAndServe(addr); err != nil {
+				log.Error("Error serving metrics", zap.Error(err))
+			}
+		}()
+		log.Info("Serving metrics", zap.String("addr", addr))
+	}
+
+	// Throughput is counted from the batches signing-service commits.
+	committed := &commits{}
+	signaturesSub, err := natsConn.Subscribe("signatures.>", committed.handle)
+	if err != nil {
+		log.Fatal("Error subscribing to signatures events", zap.Error(err))
+	}
+	defer signaturesSub.Unsubscribe()
+
+	ctx := context.Background()
+	tenants := config.Tenants()
+	subject := config.ControlSubject()
+	ticker := time.NewTicker(config.ControllerInterval())
+	defer ticker.Stop()
+
+	var prev *snapshot
+	for ; ; <-ticker.C {
+		cur, err := sample(ctx, jetstreamClient, tenants, committed, time.Now())
+		if err != nil {
+			log.Error("Error sampling signing consumers", zap.Error(err))
+			continue
+		}
+		rec := recommend(prev, cur, p)
+		prev = cur
+		report(cur, rec)
+
+		data, _ := json.Marshal(rec) // ScalingRecommendation always marshals.
+		if err := natsConn.Publish(subject, data); err != nil {
+			log.Error("Error publishing scaling recommendation", zap.Error(err))
+			continue
+		}
+		log.Info("Scaling recommendation published",
+			zap.Uint64("backlog", rec.Backlog),
+			zap.Float64("throughput", rec.Throughput),
+			zap.Int("desiredReplicas", rec.DesiredReplicas),
+			zap.Any("desiredKeys", rec.DesiredKeys))
+	}
+}
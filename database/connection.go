@@ -1,17 +1,107 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"expvar"
+	"fmt"
 	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
 	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	// Schema hooks are stitched in by the generated runtime package.
+	_ "github.com/jurshsmith/vaultstream/database/runtime"
 	_ "github.com/lib/pq"
 )
 
-func Connect() *Client {
-	dbURL := vaultStreamConfig.DatabaseURL()
-	dbClient, err := Open("postgres", dbURL)
+// pool is the connection pool of the last Connect, whose sql.DBStats are
+// published as database_pool.
+var pool atomic.Pointer[sql.DB]
+
+func init() {
+	expvar.Publish("database_pool", expvar.Func(func() any {
+		if db := pool.Load(); db != nil {
+			return db.Stats()
+		}
+		return nil
+	}))
+}
+
+// Connect opens the database for a service running up to concurrency
+// queries at once, sizing the pool as configured by DATABASE_* variables. It
+// waits up to DATABASE_STARTUP_TIMEOUT for the database to accept
+// connections.
+func Connect(concurrency int) (*Client, error) {
+	db, err := open(vaultStreamConfig.DatabasePoolFor(concurrency))
+	if err != nil {
+		return nil, err
+	}
+	pool.Store(db)
+	return NewClient(Driver(entsql.OpenDB(dialect.Postgres, db))), nil
+}
+
+// open opens DATABASE_URL with settings and waits for it to accept
+// connections.
+func open(settings vaultStreamConfig.DatabasePool) (*sql.DB, error) {
+	if !slices.Contains(sql.Drivers(), settings.Driver) {
+		return nil, fmt.Errorf("unknown database driver %q, want postgres or pgx", settings.Driver)
+	}
+	dsn, err := withStatementTimeout(vaultStreamConfig.DatabaseURL(), settings.StatementTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(settings.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening DB: %w", err)
+	}
+	db.SetMaxOpenConns(settings.MaxOpenConns)
+	db.SetMaxIdleConns(settings.MaxIdleConns)
+	db.SetConnMaxLifetime(settings.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(settings.ConnMaxIdleTime)
+
+	deadline := time.Now().Add(settings.StartupTimeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err = db.PingContext(ctx)
+		cancel()
+		if err == nil || time.Now().Add(time.Second).After(deadline) {
+			break
+		}
+		log.Printf("Database not ready yet, retrying in 1s: %v", err)
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed connecting to DB: %w", err)
+	}
+	return db, nil
+}
+
+// withStatementTimeout sets the statement_timeout run-time parameter, which
+// both drivers pass on to Postgres, unless dsn sets it already.
+func withStatementTimeout(dsn string, timeout time.Duration) (string, error) {
+	if timeout <= 0 || strings.Contains(dsn, "statement_timeout") {
+		return dsn, nil
+	}
+	ms := strconv.FormatInt(timeout.Milliseconds(), 10)
+	if !strings.HasPrefix(dsn, "postgres://") && !strings.HasPrefix(dsn, "postgresql://") {
+		// A key=value connection string.
+		return dsn + " statement_timeout=" + ms, nil
+	}
+	u, err := url.Parse(dsn)
 	if err != nil {
-		log.Fatalf("failed opening DB: %v", err)
+		return "", fmt.Errorf("invalid DATABASE_URL: %w", err)
 	}
-	return dbClient
+	q := u.Query()
+	q.Set("statement_timeout", ms)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
 }
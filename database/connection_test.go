@@ -0,0 +1,37 @@
+package database
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestDrivers verifies every DATABASE_DRIVER is built in, without build tags.
+func TestDrivers(t *testing.T) {
+	for _, driver := range []string{"postgres", "pgx"} {
+		if !slices.Contains(sql.Drivers(), driver) {
+			t.Errorf("driver %s not registered, have %v", driver, sql.Drivers())
+		}
+	}
+}
+
+func TestWithStatementTimeout(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		timeout time.Duration
+		want    string
+	}{
+		{dsn: "postgres://u:p@localhost:5432/vs?sslmode=disable", timeout: 0, want: "postgres://u:p@localhost:5432/vs?sslmode=disable"},
+		{dsn: "postgres://u:p@localhost:5432/vs?sslmode=disable", timeout: 5 * time.Second, want: "postgres://u:p@localhost:5432/vs?sslmode=disable&statement_timeout=5000"},
+		{dsn: "postgresql://localhost/vs", timeout: time.Second, want: "postgresql://localhost/vs?statement_timeout=1000"},
+		{dsn: "postgres://localhost/vs?statement_timeout=100", timeout: time.Second, want: "postgres://localhost/vs?statement_timeout=100"},
+		{dsn: "host=localhost dbname=vs", timeout: 250 * time.Millisecond, want: "host=localhost dbname=vs statement_timeout=250"},
+	}
+	for _, tt := range tests {
+		got, err := withStatementTimeout(tt.dsn, tt.timeout)
+		if err != nil || got != tt.want {
+			t.Errorf("withStatementTimeout(%q, %s) = %q, %v; want %q", tt.dsn, tt.timeout, got, err, tt.want)
+		}
+	}
+}
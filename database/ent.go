@@ -12,8 +12,12 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
+	"github.com/jurshsmith/vaultstream/database/key"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
 	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/database/webhook"
+	"github.com/jurshsmith/vaultstream/database/webhookdelivery"
 )
 
 // ent aliases to avoid import conflicts in user's code.
@@ -74,8 +78,12 @@ var (
 func checkColumn(table, column string) error {
 	initCheck.Do(func() {
 		columnCheck = sql.NewColumnCheck(map[string]func(string) bool{
-			record.Table:    record.ValidColumn,
-			signature.Table: signature.ValidColumn,
+			key.Table:             key.ValidColumn,
+			ledgerentry.Table:     ledgerentry.ValidColumn,
+			record.Table:          record.ValidColumn,
+			signature.Table:       signature.ValidColumn,
+			webhook.Table:         webhook.ValidColumn,
+			webhookdelivery.Table: webhookdelivery.ValidColumn,
 		})
 	})
 	return columnCheck(table, column)
@@ -3,8 +3,14 @@ package database
 import (
 	"context"
 	"database/sql"
+
+	"github.com/jurshsmith/vaultstream/database/schema"
 )
 
+// ErrAppendOnly is returned by updates and deletes of signatures and ledger
+// entries; see schema.AppendOnly.
+var ErrAppendOnly = schema.ErrAppendOnly
+
 // Exec runs a raw SQL statement via Ent’s underlying driver.
 func (c *Client) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	return c.config.ExecContext(ctx, query, args...)
@@ -14,3 +20,13 @@ func (c *Client) Exec(ctx context.Context, query string, args ...any) (sql.Resul
 func (c *Client) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
 	return c.config.QueryContext(ctx, query, args...)
 }
+
+// Exec runs a raw SQL statement within the transaction.
+func (tx *Tx) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.config.ExecContext(ctx, query, args...)
+}
+
+// Query runs a raw SQL query within the transaction.
+func (tx *Tx) Query(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.config.QueryContext(ctx, query, args...)
+}
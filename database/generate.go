@@ -1,3 +1,3 @@
-//go:generate go run entgo.io/ent/cmd/ent generate ./schema --target ./ --feature sql/execquery
+//go:generate go run entgo.io/ent/cmd/ent generate ./schema --target ./ --feature sql/execquery,sql/upsert,sql/versioned-migration
 
 package database
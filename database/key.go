@@ -0,0 +1,166 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/jurshsmith/vaultstream/database/key"
+)
+
+// Key is the model entity for the Key schema.
+type Key struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"id"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID string `json:"tenant_id"`
+	// Algorithm holds the value of the "algorithm" field.
+	Algorithm string `json:"algorithm"`
+	// PublicKey holds the value of the "public_key" field.
+	PublicKey string `json:"public_key"`
+	// Certificate holds the value of the "certificate" field.
+	Certificate string `json:"certificate,omitempty"`
+	// CertificateChain holds the value of the "certificate_chain" field.
+	CertificateChain []string `json:"certificate_chain,omitempty"`
+	// InsertedAt holds the value of the "inserted_at" field.
+	InsertedAt   time.Time `json:"inserted_at"`
+	selectValues sql.SelectValues
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*Key) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case key.FieldCertificateChain:
+			values[i] = new([]byte)
+		case key.FieldID:
+			values[i] = new(sql.NullInt64)
+		case key.FieldTenantID, key.FieldAlgorithm, key.FieldPublicKey, key.FieldCertificate:
+			values[i] = new(sql.NullString)
+		case key.FieldInsertedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the Key fields.
+func (k *Key) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case key.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			k.ID = int(value.Int64)
+		case key.FieldTenantID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				k.TenantID = value.String
+			}
+		case key.FieldAlgorithm:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field algorithm", values[i])
+			} else if value.Valid {
+				k.Algorithm = value.String
+			}
+		case key.FieldPublicKey:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field public_key", values[i])
+			} else if value.Valid {
+				k.PublicKey = value.String
+			}
+		case key.FieldCertificate:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field certificate", values[i])
+			} else if value.Valid {
+				k.Certificate = value.String
+			}
+		case key.FieldCertificateChain:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field certificate_chain", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &k.CertificateChain); err != nil {
+					return fmt.Errorf("unmarshal field certificate_chain: %w", err)
+				}
+			}
+		case key.FieldInsertedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field inserted_at", values[i])
+			} else if value.Valid {
+				k.InsertedAt = value.Time
+			}
+		default:
+			k.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the Key.
+// This includes values selected through modifiers, order, etc.
+func (k *Key) Value(name string) (ent.Value, error) {
+	return k.selectValues.Get(name)
+}
+
+// Update returns a builder for updating this Key.
+// Note that you need to call Key.Unwrap() before calling this method if this Key
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (k *Key) Update() *KeyUpdateOne {
+	return NewKeyClient(k.config).UpdateOne(k)
+}
+
+// Unwrap unwraps the Key entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (k *Key) Unwrap() *Key {
+	_tx, ok := k.config.driver.(*txDriver)
+	if !ok {
+		panic("database: Key is not a transactional entity")
+	}
+	k.config.driver = _tx.drv
+	return k
+}
+
+// String implements the fmt.Stringer.
+func (k *Key) String() string {
+	var builder strings.Builder
+	builder.WriteString("Key(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", k.ID))
+	builder.WriteString("tenant_id=")
+	builder.WriteString(k.TenantID)
+	builder.WriteString(", ")
+	builder.WriteString("algorithm=")
+	builder.WriteString(k.Algorithm)
+	builder.WriteString(", ")
+	builder.WriteString("public_key=")
+	builder.WriteString(k.PublicKey)
+	builder.WriteString(", ")
+	builder.WriteString("certificate=")
+	builder.WriteString(k.Certificate)
+	builder.WriteString(", ")
+	builder.WriteString("certificate_chain=")
+	builder.WriteString(fmt.Sprintf("%v", k.CertificateChain))
+	builder.WriteString(", ")
+	builder.WriteString("inserted_at=")
+	builder.WriteString(k.InsertedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// Keys is a parsable slice of Key.
+type Keys []*Key
@@ -0,0 +1,99 @@
+// Code generated by ent, DO NOT EDIT.
+
+package key
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+)
+
+const (
+	// Label holds the string label denoting the key type in the database.
+	Label = "key"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldTenantID holds the string denoting the tenant_id field in the database.
+	FieldTenantID = "tenant_id"
+	// FieldAlgorithm holds the string denoting the algorithm field in the database.
+	FieldAlgorithm = "algorithm"
+	// FieldPublicKey holds the string denoting the public_key field in the database.
+	FieldPublicKey = "public_key"
+	// FieldCertificate holds the string denoting the certificate field in the database.
+	FieldCertificate = "certificate"
+	// FieldCertificateChain holds the string denoting the certificate_chain field in the database.
+	FieldCertificateChain = "certificate_chain"
+	// FieldInsertedAt holds the string denoting the inserted_at field in the database.
+	FieldInsertedAt = "inserted_at"
+	// Table holds the table name of the key in the database.
+	Table = "keys"
+)
+
+// Columns holds all SQL columns for key fields.
+var Columns = []string{
+	FieldID,
+	FieldTenantID,
+	FieldAlgorithm,
+	FieldPublicKey,
+	FieldCertificate,
+	FieldCertificateChain,
+	FieldInsertedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DefaultTenantID holds the default value on creation for the "tenant_id" field.
+	DefaultTenantID string
+	// TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	TenantIDValidator func(string) error
+	// AlgorithmValidator is a validator for the "algorithm" field. It is called by the builders before save.
+	AlgorithmValidator func(string) error
+	// PublicKeyValidator is a validator for the "public_key" field. It is called by the builders before save.
+	PublicKeyValidator func(string) error
+	// DefaultInsertedAt holds the default value on creation for the "inserted_at" field.
+	DefaultInsertedAt func() time.Time
+	// IDValidator is a validator for the "id" field. It is called by the builders before save.
+	IDValidator func(int) error
+)
+
+// OrderOption defines the ordering options for the Key queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByTenantID orders the results by the tenant_id field.
+func ByTenantID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTenantID, opts...).ToFunc()
+}
+
+// ByAlgorithm orders the results by the algorithm field.
+func ByAlgorithm(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAlgorithm, opts...).ToFunc()
+}
+
+// ByPublicKey orders the results by the public_key field.
+func ByPublicKey(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPublicKey, opts...).ToFunc()
+}
+
+// ByCertificate orders the results by the certificate field.
+func ByCertificate(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldCertificate, opts...).ToFunc()
+}
+
+// ByInsertedAt orders the results by the inserted_at field.
+func ByInsertedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldInsertedAt, opts...).ToFunc()
+}
@@ -0,0 +1,415 @@
+// Code generated by ent, DO NOT EDIT.
+
+package key
+
+import (
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/jurshsmith/vaultstream/database/predicate"
+)
+
+// ID filters vertices based on their ID field.
+func ID(id int) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldID, id))
+}
+
+// IDEQ applies the EQ predicate on the ID field.
+func IDEQ(id int) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldID, id))
+}
+
+// IDNEQ applies the NEQ predicate on the ID field.
+func IDNEQ(id int) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldID, id))
+}
+
+// IDIn applies the In predicate on the ID field.
+func IDIn(ids ...int) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldID, ids...))
+}
+
+// IDNotIn applies the NotIn predicate on the ID field.
+func IDNotIn(ids ...int) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldID, ids...))
+}
+
+// IDGT applies the GT predicate on the ID field.
+func IDGT(id int) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldID, id))
+}
+
+// IDGTE applies the GTE predicate on the ID field.
+func IDGTE(id int) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldID, id))
+}
+
+// IDLT applies the LT predicate on the ID field.
+func IDLT(id int) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldID, id))
+}
+
+// IDLTE applies the LTE predicate on the ID field.
+func IDLTE(id int) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldID, id))
+}
+
+// TenantID applies equality check predicate on the "tenant_id" field. It's identical to TenantIDEQ.
+func TenantID(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldTenantID, v))
+}
+
+// Algorithm applies equality check predicate on the "algorithm" field. It's identical to AlgorithmEQ.
+func Algorithm(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldAlgorithm, v))
+}
+
+// PublicKey applies equality check predicate on the "public_key" field. It's identical to PublicKeyEQ.
+func PublicKey(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldPublicKey, v))
+}
+
+// Certificate applies equality check predicate on the "certificate" field. It's identical to CertificateEQ.
+func Certificate(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldCertificate, v))
+}
+
+// InsertedAt applies equality check predicate on the "inserted_at" field. It's identical to InsertedAtEQ.
+func InsertedAt(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldInsertedAt, v))
+}
+
+// TenantIDEQ applies the EQ predicate on the "tenant_id" field.
+func TenantIDEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldTenantID, v))
+}
+
+// TenantIDNEQ applies the NEQ predicate on the "tenant_id" field.
+func TenantIDNEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldTenantID, v))
+}
+
+// TenantIDIn applies the In predicate on the "tenant_id" field.
+func TenantIDIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldTenantID, vs...))
+}
+
+// TenantIDNotIn applies the NotIn predicate on the "tenant_id" field.
+func TenantIDNotIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldTenantID, vs...))
+}
+
+// TenantIDGT applies the GT predicate on the "tenant_id" field.
+func TenantIDGT(v string) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldTenantID, v))
+}
+
+// TenantIDGTE applies the GTE predicate on the "tenant_id" field.
+func TenantIDGTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldTenantID, v))
+}
+
+// TenantIDLT applies the LT predicate on the "tenant_id" field.
+func TenantIDLT(v string) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldTenantID, v))
+}
+
+// TenantIDLTE applies the LTE predicate on the "tenant_id" field.
+func TenantIDLTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldTenantID, v))
+}
+
+// TenantIDContains applies the Contains predicate on the "tenant_id" field.
+func TenantIDContains(v string) predicate.Key {
+	return predicate.Key(sql.FieldContains(FieldTenantID, v))
+}
+
+// TenantIDHasPrefix applies the HasPrefix predicate on the "tenant_id" field.
+func TenantIDHasPrefix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasPrefix(FieldTenantID, v))
+}
+
+// TenantIDHasSuffix applies the HasSuffix predicate on the "tenant_id" field.
+func TenantIDHasSuffix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasSuffix(FieldTenantID, v))
+}
+
+// TenantIDEqualFold applies the EqualFold predicate on the "tenant_id" field.
+func TenantIDEqualFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldEqualFold(FieldTenantID, v))
+}
+
+// TenantIDContainsFold applies the ContainsFold predicate on the "tenant_id" field.
+func TenantIDContainsFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldContainsFold(FieldTenantID, v))
+}
+
+// AlgorithmEQ applies the EQ predicate on the "algorithm" field.
+func AlgorithmEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmNEQ applies the NEQ predicate on the "algorithm" field.
+func AlgorithmNEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmIn applies the In predicate on the "algorithm" field.
+func AlgorithmIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldAlgorithm, vs...))
+}
+
+// AlgorithmNotIn applies the NotIn predicate on the "algorithm" field.
+func AlgorithmNotIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldAlgorithm, vs...))
+}
+
+// AlgorithmGT applies the GT predicate on the "algorithm" field.
+func AlgorithmGT(v string) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldAlgorithm, v))
+}
+
+// AlgorithmGTE applies the GTE predicate on the "algorithm" field.
+func AlgorithmGTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldAlgorithm, v))
+}
+
+// AlgorithmLT applies the LT predicate on the "algorithm" field.
+func AlgorithmLT(v string) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldAlgorithm, v))
+}
+
+// AlgorithmLTE applies the LTE predicate on the "algorithm" field.
+func AlgorithmLTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldAlgorithm, v))
+}
+
+// AlgorithmContains applies the Contains predicate on the "algorithm" field.
+func AlgorithmContains(v string) predicate.Key {
+	return predicate.Key(sql.FieldContains(FieldAlgorithm, v))
+}
+
+// AlgorithmHasPrefix applies the HasPrefix predicate on the "algorithm" field.
+func AlgorithmHasPrefix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasPrefix(FieldAlgorithm, v))
+}
+
+// AlgorithmHasSuffix applies the HasSuffix predicate on the "algorithm" field.
+func AlgorithmHasSuffix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasSuffix(FieldAlgorithm, v))
+}
+
+// AlgorithmEqualFold applies the EqualFold predicate on the "algorithm" field.
+func AlgorithmEqualFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldEqualFold(FieldAlgorithm, v))
+}
+
+// AlgorithmContainsFold applies the ContainsFold predicate on the "algorithm" field.
+func AlgorithmContainsFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldContainsFold(FieldAlgorithm, v))
+}
+
+// PublicKeyEQ applies the EQ predicate on the "public_key" field.
+func PublicKeyEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldPublicKey, v))
+}
+
+// PublicKeyNEQ applies the NEQ predicate on the "public_key" field.
+func PublicKeyNEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldPublicKey, v))
+}
+
+// PublicKeyIn applies the In predicate on the "public_key" field.
+func PublicKeyIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldPublicKey, vs...))
+}
+
+// PublicKeyNotIn applies the NotIn predicate on the "public_key" field.
+func PublicKeyNotIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldPublicKey, vs...))
+}
+
+// PublicKeyGT applies the GT predicate on the "public_key" field.
+func PublicKeyGT(v string) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldPublicKey, v))
+}
+
+// PublicKeyGTE applies the GTE predicate on the "public_key" field.
+func PublicKeyGTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldPublicKey, v))
+}
+
+// PublicKeyLT applies the LT predicate on the "public_key" field.
+func PublicKeyLT(v string) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldPublicKey, v))
+}
+
+// PublicKeyLTE applies the LTE predicate on the "public_key" field.
+func PublicKeyLTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldPublicKey, v))
+}
+
+// PublicKeyContains applies the Contains predicate on the "public_key" field.
+func PublicKeyContains(v string) predicate.Key {
+	return predicate.Key(sql.FieldContains(FieldPublicKey, v))
+}
+
+// PublicKeyHasPrefix applies the HasPrefix predicate on the "public_key" field.
+func PublicKeyHasPrefix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasPrefix(FieldPublicKey, v))
+}
+
+// PublicKeyHasSuffix applies the HasSuffix predicate on the "public_key" field.
+func PublicKeyHasSuffix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasSuffix(FieldPublicKey, v))
+}
+
+// PublicKeyEqualFold applies the EqualFold predicate on the "public_key" field.
+func PublicKeyEqualFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldEqualFold(FieldPublicKey, v))
+}
+
+// PublicKeyContainsFold applies the ContainsFold predicate on the "public_key" field.
+func PublicKeyContainsFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldContainsFold(FieldPublicKey, v))
+}
+
+// CertificateEQ applies the EQ predicate on the "certificate" field.
+func CertificateEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldCertificate, v))
+}
+
+// CertificateNEQ applies the NEQ predicate on the "certificate" field.
+func CertificateNEQ(v string) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldCertificate, v))
+}
+
+// CertificateIn applies the In predicate on the "certificate" field.
+func CertificateIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldCertificate, vs...))
+}
+
+// CertificateNotIn applies the NotIn predicate on the "certificate" field.
+func CertificateNotIn(vs ...string) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldCertificate, vs...))
+}
+
+// CertificateGT applies the GT predicate on the "certificate" field.
+func CertificateGT(v string) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldCertificate, v))
+}
+
+// CertificateGTE applies the GTE predicate on the "certificate" field.
+func CertificateGTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldCertificate, v))
+}
+
+// CertificateLT applies the LT predicate on the "certificate" field.
+func CertificateLT(v string) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldCertificate, v))
+}
+
+// CertificateLTE applies the LTE predicate on the "certificate" field.
+func CertificateLTE(v string) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldCertificate, v))
+}
+
+// CertificateContains applies the Contains predicate on the "certificate" field.
+func CertificateContains(v string) predicate.Key {
+	return predicate.Key(sql.FieldContains(FieldCertificate, v))
+}
+
+// CertificateHasPrefix applies the HasPrefix predicate on the "certificate" field.
+func CertificateHasPrefix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasPrefix(FieldCertificate, v))
+}
+
+// CertificateHasSuffix applies the HasSuffix predicate on the "certificate" field.
+func CertificateHasSuffix(v string) predicate.Key {
+	return predicate.Key(sql.FieldHasSuffix(FieldCertificate, v))
+}
+
+// CertificateIsNil applies the IsNil predicate on the "certificate" field.
+func CertificateIsNil() predicate.Key {
+	return predicate.Key(sql.FieldIsNull(FieldCertificate))
+}
+
+// CertificateNotNil applies the NotNil predicate on the "certificate" field.
+func CertificateNotNil() predicate.Key {
+	return predicate.Key(sql.FieldNotNull(FieldCertificate))
+}
+
+// CertificateEqualFold applies the EqualFold predicate on the "certificate" field.
+func CertificateEqualFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldEqualFold(FieldCertificate, v))
+}
+
+// CertificateContainsFold applies the ContainsFold predicate on the "certificate" field.
+func CertificateContainsFold(v string) predicate.Key {
+	return predicate.Key(sql.FieldContainsFold(FieldCertificate, v))
+}
+
+// CertificateChainIsNil applies the IsNil predicate on the "certificate_chain" field.
+func CertificateChainIsNil() predicate.Key {
+	return predicate.Key(sql.FieldIsNull(FieldCertificateChain))
+}
+
+// CertificateChainNotNil applies the NotNil predicate on the "certificate_chain" field.
+func CertificateChainNotNil() predicate.Key {
+	return predicate.Key(sql.FieldNotNull(FieldCertificateChain))
+}
+
+// InsertedAtEQ applies the EQ predicate on the "inserted_at" field.
+func InsertedAtEQ(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldEQ(FieldInsertedAt, v))
+}
+
+// InsertedAtNEQ applies the NEQ predicate on the "inserted_at" field.
+func InsertedAtNEQ(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldNEQ(FieldInsertedAt, v))
+}
+
+// InsertedAtIn applies the In predicate on the "inserted_at" field.
+func InsertedAtIn(vs ...time.Time) predicate.Key {
+	return predicate.Key(sql.FieldIn(FieldInsertedAt, vs...))
+}
+
+// InsertedAtNotIn applies the NotIn predicate on the "inserted_at" field.
+func InsertedAtNotIn(vs ...time.Time) predicate.Key {
+	return predicate.Key(sql.FieldNotIn(FieldInsertedAt, vs...))
+}
+
+// InsertedAtGT applies the GT predicate on the "inserted_at" field.
+func InsertedAtGT(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldGT(FieldInsertedAt, v))
+}
+
+// InsertedAtGTE applies the GTE predicate on the "inserted_at" field.
+func InsertedAtGTE(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldGTE(FieldInsertedAt, v))
+}
+
+// InsertedAtLT applies the LT predicate on the "inserted_at" field.
+func InsertedAtLT(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldLT(FieldInsertedAt, v))
+}
+
+// InsertedAtLTE applies the LTE predicate on the "inserted_at" field.
+func InsertedAtLTE(v time.Time) predicate.Key {
+	return predicate.Key(sql.FieldLTE(FieldInsertedAt, v))
+}
+
+// And groups predicates with the AND operator between them.
+func And(predicates ...predicate.Key) predicate.Key {
+	return predicate.Key(sql.AndPredicates(predicates...))
+}
+
+// Or groups predicates with the OR operator between them.
+func Or(predicates ...predicate.Key) predicate.Key {
+	return predicate.Key(sql.OrPredicates(predicates...))
+}
+
+// Not applies the not operator on the given predicate.
+func Not(p predicate.Key) predicate.Key {
+	return predicate.Key(sql.NotPredicates(p))
+}
@@ -0,0 +1,842 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/key"
+)
+
+// KeyCreate is the builder for creating a Key entity.
+type KeyCreate struct {
+	config
+	mutation *KeyMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (kc *KeyCreate) SetTenantID(s string) *KeyCreate {
+	kc.mutation.SetTenantID(s)
+	return kc
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (kc *KeyCreate) SetNillableTenantID(s *string) *KeyCreate {
+	if s != nil {
+		kc.SetTenantID(*s)
+	}
+	return kc
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (kc *KeyCreate) SetAlgorithm(s string) *KeyCreate {
+	kc.mutation.SetAlgorithm(s)
+	return kc
+}
+
+// SetPublicKey sets the "public_key" field.
+func (kc *KeyCreate) SetPublicKey(s string) *KeyCreate {
+	kc.mutation.SetPublicKey(s)
+	return kc
+}
+
+// SetCertificate sets the "certificate" field.
+func (kc *KeyCreate) SetCertificate(s string) *KeyCreate {
+	kc.mutation.SetCertificate(s)
+	return kc
+}
+
+// SetNillableCertificate sets the "certificate" field if the given value is not nil.
+func (kc *KeyCreate) SetNillableCertificate(s *string) *KeyCreate {
+	if s != nil {
+		kc.SetCertificate(*s)
+	}
+	return kc
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (kc *KeyCreate) SetCertificateChain(s []string) *KeyCreate {
+	kc.mutation.SetCertificateChain(s)
+	return kc
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (kc *KeyCreate) SetInsertedAt(t time.Time) *KeyCreate {
+	kc.mutation.SetInsertedAt(t)
+	return kc
+}
+
+// SetNillableInsertedAt sets the "inserted_at" field if the given value is not nil.
+func (kc *KeyCreate) SetNillableInsertedAt(t *time.Time) *KeyCreate {
+	if t != nil {
+		kc.SetInsertedAt(*t)
+	}
+	return kc
+}
+
+// SetID sets the "id" field.
+func (kc *KeyCreate) SetID(i int) *KeyCreate {
+	kc.mutation.SetID(i)
+	return kc
+}
+
+// Mutation returns the KeyMutation object of the builder.
+func (kc *KeyCreate) Mutation() *KeyMutation {
+	return kc.mutation
+}
+
+// Save creates the Key in the database.
+func (kc *KeyCreate) Save(ctx context.Context) (*Key, error) {
+	kc.defaults()
+	return withHooks(ctx, kc.sqlSave, kc.mutation, kc.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (kc *KeyCreate) SaveX(ctx context.Context) *Key {
+	v, err := kc.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (kc *KeyCreate) Exec(ctx context.Context) error {
+	_, err := kc.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (kc *KeyCreate) ExecX(ctx context.Context) {
+	if err := kc.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (kc *KeyCreate) defaults() {
+	if _, ok := kc.mutation.TenantID(); !ok {
+		v := key.DefaultTenantID
+		kc.mutation.SetTenantID(v)
+	}
+	if _, ok := kc.mutation.InsertedAt(); !ok {
+		v := key.DefaultInsertedAt()
+		kc.mutation.SetInsertedAt(v)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (kc *KeyCreate) check() error {
+	if _, ok := kc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`database: missing required field "Key.tenant_id"`)}
+	}
+	if v, ok := kc.mutation.TenantID(); ok {
+		if err := key.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`database: validator failed for field "Key.tenant_id": %w`, err)}
+		}
+	}
+	if _, ok := kc.mutation.Algorithm(); !ok {
+		return &ValidationError{Name: "algorithm", err: errors.New(`database: missing required field "Key.algorithm"`)}
+	}
+	if v, ok := kc.mutation.Algorithm(); ok {
+		if err := key.AlgorithmValidator(v); err != nil {
+			return &ValidationError{Name: "algorithm", err: fmt.Errorf(`database: validator failed for field "Key.algorithm": %w`, err)}
+		}
+	}
+	if _, ok := kc.mutation.PublicKey(); !ok {
+		return &ValidationError{Name: "public_key", err: errors.New(`database: missing required field "Key.public_key"`)}
+	}
+	if v, ok := kc.mutation.PublicKey(); ok {
+		if err := key.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`database: validator failed for field "Key.public_key": %w`, err)}
+		}
+	}
+	if _, ok := kc.mutation.InsertedAt(); !ok {
+		return &ValidationError{Name: "inserted_at", err: errors.New(`database: missing required field "Key.inserted_at"`)}
+	}
+	if v, ok := kc.mutation.ID(); ok {
+		if err := key.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`database: validator failed for field "Key.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (kc *KeyCreate) sqlSave(ctx context.Context) (*Key, error) {
+	if err := kc.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := kc.createSpec()
+	if err := sqlgraph.CreateNode(ctx, kc.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int(id)
+	}
+	kc.mutation.id = &_node.ID
+	kc.mutation.done = true
+	return _node, nil
+}
+
+func (kc *KeyCreate) createSpec() (*Key, *sqlgraph.CreateSpec) {
+	var (
+		_node = &Key{config: kc.config}
+		_spec = sqlgraph.NewCreateSpec(key.Table, sqlgraph.NewFieldSpec(key.FieldID, field.TypeInt))
+	)
+	_spec.OnConflict = kc.conflict
+	if id, ok := kc.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := kc.mutation.TenantID(); ok {
+		_spec.SetField(key.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
+	if value, ok := kc.mutation.Algorithm(); ok {
+		_spec.SetField(key.FieldAlgorithm, field.TypeString, value)
+		_node.Algorithm = value
+	}
+	if value, ok := kc.mutation.PublicKey(); ok {
+		_spec.SetField(key.FieldPublicKey, field.TypeString, value)
+		_node.PublicKey = value
+	}
+	if value, ok := kc.mutation.Certificate(); ok {
+		_spec.SetField(key.FieldCertificate, field.TypeString, value)
+		_node.Certificate = value
+	}
+	if value, ok := kc.mutation.CertificateChain(); ok {
+		_spec.SetField(key.FieldCertificateChain, field.TypeJSON, value)
+		_node.CertificateChain = value
+	}
+	if value, ok := kc.mutation.InsertedAt(); ok {
+		_spec.SetField(key.FieldInsertedAt, field.TypeTime, value)
+		_node.InsertedAt = value
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Key.Create().
+//		SetTenantID(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.KeyUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (kc *KeyCreate) OnConflict(opts ...sql.ConflictOption) *KeyUpsertOne {
+	kc.conflict = opts
+	return &KeyUpsertOne{
+		create: kc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (kc *KeyCreate) OnConflictColumns(columns ...string) *KeyUpsertOne {
+	kc.conflict = append(kc.conflict, sql.ConflictColumns(columns...))
+	return &KeyUpsertOne{
+		create: kc,
+	}
+}
+
+type (
+	// KeyUpsertOne is the builder for "upsert"-ing
+	//  one Key node.
+	KeyUpsertOne struct {
+		create *KeyCreate
+	}
+
+	// KeyUpsert is the "OnConflict" setter.
+	KeyUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetTenantID sets the "tenant_id" field.
+func (u *KeyUpsert) SetTenantID(v string) *KeyUpsert {
+	u.Set(key.FieldTenantID, v)
+	return u
+}
+
+// UpdateTenantID sets the "tenant_id" field to the value that was provided on create.
+func (u *KeyUpsert) UpdateTenantID() *KeyUpsert {
+	u.SetExcluded(key.FieldTenantID)
+	return u
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (u *KeyUpsert) SetAlgorithm(v string) *KeyUpsert {
+	u.Set(key.FieldAlgorithm, v)
+	return u
+}
+
+// UpdateAlgorithm sets the "algorithm" field to the value that was provided on create.
+func (u *KeyUpsert) UpdateAlgorithm() *KeyUpsert {
+	u.SetExcluded(key.FieldAlgorithm)
+	return u
+}
+
+// SetPublicKey sets the "public_key" field.
+func (u *KeyUpsert) SetPublicKey(v string) *KeyUpsert {
+	u.Set(key.FieldPublicKey, v)
+	return u
+}
+
+// UpdatePublicKey sets the "public_key" field to the value that was provided on create.
+func (u *KeyUpsert) UpdatePublicKey() *KeyUpsert {
+	u.SetExcluded(key.FieldPublicKey)
+	return u
+}
+
+// SetCertificate sets the "certificate" field.
+func (u *KeyUpsert) SetCertificate(v string) *KeyUpsert {
+	u.Set(key.FieldCertificate, v)
+	return u
+}
+
+// UpdateCertificate sets the "certificate" field to the value that was provided on create.
+func (u *KeyUpsert) UpdateCertificate() *KeyUpsert {
+	u.SetExcluded(key.FieldCertificate)
+	return u
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (u *KeyUpsert) ClearCertificate() *KeyUpsert {
+	u.SetNull(key.FieldCertificate)
+	return u
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (u *KeyUpsert) SetCertificateChain(v []string) *KeyUpsert {
+	u.Set(key.FieldCertificateChain, v)
+	return u
+}
+
+// UpdateCertificateChain sets the "certificate_chain" field to the value that was provided on create.
+func (u *KeyUpsert) UpdateCertificateChain() *KeyUpsert {
+	u.SetExcluded(key.FieldCertificateChain)
+	return u
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (u *KeyUpsert) ClearCertificateChain() *KeyUpsert {
+	u.SetNull(key.FieldCertificateChain)
+	return u
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *KeyUpsert) SetInsertedAt(v time.Time) *KeyUpsert {
+	u.Set(key.FieldInsertedAt, v)
+	return u
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *KeyUpsert) UpdateInsertedAt() *KeyUpsert {
+	u.SetExcluded(key.FieldInsertedAt)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create except the ID field.
+// Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(key.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *KeyUpsertOne) UpdateNewValues() *KeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.ID(); exists {
+			s.SetIgnore(key.FieldID)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *KeyUpsertOne) Ignore() *KeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *KeyUpsertOne) DoNothing() *KeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the KeyCreate.OnConflict
+// documentation for more info.
+func (u *KeyUpsertOne) Update(set func(*KeyUpsert)) *KeyUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&KeyUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (u *KeyUpsertOne) SetTenantID(v string) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetTenantID(v)
+	})
+}
+
+// UpdateTenantID sets the "tenant_id" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdateTenantID() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateTenantID()
+	})
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (u *KeyUpsertOne) SetAlgorithm(v string) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetAlgorithm(v)
+	})
+}
+
+// UpdateAlgorithm sets the "algorithm" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdateAlgorithm() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateAlgorithm()
+	})
+}
+
+// SetPublicKey sets the "public_key" field.
+func (u *KeyUpsertOne) SetPublicKey(v string) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetPublicKey(v)
+	})
+}
+
+// UpdatePublicKey sets the "public_key" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdatePublicKey() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdatePublicKey()
+	})
+}
+
+// SetCertificate sets the "certificate" field.
+func (u *KeyUpsertOne) SetCertificate(v string) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetCertificate(v)
+	})
+}
+
+// UpdateCertificate sets the "certificate" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdateCertificate() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateCertificate()
+	})
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (u *KeyUpsertOne) ClearCertificate() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.ClearCertificate()
+	})
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (u *KeyUpsertOne) SetCertificateChain(v []string) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetCertificateChain(v)
+	})
+}
+
+// UpdateCertificateChain sets the "certificate_chain" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdateCertificateChain() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateCertificateChain()
+	})
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (u *KeyUpsertOne) ClearCertificateChain() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.ClearCertificateChain()
+	})
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *KeyUpsertOne) SetInsertedAt(v time.Time) *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetInsertedAt(v)
+	})
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *KeyUpsertOne) UpdateInsertedAt() *KeyUpsertOne {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateInsertedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *KeyUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for KeyCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *KeyUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *KeyUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *KeyUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// KeyCreateBulk is the builder for creating many Key entities in bulk.
+type KeyCreateBulk struct {
+	config
+	err      error
+	builders []*KeyCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the Key entities in the database.
+func (kcb *KeyCreateBulk) Save(ctx context.Context) ([]*Key, error) {
+	if kcb.err != nil {
+		return nil, kcb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(kcb.builders))
+	nodes := make([]*Key, len(kcb.builders))
+	mutators := make([]Mutator, len(kcb.builders))
+	for i := range kcb.builders {
+		func(i int, root context.Context) {
+			builder := kcb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*KeyMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, kcb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = kcb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, kcb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, kcb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (kcb *KeyCreateBulk) SaveX(ctx context.Context) []*Key {
+	v, err := kcb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (kcb *KeyCreateBulk) Exec(ctx context.Context) error {
+	_, err := kcb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (kcb *KeyCreateBulk) ExecX(ctx context.Context) {
+	if err := kcb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Key.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.KeyUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (kcb *KeyCreateBulk) OnConflict(opts ...sql.ConflictOption) *KeyUpsertBulk {
+	kcb.conflict = opts
+	return &KeyUpsertBulk{
+		create: kcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (kcb *KeyCreateBulk) OnConflictColumns(columns ...string) *KeyUpsertBulk {
+	kcb.conflict = append(kcb.conflict, sql.ConflictColumns(columns...))
+	return &KeyUpsertBulk{
+		create: kcb,
+	}
+}
+
+// KeyUpsertBulk is the builder for "upsert"-ing
+// a bulk of Key nodes.
+type KeyUpsertBulk struct {
+	create *KeyCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(key.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *KeyUpsertBulk) UpdateNewValues() *KeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.ID(); exists {
+				s.SetIgnore(key.FieldID)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Key.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *KeyUpsertBulk) Ignore() *KeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *KeyUpsertBulk) DoNothing() *KeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the KeyCreateBulk.OnConflict
+// documentation for more info.
+func (u *KeyUpsertBulk) Update(set func(*KeyUpsert)) *KeyUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&KeyUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (u *KeyUpsertBulk) SetTenantID(v string) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetTenantID(v)
+	})
+}
+
+// UpdateTenantID sets the "tenant_id" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdateTenantID() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateTenantID()
+	})
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (u *KeyUpsertBulk) SetAlgorithm(v string) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetAlgorithm(v)
+	})
+}
+
+// UpdateAlgorithm sets the "algorithm" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdateAlgorithm() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateAlgorithm()
+	})
+}
+
+// SetPublicKey sets the "public_key" field.
+func (u *KeyUpsertBulk) SetPublicKey(v string) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetPublicKey(v)
+	})
+}
+
+// UpdatePublicKey sets the "public_key" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdatePublicKey() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdatePublicKey()
+	})
+}
+
+// SetCertificate sets the "certificate" field.
+func (u *KeyUpsertBulk) SetCertificate(v string) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetCertificate(v)
+	})
+}
+
+// UpdateCertificate sets the "certificate" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdateCertificate() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateCertificate()
+	})
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (u *KeyUpsertBulk) ClearCertificate() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.ClearCertificate()
+	})
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (u *KeyUpsertBulk) SetCertificateChain(v []string) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetCertificateChain(v)
+	})
+}
+
+// UpdateCertificateChain sets the "certificate_chain" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdateCertificateChain() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateCertificateChain()
+	})
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (u *KeyUpsertBulk) ClearCertificateChain() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.ClearCertificateChain()
+	})
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *KeyUpsertBulk) SetInsertedAt(v time.Time) *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.SetInsertedAt(v)
+	})
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *KeyUpsertBulk) UpdateInsertedAt() *KeyUpsertBulk {
+	return u.Update(func(s *KeyUpsert) {
+		s.UpdateInsertedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *KeyUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("database: OnConflict was set for builder %d. Set it on the KeyCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for KeyCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *KeyUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,469 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/dialect/sql/sqljson"
+	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/key"
+	"github.com/jurshsmith/vaultstream/database/predicate"
+)
+
+// KeyUpdate is the builder for updating Key entities.
+type KeyUpdate struct {
+	config
+	hooks    []Hook
+	mutation *KeyMutation
+}
+
+// Where appends a list predicates to the KeyUpdate builder.
+func (ku *KeyUpdate) Where(ps ...predicate.Key) *KeyUpdate {
+	ku.mutation.Where(ps...)
+	return ku
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (ku *KeyUpdate) SetTenantID(s string) *KeyUpdate {
+	ku.mutation.SetTenantID(s)
+	return ku
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (ku *KeyUpdate) SetNillableTenantID(s *string) *KeyUpdate {
+	if s != nil {
+		ku.SetTenantID(*s)
+	}
+	return ku
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (ku *KeyUpdate) SetAlgorithm(s string) *KeyUpdate {
+	ku.mutation.SetAlgorithm(s)
+	return ku
+}
+
+// SetNillableAlgorithm sets the "algorithm" field if the given value is not nil.
+func (ku *KeyUpdate) SetNillableAlgorithm(s *string) *KeyUpdate {
+	if s != nil {
+		ku.SetAlgorithm(*s)
+	}
+	return ku
+}
+
+// SetPublicKey sets the "public_key" field.
+func (ku *KeyUpdate) SetPublicKey(s string) *KeyUpdate {
+	ku.mutation.SetPublicKey(s)
+	return ku
+}
+
+// SetNillablePublicKey sets the "public_key" field if the given value is not nil.
+func (ku *KeyUpdate) SetNillablePublicKey(s *string) *KeyUpdate {
+	if s != nil {
+		ku.SetPublicKey(*s)
+	}
+	return ku
+}
+
+// SetCertificate sets the "certificate" field.
+func (ku *KeyUpdate) SetCertificate(s string) *KeyUpdate {
+	ku.mutation.SetCertificate(s)
+	return ku
+}
+
+// SetNillableCertificate sets the "certificate" field if the given value is not nil.
+func (ku *KeyUpdate) SetNillableCertificate(s *string) *KeyUpdate {
+	if s != nil {
+		ku.SetCertificate(*s)
+	}
+	return ku
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (ku *KeyUpdate) ClearCertificate() *KeyUpdate {
+	ku.mutation.ClearCertificate()
+	return ku
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (ku *KeyUpdate) SetCertificateChain(s []string) *KeyUpdate {
+	ku.mutation.SetCertificateChain(s)
+	return ku
+}
+
+// AppendCertificateChain appends s to the "certificate_chain" field.
+func (ku *KeyUpdate) AppendCertificateChain(s []string) *KeyUpdate {
+	ku.mutation.AppendCertificateChain(s)
+	return ku
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (ku *KeyUpdate) ClearCertificateChain() *KeyUpdate {
+	ku.mutation.ClearCertificateChain()
+	return ku
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (ku *KeyUpdate) SetInsertedAt(t time.Time) *KeyUpdate {
+	ku.mutation.SetInsertedAt(t)
+	return ku
+}
+
+// SetNillableInsertedAt sets the "inserted_at" field if the given value is not nil.
+func (ku *KeyUpdate) SetNillableInsertedAt(t *time.Time) *KeyUpdate {
+	if t != nil {
+		ku.SetInsertedAt(*t)
+	}
+	return ku
+}
+
+// Mutation returns the KeyMutation object of the builder.
+func (ku *KeyUpdate) Mutation() *KeyMutation {
+	return ku.mutation
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (ku *KeyUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, ku.sqlSave, ku.mutation, ku.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (ku *KeyUpdate) SaveX(ctx context.Context) int {
+	affected, err := ku.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (ku *KeyUpdate) Exec(ctx context.Context) error {
+	_, err := ku.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ku *KeyUpdate) ExecX(ctx context.Context) {
+	if err := ku.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (ku *KeyUpdate) check() error {
+	if v, ok := ku.mutation.TenantID(); ok {
+		if err := key.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`database: validator failed for field "Key.tenant_id": %w`, err)}
+		}
+	}
+	if v, ok := ku.mutation.Algorithm(); ok {
+		if err := key.AlgorithmValidator(v); err != nil {
+			return &ValidationError{Name: "algorithm", err: fmt.Errorf(`database: validator failed for field "Key.algorithm": %w`, err)}
+		}
+	}
+	if v, ok := ku.mutation.PublicKey(); ok {
+		if err := key.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`database: validator failed for field "Key.public_key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (ku *KeyUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	if err := ku.check(); err != nil {
+		return n, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(key.Table, key.Columns, sqlgraph.NewFieldSpec(key.FieldID, field.TypeInt))
+	if ps := ku.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := ku.mutation.TenantID(); ok {
+		_spec.SetField(key.FieldTenantID, field.TypeString, value)
+	}
+	if value, ok := ku.mutation.Algorithm(); ok {
+		_spec.SetField(key.FieldAlgorithm, field.TypeString, value)
+	}
+	if value, ok := ku.mutation.PublicKey(); ok {
+		_spec.SetField(key.FieldPublicKey, field.TypeString, value)
+	}
+	if value, ok := ku.mutation.Certificate(); ok {
+		_spec.SetField(key.FieldCertificate, field.TypeString, value)
+	}
+	if ku.mutation.CertificateCleared() {
+		_spec.ClearField(key.FieldCertificate, field.TypeString)
+	}
+	if value, ok := ku.mutation.CertificateChain(); ok {
+		_spec.SetField(key.FieldCertificateChain, field.TypeJSON, value)
+	}
+	if value, ok := ku.mutation.AppendedCertificateChain(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, key.FieldCertificateChain, value)
+		})
+	}
+	if ku.mutation.CertificateChainCleared() {
+		_spec.ClearField(key.FieldCertificateChain, field.TypeJSON)
+	}
+	if value, ok := ku.mutation.InsertedAt(); ok {
+		_spec.SetField(key.FieldInsertedAt, field.TypeTime, value)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, ku.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{key.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	ku.mutation.done = true
+	return n, nil
+}
+
+// KeyUpdateOne is the builder for updating a single Key entity.
+type KeyUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *KeyMutation
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (kuo *KeyUpdateOne) SetTenantID(s string) *KeyUpdateOne {
+	kuo.mutation.SetTenantID(s)
+	return kuo
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (kuo *KeyUpdateOne) SetNillableTenantID(s *string) *KeyUpdateOne {
+	if s != nil {
+		kuo.SetTenantID(*s)
+	}
+	return kuo
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (kuo *KeyUpdateOne) SetAlgorithm(s string) *KeyUpdateOne {
+	kuo.mutation.SetAlgorithm(s)
+	return kuo
+}
+
+// SetNillableAlgorithm sets the "algorithm" field if the given value is not nil.
+func (kuo *KeyUpdateOne) SetNillableAlgorithm(s *string) *KeyUpdateOne {
+	if s != nil {
+		kuo.SetAlgorithm(*s)
+	}
+	return kuo
+}
+
+// SetPublicKey sets the "public_key" field.
+func (kuo *KeyUpdateOne) SetPublicKey(s string) *KeyUpdateOne {
+	kuo.mutation.SetPublicKey(s)
+	return kuo
+}
+
+// SetNillablePublicKey sets the "public_key" field if the given value is not nil.
+func (kuo *KeyUpdateOne) SetNillablePublicKey(s *string) *KeyUpdateOne {
+	if s != nil {
+		kuo.SetPublicKey(*s)
+	}
+	return kuo
+}
+
+// SetCertificate sets the "certificate" field.
+func (kuo *KeyUpdateOne) SetCertificate(s string) *KeyUpdateOne {
+	kuo.mutation.SetCertificate(s)
+	return kuo
+}
+
+// SetNillableCertificate sets the "certificate" field if the given value is not nil.
+func (kuo *KeyUpdateOne) SetNillableCertificate(s *string) *KeyUpdateOne {
+	if s != nil {
+		kuo.SetCertificate(*s)
+	}
+	return kuo
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (kuo *KeyUpdateOne) ClearCertificate() *KeyUpdateOne {
+	kuo.mutation.ClearCertificate()
+	return kuo
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (kuo *KeyUpdateOne) SetCertificateChain(s []string) *KeyUpdateOne {
+	kuo.mutation.SetCertificateChain(s)
+	return kuo
+}
+
+// AppendCertificateChain appends s to the "certificate_chain" field.
+func (kuo *KeyUpdateOne) AppendCertificateChain(s []string) *KeyUpdateOne {
+	kuo.mutation.AppendCertificateChain(s)
+	return kuo
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (kuo *KeyUpdateOne) ClearCertificateChain() *KeyUpdateOne {
+	kuo.mutation.ClearCertificateChain()
+	return kuo
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (kuo *KeyUpdateOne) SetInsertedAt(t time.Time) *KeyUpdateOne {
+	kuo.mutation.SetInsertedAt(t)
+	return kuo
+}
+
+// SetNillableInsertedAt sets the "inserted_at" field if the given value is not nil.
+func (kuo *KeyUpdateOne) SetNillableInsertedAt(t *time.Time) *KeyUpdateOne {
+	if t != nil {
+		kuo.SetInsertedAt(*t)
+	}
+	return kuo
+}
+
+// Mutation returns the KeyMutation object of the builder.
+func (kuo *KeyUpdateOne) Mutation() *KeyMutation {
+	return kuo.mutation
+}
+
+// Where appends a list predicates to the KeyUpdate builder.
+func (kuo *KeyUpdateOne) Where(ps ...predicate.Key) *KeyUpdateOne {
+	kuo.mutation.Where(ps...)
+	return kuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (kuo *KeyUpdateOne) Select(field string, fields ...string) *KeyUpdateOne {
+	kuo.fields = append([]string{field}, fields...)
+	return kuo
+}
+
+// Save executes the query and returns the updated Key entity.
+func (kuo *KeyUpdateOne) Save(ctx context.Context) (*Key, error) {
+	return withHooks(ctx, kuo.sqlSave, kuo.mutation, kuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (kuo *KeyUpdateOne) SaveX(ctx context.Context) *Key {
+	node, err := kuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (kuo *KeyUpdateOne) Exec(ctx context.Context) error {
+	_, err := kuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (kuo *KeyUpdateOne) ExecX(ctx context.Context) {
+	if err := kuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (kuo *KeyUpdateOne) check() error {
+	if v, ok := kuo.mutation.TenantID(); ok {
+		if err := key.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`database: validator failed for field "Key.tenant_id": %w`, err)}
+		}
+	}
+	if v, ok := kuo.mutation.Algorithm(); ok {
+		if err := key.AlgorithmValidator(v); err != nil {
+			return &ValidationError{Name: "algorithm", err: fmt.Errorf(`database: validator failed for field "Key.algorithm": %w`, err)}
+		}
+	}
+	if v, ok := kuo.mutation.PublicKey(); ok {
+		if err := key.PublicKeyValidator(v); err != nil {
+			return &ValidationError{Name: "public_key", err: fmt.Errorf(`database: validator failed for field "Key.public_key": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (kuo *KeyUpdateOne) sqlSave(ctx context.Context) (_node *Key, err error) {
+	if err := kuo.check(); err != nil {
+		return _node, err
+	}
+	_spec := sqlgraph.NewUpdateSpec(key.Table, key.Columns, sqlgraph.NewFieldSpec(key.FieldID, field.TypeInt))
+	id, ok := kuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`database: missing "Key.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := kuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, key.FieldID)
+		for _, f := range fields {
+			if !key.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("database: invalid field %q for query", f)}
+			}
+			if f != key.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := kuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if value, ok := kuo.mutation.TenantID(); ok {
+		_spec.SetField(key.FieldTenantID, field.TypeString, value)
+	}
+	if value, ok := kuo.mutation.Algorithm(); ok {
+		_spec.SetField(key.FieldAlgorithm, field.TypeString, value)
+	}
+	if value, ok := kuo.mutation.PublicKey(); ok {
+		_spec.SetField(key.FieldPublicKey, field.TypeString, value)
+	}
+	if value, ok := kuo.mutation.Certificate(); ok {
+		_spec.SetField(key.FieldCertificate, field.TypeString, value)
+	}
+	if kuo.mutation.CertificateCleared() {
+		_spec.ClearField(key.FieldCertificate, field.TypeString)
+	}
+	if value, ok := kuo.mutation.CertificateChain(); ok {
+		_spec.SetField(key.FieldCertificateChain, field.TypeJSON, value)
+	}
+	if value, ok := kuo.mutation.AppendedCertificateChain(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, key.FieldCertificateChain, value)
+		})
+	}
+	if kuo.mutation.CertificateChainCleared() {
+		_spec.ClearField(key.FieldCertificateChain, field.TypeJSON)
+	}
+	if value, ok := kuo.mutation.InsertedAt(); ok {
+		_spec.SetField(key.FieldInsertedAt, field.TypeTime, value)
+	}
+	_node = &Key{config: kuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, kuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{key.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	kuo.mutation.done = true
+	return _node, nil
+}
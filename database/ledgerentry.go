@@ -0,0 +1,176 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+)
+
+// LedgerEntry is the model entity for the LedgerEntry schema.
+type LedgerEntry struct {
+	config `json:"-"`
+	// ID of the ent.
+	ID int `json:"seq"`
+	// PrevHash holds the value of the "prev_hash" field.
+	PrevHash string `json:"prev_hash"`
+	// BatchHash holds the value of the "batch_hash" field.
+	BatchHash string `json:"batch_hash"`
+	// Size holds the value of the "size" field.
+	Size int `json:"size"`
+	// Hash holds the value of the "hash" field.
+	Hash string `json:"hash"`
+	// InsertedAt holds the value of the "inserted_at" field.
+	InsertedAt time.Time `json:"inserted_at"`
+	// Edges holds the relations/edges for other nodes in the graph.
+	// The values are being populated by the LedgerEntryQuery when eager-loading is set.
+	Edges        LedgerEntryEdges `json:"edges"`
+	selectValues sql.SelectValues
+}
+
+// LedgerEntryEdges holds the relations/edges for other nodes in the graph.
+type LedgerEntryEdges struct {
+	// Signatures holds the value of the signatures edge.
+	Signatures []*Signature `json:"signatures,omitempty"`
+	// loadedTypes holds the information for reporting if a
+	// type was loaded (or requested) in eager-loading or not.
+	loadedTypes [1]bool
+}
+
+// SignaturesOrErr returns the Signatures value or an error if the edge
+// was not loaded in eager-loading.
+func (e LedgerEntryEdges) SignaturesOrErr() ([]*Signature, error) {
+	if e.loadedTypes[0] {
+		return e.Signatures, nil
+	}
+	return nil, &NotLoadedError{edge: "signatures"}
+}
+
+// scanValues returns the types for scanning values from sql.Rows.
+func (*LedgerEntry) scanValues(columns []string) ([]any, error) {
+	values := make([]any, len(columns))
+	for i := range columns {
+		switch columns[i] {
+		case ledgerentry.FieldID, ledgerentry.FieldSize:
+			values[i] = new(sql.NullInt64)
+		case ledgerentry.FieldPrevHash, ledgerentry.FieldBatchHash, ledgerentry.FieldHash:
+			values[i] = new(sql.NullString)
+		case ledgerentry.FieldInsertedAt:
+			values[i] = new(sql.NullTime)
+		default:
+			values[i] = new(sql.UnknownType)
+		}
+	}
+	return values, nil
+}
+
+// assignValues assigns the values that were returned from sql.Rows (after scanning)
+// to the LedgerEntry fields.
+func (le *LedgerEntry) assignValues(columns []string, values []any) error {
+	if m, n := len(values), len(columns); m < n {
+		return fmt.Errorf("mismatch number of scan values: %d != %d", m, n)
+	}
+	for i := range columns {
+		switch columns[i] {
+		case ledgerentry.FieldID:
+			value, ok := values[i].(*sql.NullInt64)
+			if !ok {
+				return fmt.Errorf("unexpected type %T for field id", value)
+			}
+			le.ID = int(value.Int64)
+		case ledgerentry.FieldPrevHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field prev_hash", values[i])
+			} else if value.Valid {
+				le.PrevHash = value.String
+			}
+		case ledgerentry.FieldBatchHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field batch_hash", values[i])
+			} else if value.Valid {
+				le.BatchHash = value.String
+			}
+		case ledgerentry.FieldSize:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field size", values[i])
+			} else if value.Valid {
+				le.Size = int(value.Int64)
+			}
+		case ledgerentry.FieldHash:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field hash", values[i])
+			} else if value.Valid {
+				le.Hash = value.String
+			}
+		case ledgerentry.FieldInsertedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field inserted_at", values[i])
+			} else if value.Valid {
+				le.InsertedAt = value.Time
+			}
+		default:
+			le.selectValues.Set(columns[i], values[i])
+		}
+	}
+	return nil
+}
+
+// Value returns the ent.Value that was dynamically selected and assigned to the LedgerEntry.
+// This includes values selected through modifiers, order, etc.
+func (le *LedgerEntry) Value(name string) (ent.Value, error) {
+	return le.selectValues.Get(name)
+}
+
+// QuerySignatures queries the "signatures" edge of the LedgerEntry entity.
+func (le *LedgerEntry) QuerySignatures() *SignatureQuery {
+	return NewLedgerEntryClient(le.config).QuerySignatures(le)
+}
+
+// Update returns a builder for updating this LedgerEntry.
+// Note that you need to call LedgerEntry.Unwrap() before calling this method if this LedgerEntry
+// was returned from a transaction, and the transaction was committed or rolled back.
+func (le *LedgerEntry) Update() *LedgerEntryUpdateOne {
+	return NewLedgerEntryClient(le.config).UpdateOne(le)
+}
+
+// Unwrap unwraps the LedgerEntry entity that was returned from a transaction after it was closed,
+// so that all future queries will be executed through the driver which created the transaction.
+func (le *LedgerEntry) Unwrap() *LedgerEntry {
+	_tx, ok := le.config.driver.(*txDriver)
+	if !ok {
+		panic("database: LedgerEntry is not a transactional entity")
+	}
+	le.config.driver = _tx.drv
+	return le
+}
+
+// String implements the fmt.Stringer.
+func (le *LedgerEntry) String() string {
+	var builder strings.Builder
+	builder.WriteString("LedgerEntry(")
+	builder.WriteString(fmt.Sprintf("id=%v, ", le.ID))
+	builder.WriteString("prev_hash=")
+	builder.WriteString(le.PrevHash)
+	builder.WriteString(", ")
+	builder.WriteString("batch_hash=")
+	builder.WriteString(le.BatchHash)
+	builder.WriteString(", ")
+	builder.WriteString("size=")
+	builder.WriteString(fmt.Sprintf("%v", le.Size))
+	builder.WriteString(", ")
+	builder.WriteString("hash=")
+	builder.WriteString(le.Hash)
+	builder.WriteString(", ")
+	builder.WriteString("inserted_at=")
+	builder.WriteString(le.InsertedAt.Format(time.ANSIC))
+	builder.WriteByte(')')
+	return builder.String()
+}
+
+// LedgerEntries is a parsable slice of LedgerEntry.
+type LedgerEntries []*LedgerEntry
@@ -0,0 +1,134 @@
+// Code generated by ent, DO NOT EDIT.
+
+package ledgerentry
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+)
+
+const (
+	// Label holds the string label denoting the ledgerentry type in the database.
+	Label = "ledger_entry"
+	// FieldID holds the string denoting the id field in the database.
+	FieldID = "id"
+	// FieldPrevHash holds the string denoting the prev_hash field in the database.
+	FieldPrevHash = "prev_hash"
+	// FieldBatchHash holds the string denoting the batch_hash field in the database.
+	FieldBatchHash = "batch_hash"
+	// FieldSize holds the string denoting the size field in the database.
+	FieldSize = "size"
+	// FieldHash holds the string denoting the hash field in the database.
+	FieldHash = "hash"
+	// FieldInsertedAt holds the string denoting the inserted_at field in the database.
+	FieldInsertedAt = "inserted_at"
+	// EdgeSignatures holds the string denoting the signatures edge name in mutations.
+	EdgeSignatures = "signatures"
+	// Table holds the table name of the ledgerentry in the database.
+	Table = "ledger"
+	// SignaturesTable is the table that holds the signatures relation/edge.
+	SignaturesTable = "signatures"
+	// SignaturesInverseTable is the table name for the Signature entity.
+	// It exists in this package in order to avoid circular dependency with the "signature" package.
+	SignaturesInverseTable = "signatures"
+	// SignaturesColumn is the table column denoting the signatures relation/edge.
+	SignaturesColumn = "ledger_seq"
+)
+
+// Columns holds all SQL columns for ledgerentry fields.
+var Columns = []string{
+	FieldID,
+	FieldPrevHash,
+	FieldBatchHash,
+	FieldSize,
+	FieldHash,
+	FieldInsertedAt,
+}
+
+// ValidColumn reports if the column name is valid (part of the table columns).
+func ValidColumn(column string) bool {
+	for i := range Columns {
+		if column == Columns[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Note that the variables below are initialized by the runtime
+// package on the initialization of the application. Therefore,
+// it should be imported in the main as follows:
+//
+//	import _ "github.com/jurshsmith/vaultstream/database/runtime"
+var (
+	Hooks [1]ent.Hook
+	// PrevHashValidator is a validator for the "prev_hash" field. It is called by the builders before save.
+	PrevHashValidator func(string) error
+	// BatchHashValidator is a validator for the "batch_hash" field. It is called by the builders before save.
+	BatchHashValidator func(string) error
+	// SizeValidator is a validator for the "size" field. It is called by the builders before save.
+	SizeValidator func(int) error
+	// HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	HashValidator func(string) error
+	// DefaultInsertedAt holds the default value on creation for the "inserted_at" field.
+	DefaultInsertedAt func() time.Time
+	// IDValidator is a validator for the "id" field. It is called by the builders before save.
+	IDValidator func(int) error
+)
+
+// OrderOption defines the ordering options for the LedgerEntry queries.
+type OrderOption func(*sql.Selector)
+
+// ByID orders the results by the id field.
+func ByID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldID, opts...).ToFunc()
+}
+
+// ByPrevHash orders the results by the prev_hash field.
+func ByPrevHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldPrevHash, opts...).ToFunc()
+}
+
+// ByBatchHash orders the results by the batch_hash field.
+func ByBatchHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldBatchHash, opts...).ToFunc()
+}
+
+// BySize orders the results by the size field.
+func BySize(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldSize, opts...).ToFunc()
+}
+
+// ByHash orders the results by the hash field.
+func ByHash(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldHash, opts...).ToFunc()
+}
+
+// ByInsertedAt orders the results by the inserted_at field.
+func ByInsertedAt(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldInsertedAt, opts...).ToFunc()
+}
+
+// BySignaturesCount orders the results by signatures count.
+func BySignaturesCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newSignaturesStep(), opts...)
+	}
+}
+
+// BySignatures orders the results by signatures terms.
+func BySignatures(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newSignaturesStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+func newSignaturesStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(SignaturesInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, SignaturesTable, SignaturesColumn),
+	)
+}
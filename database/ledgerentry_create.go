@@ -0,0 +1,608 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/signature"
+)
+
+// LedgerEntryCreate is the builder for creating a LedgerEntry entity.
+type LedgerEntryCreate struct {
+	config
+	mutation *LedgerEntryMutation
+	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetPrevHash sets the "prev_hash" field.
+func (lec *LedgerEntryCreate) SetPrevHash(s string) *LedgerEntryCreate {
+	lec.mutation.SetPrevHash(s)
+	return lec
+}
+
+// SetBatchHash sets the "batch_hash" field.
+func (lec *LedgerEntryCreate) SetBatchHash(s string) *LedgerEntryCreate {
+	lec.mutation.SetBatchHash(s)
+	return lec
+}
+
+// SetSize sets the "size" field.
+func (lec *LedgerEntryCreate) SetSize(i int) *LedgerEntryCreate {
+	lec.mutation.SetSize(i)
+	return lec
+}
+
+// SetHash sets the "hash" field.
+func (lec *LedgerEntryCreate) SetHash(s string) *LedgerEntryCreate {
+	lec.mutation.SetHash(s)
+	return lec
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (lec *LedgerEntryCreate) SetInsertedAt(t time.Time) *LedgerEntryCreate {
+	lec.mutation.SetInsertedAt(t)
+	return lec
+}
+
+// SetNillableInsertedAt sets the "inserted_at" field if the given value is not nil.
+func (lec *LedgerEntryCreate) SetNillableInsertedAt(t *time.Time) *LedgerEntryCreate {
+	if t != nil {
+		lec.SetInsertedAt(*t)
+	}
+	return lec
+}
+
+// SetID sets the "id" field.
+func (lec *LedgerEntryCreate) SetID(i int) *LedgerEntryCreate {
+	lec.mutation.SetID(i)
+	return lec
+}
+
+// AddSignatureIDs adds the "signatures" edge to the Signature entity by IDs.
+func (lec *LedgerEntryCreate) AddSignatureIDs(ids ...int) *LedgerEntryCreate {
+	lec.mutation.AddSignatureIDs(ids...)
+	return lec
+}
+
+// AddSignatures adds the "signatures" edges to the Signature entity.
+func (lec *LedgerEntryCreate) AddSignatures(s ...*Signature) *LedgerEntryCreate {
+	ids := make([]int, len(s))
+	for i := range s {
+		ids[i] = s[i].ID
+	}
+	return lec.AddSignatureIDs(ids...)
+}
+
+// Mutation returns the LedgerEntryMutation object of the builder.
+func (lec *LedgerEntryCreate) Mutation() *LedgerEntryMutation {
+	return lec.mutation
+}
+
+// Save creates the LedgerEntry in the database.
+func (lec *LedgerEntryCreate) Save(ctx context.Context) (*LedgerEntry, error) {
+	if err := lec.defaults(); err != nil {
+		return nil, err
+	}
+	return withHooks(ctx, lec.sqlSave, lec.mutation, lec.hooks)
+}
+
+// SaveX calls Save and panics if Save returns an error.
+func (lec *LedgerEntryCreate) SaveX(ctx context.Context) *LedgerEntry {
+	v, err := lec.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lec *LedgerEntryCreate) Exec(ctx context.Context) error {
+	_, err := lec.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lec *LedgerEntryCreate) ExecX(ctx context.Context) {
+	if err := lec.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// defaults sets the default values of the builder before save.
+func (lec *LedgerEntryCreate) defaults() error {
+	if _, ok := lec.mutation.InsertedAt(); !ok {
+		if ledgerentry.DefaultInsertedAt == nil {
+			return fmt.Errorf("database: uninitialized ledgerentry.DefaultInsertedAt (forgotten import database/runtime?)")
+		}
+		v := ledgerentry.DefaultInsertedAt()
+		lec.mutation.SetInsertedAt(v)
+	}
+	return nil
+}
+
+// check runs all checks and user-defined validators on the builder.
+func (lec *LedgerEntryCreate) check() error {
+	if _, ok := lec.mutation.PrevHash(); !ok {
+		return &ValidationError{Name: "prev_hash", err: errors.New(`database: missing required field "LedgerEntry.prev_hash"`)}
+	}
+	if v, ok := lec.mutation.PrevHash(); ok {
+		if err := ledgerentry.PrevHashValidator(v); err != nil {
+			return &ValidationError{Name: "prev_hash", err: fmt.Errorf(`database: validator failed for field "LedgerEntry.prev_hash": %w`, err)}
+		}
+	}
+	if _, ok := lec.mutation.BatchHash(); !ok {
+		return &ValidationError{Name: "batch_hash", err: errors.New(`database: missing required field "LedgerEntry.batch_hash"`)}
+	}
+	if v, ok := lec.mutation.BatchHash(); ok {
+		if err := ledgerentry.BatchHashValidator(v); err != nil {
+			return &ValidationError{Name: "batch_hash", err: fmt.Errorf(`database: validator failed for field "LedgerEntry.batch_hash": %w`, err)}
+		}
+	}
+	if _, ok := lec.mutation.Size(); !ok {
+		return &ValidationError{Name: "size", err: errors.New(`database: missing required field "LedgerEntry.size"`)}
+	}
+	if v, ok := lec.mutation.Size(); ok {
+		if err := ledgerentry.SizeValidator(v); err != nil {
+			return &ValidationError{Name: "size", err: fmt.Errorf(`database: validator failed for field "LedgerEntry.size": %w`, err)}
+		}
+	}
+	if _, ok := lec.mutation.Hash(); !ok {
+		return &ValidationError{Name: "hash", err: errors.New(`database: missing required field "LedgerEntry.hash"`)}
+	}
+	if v, ok := lec.mutation.Hash(); ok {
+		if err := ledgerentry.HashValidator(v); err != nil {
+			return &ValidationError{Name: "hash", err: fmt.Errorf(`database: validator failed for field "LedgerEntry.hash": %w`, err)}
+		}
+	}
+	if _, ok := lec.mutation.InsertedAt(); !ok {
+		return &ValidationError{Name: "inserted_at", err: errors.New(`database: missing required field "LedgerEntry.inserted_at"`)}
+	}
+	if v, ok := lec.mutation.ID(); ok {
+		if err := ledgerentry.IDValidator(v); err != nil {
+			return &ValidationError{Name: "id", err: fmt.Errorf(`database: validator failed for field "LedgerEntry.id": %w`, err)}
+		}
+	}
+	return nil
+}
+
+func (lec *LedgerEntryCreate) sqlSave(ctx context.Context) (*LedgerEntry, error) {
+	if err := lec.check(); err != nil {
+		return nil, err
+	}
+	_node, _spec := lec.createSpec()
+	if err := sqlgraph.CreateNode(ctx, lec.driver, _spec); err != nil {
+		if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	if _spec.ID.Value != _node.ID {
+		id := _spec.ID.Value.(int64)
+		_node.ID = int(id)
+	}
+	lec.mutation.id = &_node.ID
+	lec.mutation.done = true
+	return _node, nil
+}
+
+func (lec *LedgerEntryCreate) createSpec() (*LedgerEntry, *sqlgraph.CreateSpec) {
+	var (
+		_node = &LedgerEntry{config: lec.config}
+		_spec = sqlgraph.NewCreateSpec(ledgerentry.Table, sqlgraph.NewFieldSpec(ledgerentry.FieldID, field.TypeInt))
+	)
+	_spec.OnConflict = lec.conflict
+	if id, ok := lec.mutation.ID(); ok {
+		_node.ID = id
+		_spec.ID.Value = id
+	}
+	if value, ok := lec.mutation.PrevHash(); ok {
+		_spec.SetField(ledgerentry.FieldPrevHash, field.TypeString, value)
+		_node.PrevHash = value
+	}
+	if value, ok := lec.mutation.BatchHash(); ok {
+		_spec.SetField(ledgerentry.FieldBatchHash, field.TypeString, value)
+		_node.BatchHash = value
+	}
+	if value, ok := lec.mutation.Size(); ok {
+		_spec.SetField(ledgerentry.FieldSize, field.TypeInt, value)
+		_node.Size = value
+	}
+	if value, ok := lec.mutation.Hash(); ok {
+		_spec.SetField(ledgerentry.FieldHash, field.TypeString, value)
+		_node.Hash = value
+	}
+	if value, ok := lec.mutation.InsertedAt(); ok {
+		_spec.SetField(ledgerentry.FieldInsertedAt, field.TypeTime, value)
+		_node.InsertedAt = value
+	}
+	if nodes := lec.mutation.SignaturesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges = append(_spec.Edges, edge)
+	}
+	return _node, _spec
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.LedgerEntry.Create().
+//		SetPrevHash(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.LedgerEntryUpsert) {
+//			SetPrevHash(v+v).
+//		}).
+//		Exec(ctx)
+func (lec *LedgerEntryCreate) OnConflict(opts ...sql.ConflictOption) *LedgerEntryUpsertOne {
+	lec.conflict = opts
+	return &LedgerEntryUpsertOne{
+		create: lec,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (lec *LedgerEntryCreate) OnConflictColumns(columns ...string) *LedgerEntryUpsertOne {
+	lec.conflict = append(lec.conflict, sql.ConflictColumns(columns...))
+	return &LedgerEntryUpsertOne{
+		create: lec,
+	}
+}
+
+type (
+	// LedgerEntryUpsertOne is the builder for "upsert"-ing
+	//  one LedgerEntry node.
+	LedgerEntryUpsertOne struct {
+		create *LedgerEntryCreate
+	}
+
+	// LedgerEntryUpsert is the "OnConflict" setter.
+	LedgerEntryUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create except the ID field.
+// Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(ledgerentry.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *LedgerEntryUpsertOne) UpdateNewValues() *LedgerEntryUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.ID(); exists {
+			s.SetIgnore(ledgerentry.FieldID)
+		}
+		if _, exists := u.create.mutation.PrevHash(); exists {
+			s.SetIgnore(ledgerentry.FieldPrevHash)
+		}
+		if _, exists := u.create.mutation.BatchHash(); exists {
+			s.SetIgnore(ledgerentry.FieldBatchHash)
+		}
+		if _, exists := u.create.mutation.Size(); exists {
+			s.SetIgnore(ledgerentry.FieldSize)
+		}
+		if _, exists := u.create.mutation.Hash(); exists {
+			s.SetIgnore(ledgerentry.FieldHash)
+		}
+		if _, exists := u.create.mutation.InsertedAt(); exists {
+			s.SetIgnore(ledgerentry.FieldInsertedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *LedgerEntryUpsertOne) Ignore() *LedgerEntryUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *LedgerEntryUpsertOne) DoNothing() *LedgerEntryUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the LedgerEntryCreate.OnConflict
+// documentation for more info.
+func (u *LedgerEntryUpsertOne) Update(set func(*LedgerEntryUpsert)) *LedgerEntryUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&LedgerEntryUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// Exec executes the query.
+func (u *LedgerEntryUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for LedgerEntryCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *LedgerEntryUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *LedgerEntryUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *LedgerEntryUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// LedgerEntryCreateBulk is the builder for creating many LedgerEntry entities in bulk.
+type LedgerEntryCreateBulk struct {
+	config
+	err      error
+	builders []*LedgerEntryCreate
+	conflict []sql.ConflictOption
+}
+
+// Save creates the LedgerEntry entities in the database.
+func (lecb *LedgerEntryCreateBulk) Save(ctx context.Context) ([]*LedgerEntry, error) {
+	if lecb.err != nil {
+		return nil, lecb.err
+	}
+	specs := make([]*sqlgraph.CreateSpec, len(lecb.builders))
+	nodes := make([]*LedgerEntry, len(lecb.builders))
+	mutators := make([]Mutator, len(lecb.builders))
+	for i := range lecb.builders {
+		func(i int, root context.Context) {
+			builder := lecb.builders[i]
+			builder.defaults()
+			var mut Mutator = MutateFunc(func(ctx context.Context, m Mutation) (Value, error) {
+				mutation, ok := m.(*LedgerEntryMutation)
+				if !ok {
+					return nil, fmt.Errorf("unexpected mutation type %T", m)
+				}
+				if err := builder.check(); err != nil {
+					return nil, err
+				}
+				builder.mutation = mutation
+				var err error
+				nodes[i], specs[i] = builder.createSpec()
+				if i < len(mutators)-1 {
+					_, err = mutators[i+1].Mutate(root, lecb.builders[i+1].mutation)
+				} else {
+					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = lecb.conflict
+					// Invoke the actual operation on the latest mutation in the chain.
+					if err = sqlgraph.BatchCreate(ctx, lecb.driver, spec); err != nil {
+						if sqlgraph.IsConstraintError(err) {
+							err = &ConstraintError{msg: err.Error(), wrap: err}
+						}
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+				mutation.id = &nodes[i].ID
+				if specs[i].ID.Value != nil && nodes[i].ID == 0 {
+					id := specs[i].ID.Value.(int64)
+					nodes[i].ID = int(id)
+				}
+				mutation.done = true
+				return nodes[i], nil
+			})
+			for i := len(builder.hooks) - 1; i >= 0; i-- {
+				mut = builder.hooks[i](mut)
+			}
+			mutators[i] = mut
+		}(i, ctx)
+	}
+	if len(mutators) > 0 {
+		if _, err := mutators[0].Mutate(ctx, lecb.builders[0].mutation); err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (lecb *LedgerEntryCreateBulk) SaveX(ctx context.Context) []*LedgerEntry {
+	v, err := lecb.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Exec executes the query.
+func (lecb *LedgerEntryCreateBulk) Exec(ctx context.Context) error {
+	_, err := lecb.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (lecb *LedgerEntryCreateBulk) ExecX(ctx context.Context) {
+	if err := lecb.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.LedgerEntry.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.LedgerEntryUpsert) {
+//			SetPrevHash(v+v).
+//		}).
+//		Exec(ctx)
+func (lecb *LedgerEntryCreateBulk) OnConflict(opts ...sql.ConflictOption) *LedgerEntryUpsertBulk {
+	lecb.conflict = opts
+	return &LedgerEntryUpsertBulk{
+		create: lecb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (lecb *LedgerEntryCreateBulk) OnConflictColumns(columns ...string) *LedgerEntryUpsertBulk {
+	lecb.conflict = append(lecb.conflict, sql.ConflictColumns(columns...))
+	return &LedgerEntryUpsertBulk{
+		create: lecb,
+	}
+}
+
+// LedgerEntryUpsertBulk is the builder for "upsert"-ing
+// a bulk of LedgerEntry nodes.
+type LedgerEntryUpsertBulk struct {
+	create *LedgerEntryCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(ledgerentry.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *LedgerEntryUpsertBulk) UpdateNewValues() *LedgerEntryUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.ID(); exists {
+				s.SetIgnore(ledgerentry.FieldID)
+			}
+			if _, exists := b.mutation.PrevHash(); exists {
+				s.SetIgnore(ledgerentry.FieldPrevHash)
+			}
+			if _, exists := b.mutation.BatchHash(); exists {
+				s.SetIgnore(ledgerentry.FieldBatchHash)
+			}
+			if _, exists := b.mutation.Size(); exists {
+				s.SetIgnore(ledgerentry.FieldSize)
+			}
+			if _, exists := b.mutation.Hash(); exists {
+				s.SetIgnore(ledgerentry.FieldHash)
+			}
+			if _, exists := b.mutation.InsertedAt(); exists {
+				s.SetIgnore(ledgerentry.FieldInsertedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.LedgerEntry.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *LedgerEntryUpsertBulk) Ignore() *LedgerEntryUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *LedgerEntryUpsertBulk) DoNothing() *LedgerEntryUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the LedgerEntryCreateBulk.OnConflict
+// documentation for more info.
+func (u *LedgerEntryUpsertBulk) Update(set func(*LedgerEntryUpsert)) *LedgerEntryUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&LedgerEntryUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// Exec executes the query.
+func (u *LedgerEntryUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("database: OnConflict was set for builder %d. Set it on the LedgerEntryCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for LedgerEntryCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *LedgerEntryUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,88 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"context"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/predicate"
+)
+
+// LedgerEntryDelete is the builder for deleting a LedgerEntry entity.
+type LedgerEntryDelete struct {
+	config
+	hooks    []Hook
+	mutation *LedgerEntryMutation
+}
+
+// Where appends a list predicates to the LedgerEntryDelete builder.
+func (led *LedgerEntryDelete) Where(ps ...predicate.LedgerEntry) *LedgerEntryDelete {
+	led.mutation.Where(ps...)
+	return led
+}
+
+// Exec executes the deletion query and returns how many vertices were deleted.
+func (led *LedgerEntryDelete) Exec(ctx context.Context) (int, error) {
+	return withHooks(ctx, led.sqlExec, led.mutation, led.hooks)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (led *LedgerEntryDelete) ExecX(ctx context.Context) int {
+	n, err := led.Exec(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (led *LedgerEntryDelete) sqlExec(ctx context.Context) (int, error) {
+	_spec := sqlgraph.NewDeleteSpec(ledgerentry.Table, sqlgraph.NewFieldSpec(ledgerentry.FieldID, field.TypeInt))
+	if ps := led.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	affected, err := sqlgraph.DeleteNodes(ctx, led.driver, _spec)
+	if err != nil && sqlgraph.IsConstraintError(err) {
+		err = &ConstraintError{msg: err.Error(), wrap: err}
+	}
+	led.mutation.done = true
+	return affected, err
+}
+
+// LedgerEntryDeleteOne is the builder for deleting a single LedgerEntry entity.
+type LedgerEntryDeleteOne struct {
+	led *LedgerEntryDelete
+}
+
+// Where appends a list predicates to the LedgerEntryDelete builder.
+func (ledo *LedgerEntryDeleteOne) Where(ps ...predicate.LedgerEntry) *LedgerEntryDeleteOne {
+	ledo.led.mutation.Where(ps...)
+	return ledo
+}
+
+// Exec executes the deletion query.
+func (ledo *LedgerEntryDeleteOne) Exec(ctx context.Context) error {
+	n, err := ledo.led.Exec(ctx)
+	switch {
+	case err != nil:
+		return err
+	case n == 0:
+		return &NotFoundError{ledgerentry.Label}
+	default:
+		return nil
+	}
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (ledo *LedgerEntryDeleteOne) ExecX(ctx context.Context) {
+	if err := ledo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,338 @@
+// Code generated by ent, DO NOT EDIT.
+
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqlgraph"
+	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/predicate"
+	"github.com/jurshsmith/vaultstream/database/signature"
+)
+
+// LedgerEntryUpdate is the builder for updating LedgerEntry entities.
+type LedgerEntryUpdate struct {
+	config
+	hooks    []Hook
+	mutation *LedgerEntryMutation
+}
+
+// Where appends a list predicates to the LedgerEntryUpdate builder.
+func (leu *LedgerEntryUpdate) Where(ps ...predicate.LedgerEntry) *LedgerEntryUpdate {
+	leu.mutation.Where(ps...)
+	return leu
+}
+
+// AddSignatureIDs adds the "signatures" edge to the Signature entity by IDs.
+func (leu *LedgerEntryUpdate) AddSignatureIDs(ids ...int) *LedgerEntryUpdate {
+	leu.mutation.AddSignatureIDs(ids...)
+	return leu
+}
+
+// AddSignatures adds the "signatures" edges to the Signature entity.
+func (leu *LedgerEntryUpdate) AddSignatures(s ...*Signature) *LedgerEntryUpdate {
+	ids := make([]int, len(s))
+	for i := range s {
+		ids[i] = s[i].ID
+	}
+	return leu.AddSignatureIDs(ids...)
+}
+
+// Mutation returns the LedgerEntryMutation object of the builder.
+func (leu *LedgerEntryUpdate) Mutation() *LedgerEntryMutation {
+	return leu.mutation
+}
+
+// ClearSignatures clears all "signatures" edges to the Signature entity.
+func (leu *LedgerEntryUpdate) ClearSignatures() *LedgerEntryUpdate {
+	leu.mutation.ClearSignatures()
+	return leu
+}
+
+// RemoveSignatureIDs removes the "signatures" edge to Signature entities by IDs.
+func (leu *LedgerEntryUpdate) RemoveSignatureIDs(ids ...int) *LedgerEntryUpdate {
+	leu.mutation.RemoveSignatureIDs(ids...)
+	return leu
+}
+
+// RemoveSignatures removes "signatures" edges to Signature entities.
+func (leu *LedgerEntryUpdate) RemoveSignatures(s ...*Signature) *LedgerEntryUpdate {
+	ids := make([]int, len(s))
+	for i := range s {
+		ids[i] = s[i].ID
+	}
+	return leu.RemoveSignatureIDs(ids...)
+}
+
+// Save executes the query and returns the number of nodes affected by the update operation.
+func (leu *LedgerEntryUpdate) Save(ctx context.Context) (int, error) {
+	return withHooks(ctx, leu.sqlSave, leu.mutation, leu.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (leu *LedgerEntryUpdate) SaveX(ctx context.Context) int {
+	affected, err := leu.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return affected
+}
+
+// Exec executes the query.
+func (leu *LedgerEntryUpdate) Exec(ctx context.Context) error {
+	_, err := leu.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (leu *LedgerEntryUpdate) ExecX(ctx context.Context) {
+	if err := leu.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (leu *LedgerEntryUpdate) sqlSave(ctx context.Context) (n int, err error) {
+	_spec := sqlgraph.NewUpdateSpec(ledgerentry.Table, ledgerentry.Columns, sqlgraph.NewFieldSpec(ledgerentry.FieldID, field.TypeInt))
+	if ps := leu.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if leu.mutation.SignaturesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := leu.mutation.RemovedSignaturesIDs(); len(nodes) > 0 && !leu.mutation.SignaturesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := leu.mutation.SignaturesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	if n, err = sqlgraph.UpdateNodes(ctx, leu.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{ledgerentry.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return 0, err
+	}
+	leu.mutation.done = true
+	return n, nil
+}
+
+// LedgerEntryUpdateOne is the builder for updating a single LedgerEntry entity.
+type LedgerEntryUpdateOne struct {
+	config
+	fields   []string
+	hooks    []Hook
+	mutation *LedgerEntryMutation
+}
+
+// AddSignatureIDs adds the "signatures" edge to the Signature entity by IDs.
+func (leuo *LedgerEntryUpdateOne) AddSignatureIDs(ids ...int) *LedgerEntryUpdateOne {
+	leuo.mutation.AddSignatureIDs(ids...)
+	return leuo
+}
+
+// AddSignatures adds the "signatures" edges to the Signature entity.
+func (leuo *LedgerEntryUpdateOne) AddSignatures(s ...*Signature) *LedgerEntryUpdateOne {
+	ids := make([]int, len(s))
+	for i := range s {
+		ids[i] = s[i].ID
+	}
+	return leuo.AddSignatureIDs(ids...)
+}
+
+// Mutation returns the LedgerEntryMutation object of the builder.
+func (leuo *LedgerEntryUpdateOne) Mutation() *LedgerEntryMutation {
+	return leuo.mutation
+}
+
+// ClearSignatures clears all "signatures" edges to the Signature entity.
+func (leuo *LedgerEntryUpdateOne) ClearSignatures() *LedgerEntryUpdateOne {
+	leuo.mutation.ClearSignatures()
+	return leuo
+}
+
+// RemoveSignatureIDs removes the "signatures" edge to Signature entities by IDs.
+func (leuo *LedgerEntryUpdateOne) RemoveSignatureIDs(ids ...int) *LedgerEntryUpdateOne {
+	leuo.mutation.RemoveSignatureIDs(ids...)
+	return leuo
+}
+
+// RemoveSignatures removes "signatures" edges to Signature entities.
+func (leuo *LedgerEntryUpdateOne) RemoveSignatures(s ...*Signature) *LedgerEntryUpdateOne {
+	ids := make([]int, len(s))
+	for i := range s {
+		ids[i] = s[i].ID
+	}
+	return leuo.RemoveSignatureIDs(ids...)
+}
+
+// Where appends a list predicates to the LedgerEntryUpdate builder.
+func (leuo *LedgerEntryUpdateOne) Where(ps ...predicate.LedgerEntry) *LedgerEntryUpdateOne {
+	leuo.mutation.Where(ps...)
+	return leuo
+}
+
+// Select allows selecting one or more fields (columns) of the returned entity.
+// The default is selecting all fields defined in the entity schema.
+func (leuo *LedgerEntryUpdateOne) Select(field string, fields ...string) *LedgerEntryUpdateOne {
+	leuo.fields = append([]string{field}, fields...)
+	return leuo
+}
+
+// Save executes the query and returns the updated LedgerEntry entity.
+func (leuo *LedgerEntryUpdateOne) Save(ctx context.Context) (*LedgerEntry, error) {
+	return withHooks(ctx, leuo.sqlSave, leuo.mutation, leuo.hooks)
+}
+
+// SaveX is like Save, but panics if an error occurs.
+func (leuo *LedgerEntryUpdateOne) SaveX(ctx context.Context) *LedgerEntry {
+	node, err := leuo.Save(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return node
+}
+
+// Exec executes the query on the entity.
+func (leuo *LedgerEntryUpdateOne) Exec(ctx context.Context) error {
+	_, err := leuo.Save(ctx)
+	return err
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (leuo *LedgerEntryUpdateOne) ExecX(ctx context.Context) {
+	if err := leuo.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+func (leuo *LedgerEntryUpdateOne) sqlSave(ctx context.Context) (_node *LedgerEntry, err error) {
+	_spec := sqlgraph.NewUpdateSpec(ledgerentry.Table, ledgerentry.Columns, sqlgraph.NewFieldSpec(ledgerentry.FieldID, field.TypeInt))
+	id, ok := leuo.mutation.ID()
+	if !ok {
+		return nil, &ValidationError{Name: "id", err: errors.New(`database: missing "LedgerEntry.id" for update`)}
+	}
+	_spec.Node.ID.Value = id
+	if fields := leuo.fields; len(fields) > 0 {
+		_spec.Node.Columns = make([]string, 0, len(fields))
+		_spec.Node.Columns = append(_spec.Node.Columns, ledgerentry.FieldID)
+		for _, f := range fields {
+			if !ledgerentry.ValidColumn(f) {
+				return nil, &ValidationError{Name: f, err: fmt.Errorf("database: invalid field %q for query", f)}
+			}
+			if f != ledgerentry.FieldID {
+				_spec.Node.Columns = append(_spec.Node.Columns, f)
+			}
+		}
+	}
+	if ps := leuo.mutation.predicates; len(ps) > 0 {
+		_spec.Predicate = func(selector *sql.Selector) {
+			for i := range ps {
+				ps[i](selector)
+			}
+		}
+	}
+	if leuo.mutation.SignaturesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := leuo.mutation.RemovedSignaturesIDs(); len(nodes) > 0 && !leuo.mutation.SignaturesCleared() {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Clear = append(_spec.Edges.Clear, edge)
+	}
+	if nodes := leuo.mutation.SignaturesIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.O2M,
+			Inverse: false,
+			Table:   ledgerentry.SignaturesTable,
+			Columns: []string{ledgerentry.SignaturesColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_spec.Edges.Add = append(_spec.Edges.Add, edge)
+	}
+	_node = &LedgerEntry{config: leuo.config}
+	_spec.Assign = _node.assignValues
+	_spec.ScanValues = _node.scanValues
+	if err = sqlgraph.UpdateNode(ctx, leuo.driver, _spec); err != nil {
+		if _, ok := err.(*sqlgraph.NotFoundError); ok {
+			err = &NotFoundError{ledgerentry.Label}
+		} else if sqlgraph.IsConstraintError(err) {
+			err = &ConstraintError{msg: err.Error(), wrap: err}
+		}
+		return nil, err
+	}
+	leuo.mutation.done = true
+	return _node, nil
+}
@@ -3,28 +3,84 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// KeysColumns holds the columns for the "keys" table.
+	KeysColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, Size: 64, Default: "default"},
+		{Name: "algorithm", Type: field.TypeString},
+		{Name: "public_key", Type: field.TypeString},
+		{Name: "certificate", Type: field.TypeString, Nullable: true},
+		{Name: "certificate_chain", Type: field.TypeJSON, Nullable: true},
+		{Name: "inserted_at", Type: field.TypeTime},
+	}
+	// KeysTable holds the schema information for the "keys" table.
+	KeysTable = &schema.Table{
+		Name:       "keys",
+		Columns:    KeysColumns,
+		PrimaryKey: []*schema.Column{KeysColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "key_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{KeysColumns[1]},
+			},
+		},
+	}
+	// LedgerColumns holds the columns for the "ledger" table.
+	LedgerColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "prev_hash", Type: field.TypeString},
+		{Name: "batch_hash", Type: field.TypeString},
+		{Name: "size", Type: field.TypeInt},
+		{Name: "hash", Type: field.TypeString, Unique: true},
+		{Name: "inserted_at", Type: field.TypeTime},
+	}
+	// LedgerTable holds the schema information for the "ledger" table.
+	LedgerTable = &schema.Table{
+		Name:       "ledger",
+		Columns:    LedgerColumns,
+		PrimaryKey: []*schema.Column{LedgerColumns[0]},
+	}
 	// RecordsColumns holds the columns for the "records" table.
 	RecordsColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, Size: 64, Default: "default"},
 		{Name: "inserted_at", Type: field.TypeTime},
+		{Name: "data", Type: field.TypeBytes, Nullable: true},
 	}
 	// RecordsTable holds the schema information for the "records" table.
 	RecordsTable = &schema.Table{
 		Name:       "records",
 		Columns:    RecordsColumns,
 		PrimaryKey: []*schema.Column{RecordsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "record_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{RecordsColumns[1]},
+			},
+		},
 	}
 	// SignaturesColumns holds the columns for the "signatures" table.
 	SignaturesColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, Size: 64, Default: "default"},
 		{Name: "key_id", Type: field.TypeInt},
+		{Name: "algorithm", Type: field.TypeString, Default: "ES256"},
 		{Name: "value", Type: field.TypeString},
+		{Name: "timestamp_token", Type: field.TypeString, Nullable: true},
+		{Name: "merkle_root", Type: field.TypeString, Nullable: true},
+		{Name: "merkle_leaf_index", Type: field.TypeInt, Nullable: true},
+		{Name: "merkle_tree_size", Type: field.TypeInt, Nullable: true},
+		{Name: "merkle_path", Type: field.TypeJSON, Nullable: true},
 		{Name: "inserted_at", Type: field.TypeTime},
+		{Name: "ledger_seq", Type: field.TypeInt, Nullable: true},
 		{Name: "record_id", Type: field.TypeInt, Unique: true},
 	}
 	// SignaturesTable holds the schema information for the "signatures" table.
@@ -33,28 +89,112 @@ var (
 		Columns:    SignaturesColumns,
 		PrimaryKey: []*schema.Column{SignaturesColumns[0]},
 		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "signatures_ledger_signatures",
+				Columns:    []*schema.Column{SignaturesColumns[11]},
+				RefColumns: []*schema.Column{LedgerColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
 			{
 				Symbol:     "signatures_records_signature",
-				Columns:    []*schema.Column{SignaturesColumns[4]},
+				Columns:    []*schema.Column{SignaturesColumns[12]},
 				RefColumns: []*schema.Column{RecordsColumns[0]},
 				OnDelete:   schema.NoAction,
 			},
 		},
 		Indexes: []*schema.Index{
+			{
+				Name:    "signature_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{SignaturesColumns[1]},
+			},
 			{
 				Name:    "signature_value",
-				Unique:  true,
-				Columns: []*schema.Column{SignaturesColumns[2]},
+				Unique:  false,
+				Columns: []*schema.Column{SignaturesColumns[4]},
+			},
+			{
+				Name:    "signature_merkle_root",
+				Unique:  false,
+				Columns: []*schema.Column{SignaturesColumns[6]},
+			},
+			{
+				Name:    "signature_ledger_seq",
+				Unique:  false,
+				Columns: []*schema.Column{SignaturesColumns[11]},
+			},
+		},
+	}
+	// WebhooksColumns holds the columns for the "webhooks" table.
+	WebhooksColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "tenant_id", Type: field.TypeString, Size: 64, Default: "default"},
+		{Name: "url", Type: field.TypeString},
+		{Name: "secret", Type: field.TypeString},
+		{Name: "active", Type: field.TypeBool, Default: true},
+		{Name: "inserted_at", Type: field.TypeTime},
+	}
+	// WebhooksTable holds the schema information for the "webhooks" table.
+	WebhooksTable = &schema.Table{
+		Name:       "webhooks",
+		Columns:    WebhooksColumns,
+		PrimaryKey: []*schema.Column{WebhooksColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "webhook_tenant_id",
+				Unique:  false,
+				Columns: []*schema.Column{WebhooksColumns[1]},
+			},
+		},
+	}
+	// WebhookDeliveriesColumns holds the columns for the "webhook_deliveries" table.
+	WebhookDeliveriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeInt, Increment: true},
+		{Name: "ledger_seq", Type: field.TypeInt},
+		{Name: "attempt", Type: field.TypeInt},
+		{Name: "status_code", Type: field.TypeInt, Nullable: true},
+		{Name: "error", Type: field.TypeString, Nullable: true},
+		{Name: "succeeded", Type: field.TypeBool},
+		{Name: "inserted_at", Type: field.TypeTime},
+		{Name: "webhook_id", Type: field.TypeInt},
+	}
+	// WebhookDeliveriesTable holds the schema information for the "webhook_deliveries" table.
+	WebhookDeliveriesTable = &schema.Table{
+		Name:       "webhook_deliveries",
+		Columns:    WebhookDeliveriesColumns,
+		PrimaryKey: []*schema.Column{WebhookDeliveriesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "webhook_deliveries_webhooks_deliveries",
+				Columns:    []*schema.Column{WebhookDeliveriesColumns[7]},
+				RefColumns: []*schema.Column{WebhooksColumns[0]},
+				OnDelete:   schema.NoAction,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "webhookdelivery_webhook_id_ledger_seq",
+				Unique:  false,
+				Columns: []*schema.Column{WebhookDeliveriesColumns[7], WebhookDeliveriesColumns[1]},
 			},
 		},
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		KeysTable,
+		LedgerTable,
 		RecordsTable,
 		SignaturesTable,
+		WebhooksTable,
+		WebhookDeliveriesTable,
 	}
 )
 
 func init() {
-	SignaturesTable.ForeignKeys[0].RefTable = RecordsTable
+	LedgerTable.Annotation = &entsql.Annotation{
+		Table: "ledger",
+	}
+	SignaturesTable.ForeignKeys[0].RefTable = LedgerTable
+	SignaturesTable.ForeignKeys[1].RefTable = RecordsTable
+	WebhookDeliveriesTable.ForeignKeys[0].RefTable = WebhooksTable
 }
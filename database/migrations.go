@@ -0,0 +1,352 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	entschema "entgo.io/ent/dialect/sql/schema"
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	entmigrate "github.com/jurshsmith/vaultstream/database/migrate"
+)
+
+// Migrations holds the versioned migrations, an Atlas migration directory
+// generated from database/schema with "go run ./migrate diff <name>". Every
+// migration has its revert of the same name under down/.
+//
+//go:embed migrations/*.sql migrations/atlas.sum migrations/down/*.sql
+var Migrations embed.FS
+
+// ErrNoChanges is returned by Migrator.Up without pending migrations and by
+// Migrator.Down without applied ones.
+var ErrNoChanges = errors.New("no migrations to run")
+
+// Migration is a migration file and what has been applied of it.
+type Migration struct {
+	Version     string
+	Description string
+	// State is applied, partial, failed, baseline (skipped by a baseline
+	// revision) or pending.
+	State      string
+	Applied    int
+	Total      int
+	ExecutedAt time.Time
+	Error      string
+}
+
+// Migrator runs Migrations, recording every one it applies in the
+// schema_revisions table.
+type Migrator struct {
+	db        *sql.DB
+	drv       migrate.Driver
+	dir       *migrate.MemDir
+	revisions revisions
+}
+
+// NewMigrator connects to DATABASE_URL like Connect, but without a statement
+// timeout, which long migrations would hit.
+func NewMigrator() (*Migrator, error) {
+	settings := vaultStreamConfig.DatabasePoolFor(0)
+	settings.StatementTimeout = 0
+	db, err := open(settings)
+	if err != nil {
+		return nil, err
+	}
+	m, err := newMigrator(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func newMigrator(db *sql.DB) (*Migrator, error) {
+	drv, err := postgres.Open(db)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := migrationsDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, drv: drv, dir: dir, revisions: revisions{db: db}}, nil
+}
+
+// migrationsDir reads the migration directory out of Migrations.
+func migrationsDir() (*migrate.MemDir, error) {
+	entries, err := Migrations.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+	dir := &migrate.MemDir{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		b, err := Migrations.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if err := dir.WriteFile(e.Name(), b); err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+// Close closes the database connection.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Up applies the next n pending migrations, or all of them if n <= 0, each
+// in a transaction with its revision, so that a failed migration leaves
+// neither changes nor a revision behind. A database whose tables predate the
+// revisions table needs baseline, the version of the last migration already
+// applied to it, on the first run.
+func (m *Migrator) Up(ctx context.Context, n int, baseline string) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	opts := []migrate.ExecutorOption{migrate.WithLogger(migrationLogger{})}
+	if baseline != "" {
+		opts = append(opts, migrate.WithBaselineVersion(baseline))
+	}
+	for applied := 0; n <= 0 || applied < n; applied++ {
+		err := m.apply(ctx, opts)
+		if errors.Is(err, migrate.ErrNoPendingFiles) {
+			if applied == 0 {
+				return ErrNoChanges
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply runs the next pending migration and writes its revision in one
+// transaction. It commits a baseline revision even without pending
+// migrations.
+func (m *Migrator) apply(ctx context.Context, opts []migrate.ExecutorOption) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	drv, err := postgres.Open(tx)
+	if err != nil {
+		return err
+	}
+	ex, err := migrate.NewExecutor(drv, m.dir, revisions{db: tx}, opts...)
+	if err != nil {
+		return err
+	}
+	err = ex.ExecuteN(ctx, 1)
+	if err != nil && !errors.Is(err, migrate.ErrNoPendingFiles) {
+		return err
+	}
+	if cerr := tx.Commit(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// Down reverts the last n applied migrations, or all of them if n <= 0, with
+// their down/ files, and returns the reverted versions. It stops at a
+// baseline revision, whose migrations it never applied.
+func (m *Migrator) Down(ctx context.Context, n int) ([]string, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	revs, err := m.revisions.ReadRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 || n > len(revs) {
+		n = len(revs)
+	}
+	var reverted []string
+	for i := len(revs) - 1; i >= len(revs)-n; i-- {
+		r := revs[i]
+		if r.Type.Has(migrate.RevisionTypeBaseline) {
+			return reverted, fmt.Errorf("cannot revert baseline revision %s", r.Version)
+		}
+		if r.Applied != r.Total {
+			return reverted, fmt.Errorf("migration %s is partially applied (%d of %d statements), fix it and run up again", r.Version, r.Applied, r.Total)
+		}
+		if err := m.revert(ctx, r); err != nil {
+			return reverted, err
+		}
+		log.Printf("Reverted migration %s (%s)", r.Version, r.Description)
+		reverted = append(reverted, r.Version)
+	}
+	if len(reverted) == 0 {
+		return nil, ErrNoChanges
+	}
+	return reverted, nil
+}
+
+// revert runs the down file of r and deletes r in one transaction.
+func (m *Migrator) revert(ctx context.Context, r *migrate.Revision) error {
+	name := r.Version + "_" + r.Description + ".sql"
+	b, err := Migrations.ReadFile(path.Join("migrations", "down", name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("migration %s cannot be reverted: down/%s is missing", r.Version, name)
+	}
+	if err != nil {
+		return err
+	}
+	stmts, err := migrate.NewLocalFile(name, b).Stmts()
+	if err != nil {
+		return fmt.Errorf("failed reading down/%s: %w", name, err)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed reverting migration %s: %w: %s", r.Version, err, stmt)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, deleteRevision, r.Version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Status lists every migration file with what has been applied of it.
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	if err := m.revisions.init(ctx); err != nil {
+		return nil, err
+	}
+	revs, err := m.revisions.ReadRevisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[string]*migrate.Revision, len(revs))
+	var baseline string
+	for _, r := range revs {
+		byVersion[r.Version] = r
+		if r.Type.Has(migrate.RevisionTypeBaseline) {
+			baseline = r.Version
+		}
+	}
+	files, err := m.dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	status := make([]Migration, 0, len(files))
+	for _, f := range files {
+		s := Migration{Version: f.Version(), Description: f.Desc(), State: "pending"}
+		r, ok := byVersion[f.Version()]
+		switch {
+		case ok && r.Type.Has(migrate.RevisionTypeBaseline), !ok && f.Version() <= baseline:
+			s.State = "baseline"
+		case ok:
+			s.Applied, s.Total, s.ExecutedAt, s.Error = r.Applied, r.Total, r.ExecutedAt, r.Error
+			switch {
+			case r.Error != "":
+				s.State = "failed"
+			case r.Applied != r.Total:
+				s.State = "partial"
+			default:
+				s.State = "applied"
+			}
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Drift returns the statements that would bring the database in line with
+// database/schema, none if it matches. Unlike the migrations services rely
+// on, it also reports columns and indexes missing from the schema.
+func (m *Migrator) Drift(ctx context.Context) ([]string, error) {
+	dir := &migrate.MemDir{}
+	atlas, err := entschema.NewMigrate(
+		entsql.OpenDB(dialect.Postgres, m.db),
+		entschema.WithDir(dir),
+		entschema.WithDropColumn(true),
+		entschema.WithDropIndex(true),
+		entschema.WithErrNoPlan(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := atlas.NamedDiff(ctx, "drift", entmigrate.Tables...); err != nil {
+		if errors.Is(err, migrate.ErrNoPlan) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	files, err := dir.Files()
+	if err != nil {
+		return nil, err
+	}
+	var stmts []string
+	for _, f := range files {
+		s, err := f.(*migrate.LocalFile).Stmts()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, s...)
+	}
+	return stmts, nil
+}
+
+// lock takes a session-level advisory lock, so that services starting
+// together do not migrate concurrently.
+func (m *Migrator) lock(ctx context.Context) (schema.UnlockFunc, error) {
+	if err := m.revisions.init(ctx); err != nil {
+		return nil, err
+	}
+	unlock, err := m.drv.(schema.Locker).Lock(ctx, "vaultstream_migrate", time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("failed acquiring the migration lock: %w", err)
+	}
+	return unlock, nil
+}
+
+// migrationLogger logs every migration file Up applies and the errors it
+// fails with.
+type migrationLogger struct{}
+
+func (migrationLogger) Log(e migrate.LogEntry) {
+	switch e := e.(type) {
+	case migrate.LogFile:
+		if e.Skip > 0 {
+			log.Printf("Resuming migration %s (%s) after %d statements", e.File.Version(), e.File.Desc(), e.Skip)
+			return
+		}
+		log.Printf("Applying migration %s (%s)", e.File.Version(), e.File.Desc())
+	case migrate.LogError:
+		if e.SQL != "" {
+			log.Printf("Migration failed: %v: %s", e.Error, e.SQL)
+			return
+		}
+		log.Printf("Migration failed: %v", e.Error)
+	}
+}
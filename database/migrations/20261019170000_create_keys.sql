@@ -0,0 +1,6 @@
+CREATE TABLE keys (
+    id INT PRIMARY KEY,
+    algorithm TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    inserted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1 @@
+ALTER TABLE signatures ADD COLUMN algorithm TEXT NOT NULL DEFAULT 'ES256';
@@ -0,0 +1 @@
+ALTER TABLE keys ADD COLUMN certificate TEXT;
@@ -0,0 +1 @@
+ALTER TABLE signatures ADD COLUMN timestamp_token TEXT;
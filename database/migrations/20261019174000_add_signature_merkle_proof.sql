@@ -0,0 +1,10 @@
+-- Merkle mode signs one tree head per batch, so signature values repeat.
+ALTER TABLE signatures DROP CONSTRAINT unique_signature_value;
+CREATE INDEX signature_value ON signatures (value);
+
+ALTER TABLE signatures
+    ADD COLUMN merkle_root TEXT,
+    ADD COLUMN merkle_leaf_index INT,
+    ADD COLUMN merkle_tree_size INT,
+    ADD COLUMN merkle_path JSONB;
+CREATE INDEX signature_merkle_root ON signatures (merkle_root);
@@ -0,0 +1,11 @@
+CREATE TABLE ledger (
+    id INT PRIMARY KEY CHECK (id > 0),
+    prev_hash TEXT NOT NULL,
+    batch_hash TEXT NOT NULL,
+    size INT NOT NULL,
+    hash TEXT NOT NULL UNIQUE,
+    inserted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE signatures ADD COLUMN ledger_seq INT REFERENCES ledger(id);
+CREATE INDEX signature_ledger_seq ON signatures (ledger_seq);
@@ -0,0 +1,19 @@
+CREATE TABLE webhooks (
+    id SERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    inserted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE webhook_deliveries (
+    id SERIAL PRIMARY KEY,
+    webhook_id INT NOT NULL REFERENCES webhooks(id),
+    ledger_seq INT NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT,
+    succeeded BOOLEAN NOT NULL,
+    inserted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX webhookdelivery_webhook_id_ledger_seq ON webhook_deliveries (webhook_id, ledger_seq);
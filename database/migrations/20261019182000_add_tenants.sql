@@ -0,0 +1,9 @@
+ALTER TABLE records ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE keys ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE signatures ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+ALTER TABLE webhooks ADD COLUMN tenant_id VARCHAR(64) NOT NULL DEFAULT 'default';
+
+CREATE INDEX record_tenant_id ON records (tenant_id);
+CREATE INDEX key_tenant_id ON keys (tenant_id);
+CREATE INDEX signature_tenant_id ON signatures (tenant_id);
+CREATE INDEX webhook_tenant_id ON webhooks (tenant_id);
@@ -0,0 +1,85 @@
+-- Brings the hand-written schema in line with database/schema, from which
+-- later migrations are generated: ent ints are bigint with identity IDs, ent
+-- strings are varchar, and timestamps, constraints and foreign keys are the
+-- ones ent declares. Positive IDs are validated by ent rather than a CHECK.
+
+-- records
+ALTER TABLE records ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE records_id_seq;
+ALTER TABLE records ALTER COLUMN id TYPE bigint, ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE records ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('records', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM records;
+
+-- keys
+ALTER TABLE keys
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN algorithm TYPE character varying,
+    ALTER COLUMN public_key TYPE character varying,
+    ALTER COLUMN certificate TYPE character varying,
+    ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE keys ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('keys', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM keys;
+
+-- ledger
+ALTER TABLE ledger DROP CONSTRAINT ledger_id_check;
+ALTER TABLE ledger
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN prev_hash TYPE character varying,
+    ALTER COLUMN batch_hash TYPE character varying,
+    ALTER COLUMN size TYPE bigint,
+    ALTER COLUMN hash TYPE character varying,
+    ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE ledger ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('ledger', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM ledger;
+
+-- signatures: record_id was declared SERIAL, although every row names its
+-- record, and a record has at most one signature. ent clears ledger_seq
+-- rather than refusing to delete a ledger entry.
+ALTER TABLE signatures
+    DROP CONSTRAINT signatures_ledger_seq_fkey,
+    ADD CONSTRAINT signatures_ledger_signatures FOREIGN KEY (ledger_seq) REFERENCES ledger(id) ON DELETE SET NULL;
+ALTER TABLE signatures RENAME CONSTRAINT signatures_record_id_fkey TO signatures_records_signature;
+ALTER TABLE signatures ALTER COLUMN id DROP DEFAULT, ALTER COLUMN record_id DROP DEFAULT;
+DROP SEQUENCE signatures_id_seq;
+DROP SEQUENCE signatures_record_id_seq;
+ALTER TABLE signatures
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN record_id TYPE bigint,
+    ALTER COLUMN key_id TYPE bigint,
+    ALTER COLUMN algorithm TYPE character varying,
+    ALTER COLUMN value TYPE character varying,
+    ALTER COLUMN timestamp_token TYPE character varying,
+    ALTER COLUMN merkle_root TYPE character varying,
+    ALTER COLUMN merkle_leaf_index TYPE bigint,
+    ALTER COLUMN merkle_tree_size TYPE bigint,
+    ALTER COLUMN ledger_seq TYPE bigint,
+    ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE signatures ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('signatures', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM signatures;
+CREATE UNIQUE INDEX signatures_record_id_key ON signatures (record_id);
+
+-- webhooks
+ALTER TABLE webhooks ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE webhooks_id_seq;
+ALTER TABLE webhooks
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN url TYPE character varying,
+    ALTER COLUMN secret TYPE character varying,
+    ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE webhooks ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('webhooks', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM webhooks;
+
+-- webhook_deliveries
+ALTER TABLE webhook_deliveries RENAME CONSTRAINT webhook_deliveries_webhook_id_fkey TO webhook_deliveries_webhooks_deliveries;
+ALTER TABLE webhook_deliveries ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE webhook_deliveries_id_seq;
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN id TYPE bigint,
+    ALTER COLUMN webhook_id TYPE bigint,
+    ALTER COLUMN ledger_seq TYPE bigint,
+    ALTER COLUMN attempt TYPE bigint,
+    ALTER COLUMN status_code TYPE bigint,
+    ALTER COLUMN error TYPE character varying,
+    ALTER COLUMN inserted_at DROP DEFAULT;
+ALTER TABLE webhook_deliveries ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY;
+SELECT setval(pg_get_serial_sequence('webhook_deliveries', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM webhook_deliveries;
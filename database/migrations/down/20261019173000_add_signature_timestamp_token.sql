@@ -0,0 +1 @@
+ALTER TABLE signatures DROP COLUMN timestamp_token;
@@ -0,0 +1,9 @@
+-- Fails once Merkle mode stored a batch, whose rows share the signature value.
+DROP INDEX signature_merkle_root;
+ALTER TABLE signatures
+    DROP COLUMN merkle_root,
+    DROP COLUMN merkle_leaf_index,
+    DROP COLUMN merkle_tree_size,
+    DROP COLUMN merkle_path;
+DROP INDEX signature_value;
+ALTER TABLE signatures ADD CONSTRAINT unique_signature_value UNIQUE (value);
@@ -0,0 +1,2 @@
+ALTER TABLE signatures DROP COLUMN ledger_seq;
+DROP TABLE ledger;
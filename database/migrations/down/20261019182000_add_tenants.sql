@@ -0,0 +1,4 @@
+ALTER TABLE records DROP COLUMN tenant_id;
+ALTER TABLE keys DROP COLUMN tenant_id;
+ALTER TABLE signatures DROP COLUMN tenant_id;
+ALTER TABLE webhooks DROP COLUMN tenant_id;
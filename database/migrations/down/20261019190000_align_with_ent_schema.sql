@@ -0,0 +1,83 @@
+-- Restores the hand-written schema: serial IDs, int and text columns and
+-- database-side defaults, checks and foreign keys.
+
+-- webhook_deliveries
+ALTER TABLE webhook_deliveries ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE webhook_deliveries
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN webhook_id TYPE integer,
+    ALTER COLUMN ledger_seq TYPE integer,
+    ALTER COLUMN attempt TYPE integer,
+    ALTER COLUMN status_code TYPE integer,
+    ALTER COLUMN error TYPE text,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+CREATE SEQUENCE webhook_deliveries_id_seq AS integer OWNED BY webhook_deliveries.id;
+ALTER TABLE webhook_deliveries ALTER COLUMN id SET DEFAULT nextval('webhook_deliveries_id_seq');
+SELECT setval('webhook_deliveries_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM webhook_deliveries;
+ALTER TABLE webhook_deliveries RENAME CONSTRAINT webhook_deliveries_webhooks_deliveries TO webhook_deliveries_webhook_id_fkey;
+
+-- webhooks
+ALTER TABLE webhooks ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE webhooks
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN url TYPE text,
+    ALTER COLUMN secret TYPE text,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+CREATE SEQUENCE webhooks_id_seq AS integer OWNED BY webhooks.id;
+ALTER TABLE webhooks ALTER COLUMN id SET DEFAULT nextval('webhooks_id_seq');
+SELECT setval('webhooks_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM webhooks;
+
+-- signatures
+DROP INDEX signatures_record_id_key;
+ALTER TABLE signatures ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE signatures
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN record_id TYPE integer,
+    ALTER COLUMN key_id TYPE integer,
+    ALTER COLUMN algorithm TYPE text,
+    ALTER COLUMN value TYPE text,
+    ALTER COLUMN timestamp_token TYPE text,
+    ALTER COLUMN merkle_root TYPE text,
+    ALTER COLUMN merkle_leaf_index TYPE integer,
+    ALTER COLUMN merkle_tree_size TYPE integer,
+    ALTER COLUMN ledger_seq TYPE integer,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+CREATE SEQUENCE signatures_id_seq AS integer OWNED BY signatures.id;
+CREATE SEQUENCE signatures_record_id_seq AS integer OWNED BY signatures.record_id;
+ALTER TABLE signatures
+    ALTER COLUMN id SET DEFAULT nextval('signatures_id_seq'),
+    ALTER COLUMN record_id SET DEFAULT nextval('signatures_record_id_seq');
+SELECT setval('signatures_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM signatures;
+ALTER TABLE signatures RENAME CONSTRAINT signatures_records_signature TO signatures_record_id_fkey;
+ALTER TABLE signatures
+    DROP CONSTRAINT signatures_ledger_signatures,
+    ADD CONSTRAINT signatures_ledger_seq_fkey FOREIGN KEY (ledger_seq) REFERENCES ledger(id);
+
+-- ledger
+ALTER TABLE ledger ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE ledger
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN prev_hash TYPE text,
+    ALTER COLUMN batch_hash TYPE text,
+    ALTER COLUMN size TYPE integer,
+    ALTER COLUMN hash TYPE text,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+ALTER TABLE ledger ADD CONSTRAINT ledger_id_check CHECK (id > 0);
+
+-- keys
+ALTER TABLE keys ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE keys
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN algorithm TYPE text,
+    ALTER COLUMN public_key TYPE text,
+    ALTER COLUMN certificate TYPE text,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+
+-- records
+ALTER TABLE records ALTER COLUMN id DROP IDENTITY;
+ALTER TABLE records
+    ALTER COLUMN id TYPE integer,
+    ALTER COLUMN inserted_at SET DEFAULT now();
+CREATE SEQUENCE records_id_seq AS integer OWNED BY records.id;
+ALTER TABLE records ALTER COLUMN id SET DEFAULT nextval('records_id_seq');
+SELECT setval('records_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM records;
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+)
+
+func TestMigrationsDir(t *testing.T) {
+	dir, err := migrationsDir()
+	if err != nil {
+		t.Fatalf("migrationsDir() returned an unexpected error: %v", err)
+	}
+	if err := migrate.Validate(dir); err != nil {
+		t.Fatalf("migrations do not match atlas.sum, run \"go run ./migrate hash\": %v", err)
+	}
+	files, err := dir.Files()
+	if err != nil {
+		t.Fatalf("Files() returned an unexpected error: %v", err)
+	}
+	names := make(map[string]bool, len(files))
+	for _, f := range files {
+		names[f.Name()] = true
+		if _, err := Migrations.ReadFile(path.Join("migrations", "down", f.Name())); err != nil {
+			t.Errorf("migration %s has no down/%s", f.Version(), f.Name())
+		}
+	}
+	downs, err := Migrations.ReadDir("migrations/down")
+	if err != nil {
+		t.Fatalf("ReadDir(down) returned an unexpected error: %v", err)
+	}
+	for _, d := range downs {
+		if !names[d.Name()] {
+			t.Errorf("down/%s reverts no migration", d.Name())
+		}
+	}
+}
+
+// TestSchemaDrift migrates DATABASE_URL and fails if it still differs from
+// database/schema, i.e. a schema change lacks its migration.
+func TestSchemaDrift(t *testing.T) {
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+	m, err := NewMigrator()
+	if err != nil {
+		t.Skipf("Skipping, database unavailable: %v", err)
+	}
+	defer m.Close()
+
+	ctx := context.Background()
+	if err := m.Up(ctx, 0, ""); err != nil && !errors.Is(err, ErrNoChanges) {
+		t.Fatalf("Up() returned an unexpected error: %v", err)
+	}
+	stmts, err := m.Drift(ctx)
+	if err != nil {
+		t.Fatalf("Drift() returned an unexpected error: %v", err)
+	}
+	if len(stmts) > 0 {
+		t.Errorf("database differs from database/schema, run \"go run ./migrate diff <name>\"; it needs:\n%s", strings.Join(stmts, "\n"))
+	}
+}
@@ -11,9 +11,13 @@ import (
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
+	"github.com/jurshsmith/vaultstream/database/key"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
 	"github.com/jurshsmith/vaultstream/database/predicate"
 	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/database/webhook"
+	"github.com/jurshsmith/vaultstream/database/webhookdelivery"
 )
 
 const (
@@ -25,36 +29,3182 @@ const (
 	OpUpdateOne = ent.OpUpdateOne
 
 	// Node types.
-	TypeRecord    = "Record"
-	TypeSignature = "Signature"
+	TypeKey             = "Key"
+	TypeLedgerEntry     = "LedgerEntry"
+	TypeRecord          = "Record"
+	TypeSignature       = "Signature"
+	TypeWebhook         = "Webhook"
+	TypeWebhookDelivery = "WebhookDelivery"
 )
 
+// KeyMutation represents an operation that mutates the Key nodes in the graph.
+type KeyMutation struct {
+	config
+	op                      Op
+	typ                     string
+	id                      *int
+	tenant_id               *string
+	algorithm               *string
+	public_key              *string
+	certificate             *string
+	certificate_chain       *[]string
+	appendcertificate_chain []string
+	inserted_at             *time.Time
+	clearedFields           map[string]struct{}
+	done                    bool
+	oldValue                func(context.Context) (*Key, error)
+	predicates              []predicate.Key
+}
+
+var _ ent.Mutation = (*KeyMutation)(nil)
+
+// keyOption allows management of the mutation configuration using functional options.
+type keyOption func(*KeyMutation)
+
+// newKeyMutation creates new mutation for the Key entity.
+func newKeyMutation(c config, op Op, opts ...keyOption) *KeyMutation {
+	m := &KeyMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeKey,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withKeyID sets the ID field of the mutation.
+func withKeyID(id int) keyOption {
+	return func(m *KeyMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Key
+		)
+		m.oldValue = func(ctx context.Context) (*Key, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Key.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withKey sets the old Key of the mutation.
+func withKey(node *Key) keyOption {
+	return func(m *KeyMutation) {
+		m.oldValue = func(context.Context) (*Key, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m KeyMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m KeyMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("database: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of Key entities.
+func (m *KeyMutation) SetID(id int) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *KeyMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *KeyMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Key.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (m *KeyMutation) SetTenantID(s string) {
+	m.tenant_id = &s
+}
+
+// TenantID returns the value of the "tenant_id" field in the mutation.
+func (m *KeyMutation) TenantID() (r string, exists bool) {
+	v := m.tenant_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTenantID returns the old "tenant_id" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldTenantID(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTenantID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTenantID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTenantID: %w", err)
+	}
+	return oldValue.TenantID, nil
+}
+
+// ResetTenantID resets all changes to the "tenant_id" field.
+func (m *KeyMutation) ResetTenantID() {
+	m.tenant_id = nil
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (m *KeyMutation) SetAlgorithm(s string) {
+	m.algorithm = &s
+}
+
+// Algorithm returns the value of the "algorithm" field in the mutation.
+func (m *KeyMutation) Algorithm() (r string, exists bool) {
+	v := m.algorithm
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAlgorithm returns the old "algorithm" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldAlgorithm(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAlgorithm is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAlgorithm requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAlgorithm: %w", err)
+	}
+	return oldValue.Algorithm, nil
+}
+
+// ResetAlgorithm resets all changes to the "algorithm" field.
+func (m *KeyMutation) ResetAlgorithm() {
+	m.algorithm = nil
+}
+
+// SetPublicKey sets the "public_key" field.
+func (m *KeyMutation) SetPublicKey(s string) {
+	m.public_key = &s
+}
+
+// PublicKey returns the value of the "public_key" field in the mutation.
+func (m *KeyMutation) PublicKey() (r string, exists bool) {
+	v := m.public_key
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPublicKey returns the old "public_key" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldPublicKey(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPublicKey is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPublicKey requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPublicKey: %w", err)
+	}
+	return oldValue.PublicKey, nil
+}
+
+// ResetPublicKey resets all changes to the "public_key" field.
+func (m *KeyMutation) ResetPublicKey() {
+	m.public_key = nil
+}
+
+// SetCertificate sets the "certificate" field.
+func (m *KeyMutation) SetCertificate(s string) {
+	m.certificate = &s
+}
+
+// Certificate returns the value of the "certificate" field in the mutation.
+func (m *KeyMutation) Certificate() (r string, exists bool) {
+	v := m.certificate
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCertificate returns the old "certificate" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldCertificate(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCertificate is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCertificate requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCertificate: %w", err)
+	}
+	return oldValue.Certificate, nil
+}
+
+// ClearCertificate clears the value of the "certificate" field.
+func (m *KeyMutation) ClearCertificate() {
+	m.certificate = nil
+	m.clearedFields[key.FieldCertificate] = struct{}{}
+}
+
+// CertificateCleared returns if the "certificate" field was cleared in this mutation.
+func (m *KeyMutation) CertificateCleared() bool {
+	_, ok := m.clearedFields[key.FieldCertificate]
+	return ok
+}
+
+// ResetCertificate resets all changes to the "certificate" field.
+func (m *KeyMutation) ResetCertificate() {
+	m.certificate = nil
+	delete(m.clearedFields, key.FieldCertificate)
+}
+
+// SetCertificateChain sets the "certificate_chain" field.
+func (m *KeyMutation) SetCertificateChain(s []string) {
+	m.certificate_chain = &s
+	m.appendcertificate_chain = nil
+}
+
+// CertificateChain returns the value of the "certificate_chain" field in the mutation.
+func (m *KeyMutation) CertificateChain() (r []string, exists bool) {
+	v := m.certificate_chain
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldCertificateChain returns the old "certificate_chain" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldCertificateChain(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldCertificateChain is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldCertificateChain requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldCertificateChain: %w", err)
+	}
+	return oldValue.CertificateChain, nil
+}
+
+// AppendCertificateChain adds s to the "certificate_chain" field.
+func (m *KeyMutation) AppendCertificateChain(s []string) {
+	m.appendcertificate_chain = append(m.appendcertificate_chain, s...)
+}
+
+// AppendedCertificateChain returns the list of values that were appended to the "certificate_chain" field in this mutation.
+func (m *KeyMutation) AppendedCertificateChain() ([]string, bool) {
+	if len(m.appendcertificate_chain) == 0 {
+		return nil, false
+	}
+	return m.appendcertificate_chain, true
+}
+
+// ClearCertificateChain clears the value of the "certificate_chain" field.
+func (m *KeyMutation) ClearCertificateChain() {
+	m.certificate_chain = nil
+	m.appendcertificate_chain = nil
+	m.clearedFields[key.FieldCertificateChain] = struct{}{}
+}
+
+// CertificateChainCleared returns if the "certificate_chain" field was cleared in this mutation.
+func (m *KeyMutation) CertificateChainCleared() bool {
+	_, ok := m.clearedFields[key.FieldCertificateChain]
+	return ok
+}
+
+// ResetCertificateChain resets all changes to the "certificate_chain" field.
+func (m *KeyMutation) ResetCertificateChain() {
+	m.certificate_chain = nil
+	m.appendcertificate_chain = nil
+	delete(m.clearedFields, key.FieldCertificateChain)
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (m *KeyMutation) SetInsertedAt(t time.Time) {
+	m.inserted_at = &t
+}
+
+// InsertedAt returns the value of the "inserted_at" field in the mutation.
+func (m *KeyMutation) InsertedAt() (r time.Time, exists bool) {
+	v := m.inserted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInsertedAt returns the old "inserted_at" field's value of the Key entity.
+// If the Key object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *KeyMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInsertedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInsertedAt: %w", err)
+	}
+	return oldValue.InsertedAt, nil
+}
+
+// ResetInsertedAt resets all changes to the "inserted_at" field.
+func (m *KeyMutation) ResetInsertedAt() {
+	m.inserted_at = nil
+}
+
+// Where appends a list predicates to the KeyMutation builder.
+func (m *KeyMutation) Where(ps ...predicate.Key) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the KeyMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *KeyMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Key, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *KeyMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *KeyMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Key).
+func (m *KeyMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *KeyMutation) Fields() []string {
+	fields := make([]string, 0, 6)
+	if m.tenant_id != nil {
+		fields = append(fields, key.FieldTenantID)
+	}
+	if m.algorithm != nil {
+		fields = append(fields, key.FieldAlgorithm)
+	}
+	if m.public_key != nil {
+		fields = append(fields, key.FieldPublicKey)
+	}
+	if m.certificate != nil {
+		fields = append(fields, key.FieldCertificate)
+	}
+	if m.certificate_chain != nil {
+		fields = append(fields, key.FieldCertificateChain)
+	}
+	if m.inserted_at != nil {
+		fields = append(fields, key.FieldInsertedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *KeyMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case key.FieldTenantID:
+		return m.TenantID()
+	case key.FieldAlgorithm:
+		return m.Algorithm()
+	case key.FieldPublicKey:
+		return m.PublicKey()
+	case key.FieldCertificate:
+		return m.Certificate()
+	case key.FieldCertificateChain:
+		return m.CertificateChain()
+	case key.FieldInsertedAt:
+		return m.InsertedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *KeyMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case key.FieldTenantID:
+		return m.OldTenantID(ctx)
+	case key.FieldAlgorithm:
+		return m.OldAlgorithm(ctx)
+	case key.FieldPublicKey:
+		return m.OldPublicKey(ctx)
+	case key.FieldCertificate:
+		return m.OldCertificate(ctx)
+	case key.FieldCertificateChain:
+		return m.OldCertificateChain(ctx)
+	case key.FieldInsertedAt:
+		return m.OldInsertedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown Key field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *KeyMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case key.FieldTenantID:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTenantID(v)
+		return nil
+	case key.FieldAlgorithm:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAlgorithm(v)
+		return nil
+	case key.FieldPublicKey:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPublicKey(v)
+		return nil
+	case key.FieldCertificate:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCertificate(v)
+		return nil
+	case key.FieldCertificateChain:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetCertificateChain(v)
+		return nil
+	case key.FieldInsertedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetInsertedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Key field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *KeyMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *KeyMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *KeyMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Key numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *KeyMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(key.FieldCertificate) {
+		fields = append(fields, key.FieldCertificate)
+	}
+	if m.FieldCleared(key.FieldCertificateChain) {
+		fields = append(fields, key.FieldCertificateChain)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *KeyMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *KeyMutation) ClearField(name string) error {
+	switch name {
+	case key.FieldCertificate:
+		m.ClearCertificate()
+		return nil
+	case key.FieldCertificateChain:
+		m.ClearCertificateChain()
+		return nil
+	}
+	return fmt.Errorf("unknown Key nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *KeyMutation) ResetField(name string) error {
+	switch name {
+	case key.FieldTenantID:
+		m.ResetTenantID()
+		return nil
+	case key.FieldAlgorithm:
+		m.ResetAlgorithm()
+		return nil
+	case key.FieldPublicKey:
+		m.ResetPublicKey()
+		return nil
+	case key.FieldCertificate:
+		m.ResetCertificate()
+		return nil
+	case key.FieldCertificateChain:
+		m.ResetCertificateChain()
+		return nil
+	case key.FieldInsertedAt:
+		m.ResetInsertedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Key field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *KeyMutation) AddedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *KeyMutation) AddedIDs(name string) []ent.Value {
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *KeyMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *KeyMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *KeyMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 0)
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *KeyMutation) EdgeCleared(name string) bool {
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *KeyMutation) ClearEdge(name string) error {
+	return fmt.Errorf("unknown Key unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *KeyMutation) ResetEdge(name string) error {
+	return fmt.Errorf("unknown Key edge %s", name)
+}
+
+// LedgerEntryMutation represents an operation that mutates the LedgerEntry nodes in the graph.
+type LedgerEntryMutation struct {
+	config
+	op                Op
+	typ               string
+	id                *int
+	prev_hash         *string
+	batch_hash        *string
+	size              *int
+	addsize           *int
+	hash              *string
+	inserted_at       *time.Time
+	clearedFields     map[string]struct{}
+	signatures        map[int]struct{}
+	removedsignatures map[int]struct{}
+	clearedsignatures bool
+	done              bool
+	oldValue          func(context.Context) (*LedgerEntry, error)
+	predicates        []predicate.LedgerEntry
+}
+
+var _ ent.Mutation = (*LedgerEntryMutation)(nil)
+
+// ledgerentryOption allows management of the mutation configuration using functional options.
+type ledgerentryOption func(*LedgerEntryMutation)
+
+// newLedgerEntryMutation creates new mutation for the LedgerEntry entity.
+func newLedgerEntryMutation(c config, op Op, opts ...ledgerentryOption) *LedgerEntryMutation {
+	m := &LedgerEntryMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeLedgerEntry,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withLedgerEntryID sets the ID field of the mutation.
+func withLedgerEntryID(id int) ledgerentryOption {
+	return func(m *LedgerEntryMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *LedgerEntry
+		)
+		m.oldValue = func(ctx context.Context) (*LedgerEntry, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().LedgerEntry.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withLedgerEntry sets the old LedgerEntry of the mutation.
+func withLedgerEntry(node *LedgerEntry) ledgerentryOption {
+	return func(m *LedgerEntryMutation) {
+		m.oldValue = func(context.Context) (*LedgerEntry, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m LedgerEntryMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m LedgerEntryMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("database: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of LedgerEntry entities.
+func (m *LedgerEntryMutation) SetID(id int) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *LedgerEntryMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *LedgerEntryMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().LedgerEntry.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetPrevHash sets the "prev_hash" field.
+func (m *LedgerEntryMutation) SetPrevHash(s string) {
+	m.prev_hash = &s
+}
+
+// PrevHash returns the value of the "prev_hash" field in the mutation.
+func (m *LedgerEntryMutation) PrevHash() (r string, exists bool) {
+	v := m.prev_hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldPrevHash returns the old "prev_hash" field's value of the LedgerEntry entity.
+// If the LedgerEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *LedgerEntryMutation) OldPrevHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldPrevHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldPrevHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldPrevHash: %w", err)
+	}
+	return oldValue.PrevHash, nil
+}
+
+// ResetPrevHash resets all changes to the "prev_hash" field.
+func (m *LedgerEntryMutation) ResetPrevHash() {
+	m.prev_hash = nil
+}
+
+// SetBatchHash sets the "batch_hash" field.
+func (m *LedgerEntryMutation) SetBatchHash(s string) {
+	m.batch_hash = &s
+}
+
+// BatchHash returns the value of the "batch_hash" field in the mutation.
+func (m *LedgerEntryMutation) BatchHash() (r string, exists bool) {
+	v := m.batch_hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldBatchHash returns the old "batch_hash" field's value of the LedgerEntry entity.
+// If the LedgerEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *LedgerEntryMutation) OldBatchHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldBatchHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldBatchHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldBatchHash: %w", err)
+	}
+	return oldValue.BatchHash, nil
+}
+
+// ResetBatchHash resets all changes to the "batch_hash" field.
+func (m *LedgerEntryMutation) ResetBatchHash() {
+	m.batch_hash = nil
+}
+
+// SetSize sets the "size" field.
+func (m *LedgerEntryMutation) SetSize(i int) {
+	m.size = &i
+	m.addsize = nil
+}
+
+// Size returns the value of the "size" field in the mutation.
+func (m *LedgerEntryMutation) Size() (r int, exists bool) {
+	v := m.size
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSize returns the old "size" field's value of the LedgerEntry entity.
+// If the LedgerEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *LedgerEntryMutation) OldSize(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSize is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSize requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSize: %w", err)
+	}
+	return oldValue.Size, nil
+}
+
+// AddSize adds i to the "size" field.
+func (m *LedgerEntryMutation) AddSize(i int) {
+	if m.addsize != nil {
+		*m.addsize += i
+	} else {
+		m.addsize = &i
+	}
+}
+
+// AddedSize returns the value that was added to the "size" field in this mutation.
+func (m *LedgerEntryMutation) AddedSize() (r int, exists bool) {
+	v := m.addsize
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetSize resets all changes to the "size" field.
+func (m *LedgerEntryMutation) ResetSize() {
+	m.size = nil
+	m.addsize = nil
+}
+
+// SetHash sets the "hash" field.
+func (m *LedgerEntryMutation) SetHash(s string) {
+	m.hash = &s
+}
+
+// Hash returns the value of the "hash" field in the mutation.
+func (m *LedgerEntryMutation) Hash() (r string, exists bool) {
+	v := m.hash
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldHash returns the old "hash" field's value of the LedgerEntry entity.
+// If the LedgerEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *LedgerEntryMutation) OldHash(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldHash is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldHash requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldHash: %w", err)
+	}
+	return oldValue.Hash, nil
+}
+
+// ResetHash resets all changes to the "hash" field.
+func (m *LedgerEntryMutation) ResetHash() {
+	m.hash = nil
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (m *LedgerEntryMutation) SetInsertedAt(t time.Time) {
+	m.inserted_at = &t
+}
+
+// InsertedAt returns the value of the "inserted_at" field in the mutation.
+func (m *LedgerEntryMutation) InsertedAt() (r time.Time, exists bool) {
+	v := m.inserted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInsertedAt returns the old "inserted_at" field's value of the LedgerEntry entity.
+// If the LedgerEntry object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *LedgerEntryMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInsertedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInsertedAt: %w", err)
+	}
+	return oldValue.InsertedAt, nil
+}
+
+// ResetInsertedAt resets all changes to the "inserted_at" field.
+func (m *LedgerEntryMutation) ResetInsertedAt() {
+	m.inserted_at = nil
+}
+
+// AddSignatureIDs adds the "signatures" edge to the Signature entity by ids.
+func (m *LedgerEntryMutation) AddSignatureIDs(ids ...int) {
+	if m.signatures == nil {
+		m.signatures = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.signatures[ids[i]] = struct{}{}
+	}
+}
+
+// ClearSignatures clears the "signatures" edge to the Signature entity.
+func (m *LedgerEntryMutation) ClearSignatures() {
+	m.clearedsignatures = true
+}
+
+// SignaturesCleared reports if the "signatures" edge to the Signature entity was cleared.
+func (m *LedgerEntryMutation) SignaturesCleared() bool {
+	return m.clearedsignatures
+}
+
+// RemoveSignatureIDs removes the "signatures" edge to the Signature entity by IDs.
+func (m *LedgerEntryMutation) RemoveSignatureIDs(ids ...int) {
+	if m.removedsignatures == nil {
+		m.removedsignatures = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.signatures, ids[i])
+		m.removedsignatures[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedSignatures returns the removed IDs of the "signatures" edge to the Signature entity.
+func (m *LedgerEntryMutation) RemovedSignaturesIDs() (ids []int) {
+	for id := range m.removedsignatures {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// SignaturesIDs returns the "signatures" edge IDs in the mutation.
+func (m *LedgerEntryMutation) SignaturesIDs() (ids []int) {
+	for id := range m.signatures {
+		ids = append(ids, id)
+	}
+	return
+}
+
+// ResetSignatures resets all changes to the "signatures" edge.
+func (m *LedgerEntryMutation) ResetSignatures() {
+	m.signatures = nil
+	m.clearedsignatures = false
+	m.removedsignatures = nil
+}
+
+// Where appends a list predicates to the LedgerEntryMutation builder.
+func (m *LedgerEntryMutation) Where(ps ...predicate.LedgerEntry) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the LedgerEntryMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *LedgerEntryMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.LedgerEntry, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *LedgerEntryMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *LedgerEntryMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (LedgerEntry).
+func (m *LedgerEntryMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *LedgerEntryMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.prev_hash != nil {
+		fields = append(fields, ledgerentry.FieldPrevHash)
+	}
+	if m.batch_hash != nil {
+		fields = append(fields, ledgerentry.FieldBatchHash)
+	}
+	if m.size != nil {
+		fields = append(fields, ledgerentry.FieldSize)
+	}
+	if m.hash != nil {
+		fields = append(fields, ledgerentry.FieldHash)
+	}
+	if m.inserted_at != nil {
+		fields = append(fields, ledgerentry.FieldInsertedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *LedgerEntryMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case ledgerentry.FieldPrevHash:
+		return m.PrevHash()
+	case ledgerentry.FieldBatchHash:
+		return m.BatchHash()
+	case ledgerentry.FieldSize:
+		return m.Size()
+	case ledgerentry.FieldHash:
+		return m.Hash()
+	case ledgerentry.FieldInsertedAt:
+		return m.InsertedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *LedgerEntryMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case ledgerentry.FieldPrevHash:
+		return m.OldPrevHash(ctx)
+	case ledgerentry.FieldBatchHash:
+		return m.OldBatchHash(ctx)
+	case ledgerentry.FieldSize:
+		return m.OldSize(ctx)
+	case ledgerentry.FieldHash:
+		return m.OldHash(ctx)
+	case ledgerentry.FieldInsertedAt:
+		return m.OldInsertedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown LedgerEntry field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *LedgerEntryMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case ledgerentry.FieldPrevHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetPrevHash(v)
+		return nil
+	case ledgerentry.FieldBatchHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetBatchHash(v)
+		return nil
+	case ledgerentry.FieldSize:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSize(v)
+		return nil
+	case ledgerentry.FieldHash:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetHash(v)
+		return nil
+	case ledgerentry.FieldInsertedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetInsertedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown LedgerEntry field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *LedgerEntryMutation) AddedFields() []string {
+	var fields []string
+	if m.addsize != nil {
+		fields = append(fields, ledgerentry.FieldSize)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *LedgerEntryMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case ledgerentry.FieldSize:
+		return m.AddedSize()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *LedgerEntryMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case ledgerentry.FieldSize:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddSize(v)
+		return nil
+	}
+	return fmt.Errorf("unknown LedgerEntry numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *LedgerEntryMutation) ClearedFields() []string {
+	return nil
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *LedgerEntryMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *LedgerEntryMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown LedgerEntry nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *LedgerEntryMutation) ResetField(name string) error {
+	switch name {
+	case ledgerentry.FieldPrevHash:
+		m.ResetPrevHash()
+		return nil
+	case ledgerentry.FieldBatchHash:
+		m.ResetBatchHash()
+		return nil
+	case ledgerentry.FieldSize:
+		m.ResetSize()
+		return nil
+	case ledgerentry.FieldHash:
+		m.ResetHash()
+		return nil
+	case ledgerentry.FieldInsertedAt:
+		m.ResetInsertedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown LedgerEntry field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *LedgerEntryMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.signatures != nil {
+		edges = append(edges, ledgerentry.EdgeSignatures)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *LedgerEntryMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case ledgerentry.EdgeSignatures:
+		ids := make([]ent.Value, 0, len(m.signatures))
+		for id := range m.signatures {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *LedgerEntryMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.removedsignatures != nil {
+		edges = append(edges, ledgerentry.EdgeSignatures)
+	}
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *LedgerEntryMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case ledgerentry.EdgeSignatures:
+		ids := make([]ent.Value, 0, len(m.removedsignatures))
+		for id := range m.removedsignatures {
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *LedgerEntryMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedsignatures {
+		edges = append(edges, ledgerentry.EdgeSignatures)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *LedgerEntryMutation) EdgeCleared(name string) bool {
+	switch name {
+	case ledgerentry.EdgeSignatures:
+		return m.clearedsignatures
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *LedgerEntryMutation) ClearEdge(name string) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown LedgerEntry unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *LedgerEntryMutation) ResetEdge(name string) error {
+	switch name {
+	case ledgerentry.EdgeSignatures:
+		m.ResetSignatures()
+		return nil
+	}
+	return fmt.Errorf("unknown LedgerEntry edge %s", name)
+}
+
 // RecordMutation represents an operation that mutates the Record nodes in the graph.
 type RecordMutation struct {
 	config
-	op               Op
-	typ              string
-	id               *int
-	inserted_at      *time.Time
-	clearedFields    map[string]struct{}
-	signature        *int
-	clearedsignature bool
-	done             bool
-	oldValue         func(context.Context) (*Record, error)
-	predicates       []predicate.Record
+	op               Op
+	typ              string
+	id               *int
+	tenant_id        *string
+	inserted_at      *time.Time
+	data             *[]byte
+	clearedFields    map[string]struct{}
+	signature        *int
+	clearedsignature bool
+	done             bool
+	oldValue         func(context.Context) (*Record, error)
+	predicates       []predicate.Record
+}
+
+var _ ent.Mutation = (*RecordMutation)(nil)
+
+// recordOption allows management of the mutation configuration using functional options.
+type recordOption func(*RecordMutation)
+
+// newRecordMutation creates new mutation for the Record entity.
+func newRecordMutation(c config, op Op, opts ...recordOption) *RecordMutation {
+	m := &RecordMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeRecord,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withRecordID sets the ID field of the mutation.
+func withRecordID(id int) recordOption {
+	return func(m *RecordMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Record
+		)
+		m.oldValue = func(ctx context.Context) (*Record, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Record.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withRecord sets the old Record of the mutation.
+func withRecord(node *Record) recordOption {
+	return func(m *RecordMutation) {
+		m.oldValue = func(context.Context) (*Record, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m RecordMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m RecordMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("database: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// SetID sets the value of the id field. Note that this
+// operation is only accepted on creation of Record entities.
+func (m *RecordMutation) SetID(id int) {
+	m.id = &id
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *RecordMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *RecordMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Record.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (m *RecordMutation) SetTenantID(s string) {
+	m.tenant_id = &s
+}
+
+// TenantID returns the value of the "tenant_id" field in the mutation.
+func (m *RecordMutation) TenantID() (r string, exists bool) {
+	v := m.tenant_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTenantID returns the old "tenant_id" field's value of the Record entity.
+// If the Record object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RecordMutation) OldTenantID(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTenantID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTenantID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTenantID: %w", err)
+	}
+	return oldValue.TenantID, nil
+}
+
+// ResetTenantID resets all changes to the "tenant_id" field.
+func (m *RecordMutation) ResetTenantID() {
+	m.tenant_id = nil
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (m *RecordMutation) SetInsertedAt(t time.Time) {
+	m.inserted_at = &t
+}
+
+// InsertedAt returns the value of the "inserted_at" field in the mutation.
+func (m *RecordMutation) InsertedAt() (r time.Time, exists bool) {
+	v := m.inserted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInsertedAt returns the old "inserted_at" field's value of the Record entity.
+// If the Record object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RecordMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInsertedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInsertedAt: %w", err)
+	}
+	return oldValue.InsertedAt, nil
+}
+
+// ResetInsertedAt resets all changes to the "inserted_at" field.
+func (m *RecordMutation) ResetInsertedAt() {
+	m.inserted_at = nil
+}
+
+// SetData sets the "data" field.
+func (m *RecordMutation) SetData(b []byte) {
+	m.data = &b
+}
+
+// Data returns the value of the "data" field in the mutation.
+func (m *RecordMutation) Data() (r []byte, exists bool) {
+	v := m.data
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldData returns the old "data" field's value of the Record entity.
+// If the Record object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RecordMutation) OldData(ctx context.Context) (v []byte, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldData is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldData requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldData: %w", err)
+	}
+	return oldValue.Data, nil
+}
+
+// ClearData clears the value of the "data" field.
+func (m *RecordMutation) ClearData() {
+	m.data = nil
+	m.clearedFields[record.FieldData] = struct{}{}
+}
+
+// DataCleared returns if the "data" field was cleared in this mutation.
+func (m *RecordMutation) DataCleared() bool {
+	_, ok := m.clearedFields[record.FieldData]
+	return ok
+}
+
+// ResetData resets all changes to the "data" field.
+func (m *RecordMutation) ResetData() {
+	m.data = nil
+	delete(m.clearedFields, record.FieldData)
+}
+
+// SetSignatureID sets the "signature" edge to the Signature entity by id.
+func (m *RecordMutation) SetSignatureID(id int) {
+	m.signature = &id
+}
+
+// ClearSignature clears the "signature" edge to the Signature entity.
+func (m *RecordMutation) ClearSignature() {
+	m.clearedsignature = true
+}
+
+// SignatureCleared reports if the "signature" edge to the Signature entity was cleared.
+func (m *RecordMutation) SignatureCleared() bool {
+	return m.clearedsignature
+}
+
+// SignatureID returns the "signature" edge ID in the mutation.
+func (m *RecordMutation) SignatureID() (id int, exists bool) {
+	if m.signature != nil {
+		return *m.signature, true
+	}
+	return
+}
+
+// SignatureIDs returns the "signature" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// SignatureID instead. It exists only for internal usage by the builders.
+func (m *RecordMutation) SignatureIDs() (ids []int) {
+	if id := m.signature; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetSignature resets all changes to the "signature" edge.
+func (m *RecordMutation) ResetSignature() {
+	m.signature = nil
+	m.clearedsignature = false
+}
+
+// Where appends a list predicates to the RecordMutation builder.
+func (m *RecordMutation) Where(ps ...predicate.Record) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the RecordMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *RecordMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Record, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *RecordMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *RecordMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Record).
+func (m *RecordMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *RecordMutation) Fields() []string {
+	fields := make([]string, 0, 3)
+	if m.tenant_id != nil {
+		fields = append(fields, record.FieldTenantID)
+	}
+	if m.inserted_at != nil {
+		fields = append(fields, record.FieldInsertedAt)
+	}
+	if m.data != nil {
+		fields = append(fields, record.FieldData)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *RecordMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case record.FieldTenantID:
+		return m.TenantID()
+	case record.FieldInsertedAt:
+		return m.InsertedAt()
+	case record.FieldData:
+		return m.Data()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *RecordMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case record.FieldTenantID:
+		return m.OldTenantID(ctx)
+	case record.FieldInsertedAt:
+		return m.OldInsertedAt(ctx)
+	case record.FieldData:
+		return m.OldData(ctx)
+	}
+	return nil, fmt.Errorf("unknown Record field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *RecordMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case record.FieldTenantID:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTenantID(v)
+		return nil
+	case record.FieldInsertedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetInsertedAt(v)
+		return nil
+	case record.FieldData:
+		v, ok := value.([]byte)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetData(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Record field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *RecordMutation) AddedFields() []string {
+	return nil
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *RecordMutation) AddedField(name string) (ent.Value, bool) {
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *RecordMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	}
+	return fmt.Errorf("unknown Record numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *RecordMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(record.FieldData) {
+		fields = append(fields, record.FieldData)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *RecordMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *RecordMutation) ClearField(name string) error {
+	switch name {
+	case record.FieldData:
+		m.ClearData()
+		return nil
+	}
+	return fmt.Errorf("unknown Record nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *RecordMutation) ResetField(name string) error {
+	switch name {
+	case record.FieldTenantID:
+		m.ResetTenantID()
+		return nil
+	case record.FieldInsertedAt:
+		m.ResetInsertedAt()
+		return nil
+	case record.FieldData:
+		m.ResetData()
+		return nil
+	}
+	return fmt.Errorf("unknown Record field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *RecordMutation) AddedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.signature != nil {
+		edges = append(edges, record.EdgeSignature)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *RecordMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case record.EdgeSignature:
+		if id := m.signature; id != nil {
+			return []ent.Value{*id}
+		}
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *RecordMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 1)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *RecordMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *RecordMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 1)
+	if m.clearedsignature {
+		edges = append(edges, record.EdgeSignature)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *RecordMutation) EdgeCleared(name string) bool {
+	switch name {
+	case record.EdgeSignature:
+		return m.clearedsignature
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *RecordMutation) ClearEdge(name string) error {
+	switch name {
+	case record.EdgeSignature:
+		m.ClearSignature()
+		return nil
+	}
+	return fmt.Errorf("unknown Record unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *RecordMutation) ResetEdge(name string) error {
+	switch name {
+	case record.EdgeSignature:
+		m.ResetSignature()
+		return nil
+	}
+	return fmt.Errorf("unknown Record edge %s", name)
+}
+
+// SignatureMutation represents an operation that mutates the Signature nodes in the graph.
+type SignatureMutation struct {
+	config
+	op                   Op
+	typ                  string
+	id                   *int
+	tenant_id            *string
+	key_id               *int
+	addkey_id            *int
+	algorithm            *string
+	value                *string
+	timestamp_token      *string
+	merkle_root          *string
+	merkle_leaf_index    *int
+	addmerkle_leaf_index *int
+	merkle_tree_size     *int
+	addmerkle_tree_size  *int
+	merkle_path          *[]string
+	appendmerkle_path    []string
+	inserted_at          *time.Time
+	clearedFields        map[string]struct{}
+	record               *int
+	clearedrecord        bool
+	ledger               *int
+	clearedledger        bool
+	done                 bool
+	oldValue             func(context.Context) (*Signature, error)
+	predicates           []predicate.Signature
+}
+
+var _ ent.Mutation = (*SignatureMutation)(nil)
+
+// signatureOption allows management of the mutation configuration using functional options.
+type signatureOption func(*SignatureMutation)
+
+// newSignatureMutation creates new mutation for the Signature entity.
+func newSignatureMutation(c config, op Op, opts ...signatureOption) *SignatureMutation {
+	m := &SignatureMutation{
+		config:        c,
+		op:            op,
+		typ:           TypeSignature,
+		clearedFields: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// withSignatureID sets the ID field of the mutation.
+func withSignatureID(id int) signatureOption {
+	return func(m *SignatureMutation) {
+		var (
+			err   error
+			once  sync.Once
+			value *Signature
+		)
+		m.oldValue = func(ctx context.Context) (*Signature, error) {
+			once.Do(func() {
+				if m.done {
+					err = errors.New("querying old values post mutation is not allowed")
+				} else {
+					value, err = m.Client().Signature.Get(ctx, id)
+				}
+			})
+			return value, err
+		}
+		m.id = &id
+	}
+}
+
+// withSignature sets the old Signature of the mutation.
+func withSignature(node *Signature) signatureOption {
+	return func(m *SignatureMutation) {
+		m.oldValue = func(context.Context) (*Signature, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m SignatureMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m SignatureMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("database: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *SignatureMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *SignatureMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Signature.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (m *SignatureMutation) SetTenantID(s string) {
+	m.tenant_id = &s
+}
+
+// TenantID returns the value of the "tenant_id" field in the mutation.
+func (m *SignatureMutation) TenantID() (r string, exists bool) {
+	v := m.tenant_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTenantID returns the old "tenant_id" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldTenantID(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTenantID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTenantID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTenantID: %w", err)
+	}
+	return oldValue.TenantID, nil
+}
+
+// ResetTenantID resets all changes to the "tenant_id" field.
+func (m *SignatureMutation) ResetTenantID() {
+	m.tenant_id = nil
+}
+
+// SetRecordID sets the "record_id" field.
+func (m *SignatureMutation) SetRecordID(i int) {
+	m.record = &i
+}
+
+// RecordID returns the value of the "record_id" field in the mutation.
+func (m *SignatureMutation) RecordID() (r int, exists bool) {
+	v := m.record
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldRecordID returns the old "record_id" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldRecordID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldRecordID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldRecordID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldRecordID: %w", err)
+	}
+	return oldValue.RecordID, nil
+}
+
+// ResetRecordID resets all changes to the "record_id" field.
+func (m *SignatureMutation) ResetRecordID() {
+	m.record = nil
+}
+
+// SetKeyID sets the "key_id" field.
+func (m *SignatureMutation) SetKeyID(i int) {
+	m.key_id = &i
+	m.addkey_id = nil
+}
+
+// KeyID returns the value of the "key_id" field in the mutation.
+func (m *SignatureMutation) KeyID() (r int, exists bool) {
+	v := m.key_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldKeyID returns the old "key_id" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldKeyID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldKeyID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldKeyID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldKeyID: %w", err)
+	}
+	return oldValue.KeyID, nil
+}
+
+// AddKeyID adds i to the "key_id" field.
+func (m *SignatureMutation) AddKeyID(i int) {
+	if m.addkey_id != nil {
+		*m.addkey_id += i
+	} else {
+		m.addkey_id = &i
+	}
+}
+
+// AddedKeyID returns the value that was added to the "key_id" field in this mutation.
+func (m *SignatureMutation) AddedKeyID() (r int, exists bool) {
+	v := m.addkey_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetKeyID resets all changes to the "key_id" field.
+func (m *SignatureMutation) ResetKeyID() {
+	m.key_id = nil
+	m.addkey_id = nil
+}
+
+// SetAlgorithm sets the "algorithm" field.
+func (m *SignatureMutation) SetAlgorithm(s string) {
+	m.algorithm = &s
+}
+
+// Algorithm returns the value of the "algorithm" field in the mutation.
+func (m *SignatureMutation) Algorithm() (r string, exists bool) {
+	v := m.algorithm
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldAlgorithm returns the old "algorithm" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldAlgorithm(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldAlgorithm is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldAlgorithm requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldAlgorithm: %w", err)
+	}
+	return oldValue.Algorithm, nil
+}
+
+// ResetAlgorithm resets all changes to the "algorithm" field.
+func (m *SignatureMutation) ResetAlgorithm() {
+	m.algorithm = nil
+}
+
+// SetValue sets the "value" field.
+func (m *SignatureMutation) SetValue(s string) {
+	m.value = &s
+}
+
+// Value returns the value of the "value" field in the mutation.
+func (m *SignatureMutation) Value() (r string, exists bool) {
+	v := m.value
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldValue returns the old "value" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldValue(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldValue is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldValue requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldValue: %w", err)
+	}
+	return oldValue.Value, nil
+}
+
+// ResetValue resets all changes to the "value" field.
+func (m *SignatureMutation) ResetValue() {
+	m.value = nil
+}
+
+// SetTimestampToken sets the "timestamp_token" field.
+func (m *SignatureMutation) SetTimestampToken(s string) {
+	m.timestamp_token = &s
+}
+
+// TimestampToken returns the value of the "timestamp_token" field in the mutation.
+func (m *SignatureMutation) TimestampToken() (r string, exists bool) {
+	v := m.timestamp_token
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTimestampToken returns the old "timestamp_token" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldTimestampToken(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTimestampToken is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTimestampToken requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTimestampToken: %w", err)
+	}
+	return oldValue.TimestampToken, nil
+}
+
+// ClearTimestampToken clears the value of the "timestamp_token" field.
+func (m *SignatureMutation) ClearTimestampToken() {
+	m.timestamp_token = nil
+	m.clearedFields[signature.FieldTimestampToken] = struct{}{}
+}
+
+// TimestampTokenCleared returns if the "timestamp_token" field was cleared in this mutation.
+func (m *SignatureMutation) TimestampTokenCleared() bool {
+	_, ok := m.clearedFields[signature.FieldTimestampToken]
+	return ok
+}
+
+// ResetTimestampToken resets all changes to the "timestamp_token" field.
+func (m *SignatureMutation) ResetTimestampToken() {
+	m.timestamp_token = nil
+	delete(m.clearedFields, signature.FieldTimestampToken)
+}
+
+// SetMerkleRoot sets the "merkle_root" field.
+func (m *SignatureMutation) SetMerkleRoot(s string) {
+	m.merkle_root = &s
+}
+
+// MerkleRoot returns the value of the "merkle_root" field in the mutation.
+func (m *SignatureMutation) MerkleRoot() (r string, exists bool) {
+	v := m.merkle_root
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMerkleRoot returns the old "merkle_root" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldMerkleRoot(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMerkleRoot is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMerkleRoot requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMerkleRoot: %w", err)
+	}
+	return oldValue.MerkleRoot, nil
+}
+
+// ClearMerkleRoot clears the value of the "merkle_root" field.
+func (m *SignatureMutation) ClearMerkleRoot() {
+	m.merkle_root = nil
+	m.clearedFields[signature.FieldMerkleRoot] = struct{}{}
+}
+
+// MerkleRootCleared returns if the "merkle_root" field was cleared in this mutation.
+func (m *SignatureMutation) MerkleRootCleared() bool {
+	_, ok := m.clearedFields[signature.FieldMerkleRoot]
+	return ok
+}
+
+// ResetMerkleRoot resets all changes to the "merkle_root" field.
+func (m *SignatureMutation) ResetMerkleRoot() {
+	m.merkle_root = nil
+	delete(m.clearedFields, signature.FieldMerkleRoot)
+}
+
+// SetMerkleLeafIndex sets the "merkle_leaf_index" field.
+func (m *SignatureMutation) SetMerkleLeafIndex(i int) {
+	m.merkle_leaf_index = &i
+	m.addmerkle_leaf_index = nil
+}
+
+// MerkleLeafIndex returns the value of the "merkle_leaf_index" field in the mutation.
+func (m *SignatureMutation) MerkleLeafIndex() (r int, exists bool) {
+	v := m.merkle_leaf_index
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMerkleLeafIndex returns the old "merkle_leaf_index" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldMerkleLeafIndex(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMerkleLeafIndex is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMerkleLeafIndex requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMerkleLeafIndex: %w", err)
+	}
+	return oldValue.MerkleLeafIndex, nil
+}
+
+// AddMerkleLeafIndex adds i to the "merkle_leaf_index" field.
+func (m *SignatureMutation) AddMerkleLeafIndex(i int) {
+	if m.addmerkle_leaf_index != nil {
+		*m.addmerkle_leaf_index += i
+	} else {
+		m.addmerkle_leaf_index = &i
+	}
+}
+
+// AddedMerkleLeafIndex returns the value that was added to the "merkle_leaf_index" field in this mutation.
+func (m *SignatureMutation) AddedMerkleLeafIndex() (r int, exists bool) {
+	v := m.addmerkle_leaf_index
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearMerkleLeafIndex clears the value of the "merkle_leaf_index" field.
+func (m *SignatureMutation) ClearMerkleLeafIndex() {
+	m.merkle_leaf_index = nil
+	m.addmerkle_leaf_index = nil
+	m.clearedFields[signature.FieldMerkleLeafIndex] = struct{}{}
+}
+
+// MerkleLeafIndexCleared returns if the "merkle_leaf_index" field was cleared in this mutation.
+func (m *SignatureMutation) MerkleLeafIndexCleared() bool {
+	_, ok := m.clearedFields[signature.FieldMerkleLeafIndex]
+	return ok
+}
+
+// ResetMerkleLeafIndex resets all changes to the "merkle_leaf_index" field.
+func (m *SignatureMutation) ResetMerkleLeafIndex() {
+	m.merkle_leaf_index = nil
+	m.addmerkle_leaf_index = nil
+	delete(m.clearedFields, signature.FieldMerkleLeafIndex)
+}
+
+// SetMerkleTreeSize sets the "merkle_tree_size" field.
+func (m *SignatureMutation) SetMerkleTreeSize(i int) {
+	m.merkle_tree_size = &i
+	m.addmerkle_tree_size = nil
+}
+
+// MerkleTreeSize returns the value of the "merkle_tree_size" field in the mutation.
+func (m *SignatureMutation) MerkleTreeSize() (r int, exists bool) {
+	v := m.merkle_tree_size
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMerkleTreeSize returns the old "merkle_tree_size" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldMerkleTreeSize(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMerkleTreeSize is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMerkleTreeSize requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMerkleTreeSize: %w", err)
+	}
+	return oldValue.MerkleTreeSize, nil
+}
+
+// AddMerkleTreeSize adds i to the "merkle_tree_size" field.
+func (m *SignatureMutation) AddMerkleTreeSize(i int) {
+	if m.addmerkle_tree_size != nil {
+		*m.addmerkle_tree_size += i
+	} else {
+		m.addmerkle_tree_size = &i
+	}
+}
+
+// AddedMerkleTreeSize returns the value that was added to the "merkle_tree_size" field in this mutation.
+func (m *SignatureMutation) AddedMerkleTreeSize() (r int, exists bool) {
+	v := m.addmerkle_tree_size
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ClearMerkleTreeSize clears the value of the "merkle_tree_size" field.
+func (m *SignatureMutation) ClearMerkleTreeSize() {
+	m.merkle_tree_size = nil
+	m.addmerkle_tree_size = nil
+	m.clearedFields[signature.FieldMerkleTreeSize] = struct{}{}
+}
+
+// MerkleTreeSizeCleared returns if the "merkle_tree_size" field was cleared in this mutation.
+func (m *SignatureMutation) MerkleTreeSizeCleared() bool {
+	_, ok := m.clearedFields[signature.FieldMerkleTreeSize]
+	return ok
+}
+
+// ResetMerkleTreeSize resets all changes to the "merkle_tree_size" field.
+func (m *SignatureMutation) ResetMerkleTreeSize() {
+	m.merkle_tree_size = nil
+	m.addmerkle_tree_size = nil
+	delete(m.clearedFields, signature.FieldMerkleTreeSize)
+}
+
+// SetMerklePath sets the "merkle_path" field.
+func (m *SignatureMutation) SetMerklePath(s []string) {
+	m.merkle_path = &s
+	m.appendmerkle_path = nil
+}
+
+// MerklePath returns the value of the "merkle_path" field in the mutation.
+func (m *SignatureMutation) MerklePath() (r []string, exists bool) {
+	v := m.merkle_path
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldMerklePath returns the old "merkle_path" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldMerklePath(ctx context.Context) (v []string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldMerklePath is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldMerklePath requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldMerklePath: %w", err)
+	}
+	return oldValue.MerklePath, nil
+}
+
+// AppendMerklePath adds s to the "merkle_path" field.
+func (m *SignatureMutation) AppendMerklePath(s []string) {
+	m.appendmerkle_path = append(m.appendmerkle_path, s...)
+}
+
+// AppendedMerklePath returns the list of values that were appended to the "merkle_path" field in this mutation.
+func (m *SignatureMutation) AppendedMerklePath() ([]string, bool) {
+	if len(m.appendmerkle_path) == 0 {
+		return nil, false
+	}
+	return m.appendmerkle_path, true
+}
+
+// ClearMerklePath clears the value of the "merkle_path" field.
+func (m *SignatureMutation) ClearMerklePath() {
+	m.merkle_path = nil
+	m.appendmerkle_path = nil
+	m.clearedFields[signature.FieldMerklePath] = struct{}{}
+}
+
+// MerklePathCleared returns if the "merkle_path" field was cleared in this mutation.
+func (m *SignatureMutation) MerklePathCleared() bool {
+	_, ok := m.clearedFields[signature.FieldMerklePath]
+	return ok
+}
+
+// ResetMerklePath resets all changes to the "merkle_path" field.
+func (m *SignatureMutation) ResetMerklePath() {
+	m.merkle_path = nil
+	m.appendmerkle_path = nil
+	delete(m.clearedFields, signature.FieldMerklePath)
+}
+
+// SetLedgerSeq sets the "ledger_seq" field.
+func (m *SignatureMutation) SetLedgerSeq(i int) {
+	m.ledger = &i
+}
+
+// LedgerSeq returns the value of the "ledger_seq" field in the mutation.
+func (m *SignatureMutation) LedgerSeq() (r int, exists bool) {
+	v := m.ledger
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLedgerSeq returns the old "ledger_seq" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldLedgerSeq(ctx context.Context) (v *int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLedgerSeq is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLedgerSeq requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLedgerSeq: %w", err)
+	}
+	return oldValue.LedgerSeq, nil
+}
+
+// ClearLedgerSeq clears the value of the "ledger_seq" field.
+func (m *SignatureMutation) ClearLedgerSeq() {
+	m.ledger = nil
+	m.clearedFields[signature.FieldLedgerSeq] = struct{}{}
+}
+
+// LedgerSeqCleared returns if the "ledger_seq" field was cleared in this mutation.
+func (m *SignatureMutation) LedgerSeqCleared() bool {
+	_, ok := m.clearedFields[signature.FieldLedgerSeq]
+	return ok
+}
+
+// ResetLedgerSeq resets all changes to the "ledger_seq" field.
+func (m *SignatureMutation) ResetLedgerSeq() {
+	m.ledger = nil
+	delete(m.clearedFields, signature.FieldLedgerSeq)
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (m *SignatureMutation) SetInsertedAt(t time.Time) {
+	m.inserted_at = &t
+}
+
+// InsertedAt returns the value of the "inserted_at" field in the mutation.
+func (m *SignatureMutation) InsertedAt() (r time.Time, exists bool) {
+	v := m.inserted_at
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldInsertedAt returns the old "inserted_at" field's value of the Signature entity.
+// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *SignatureMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldInsertedAt requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldInsertedAt: %w", err)
+	}
+	return oldValue.InsertedAt, nil
+}
+
+// ResetInsertedAt resets all changes to the "inserted_at" field.
+func (m *SignatureMutation) ResetInsertedAt() {
+	m.inserted_at = nil
+}
+
+// ClearRecord clears the "record" edge to the Record entity.
+func (m *SignatureMutation) ClearRecord() {
+	m.clearedrecord = true
+	m.clearedFields[signature.FieldRecordID] = struct{}{}
+}
+
+// RecordCleared reports if the "record" edge to the Record entity was cleared.
+func (m *SignatureMutation) RecordCleared() bool {
+	return m.clearedrecord
+}
+
+// RecordIDs returns the "record" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// RecordID instead. It exists only for internal usage by the builders.
+func (m *SignatureMutation) RecordIDs() (ids []int) {
+	if id := m.record; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetRecord resets all changes to the "record" edge.
+func (m *SignatureMutation) ResetRecord() {
+	m.record = nil
+	m.clearedrecord = false
+}
+
+// SetLedgerID sets the "ledger" edge to the LedgerEntry entity by id.
+func (m *SignatureMutation) SetLedgerID(id int) {
+	m.ledger = &id
+}
+
+// ClearLedger clears the "ledger" edge to the LedgerEntry entity.
+func (m *SignatureMutation) ClearLedger() {
+	m.clearedledger = true
+	m.clearedFields[signature.FieldLedgerSeq] = struct{}{}
+}
+
+// LedgerCleared reports if the "ledger" edge to the LedgerEntry entity was cleared.
+func (m *SignatureMutation) LedgerCleared() bool {
+	return m.LedgerSeqCleared() || m.clearedledger
+}
+
+// LedgerID returns the "ledger" edge ID in the mutation.
+func (m *SignatureMutation) LedgerID() (id int, exists bool) {
+	if m.ledger != nil {
+		return *m.ledger, true
+	}
+	return
+}
+
+// LedgerIDs returns the "ledger" edge IDs in the mutation.
+// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
+// LedgerID instead. It exists only for internal usage by the builders.
+func (m *SignatureMutation) LedgerIDs() (ids []int) {
+	if id := m.ledger; id != nil {
+		ids = append(ids, *id)
+	}
+	return
+}
+
+// ResetLedger resets all changes to the "ledger" edge.
+func (m *SignatureMutation) ResetLedger() {
+	m.ledger = nil
+	m.clearedledger = false
+}
+
+// Where appends a list predicates to the SignatureMutation builder.
+func (m *SignatureMutation) Where(ps ...predicate.Signature) {
+	m.predicates = append(m.predicates, ps...)
+}
+
+// WhereP appends storage-level predicates to the SignatureMutation builder. Using this method,
+// users can use type-assertion to append predicates that do not depend on any generated package.
+func (m *SignatureMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Signature, len(ps))
+	for i := range ps {
+		p[i] = ps[i]
+	}
+	m.Where(p...)
+}
+
+// Op returns the operation name.
+func (m *SignatureMutation) Op() Op {
+	return m.op
+}
+
+// SetOp allows setting the mutation operation.
+func (m *SignatureMutation) SetOp(op Op) {
+	m.op = op
+}
+
+// Type returns the node type of this mutation (Signature).
+func (m *SignatureMutation) Type() string {
+	return m.typ
+}
+
+// Fields returns all fields that were changed during this mutation. Note that in
+// order to get all numeric fields that were incremented/decremented, call
+// AddedFields().
+func (m *SignatureMutation) Fields() []string {
+	fields := make([]string, 0, 12)
+	if m.tenant_id != nil {
+		fields = append(fields, signature.FieldTenantID)
+	}
+	if m.record != nil {
+		fields = append(fields, signature.FieldRecordID)
+	}
+	if m.key_id != nil {
+		fields = append(fields, signature.FieldKeyID)
+	}
+	if m.algorithm != nil {
+		fields = append(fields, signature.FieldAlgorithm)
+	}
+	if m.value != nil {
+		fields = append(fields, signature.FieldValue)
+	}
+	if m.timestamp_token != nil {
+		fields = append(fields, signature.FieldTimestampToken)
+	}
+	if m.merkle_root != nil {
+		fields = append(fields, signature.FieldMerkleRoot)
+	}
+	if m.merkle_leaf_index != nil {
+		fields = append(fields, signature.FieldMerkleLeafIndex)
+	}
+	if m.merkle_tree_size != nil {
+		fields = append(fields, signature.FieldMerkleTreeSize)
+	}
+	if m.merkle_path != nil {
+		fields = append(fields, signature.FieldMerklePath)
+	}
+	if m.ledger != nil {
+		fields = append(fields, signature.FieldLedgerSeq)
+	}
+	if m.inserted_at != nil {
+		fields = append(fields, signature.FieldInsertedAt)
+	}
+	return fields
+}
+
+// Field returns the value of a field with the given name. The second boolean
+// return value indicates that this field was not set, or was not defined in the
+// schema.
+func (m *SignatureMutation) Field(name string) (ent.Value, bool) {
+	switch name {
+	case signature.FieldTenantID:
+		return m.TenantID()
+	case signature.FieldRecordID:
+		return m.RecordID()
+	case signature.FieldKeyID:
+		return m.KeyID()
+	case signature.FieldAlgorithm:
+		return m.Algorithm()
+	case signature.FieldValue:
+		return m.Value()
+	case signature.FieldTimestampToken:
+		return m.TimestampToken()
+	case signature.FieldMerkleRoot:
+		return m.MerkleRoot()
+	case signature.FieldMerkleLeafIndex:
+		return m.MerkleLeafIndex()
+	case signature.FieldMerkleTreeSize:
+		return m.MerkleTreeSize()
+	case signature.FieldMerklePath:
+		return m.MerklePath()
+	case signature.FieldLedgerSeq:
+		return m.LedgerSeq()
+	case signature.FieldInsertedAt:
+		return m.InsertedAt()
+	}
+	return nil, false
+}
+
+// OldField returns the old value of the field from the database. An error is
+// returned if the mutation operation is not UpdateOne, or the query to the
+// database failed.
+func (m *SignatureMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+	switch name {
+	case signature.FieldTenantID:
+		return m.OldTenantID(ctx)
+	case signature.FieldRecordID:
+		return m.OldRecordID(ctx)
+	case signature.FieldKeyID:
+		return m.OldKeyID(ctx)
+	case signature.FieldAlgorithm:
+		return m.OldAlgorithm(ctx)
+	case signature.FieldValue:
+		return m.OldValue(ctx)
+	case signature.FieldTimestampToken:
+		return m.OldTimestampToken(ctx)
+	case signature.FieldMerkleRoot:
+		return m.OldMerkleRoot(ctx)
+	case signature.FieldMerkleLeafIndex:
+		return m.OldMerkleLeafIndex(ctx)
+	case signature.FieldMerkleTreeSize:
+		return m.OldMerkleTreeSize(ctx)
+	case signature.FieldMerklePath:
+		return m.OldMerklePath(ctx)
+	case signature.FieldLedgerSeq:
+		return m.OldLedgerSeq(ctx)
+	case signature.FieldInsertedAt:
+		return m.OldInsertedAt(ctx)
+	}
+	return nil, fmt.Errorf("unknown Signature field %s", name)
+}
+
+// SetField sets the value of a field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *SignatureMutation) SetField(name string, value ent.Value) error {
+	switch name {
+	case signature.FieldTenantID:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTenantID(v)
+		return nil
+	case signature.FieldRecordID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetRecordID(v)
+		return nil
+	case signature.FieldKeyID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetKeyID(v)
+		return nil
+	case signature.FieldAlgorithm:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAlgorithm(v)
+		return nil
+	case signature.FieldValue:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetValue(v)
+		return nil
+	case signature.FieldTimestampToken:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTimestampToken(v)
+		return nil
+	case signature.FieldMerkleRoot:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMerkleRoot(v)
+		return nil
+	case signature.FieldMerkleLeafIndex:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMerkleLeafIndex(v)
+		return nil
+	case signature.FieldMerkleTreeSize:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMerkleTreeSize(v)
+		return nil
+	case signature.FieldMerklePath:
+		v, ok := value.([]string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetMerklePath(v)
+		return nil
+	case signature.FieldLedgerSeq:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetLedgerSeq(v)
+		return nil
+	case signature.FieldInsertedAt:
+		v, ok := value.(time.Time)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetInsertedAt(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Signature field %s", name)
+}
+
+// AddedFields returns all numeric fields that were incremented/decremented during
+// this mutation.
+func (m *SignatureMutation) AddedFields() []string {
+	var fields []string
+	if m.addkey_id != nil {
+		fields = append(fields, signature.FieldKeyID)
+	}
+	if m.addmerkle_leaf_index != nil {
+		fields = append(fields, signature.FieldMerkleLeafIndex)
+	}
+	if m.addmerkle_tree_size != nil {
+		fields = append(fields, signature.FieldMerkleTreeSize)
+	}
+	return fields
+}
+
+// AddedField returns the numeric value that was incremented/decremented on a field
+// with the given name. The second boolean return value indicates that this field
+// was not set, or was not defined in the schema.
+func (m *SignatureMutation) AddedField(name string) (ent.Value, bool) {
+	switch name {
+	case signature.FieldKeyID:
+		return m.AddedKeyID()
+	case signature.FieldMerkleLeafIndex:
+		return m.AddedMerkleLeafIndex()
+	case signature.FieldMerkleTreeSize:
+		return m.AddedMerkleTreeSize()
+	}
+	return nil, false
+}
+
+// AddField adds the value to the field with the given name. It returns an error if
+// the field is not defined in the schema, or if the type mismatched the field
+// type.
+func (m *SignatureMutation) AddField(name string, value ent.Value) error {
+	switch name {
+	case signature.FieldKeyID:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddKeyID(v)
+		return nil
+	case signature.FieldMerkleLeafIndex:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMerkleLeafIndex(v)
+		return nil
+	case signature.FieldMerkleTreeSize:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddMerkleTreeSize(v)
+		return nil
+	}
+	return fmt.Errorf("unknown Signature numeric field %s", name)
+}
+
+// ClearedFields returns all nullable fields that were cleared during this
+// mutation.
+func (m *SignatureMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(signature.FieldTimestampToken) {
+		fields = append(fields, signature.FieldTimestampToken)
+	}
+	if m.FieldCleared(signature.FieldMerkleRoot) {
+		fields = append(fields, signature.FieldMerkleRoot)
+	}
+	if m.FieldCleared(signature.FieldMerkleLeafIndex) {
+		fields = append(fields, signature.FieldMerkleLeafIndex)
+	}
+	if m.FieldCleared(signature.FieldMerkleTreeSize) {
+		fields = append(fields, signature.FieldMerkleTreeSize)
+	}
+	if m.FieldCleared(signature.FieldMerklePath) {
+		fields = append(fields, signature.FieldMerklePath)
+	}
+	if m.FieldCleared(signature.FieldLedgerSeq) {
+		fields = append(fields, signature.FieldLedgerSeq)
+	}
+	return fields
+}
+
+// FieldCleared returns a boolean indicating if a field with the given name was
+// cleared in this mutation.
+func (m *SignatureMutation) FieldCleared(name string) bool {
+	_, ok := m.clearedFields[name]
+	return ok
+}
+
+// ClearField clears the value of the field with the given name. It returns an
+// error if the field is not defined in the schema.
+func (m *SignatureMutation) ClearField(name string) error {
+	switch name {
+	case signature.FieldTimestampToken:
+		m.ClearTimestampToken()
+		return nil
+	case signature.FieldMerkleRoot:
+		m.ClearMerkleRoot()
+		return nil
+	case signature.FieldMerkleLeafIndex:
+		m.ClearMerkleLeafIndex()
+		return nil
+	case signature.FieldMerkleTreeSize:
+		m.ClearMerkleTreeSize()
+		return nil
+	case signature.FieldMerklePath:
+		m.ClearMerklePath()
+		return nil
+	case signature.FieldLedgerSeq:
+		m.ClearLedgerSeq()
+		return nil
+	}
+	return fmt.Errorf("unknown Signature nullable field %s", name)
+}
+
+// ResetField resets all changes in the mutation for the field with the given name.
+// It returns an error if the field is not defined in the schema.
+func (m *SignatureMutation) ResetField(name string) error {
+	switch name {
+	case signature.FieldTenantID:
+		m.ResetTenantID()
+		return nil
+	case signature.FieldRecordID:
+		m.ResetRecordID()
+		return nil
+	case signature.FieldKeyID:
+		m.ResetKeyID()
+		return nil
+	case signature.FieldAlgorithm:
+		m.ResetAlgorithm()
+		return nil
+	case signature.FieldValue:
+		m.ResetValue()
+		return nil
+	case signature.FieldTimestampToken:
+		m.ResetTimestampToken()
+		return nil
+	case signature.FieldMerkleRoot:
+		m.ResetMerkleRoot()
+		return nil
+	case signature.FieldMerkleLeafIndex:
+		m.ResetMerkleLeafIndex()
+		return nil
+	case signature.FieldMerkleTreeSize:
+		m.ResetMerkleTreeSize()
+		return nil
+	case signature.FieldMerklePath:
+		m.ResetMerklePath()
+		return nil
+	case signature.FieldLedgerSeq:
+		m.ResetLedgerSeq()
+		return nil
+	case signature.FieldInsertedAt:
+		m.ResetInsertedAt()
+		return nil
+	}
+	return fmt.Errorf("unknown Signature field %s", name)
+}
+
+// AddedEdges returns all edge names that were set/added in this mutation.
+func (m *SignatureMutation) AddedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.record != nil {
+		edges = append(edges, signature.EdgeRecord)
+	}
+	if m.ledger != nil {
+		edges = append(edges, signature.EdgeLedger)
+	}
+	return edges
+}
+
+// AddedIDs returns all IDs (to other nodes) that were added for the given edge
+// name in this mutation.
+func (m *SignatureMutation) AddedIDs(name string) []ent.Value {
+	switch name {
+	case signature.EdgeRecord:
+		if id := m.record; id != nil {
+			return []ent.Value{*id}
+		}
+	case signature.EdgeLedger:
+		if id := m.ledger; id != nil {
+			return []ent.Value{*id}
+		}
+	}
+	return nil
+}
+
+// RemovedEdges returns all edge names that were removed in this mutation.
+func (m *SignatureMutation) RemovedEdges() []string {
+	edges := make([]string, 0, 2)
+	return edges
+}
+
+// RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
+// the given name in this mutation.
+func (m *SignatureMutation) RemovedIDs(name string) []ent.Value {
+	return nil
+}
+
+// ClearedEdges returns all edge names that were cleared in this mutation.
+func (m *SignatureMutation) ClearedEdges() []string {
+	edges := make([]string, 0, 2)
+	if m.clearedrecord {
+		edges = append(edges, signature.EdgeRecord)
+	}
+	if m.clearedledger {
+		edges = append(edges, signature.EdgeLedger)
+	}
+	return edges
+}
+
+// EdgeCleared returns a boolean which indicates if the edge with the given name
+// was cleared in this mutation.
+func (m *SignatureMutation) EdgeCleared(name string) bool {
+	switch name {
+	case signature.EdgeRecord:
+		return m.clearedrecord
+	case signature.EdgeLedger:
+		return m.clearedledger
+	}
+	return false
+}
+
+// ClearEdge clears the value of the edge with the given name. It returns an error
+// if that edge is not defined in the schema.
+func (m *SignatureMutation) ClearEdge(name string) error {
+	switch name {
+	case signature.EdgeRecord:
+		m.ClearRecord()
+		return nil
+	case signature.EdgeLedger:
+		m.ClearLedger()
+		return nil
+	}
+	return fmt.Errorf("unknown Signature unique edge %s", name)
+}
+
+// ResetEdge resets all changes to the edge with the given name in this mutation.
+// It returns an error if the edge is not defined in the schema.
+func (m *SignatureMutation) ResetEdge(name string) error {
+	switch name {
+	case signature.EdgeRecord:
+		m.ResetRecord()
+		return nil
+	case signature.EdgeLedger:
+		m.ResetLedger()
+		return nil
+	}
+	return fmt.Errorf("unknown Signature edge %s", name)
+}
+
+// WebhookMutation represents an operation that mutates the Webhook nodes in the graph.
+type WebhookMutation struct {
+	config
+	op                Op
+	typ               string
+	id                *int
+	tenant_id         *string
+	url               *string
+	secret            *string
+	active            *bool
+	inserted_at       *time.Time
+	clearedFields     map[string]struct{}
+	deliveries        map[int]struct{}
+	removeddeliveries map[int]struct{}
+	cleareddeliveries bool
+	done              bool
+	oldValue          func(context.Context) (*Webhook, error)
+	predicates        []predicate.Webhook
 }
 
-var _ ent.Mutation = (*RecordMutation)(nil)
+var _ ent.Mutation = (*WebhookMutation)(nil)
 
-// recordOption allows management of the mutation configuration using functional options.
-type recordOption func(*RecordMutation)
+// webhookOption allows management of the mutation configuration using functional options.
+type webhookOption func(*WebhookMutation)
 
-// newRecordMutation creates new mutation for the Record entity.
-func newRecordMutation(c config, op Op, opts ...recordOption) *RecordMutation {
-	m := &RecordMutation{
+// newWebhookMutation creates new mutation for the Webhook entity.
+func newWebhookMutation(c config, op Op, opts ...webhookOption) *WebhookMutation {
+	m := &WebhookMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeRecord,
+		typ:           TypeWebhook,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -63,20 +3213,20 @@ func newRecordMutation(c config, op Op, opts ...recordOption) *RecordMutation {
 	return m
 }
 
-// withRecordID sets the ID field of the mutation.
-func withRecordID(id int) recordOption {
-	return func(m *RecordMutation) {
+// withWebhookID sets the ID field of the mutation.
+func withWebhookID(id int) webhookOption {
+	return func(m *WebhookMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Record
+			value *Webhook
 		)
-		m.oldValue = func(ctx context.Context) (*Record, error) {
+		m.oldValue = func(ctx context.Context) (*Webhook, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Record.Get(ctx, id)
+					value, err = m.Client().Webhook.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -85,76 +3235,214 @@ func withRecordID(id int) recordOption {
 	}
 }
 
-// withRecord sets the old Record of the mutation.
-func withRecord(node *Record) recordOption {
-	return func(m *RecordMutation) {
-		m.oldValue = func(context.Context) (*Record, error) {
-			return node, nil
-		}
-		m.id = &node.ID
+// withWebhook sets the old Webhook of the mutation.
+func withWebhook(node *Webhook) webhookOption {
+	return func(m *WebhookMutation) {
+		m.oldValue = func(context.Context) (*Webhook, error) {
+			return node, nil
+		}
+		m.id = &node.ID
+	}
+}
+
+// Client returns a new `ent.Client` from the mutation. If the mutation was
+// executed in a transaction (ent.Tx), a transactional client is returned.
+func (m WebhookMutation) Client() *Client {
+	client := &Client{config: m.config}
+	client.init()
+	return client
+}
+
+// Tx returns an `ent.Tx` for mutations that were executed in transactions;
+// it returns an error otherwise.
+func (m WebhookMutation) Tx() (*Tx, error) {
+	if _, ok := m.driver.(*txDriver); !ok {
+		return nil, errors.New("database: mutation is not running in a transaction")
+	}
+	tx := &Tx{config: m.config}
+	tx.init()
+	return tx, nil
+}
+
+// ID returns the ID value in the mutation. Note that the ID is only available
+// if it was provided to the builder or after it was returned from the database.
+func (m *WebhookMutation) ID() (id int, exists bool) {
+	if m.id == nil {
+		return
+	}
+	return *m.id, true
+}
+
+// IDs queries the database and returns the entity ids that match the mutation's predicate.
+// That means, if the mutation is applied within a transaction with an isolation level such
+// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
+// or updated by the mutation.
+func (m *WebhookMutation) IDs(ctx context.Context) ([]int, error) {
+	switch {
+	case m.op.Is(OpUpdateOne | OpDeleteOne):
+		id, exists := m.ID()
+		if exists {
+			return []int{id}, nil
+		}
+		fallthrough
+	case m.op.Is(OpUpdate | OpDelete):
+		return m.Client().Webhook.Query().Where(m.predicates...).IDs(ctx)
+	default:
+		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+	}
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (m *WebhookMutation) SetTenantID(s string) {
+	m.tenant_id = &s
+}
+
+// TenantID returns the value of the "tenant_id" field in the mutation.
+func (m *WebhookMutation) TenantID() (r string, exists bool) {
+	v := m.tenant_id
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldTenantID returns the old "tenant_id" field's value of the Webhook entity.
+// If the Webhook object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookMutation) OldTenantID(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldTenantID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldTenantID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldTenantID: %w", err)
+	}
+	return oldValue.TenantID, nil
+}
+
+// ResetTenantID resets all changes to the "tenant_id" field.
+func (m *WebhookMutation) ResetTenantID() {
+	m.tenant_id = nil
+}
+
+// SetURL sets the "url" field.
+func (m *WebhookMutation) SetURL(s string) {
+	m.url = &s
+}
+
+// URL returns the value of the "url" field in the mutation.
+func (m *WebhookMutation) URL() (r string, exists bool) {
+	v := m.url
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldURL returns the old "url" field's value of the Webhook entity.
+// If the Webhook object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookMutation) OldURL(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldURL is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldURL requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldURL: %w", err)
+	}
+	return oldValue.URL, nil
+}
+
+// ResetURL resets all changes to the "url" field.
+func (m *WebhookMutation) ResetURL() {
+	m.url = nil
+}
+
+// SetSecret sets the "secret" field.
+func (m *WebhookMutation) SetSecret(s string) {
+	m.secret = &s
+}
+
+// Secret returns the value of the "secret" field in the mutation.
+func (m *WebhookMutation) Secret() (r string, exists bool) {
+	v := m.secret
+	if v == nil {
+		return
 	}
+	return *v, true
 }
 
-// Client returns a new `ent.Client` from the mutation. If the mutation was
-// executed in a transaction (ent.Tx), a transactional client is returned.
-func (m RecordMutation) Client() *Client {
-	client := &Client{config: m.config}
-	client.init()
-	return client
+// OldSecret returns the old "secret" field's value of the Webhook entity.
+// If the Webhook object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookMutation) OldSecret(ctx context.Context) (v string, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSecret is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSecret requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSecret: %w", err)
+	}
+	return oldValue.Secret, nil
 }
 
-// Tx returns an `ent.Tx` for mutations that were executed in transactions;
-// it returns an error otherwise.
-func (m RecordMutation) Tx() (*Tx, error) {
-	if _, ok := m.driver.(*txDriver); !ok {
-		return nil, errors.New("database: mutation is not running in a transaction")
-	}
-	tx := &Tx{config: m.config}
-	tx.init()
-	return tx, nil
+// ResetSecret resets all changes to the "secret" field.
+func (m *WebhookMutation) ResetSecret() {
+	m.secret = nil
 }
 
-// SetID sets the value of the id field. Note that this
-// operation is only accepted on creation of Record entities.
-func (m *RecordMutation) SetID(id int) {
-	m.id = &id
+// SetActive sets the "active" field.
+func (m *WebhookMutation) SetActive(b bool) {
+	m.active = &b
 }
 
-// ID returns the ID value in the mutation. Note that the ID is only available
-// if it was provided to the builder or after it was returned from the database.
-func (m *RecordMutation) ID() (id int, exists bool) {
-	if m.id == nil {
+// Active returns the value of the "active" field in the mutation.
+func (m *WebhookMutation) Active() (r bool, exists bool) {
+	v := m.active
+	if v == nil {
 		return
 	}
-	return *m.id, true
+	return *v, true
 }
 
-// IDs queries the database and returns the entity ids that match the mutation's predicate.
-// That means, if the mutation is applied within a transaction with an isolation level such
-// as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
-// or updated by the mutation.
-func (m *RecordMutation) IDs(ctx context.Context) ([]int, error) {
-	switch {
-	case m.op.Is(OpUpdateOne | OpDeleteOne):
-		id, exists := m.ID()
-		if exists {
-			return []int{id}, nil
-		}
-		fallthrough
-	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Record.Query().Where(m.predicates...).IDs(ctx)
-	default:
-		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
+// OldActive returns the old "active" field's value of the Webhook entity.
+// If the Webhook object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookMutation) OldActive(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldActive is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldActive requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldActive: %w", err)
 	}
+	return oldValue.Active, nil
+}
+
+// ResetActive resets all changes to the "active" field.
+func (m *WebhookMutation) ResetActive() {
+	m.active = nil
 }
 
 // SetInsertedAt sets the "inserted_at" field.
-func (m *RecordMutation) SetInsertedAt(t time.Time) {
+func (m *WebhookMutation) SetInsertedAt(t time.Time) {
 	m.inserted_at = &t
 }
 
 // InsertedAt returns the value of the "inserted_at" field in the mutation.
-func (m *RecordMutation) InsertedAt() (r time.Time, exists bool) {
+func (m *WebhookMutation) InsertedAt() (r time.Time, exists bool) {
 	v := m.inserted_at
 	if v == nil {
 		return
@@ -162,10 +3450,10 @@ func (m *RecordMutation) InsertedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldInsertedAt returns the old "inserted_at" field's value of the Record entity.
-// If the Record object wasn't provided to the builder, the object is fetched from the database.
+// OldInsertedAt returns the old "inserted_at" field's value of the Webhook entity.
+// If the Webhook object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *RecordMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+func (m *WebhookMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
 	}
@@ -180,58 +3468,73 @@ func (m *RecordMutation) OldInsertedAt(ctx context.Context) (v time.Time, err er
 }
 
 // ResetInsertedAt resets all changes to the "inserted_at" field.
-func (m *RecordMutation) ResetInsertedAt() {
+func (m *WebhookMutation) ResetInsertedAt() {
 	m.inserted_at = nil
 }
 
-// SetSignatureID sets the "signature" edge to the Signature entity by id.
-func (m *RecordMutation) SetSignatureID(id int) {
-	m.signature = &id
+// AddDeliveryIDs adds the "deliveries" edge to the WebhookDelivery entity by ids.
+func (m *WebhookMutation) AddDeliveryIDs(ids ...int) {
+	if m.deliveries == nil {
+		m.deliveries = make(map[int]struct{})
+	}
+	for i := range ids {
+		m.deliveries[ids[i]] = struct{}{}
+	}
 }
 
-// ClearSignature clears the "signature" edge to the Signature entity.
-func (m *RecordMutation) ClearSignature() {
-	m.clearedsignature = true
+// ClearDeliveries clears the "deliveries" edge to the WebhookDelivery entity.
+func (m *WebhookMutation) ClearDeliveries() {
+	m.cleareddeliveries = true
 }
 
-// SignatureCleared reports if the "signature" edge to the Signature entity was cleared.
-func (m *RecordMutation) SignatureCleared() bool {
-	return m.clearedsignature
+// DeliveriesCleared reports if the "deliveries" edge to the WebhookDelivery entity was cleared.
+func (m *WebhookMutation) DeliveriesCleared() bool {
+	return m.cleareddeliveries
 }
 
-// SignatureID returns the "signature" edge ID in the mutation.
-func (m *RecordMutation) SignatureID() (id int, exists bool) {
-	if m.signature != nil {
-		return *m.signature, true
+// RemoveDeliveryIDs removes the "deliveries" edge to the WebhookDelivery entity by IDs.
+func (m *WebhookMutation) RemoveDeliveryIDs(ids ...int) {
+	if m.removeddeliveries == nil {
+		m.removeddeliveries = make(map[int]struct{})
+	}
+	for i := range ids {
+		delete(m.deliveries, ids[i])
+		m.removeddeliveries[ids[i]] = struct{}{}
+	}
+}
+
+// RemovedDeliveries returns the removed IDs of the "deliveries" edge to the WebhookDelivery entity.
+func (m *WebhookMutation) RemovedDeliveriesIDs() (ids []int) {
+	for id := range m.removeddeliveries {
+		ids = append(ids, id)
 	}
 	return
 }
 
-// SignatureIDs returns the "signature" edge IDs in the mutation.
-// Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// SignatureID instead. It exists only for internal usage by the builders.
-func (m *RecordMutation) SignatureIDs() (ids []int) {
-	if id := m.signature; id != nil {
-		ids = append(ids, *id)
+// DeliveriesIDs returns the "deliveries" edge IDs in the mutation.
+func (m *WebhookMutation) DeliveriesIDs() (ids []int) {
+	for id := range m.deliveries {
+		ids = append(ids, id)
 	}
 	return
 }
 
-// ResetSignature resets all changes to the "signature" edge.
-func (m *RecordMutation) ResetSignature() {
-	m.signature = nil
-	m.clearedsignature = false
+// ResetDeliveries resets all changes to the "deliveries" edge.
+func (m *WebhookMutation) ResetDeliveries() {
+	m.deliveries = nil
+	m.cleareddeliveries = false
+	m.removeddeliveries = nil
 }
 
-// Where appends a list predicates to the RecordMutation builder.
-func (m *RecordMutation) Where(ps ...predicate.Record) {
+// Where appends a list predicates to the WebhookMutation builder.
+func (m *WebhookMutation) Where(ps ...predicate.Webhook) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the RecordMutation builder. Using this method,
+// WhereP appends storage-level predicates to the WebhookMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *RecordMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Record, len(ps))
+func (m *WebhookMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.Webhook, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -239,27 +3542,39 @@ func (m *RecordMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *RecordMutation) Op() Op {
+func (m *WebhookMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *RecordMutation) SetOp(op Op) {
+func (m *WebhookMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Record).
-func (m *RecordMutation) Type() string {
+// Type returns the node type of this mutation (Webhook).
+func (m *WebhookMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *RecordMutation) Fields() []string {
-	fields := make([]string, 0, 1)
+func (m *WebhookMutation) Fields() []string {
+	fields := make([]string, 0, 5)
+	if m.tenant_id != nil {
+		fields = append(fields, webhook.FieldTenantID)
+	}
+	if m.url != nil {
+		fields = append(fields, webhook.FieldURL)
+	}
+	if m.secret != nil {
+		fields = append(fields, webhook.FieldSecret)
+	}
+	if m.active != nil {
+		fields = append(fields, webhook.FieldActive)
+	}
 	if m.inserted_at != nil {
-		fields = append(fields, record.FieldInsertedAt)
+		fields = append(fields, webhook.FieldInsertedAt)
 	}
 	return fields
 }
@@ -267,9 +3582,17 @@ func (m *RecordMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *RecordMutation) Field(name string) (ent.Value, bool) {
+func (m *WebhookMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case record.FieldInsertedAt:
+	case webhook.FieldTenantID:
+		return m.TenantID()
+	case webhook.FieldURL:
+		return m.URL()
+	case webhook.FieldSecret:
+		return m.Secret()
+	case webhook.FieldActive:
+		return m.Active()
+	case webhook.FieldInsertedAt:
 		return m.InsertedAt()
 	}
 	return nil, false
@@ -278,20 +3601,56 @@ func (m *RecordMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *RecordMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *WebhookMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case record.FieldInsertedAt:
+	case webhook.FieldTenantID:
+		return m.OldTenantID(ctx)
+	case webhook.FieldURL:
+		return m.OldURL(ctx)
+	case webhook.FieldSecret:
+		return m.OldSecret(ctx)
+	case webhook.FieldActive:
+		return m.OldActive(ctx)
+	case webhook.FieldInsertedAt:
 		return m.OldInsertedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Record field %s", name)
+	return nil, fmt.Errorf("unknown Webhook field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *RecordMutation) SetField(name string, value ent.Value) error {
+func (m *WebhookMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case record.FieldInsertedAt:
+	case webhook.FieldTenantID:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetTenantID(v)
+		return nil
+	case webhook.FieldURL:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetURL(v)
+		return nil
+	case webhook.FieldSecret:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSecret(v)
+		return nil
+	case webhook.FieldActive:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetActive(v)
+		return nil
+	case webhook.FieldInsertedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
@@ -299,164 +3658,191 @@ func (m *RecordMutation) SetField(name string, value ent.Value) error {
 		m.SetInsertedAt(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Record field %s", name)
+	return fmt.Errorf("unknown Webhook field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *RecordMutation) AddedFields() []string {
+func (m *WebhookMutation) AddedFields() []string {
 	return nil
 }
 
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *RecordMutation) AddedField(name string) (ent.Value, bool) {
+func (m *WebhookMutation) AddedField(name string) (ent.Value, bool) {
 	return nil, false
 }
 
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *RecordMutation) AddField(name string, value ent.Value) error {
+func (m *WebhookMutation) AddField(name string, value ent.Value) error {
 	switch name {
 	}
-	return fmt.Errorf("unknown Record numeric field %s", name)
+	return fmt.Errorf("unknown Webhook numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *RecordMutation) ClearedFields() []string {
+func (m *WebhookMutation) ClearedFields() []string {
 	return nil
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *RecordMutation) FieldCleared(name string) bool {
+func (m *WebhookMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *RecordMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Record nullable field %s", name)
+func (m *WebhookMutation) ClearField(name string) error {
+	return fmt.Errorf("unknown Webhook nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *RecordMutation) ResetField(name string) error {
+func (m *WebhookMutation) ResetField(name string) error {
 	switch name {
-	case record.FieldInsertedAt:
+	case webhook.FieldTenantID:
+		m.ResetTenantID()
+		return nil
+	case webhook.FieldURL:
+		m.ResetURL()
+		return nil
+	case webhook.FieldSecret:
+		m.ResetSecret()
+		return nil
+	case webhook.FieldActive:
+		m.ResetActive()
+		return nil
+	case webhook.FieldInsertedAt:
 		m.ResetInsertedAt()
 		return nil
 	}
-	return fmt.Errorf("unknown Record field %s", name)
+	return fmt.Errorf("unknown Webhook field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *RecordMutation) AddedEdges() []string {
+func (m *WebhookMutation) AddedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.signature != nil {
-		edges = append(edges, record.EdgeSignature)
+	if m.deliveries != nil {
+		edges = append(edges, webhook.EdgeDeliveries)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *RecordMutation) AddedIDs(name string) []ent.Value {
+func (m *WebhookMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case record.EdgeSignature:
-		if id := m.signature; id != nil {
-			return []ent.Value{*id}
+	case webhook.EdgeDeliveries:
+		ids := make([]ent.Value, 0, len(m.deliveries))
+		for id := range m.deliveries {
+			ids = append(ids, id)
 		}
+		return ids
 	}
 	return nil
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *RecordMutation) RemovedEdges() []string {
+func (m *WebhookMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 1)
+	if m.removeddeliveries != nil {
+		edges = append(edges, webhook.EdgeDeliveries)
+	}
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *RecordMutation) RemovedIDs(name string) []ent.Value {
+func (m *WebhookMutation) RemovedIDs(name string) []ent.Value {
+	switch name {
+	case webhook.EdgeDeliveries:
+		ids := make([]ent.Value, 0, len(m.removeddeliveries))
+		for id := range m.removeddeliveries {
+			ids = append(ids, id)
+		}
+		return ids
+	}
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *RecordMutation) ClearedEdges() []string {
+func (m *WebhookMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.clearedsignature {
-		edges = append(edges, record.EdgeSignature)
+	if m.cleareddeliveries {
+		edges = append(edges, webhook.EdgeDeliveries)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *RecordMutation) EdgeCleared(name string) bool {
+func (m *WebhookMutation) EdgeCleared(name string) bool {
 	switch name {
-	case record.EdgeSignature:
-		return m.clearedsignature
+	case webhook.EdgeDeliveries:
+		return m.cleareddeliveries
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *RecordMutation) ClearEdge(name string) error {
+func (m *WebhookMutation) ClearEdge(name string) error {
 	switch name {
-	case record.EdgeSignature:
-		m.ClearSignature()
-		return nil
 	}
-	return fmt.Errorf("unknown Record unique edge %s", name)
+	return fmt.Errorf("unknown Webhook unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *RecordMutation) ResetEdge(name string) error {
+func (m *WebhookMutation) ResetEdge(name string) error {
 	switch name {
-	case record.EdgeSignature:
-		m.ResetSignature()
+	case webhook.EdgeDeliveries:
+		m.ResetDeliveries()
 		return nil
 	}
-	return fmt.Errorf("unknown Record edge %s", name)
+	return fmt.Errorf("unknown Webhook edge %s", name)
 }
 
-// SignatureMutation represents an operation that mutates the Signature nodes in the graph.
-type SignatureMutation struct {
+// WebhookDeliveryMutation represents an operation that mutates the WebhookDelivery nodes in the graph.
+type WebhookDeliveryMutation struct {
 	config
-	op            Op
-	typ           string
-	id            *int
-	key_id        *int
-	addkey_id     *int
-	value         *string
-	inserted_at   *time.Time
-	clearedFields map[string]struct{}
-	record        *int
-	clearedrecord bool
-	done          bool
-	oldValue      func(context.Context) (*Signature, error)
-	predicates    []predicate.Signature
+	op             Op
+	typ            string
+	id             *int
+	ledger_seq     *int
+	addledger_seq  *int
+	attempt        *int
+	addattempt     *int
+	status_code    *int
+	addstatus_code *int
+	error          *string
+	succeeded      *bool
+	inserted_at    *time.Time
+	clearedFields  map[string]struct{}
+	webhook        *int
+	clearedwebhook bool
+	done           bool
+	oldValue       func(context.Context) (*WebhookDelivery, error)
+	predicates     []predicate.WebhookDelivery
 }
 
-var _ ent.Mutation = (*SignatureMutation)(nil)
+var _ ent.Mutation = (*WebhookDeliveryMutation)(nil)
 
-// signatureOption allows management of the mutation configuration using functional options.
-type signatureOption func(*SignatureMutation)
+// webhookdeliveryOption allows management of the mutation configuration using functional options.
+type webhookdeliveryOption func(*WebhookDeliveryMutation)
 
-// newSignatureMutation creates new mutation for the Signature entity.
-func newSignatureMutation(c config, op Op, opts ...signatureOption) *SignatureMutation {
-	m := &SignatureMutation{
+// newWebhookDeliveryMutation creates new mutation for the WebhookDelivery entity.
+func newWebhookDeliveryMutation(c config, op Op, opts ...webhookdeliveryOption) *WebhookDeliveryMutation {
+	m := &WebhookDeliveryMutation{
 		config:        c,
 		op:            op,
-		typ:           TypeSignature,
+		typ:           TypeWebhookDelivery,
 		clearedFields: make(map[string]struct{}),
 	}
 	for _, opt := range opts {
@@ -465,20 +3851,20 @@ func newSignatureMutation(c config, op Op, opts ...signatureOption) *SignatureMu
 	return m
 }
 
-// withSignatureID sets the ID field of the mutation.
-func withSignatureID(id int) signatureOption {
-	return func(m *SignatureMutation) {
+// withWebhookDeliveryID sets the ID field of the mutation.
+func withWebhookDeliveryID(id int) webhookdeliveryOption {
+	return func(m *WebhookDeliveryMutation) {
 		var (
 			err   error
 			once  sync.Once
-			value *Signature
+			value *WebhookDelivery
 		)
-		m.oldValue = func(ctx context.Context) (*Signature, error) {
+		m.oldValue = func(ctx context.Context) (*WebhookDelivery, error) {
 			once.Do(func() {
 				if m.done {
 					err = errors.New("querying old values post mutation is not allowed")
 				} else {
-					value, err = m.Client().Signature.Get(ctx, id)
+					value, err = m.Client().WebhookDelivery.Get(ctx, id)
 				}
 			})
 			return value, err
@@ -487,10 +3873,10 @@ func withSignatureID(id int) signatureOption {
 	}
 }
 
-// withSignature sets the old Signature of the mutation.
-func withSignature(node *Signature) signatureOption {
-	return func(m *SignatureMutation) {
-		m.oldValue = func(context.Context) (*Signature, error) {
+// withWebhookDelivery sets the old WebhookDelivery of the mutation.
+func withWebhookDelivery(node *WebhookDelivery) webhookdeliveryOption {
+	return func(m *WebhookDeliveryMutation) {
+		m.oldValue = func(context.Context) (*WebhookDelivery, error) {
 			return node, nil
 		}
 		m.id = &node.ID
@@ -499,7 +3885,7 @@ func withSignature(node *Signature) signatureOption {
 
 // Client returns a new `ent.Client` from the mutation. If the mutation was
 // executed in a transaction (ent.Tx), a transactional client is returned.
-func (m SignatureMutation) Client() *Client {
+func (m WebhookDeliveryMutation) Client() *Client {
 	client := &Client{config: m.config}
 	client.init()
 	return client
@@ -507,7 +3893,7 @@ func (m SignatureMutation) Client() *Client {
 
 // Tx returns an `ent.Tx` for mutations that were executed in transactions;
 // it returns an error otherwise.
-func (m SignatureMutation) Tx() (*Tx, error) {
+func (m WebhookDeliveryMutation) Tx() (*Tx, error) {
 	if _, ok := m.driver.(*txDriver); !ok {
 		return nil, errors.New("database: mutation is not running in a transaction")
 	}
@@ -518,7 +3904,7 @@ func (m SignatureMutation) Tx() (*Tx, error) {
 
 // ID returns the ID value in the mutation. Note that the ID is only available
 // if it was provided to the builder or after it was returned from the database.
-func (m *SignatureMutation) ID() (id int, exists bool) {
+func (m *WebhookDeliveryMutation) ID() (id int, exists bool) {
 	if m.id == nil {
 		return
 	}
@@ -529,7 +3915,7 @@ func (m *SignatureMutation) ID() (id int, exists bool) {
 // That means, if the mutation is applied within a transaction with an isolation level such
 // as sql.LevelSerializable, the returned ids match the ids of the rows that will be updated
 // or updated by the mutation.
-func (m *SignatureMutation) IDs(ctx context.Context) ([]int, error) {
+func (m *WebhookDeliveryMutation) IDs(ctx context.Context) ([]int, error) {
 	switch {
 	case m.op.Is(OpUpdateOne | OpDeleteOne):
 		id, exists := m.ID()
@@ -538,147 +3924,322 @@ func (m *SignatureMutation) IDs(ctx context.Context) ([]int, error) {
 		}
 		fallthrough
 	case m.op.Is(OpUpdate | OpDelete):
-		return m.Client().Signature.Query().Where(m.predicates...).IDs(ctx)
+		return m.Client().WebhookDelivery.Query().Where(m.predicates...).IDs(ctx)
 	default:
 		return nil, fmt.Errorf("IDs is not allowed on %s operations", m.op)
 	}
 }
 
-// SetRecordID sets the "record_id" field.
-func (m *SignatureMutation) SetRecordID(i int) {
-	m.record = &i
+// SetWebhookID sets the "webhook_id" field.
+func (m *WebhookDeliveryMutation) SetWebhookID(i int) {
+	m.webhook = &i
+}
+
+// WebhookID returns the value of the "webhook_id" field in the mutation.
+func (m *WebhookDeliveryMutation) WebhookID() (r int, exists bool) {
+	v := m.webhook
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldWebhookID returns the old "webhook_id" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookDeliveryMutation) OldWebhookID(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldWebhookID is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldWebhookID requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldWebhookID: %w", err)
+	}
+	return oldValue.WebhookID, nil
+}
+
+// ResetWebhookID resets all changes to the "webhook_id" field.
+func (m *WebhookDeliveryMutation) ResetWebhookID() {
+	m.webhook = nil
+}
+
+// SetLedgerSeq sets the "ledger_seq" field.
+func (m *WebhookDeliveryMutation) SetLedgerSeq(i int) {
+	m.ledger_seq = &i
+	m.addledger_seq = nil
+}
+
+// LedgerSeq returns the value of the "ledger_seq" field in the mutation.
+func (m *WebhookDeliveryMutation) LedgerSeq() (r int, exists bool) {
+	v := m.ledger_seq
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldLedgerSeq returns the old "ledger_seq" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookDeliveryMutation) OldLedgerSeq(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldLedgerSeq is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldLedgerSeq requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldLedgerSeq: %w", err)
+	}
+	return oldValue.LedgerSeq, nil
+}
+
+// AddLedgerSeq adds i to the "ledger_seq" field.
+func (m *WebhookDeliveryMutation) AddLedgerSeq(i int) {
+	if m.addledger_seq != nil {
+		*m.addledger_seq += i
+	} else {
+		m.addledger_seq = &i
+	}
+}
+
+// AddedLedgerSeq returns the value that was added to the "ledger_seq" field in this mutation.
+func (m *WebhookDeliveryMutation) AddedLedgerSeq() (r int, exists bool) {
+	v := m.addledger_seq
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// RecordID returns the value of the "record_id" field in the mutation.
-func (m *SignatureMutation) RecordID() (r int, exists bool) {
-	v := m.record
+// ResetLedgerSeq resets all changes to the "ledger_seq" field.
+func (m *WebhookDeliveryMutation) ResetLedgerSeq() {
+	m.ledger_seq = nil
+	m.addledger_seq = nil
+}
+
+// SetAttempt sets the "attempt" field.
+func (m *WebhookDeliveryMutation) SetAttempt(i int) {
+	m.attempt = &i
+	m.addattempt = nil
+}
+
+// Attempt returns the value of the "attempt" field in the mutation.
+func (m *WebhookDeliveryMutation) Attempt() (r int, exists bool) {
+	v := m.attempt
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldRecordID returns the old "record_id" field's value of the Signature entity.
-// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// OldAttempt returns the old "attempt" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *SignatureMutation) OldRecordID(ctx context.Context) (v int, err error) {
+func (m *WebhookDeliveryMutation) OldAttempt(ctx context.Context) (v int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldRecordID is only allowed on UpdateOne operations")
+		return v, errors.New("OldAttempt is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldRecordID requires an ID field in the mutation")
+		return v, errors.New("OldAttempt requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldRecordID: %w", err)
+		return v, fmt.Errorf("querying old value for OldAttempt: %w", err)
 	}
-	return oldValue.RecordID, nil
+	return oldValue.Attempt, nil
 }
 
-// ResetRecordID resets all changes to the "record_id" field.
-func (m *SignatureMutation) ResetRecordID() {
-	m.record = nil
+// AddAttempt adds i to the "attempt" field.
+func (m *WebhookDeliveryMutation) AddAttempt(i int) {
+	if m.addattempt != nil {
+		*m.addattempt += i
+	} else {
+		m.addattempt = &i
+	}
 }
 
-// SetKeyID sets the "key_id" field.
-func (m *SignatureMutation) SetKeyID(i int) {
-	m.key_id = &i
-	m.addkey_id = nil
+// AddedAttempt returns the value that was added to the "attempt" field in this mutation.
+func (m *WebhookDeliveryMutation) AddedAttempt() (r int, exists bool) {
+	v := m.addattempt
+	if v == nil {
+		return
+	}
+	return *v, true
 }
 
-// KeyID returns the value of the "key_id" field in the mutation.
-func (m *SignatureMutation) KeyID() (r int, exists bool) {
-	v := m.key_id
+// ResetAttempt resets all changes to the "attempt" field.
+func (m *WebhookDeliveryMutation) ResetAttempt() {
+	m.attempt = nil
+	m.addattempt = nil
+}
+
+// SetStatusCode sets the "status_code" field.
+func (m *WebhookDeliveryMutation) SetStatusCode(i int) {
+	m.status_code = &i
+	m.addstatus_code = nil
+}
+
+// StatusCode returns the value of the "status_code" field in the mutation.
+func (m *WebhookDeliveryMutation) StatusCode() (r int, exists bool) {
+	v := m.status_code
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldKeyID returns the old "key_id" field's value of the Signature entity.
-// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// OldStatusCode returns the old "status_code" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *SignatureMutation) OldKeyID(ctx context.Context) (v int, err error) {
+func (m *WebhookDeliveryMutation) OldStatusCode(ctx context.Context) (v *int, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldKeyID is only allowed on UpdateOne operations")
+		return v, errors.New("OldStatusCode is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldKeyID requires an ID field in the mutation")
+		return v, errors.New("OldStatusCode requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldKeyID: %w", err)
+		return v, fmt.Errorf("querying old value for OldStatusCode: %w", err)
 	}
-	return oldValue.KeyID, nil
+	return oldValue.StatusCode, nil
 }
 
-// AddKeyID adds i to the "key_id" field.
-func (m *SignatureMutation) AddKeyID(i int) {
-	if m.addkey_id != nil {
-		*m.addkey_id += i
+// AddStatusCode adds i to the "status_code" field.
+func (m *WebhookDeliveryMutation) AddStatusCode(i int) {
+	if m.addstatus_code != nil {
+		*m.addstatus_code += i
 	} else {
-		m.addkey_id = &i
+		m.addstatus_code = &i
 	}
 }
 
-// AddedKeyID returns the value that was added to the "key_id" field in this mutation.
-func (m *SignatureMutation) AddedKeyID() (r int, exists bool) {
-	v := m.addkey_id
+// AddedStatusCode returns the value that was added to the "status_code" field in this mutation.
+func (m *WebhookDeliveryMutation) AddedStatusCode() (r int, exists bool) {
+	v := m.addstatus_code
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// ResetKeyID resets all changes to the "key_id" field.
-func (m *SignatureMutation) ResetKeyID() {
-	m.key_id = nil
-	m.addkey_id = nil
+// ClearStatusCode clears the value of the "status_code" field.
+func (m *WebhookDeliveryMutation) ClearStatusCode() {
+	m.status_code = nil
+	m.addstatus_code = nil
+	m.clearedFields[webhookdelivery.FieldStatusCode] = struct{}{}
 }
 
-// SetValue sets the "value" field.
-func (m *SignatureMutation) SetValue(s string) {
-	m.value = &s
+// StatusCodeCleared returns if the "status_code" field was cleared in this mutation.
+func (m *WebhookDeliveryMutation) StatusCodeCleared() bool {
+	_, ok := m.clearedFields[webhookdelivery.FieldStatusCode]
+	return ok
 }
 
-// Value returns the value of the "value" field in the mutation.
-func (m *SignatureMutation) Value() (r string, exists bool) {
-	v := m.value
+// ResetStatusCode resets all changes to the "status_code" field.
+func (m *WebhookDeliveryMutation) ResetStatusCode() {
+	m.status_code = nil
+	m.addstatus_code = nil
+	delete(m.clearedFields, webhookdelivery.FieldStatusCode)
+}
+
+// SetError sets the "error" field.
+func (m *WebhookDeliveryMutation) SetError(s string) {
+	m.error = &s
+}
+
+// Error returns the value of the "error" field in the mutation.
+func (m *WebhookDeliveryMutation) Error() (r string, exists bool) {
+	v := m.error
 	if v == nil {
 		return
 	}
 	return *v, true
 }
 
-// OldValue returns the old "value" field's value of the Signature entity.
-// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// OldError returns the old "error" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *SignatureMutation) OldValue(ctx context.Context) (v string, err error) {
+func (m *WebhookDeliveryMutation) OldError(ctx context.Context) (v string, err error) {
 	if !m.op.Is(OpUpdateOne) {
-		return v, errors.New("OldValue is only allowed on UpdateOne operations")
+		return v, errors.New("OldError is only allowed on UpdateOne operations")
 	}
 	if m.id == nil || m.oldValue == nil {
-		return v, errors.New("OldValue requires an ID field in the mutation")
+		return v, errors.New("OldError requires an ID field in the mutation")
 	}
 	oldValue, err := m.oldValue(ctx)
 	if err != nil {
-		return v, fmt.Errorf("querying old value for OldValue: %w", err)
+		return v, fmt.Errorf("querying old value for OldError: %w", err)
 	}
-	return oldValue.Value, nil
+	return oldValue.Error, nil
 }
 
-// ResetValue resets all changes to the "value" field.
-func (m *SignatureMutation) ResetValue() {
-	m.value = nil
+// ClearError clears the value of the "error" field.
+func (m *WebhookDeliveryMutation) ClearError() {
+	m.error = nil
+	m.clearedFields[webhookdelivery.FieldError] = struct{}{}
+}
+
+// ErrorCleared returns if the "error" field was cleared in this mutation.
+func (m *WebhookDeliveryMutation) ErrorCleared() bool {
+	_, ok := m.clearedFields[webhookdelivery.FieldError]
+	return ok
+}
+
+// ResetError resets all changes to the "error" field.
+func (m *WebhookDeliveryMutation) ResetError() {
+	m.error = nil
+	delete(m.clearedFields, webhookdelivery.FieldError)
+}
+
+// SetSucceeded sets the "succeeded" field.
+func (m *WebhookDeliveryMutation) SetSucceeded(b bool) {
+	m.succeeded = &b
+}
+
+// Succeeded returns the value of the "succeeded" field in the mutation.
+func (m *WebhookDeliveryMutation) Succeeded() (r bool, exists bool) {
+	v := m.succeeded
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldSucceeded returns the old "succeeded" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *WebhookDeliveryMutation) OldSucceeded(ctx context.Context) (v bool, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldSucceeded is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldSucceeded requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldSucceeded: %w", err)
+	}
+	return oldValue.Succeeded, nil
+}
+
+// ResetSucceeded resets all changes to the "succeeded" field.
+func (m *WebhookDeliveryMutation) ResetSucceeded() {
+	m.succeeded = nil
 }
 
 // SetInsertedAt sets the "inserted_at" field.
-func (m *SignatureMutation) SetInsertedAt(t time.Time) {
+func (m *WebhookDeliveryMutation) SetInsertedAt(t time.Time) {
 	m.inserted_at = &t
 }
 
 // InsertedAt returns the value of the "inserted_at" field in the mutation.
-func (m *SignatureMutation) InsertedAt() (r time.Time, exists bool) {
+func (m *WebhookDeliveryMutation) InsertedAt() (r time.Time, exists bool) {
 	v := m.inserted_at
 	if v == nil {
 		return
@@ -686,10 +4247,10 @@ func (m *SignatureMutation) InsertedAt() (r time.Time, exists bool) {
 	return *v, true
 }
 
-// OldInsertedAt returns the old "inserted_at" field's value of the Signature entity.
-// If the Signature object wasn't provided to the builder, the object is fetched from the database.
+// OldInsertedAt returns the old "inserted_at" field's value of the WebhookDelivery entity.
+// If the WebhookDelivery object wasn't provided to the builder, the object is fetched from the database.
 // An error is returned if the mutation operation is not UpdateOne, or the database query fails.
-func (m *SignatureMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
+func (m *WebhookDeliveryMutation) OldInsertedAt(ctx context.Context) (v time.Time, err error) {
 	if !m.op.Is(OpUpdateOne) {
 		return v, errors.New("OldInsertedAt is only allowed on UpdateOne operations")
 	}
@@ -704,46 +4265,46 @@ func (m *SignatureMutation) OldInsertedAt(ctx context.Context) (v time.Time, err
 }
 
 // ResetInsertedAt resets all changes to the "inserted_at" field.
-func (m *SignatureMutation) ResetInsertedAt() {
+func (m *WebhookDeliveryMutation) ResetInsertedAt() {
 	m.inserted_at = nil
 }
 
-// ClearRecord clears the "record" edge to the Record entity.
-func (m *SignatureMutation) ClearRecord() {
-	m.clearedrecord = true
-	m.clearedFields[signature.FieldRecordID] = struct{}{}
+// ClearWebhook clears the "webhook" edge to the Webhook entity.
+func (m *WebhookDeliveryMutation) ClearWebhook() {
+	m.clearedwebhook = true
+	m.clearedFields[webhookdelivery.FieldWebhookID] = struct{}{}
 }
 
-// RecordCleared reports if the "record" edge to the Record entity was cleared.
-func (m *SignatureMutation) RecordCleared() bool {
-	return m.clearedrecord
+// WebhookCleared reports if the "webhook" edge to the Webhook entity was cleared.
+func (m *WebhookDeliveryMutation) WebhookCleared() bool {
+	return m.clearedwebhook
 }
 
-// RecordIDs returns the "record" edge IDs in the mutation.
+// WebhookIDs returns the "webhook" edge IDs in the mutation.
 // Note that IDs always returns len(IDs) <= 1 for unique edges, and you should use
-// RecordID instead. It exists only for internal usage by the builders.
-func (m *SignatureMutation) RecordIDs() (ids []int) {
-	if id := m.record; id != nil {
+// WebhookID instead. It exists only for internal usage by the builders.
+func (m *WebhookDeliveryMutation) WebhookIDs() (ids []int) {
+	if id := m.webhook; id != nil {
 		ids = append(ids, *id)
 	}
 	return
 }
 
-// ResetRecord resets all changes to the "record" edge.
-func (m *SignatureMutation) ResetRecord() {
-	m.record = nil
-	m.clearedrecord = false
+// ResetWebhook resets all changes to the "webhook" edge.
+func (m *WebhookDeliveryMutation) ResetWebhook() {
+	m.webhook = nil
+	m.clearedwebhook = false
 }
 
-// Where appends a list predicates to the SignatureMutation builder.
-func (m *SignatureMutation) Where(ps ...predicate.Signature) {
+// Where appends a list predicates to the WebhookDeliveryMutation builder.
+func (m *WebhookDeliveryMutation) Where(ps ...predicate.WebhookDelivery) {
 	m.predicates = append(m.predicates, ps...)
 }
 
-// WhereP appends storage-level predicates to the SignatureMutation builder. Using this method,
+// WhereP appends storage-level predicates to the WebhookDeliveryMutation builder. Using this method,
 // users can use type-assertion to append predicates that do not depend on any generated package.
-func (m *SignatureMutation) WhereP(ps ...func(*sql.Selector)) {
-	p := make([]predicate.Signature, len(ps))
+func (m *WebhookDeliveryMutation) WhereP(ps ...func(*sql.Selector)) {
+	p := make([]predicate.WebhookDelivery, len(ps))
 	for i := range ps {
 		p[i] = ps[i]
 	}
@@ -751,36 +4312,45 @@ func (m *SignatureMutation) WhereP(ps ...func(*sql.Selector)) {
 }
 
 // Op returns the operation name.
-func (m *SignatureMutation) Op() Op {
+func (m *WebhookDeliveryMutation) Op() Op {
 	return m.op
 }
 
 // SetOp allows setting the mutation operation.
-func (m *SignatureMutation) SetOp(op Op) {
+func (m *WebhookDeliveryMutation) SetOp(op Op) {
 	m.op = op
 }
 
-// Type returns the node type of this mutation (Signature).
-func (m *SignatureMutation) Type() string {
+// Type returns the node type of this mutation (WebhookDelivery).
+func (m *WebhookDeliveryMutation) Type() string {
 	return m.typ
 }
 
 // Fields returns all fields that were changed during this mutation. Note that in
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
-func (m *SignatureMutation) Fields() []string {
-	fields := make([]string, 0, 4)
-	if m.record != nil {
-		fields = append(fields, signature.FieldRecordID)
+func (m *WebhookDeliveryMutation) Fields() []string {
+	fields := make([]string, 0, 7)
+	if m.webhook != nil {
+		fields = append(fields, webhookdelivery.FieldWebhookID)
 	}
-	if m.key_id != nil {
-		fields = append(fields, signature.FieldKeyID)
+	if m.ledger_seq != nil {
+		fields = append(fields, webhookdelivery.FieldLedgerSeq)
 	}
-	if m.value != nil {
-		fields = append(fields, signature.FieldValue)
+	if m.attempt != nil {
+		fields = append(fields, webhookdelivery.FieldAttempt)
+	}
+	if m.status_code != nil {
+		fields = append(fields, webhookdelivery.FieldStatusCode)
+	}
+	if m.error != nil {
+		fields = append(fields, webhookdelivery.FieldError)
+	}
+	if m.succeeded != nil {
+		fields = append(fields, webhookdelivery.FieldSucceeded)
 	}
 	if m.inserted_at != nil {
-		fields = append(fields, signature.FieldInsertedAt)
+		fields = append(fields, webhookdelivery.FieldInsertedAt)
 	}
 	return fields
 }
@@ -788,15 +4358,21 @@ func (m *SignatureMutation) Fields() []string {
 // Field returns the value of a field with the given name. The second boolean
 // return value indicates that this field was not set, or was not defined in the
 // schema.
-func (m *SignatureMutation) Field(name string) (ent.Value, bool) {
+func (m *WebhookDeliveryMutation) Field(name string) (ent.Value, bool) {
 	switch name {
-	case signature.FieldRecordID:
-		return m.RecordID()
-	case signature.FieldKeyID:
-		return m.KeyID()
-	case signature.FieldValue:
-		return m.Value()
-	case signature.FieldInsertedAt:
+	case webhookdelivery.FieldWebhookID:
+		return m.WebhookID()
+	case webhookdelivery.FieldLedgerSeq:
+		return m.LedgerSeq()
+	case webhookdelivery.FieldAttempt:
+		return m.Attempt()
+	case webhookdelivery.FieldStatusCode:
+		return m.StatusCode()
+	case webhookdelivery.FieldError:
+		return m.Error()
+	case webhookdelivery.FieldSucceeded:
+		return m.Succeeded()
+	case webhookdelivery.FieldInsertedAt:
 		return m.InsertedAt()
 	}
 	return nil, false
@@ -805,47 +4381,74 @@ func (m *SignatureMutation) Field(name string) (ent.Value, bool) {
 // OldField returns the old value of the field from the database. An error is
 // returned if the mutation operation is not UpdateOne, or the query to the
 // database failed.
-func (m *SignatureMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
+func (m *WebhookDeliveryMutation) OldField(ctx context.Context, name string) (ent.Value, error) {
 	switch name {
-	case signature.FieldRecordID:
-		return m.OldRecordID(ctx)
-	case signature.FieldKeyID:
-		return m.OldKeyID(ctx)
-	case signature.FieldValue:
-		return m.OldValue(ctx)
-	case signature.FieldInsertedAt:
+	case webhookdelivery.FieldWebhookID:
+		return m.OldWebhookID(ctx)
+	case webhookdelivery.FieldLedgerSeq:
+		return m.OldLedgerSeq(ctx)
+	case webhookdelivery.FieldAttempt:
+		return m.OldAttempt(ctx)
+	case webhookdelivery.FieldStatusCode:
+		return m.OldStatusCode(ctx)
+	case webhookdelivery.FieldError:
+		return m.OldError(ctx)
+	case webhookdelivery.FieldSucceeded:
+		return m.OldSucceeded(ctx)
+	case webhookdelivery.FieldInsertedAt:
 		return m.OldInsertedAt(ctx)
 	}
-	return nil, fmt.Errorf("unknown Signature field %s", name)
+	return nil, fmt.Errorf("unknown WebhookDelivery field %s", name)
 }
 
 // SetField sets the value of a field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *SignatureMutation) SetField(name string, value ent.Value) error {
+func (m *WebhookDeliveryMutation) SetField(name string, value ent.Value) error {
 	switch name {
-	case signature.FieldRecordID:
+	case webhookdelivery.FieldWebhookID:
 		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetRecordID(v)
+		m.SetWebhookID(v)
 		return nil
-	case signature.FieldKeyID:
+	case webhookdelivery.FieldLedgerSeq:
 		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetKeyID(v)
+		m.SetLedgerSeq(v)
 		return nil
-	case signature.FieldValue:
+	case webhookdelivery.FieldAttempt:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetAttempt(v)
+		return nil
+	case webhookdelivery.FieldStatusCode:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetStatusCode(v)
+		return nil
+	case webhookdelivery.FieldError:
 		v, ok := value.(string)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.SetValue(v)
+		m.SetError(v)
 		return nil
-	case signature.FieldInsertedAt:
+	case webhookdelivery.FieldSucceeded:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetSucceeded(v)
+		return nil
+	case webhookdelivery.FieldInsertedAt:
 		v, ok := value.(time.Time)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
@@ -853,15 +4456,21 @@ func (m *SignatureMutation) SetField(name string, value ent.Value) error {
 		m.SetInsertedAt(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Signature field %s", name)
+	return fmt.Errorf("unknown WebhookDelivery field %s", name)
 }
 
 // AddedFields returns all numeric fields that were incremented/decremented during
 // this mutation.
-func (m *SignatureMutation) AddedFields() []string {
+func (m *WebhookDeliveryMutation) AddedFields() []string {
 	var fields []string
-	if m.addkey_id != nil {
-		fields = append(fields, signature.FieldKeyID)
+	if m.addledger_seq != nil {
+		fields = append(fields, webhookdelivery.FieldLedgerSeq)
+	}
+	if m.addattempt != nil {
+		fields = append(fields, webhookdelivery.FieldAttempt)
+	}
+	if m.addstatus_code != nil {
+		fields = append(fields, webhookdelivery.FieldStatusCode)
 	}
 	return fields
 }
@@ -869,10 +4478,14 @@ func (m *SignatureMutation) AddedFields() []string {
 // AddedField returns the numeric value that was incremented/decremented on a field
 // with the given name. The second boolean return value indicates that this field
 // was not set, or was not defined in the schema.
-func (m *SignatureMutation) AddedField(name string) (ent.Value, bool) {
+func (m *WebhookDeliveryMutation) AddedField(name string) (ent.Value, bool) {
 	switch name {
-	case signature.FieldKeyID:
-		return m.AddedKeyID()
+	case webhookdelivery.FieldLedgerSeq:
+		return m.AddedLedgerSeq()
+	case webhookdelivery.FieldAttempt:
+		return m.AddedAttempt()
+	case webhookdelivery.FieldStatusCode:
+		return m.AddedStatusCode()
 	}
 	return nil, false
 }
@@ -880,73 +4493,111 @@ func (m *SignatureMutation) AddedField(name string) (ent.Value, bool) {
 // AddField adds the value to the field with the given name. It returns an error if
 // the field is not defined in the schema, or if the type mismatched the field
 // type.
-func (m *SignatureMutation) AddField(name string, value ent.Value) error {
+func (m *WebhookDeliveryMutation) AddField(name string, value ent.Value) error {
 	switch name {
-	case signature.FieldKeyID:
+	case webhookdelivery.FieldLedgerSeq:
 		v, ok := value.(int)
 		if !ok {
 			return fmt.Errorf("unexpected type %T for field %s", value, name)
 		}
-		m.AddKeyID(v)
+		m.AddLedgerSeq(v)
+		return nil
+	case webhookdelivery.FieldAttempt:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddAttempt(v)
+		return nil
+	case webhookdelivery.FieldStatusCode:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddStatusCode(v)
 		return nil
 	}
-	return fmt.Errorf("unknown Signature numeric field %s", name)
+	return fmt.Errorf("unknown WebhookDelivery numeric field %s", name)
 }
 
 // ClearedFields returns all nullable fields that were cleared during this
 // mutation.
-func (m *SignatureMutation) ClearedFields() []string {
-	return nil
+func (m *WebhookDeliveryMutation) ClearedFields() []string {
+	var fields []string
+	if m.FieldCleared(webhookdelivery.FieldStatusCode) {
+		fields = append(fields, webhookdelivery.FieldStatusCode)
+	}
+	if m.FieldCleared(webhookdelivery.FieldError) {
+		fields = append(fields, webhookdelivery.FieldError)
+	}
+	return fields
 }
 
 // FieldCleared returns a boolean indicating if a field with the given name was
 // cleared in this mutation.
-func (m *SignatureMutation) FieldCleared(name string) bool {
+func (m *WebhookDeliveryMutation) FieldCleared(name string) bool {
 	_, ok := m.clearedFields[name]
 	return ok
 }
 
 // ClearField clears the value of the field with the given name. It returns an
 // error if the field is not defined in the schema.
-func (m *SignatureMutation) ClearField(name string) error {
-	return fmt.Errorf("unknown Signature nullable field %s", name)
+func (m *WebhookDeliveryMutation) ClearField(name string) error {
+	switch name {
+	case webhookdelivery.FieldStatusCode:
+		m.ClearStatusCode()
+		return nil
+	case webhookdelivery.FieldError:
+		m.ClearError()
+		return nil
+	}
+	return fmt.Errorf("unknown WebhookDelivery nullable field %s", name)
 }
 
 // ResetField resets all changes in the mutation for the field with the given name.
 // It returns an error if the field is not defined in the schema.
-func (m *SignatureMutation) ResetField(name string) error {
+func (m *WebhookDeliveryMutation) ResetField(name string) error {
 	switch name {
-	case signature.FieldRecordID:
-		m.ResetRecordID()
+	case webhookdelivery.FieldWebhookID:
+		m.ResetWebhookID()
 		return nil
-	case signature.FieldKeyID:
-		m.ResetKeyID()
+	case webhookdelivery.FieldLedgerSeq:
+		m.ResetLedgerSeq()
 		return nil
-	case signature.FieldValue:
-		m.ResetValue()
+	case webhookdelivery.FieldAttempt:
+		m.ResetAttempt()
 		return nil
-	case signature.FieldInsertedAt:
+	case webhookdelivery.FieldStatusCode:
+		m.ResetStatusCode()
+		return nil
+	case webhookdelivery.FieldError:
+		m.ResetError()
+		return nil
+	case webhookdelivery.FieldSucceeded:
+		m.ResetSucceeded()
+		return nil
+	case webhookdelivery.FieldInsertedAt:
 		m.ResetInsertedAt()
 		return nil
 	}
-	return fmt.Errorf("unknown Signature field %s", name)
+	return fmt.Errorf("unknown WebhookDelivery field %s", name)
 }
 
 // AddedEdges returns all edge names that were set/added in this mutation.
-func (m *SignatureMutation) AddedEdges() []string {
+func (m *WebhookDeliveryMutation) AddedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.record != nil {
-		edges = append(edges, signature.EdgeRecord)
+	if m.webhook != nil {
+		edges = append(edges, webhookdelivery.EdgeWebhook)
 	}
 	return edges
 }
 
 // AddedIDs returns all IDs (to other nodes) that were added for the given edge
 // name in this mutation.
-func (m *SignatureMutation) AddedIDs(name string) []ent.Value {
+func (m *WebhookDeliveryMutation) AddedIDs(name string) []ent.Value {
 	switch name {
-	case signature.EdgeRecord:
-		if id := m.record; id != nil {
+	case webhookdelivery.EdgeWebhook:
+		if id := m.webhook; id != nil {
 			return []ent.Value{*id}
 		}
 	}
@@ -954,54 +4605,54 @@ func (m *SignatureMutation) AddedIDs(name string) []ent.Value {
 }
 
 // RemovedEdges returns all edge names that were removed in this mutation.
-func (m *SignatureMutation) RemovedEdges() []string {
+func (m *WebhookDeliveryMutation) RemovedEdges() []string {
 	edges := make([]string, 0, 1)
 	return edges
 }
 
 // RemovedIDs returns all IDs (to other nodes) that were removed for the edge with
 // the given name in this mutation.
-func (m *SignatureMutation) RemovedIDs(name string) []ent.Value {
+func (m *WebhookDeliveryMutation) RemovedIDs(name string) []ent.Value {
 	return nil
 }
 
 // ClearedEdges returns all edge names that were cleared in this mutation.
-func (m *SignatureMutation) ClearedEdges() []string {
+func (m *WebhookDeliveryMutation) ClearedEdges() []string {
 	edges := make([]string, 0, 1)
-	if m.clearedrecord {
-		edges = append(edges, signature.EdgeRecord)
+	if m.clearedwebhook {
+		edges = append(edges, webhookdelivery.EdgeWebhook)
 	}
 	return edges
 }
 
 // EdgeCleared returns a boolean which indicates if the edge with the given name
 // was cleared in this mutation.
-func (m *SignatureMutation) EdgeCleared(name string) bool {
+func (m *WebhookDeliveryMutation) EdgeCleared(name string) bool {
 	switch name {
-	case signature.EdgeRecord:
-		return m.clearedrecord
+	case webhookdelivery.EdgeWebhook:
+		return m.clearedwebhook
 	}
 	return false
 }
 
 // ClearEdge clears the value of the edge with the given name. It returns an error
 // if that edge is not defined in the schema.
-func (m *SignatureMutation) ClearEdge(name string) error {
+func (m *WebhookDeliveryMutation) ClearEdge(name string) error {
 	switch name {
-	case signature.EdgeRecord:
-		m.ClearRecord()
+	case webhookdelivery.EdgeWebhook:
+		m.ClearWebhook()
 		return nil
 	}
-	return fmt.Errorf("unknown Signature unique edge %s", name)
+	return fmt.Errorf("unknown WebhookDelivery unique edge %s", name)
 }
 
 // ResetEdge resets all changes to the edge with the given name in this mutation.
 // It returns an error if the edge is not defined in the schema.
-func (m *SignatureMutation) ResetEdge(name string) error {
+func (m *WebhookDeliveryMutation) ResetEdge(name string) error {
 	switch name {
-	case signature.EdgeRecord:
-		m.ResetRecord()
+	case webhookdelivery.EdgeWebhook:
+		m.ResetWebhook()
 		return nil
 	}
-	return fmt.Errorf("unknown Signature edge %s", name)
+	return fmt.Errorf("unknown WebhookDelivery edge %s", name)
 }
@@ -0,0 +1,4 @@
+package database
+
+// pgx's database/sql driver, selected with DATABASE_DRIVER=pgx.
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -18,8 +18,12 @@ type Record struct {
 	config `json:"-"`
 	// ID of the ent.
 	ID int `json:"id"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID string `json:"tenant_id"`
 	// InsertedAt holds the value of the "inserted_at" field.
 	InsertedAt time.Time `json:"inserted_at"`
+	// Data holds the value of the "data" field.
+	Data []byte `json:"data,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the RecordQuery when eager-loading is set.
 	Edges        RecordEdges `json:"edges"`
@@ -51,8 +55,12 @@ func (*Record) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
+		case record.FieldData:
+			values[i] = new([]byte)
 		case record.FieldID:
 			values[i] = new(sql.NullInt64)
+		case record.FieldTenantID:
+			values[i] = new(sql.NullString)
 		case record.FieldInsertedAt:
 			values[i] = new(sql.NullTime)
 		default:
@@ -76,12 +84,24 @@ func (r *Record) assignValues(columns []string, values []any) error {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
 			r.ID = int(value.Int64)
+		case record.FieldTenantID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				r.TenantID = value.String
+			}
 		case record.FieldInsertedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field inserted_at", values[i])
 			} else if value.Valid {
 				r.InsertedAt = value.Time
 			}
+		case record.FieldData:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field data", values[i])
+			} else if value != nil {
+				r.Data = *value
+			}
 		default:
 			r.selectValues.Set(columns[i], values[i])
 		}
@@ -123,8 +143,14 @@ func (r *Record) String() string {
 	var builder strings.Builder
 	builder.WriteString("Record(")
 	builder.WriteString(fmt.Sprintf("id=%v, ", r.ID))
+	builder.WriteString("tenant_id=")
+	builder.WriteString(r.TenantID)
+	builder.WriteString(", ")
 	builder.WriteString("inserted_at=")
 	builder.WriteString(r.InsertedAt.Format(time.ANSIC))
+	builder.WriteString(", ")
+	builder.WriteString("data=")
+	builder.WriteString(fmt.Sprintf("%v", r.Data))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -14,8 +14,12 @@ const (
 	Label = "record"
 	// FieldID holds the string denoting the id field in the database.
 	FieldID = "id"
+	// FieldTenantID holds the string denoting the tenant_id field in the database.
+	FieldTenantID = "tenant_id"
 	// FieldInsertedAt holds the string denoting the inserted_at field in the database.
 	FieldInsertedAt = "inserted_at"
+	// FieldData holds the string denoting the data field in the database.
+	FieldData = "data"
 	// EdgeSignature holds the string denoting the signature edge name in mutations.
 	EdgeSignature = "signature"
 	// Table holds the table name of the record in the database.
@@ -32,7 +36,9 @@ const (
 // Columns holds all SQL columns for record fields.
 var Columns = []string{
 	FieldID,
+	FieldTenantID,
 	FieldInsertedAt,
+	FieldData,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -46,6 +52,10 @@ func ValidColumn(column string) bool {
 }
 
 var (
+	// DefaultTenantID holds the default value on creation for the "tenant_id" field.
+	DefaultTenantID string
+	// TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	TenantIDValidator func(string) error
 	// DefaultInsertedAt holds the default value on creation for the "inserted_at" field.
 	DefaultInsertedAt func() time.Time
 )
@@ -58,6 +68,11 @@ func ByID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldID, opts...).ToFunc()
 }
 
+// ByTenantID orders the results by the tenant_id field.
+func ByTenantID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTenantID, opts...).ToFunc()
+}
+
 // ByInsertedAt orders the results by the inserted_at field.
 func ByInsertedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldInsertedAt, opts...).ToFunc()
@@ -55,11 +55,86 @@ func IDLTE(id int) predicate.Record {
 	return predicate.Record(sql.FieldLTE(FieldID, id))
 }
 
+// TenantID applies equality check predicate on the "tenant_id" field. It's identical to TenantIDEQ.
+func TenantID(v string) predicate.Record {
+	return predicate.Record(sql.FieldEQ(FieldTenantID, v))
+}
+
 // InsertedAt applies equality check predicate on the "inserted_at" field. It's identical to InsertedAtEQ.
 func InsertedAt(v time.Time) predicate.Record {
 	return predicate.Record(sql.FieldEQ(FieldInsertedAt, v))
 }
 
+// Data applies equality check predicate on the "data" field. It's identical to DataEQ.
+func Data(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldEQ(FieldData, v))
+}
+
+// TenantIDEQ applies the EQ predicate on the "tenant_id" field.
+func TenantIDEQ(v string) predicate.Record {
+	return predicate.Record(sql.FieldEQ(FieldTenantID, v))
+}
+
+// TenantIDNEQ applies the NEQ predicate on the "tenant_id" field.
+func TenantIDNEQ(v string) predicate.Record {
+	return predicate.Record(sql.FieldNEQ(FieldTenantID, v))
+}
+
+// TenantIDIn applies the In predicate on the "tenant_id" field.
+func TenantIDIn(vs ...string) predicate.Record {
+	return predicate.Record(sql.FieldIn(FieldTenantID, vs...))
+}
+
+// TenantIDNotIn applies the NotIn predicate on the "tenant_id" field.
+func TenantIDNotIn(vs ...string) predicate.Record {
+	return predicate.Record(sql.FieldNotIn(FieldTenantID, vs...))
+}
+
+// TenantIDGT applies the GT predicate on the "tenant_id" field.
+func TenantIDGT(v string) predicate.Record {
+	return predicate.Record(sql.FieldGT(FieldTenantID, v))
+}
+
+// TenantIDGTE applies the GTE predicate on the "tenant_id" field.
+func TenantIDGTE(v string) predicate.Record {
+	return predicate.Record(sql.FieldGTE(FieldTenantID, v))
+}
+
+// TenantIDLT applies the LT predicate on the "tenant_id" field.
+func TenantIDLT(v string) predicate.Record {
+	return predicate.Record(sql.FieldLT(FieldTenantID, v))
+}
+
+// TenantIDLTE applies the LTE predicate on the "tenant_id" field.
+func TenantIDLTE(v string) predicate.Record {
+	return predicate.Record(sql.FieldLTE(FieldTenantID, v))
+}
+
+// TenantIDContains applies the Contains predicate on the "tenant_id" field.
+func TenantIDContains(v string) predicate.Record {
+	return predicate.Record(sql.FieldContains(FieldTenantID, v))
+}
+
+// TenantIDHasPrefix applies the HasPrefix predicate on the "tenant_id" field.
+func TenantIDHasPrefix(v string) predicate.Record {
+	return predicate.Record(sql.FieldHasPrefix(FieldTenantID, v))
+}
+
+// TenantIDHasSuffix applies the HasSuffix predicate on the "tenant_id" field.
+func TenantIDHasSuffix(v string) predicate.Record {
+	return predicate.Record(sql.FieldHasSuffix(FieldTenantID, v))
+}
+
+// TenantIDEqualFold applies the EqualFold predicate on the "tenant_id" field.
+func TenantIDEqualFold(v string) predicate.Record {
+	return predicate.Record(sql.FieldEqualFold(FieldTenantID, v))
+}
+
+// TenantIDContainsFold applies the ContainsFold predicate on the "tenant_id" field.
+func TenantIDContainsFold(v string) predicate.Record {
+	return predicate.Record(sql.FieldContainsFold(FieldTenantID, v))
+}
+
 // InsertedAtEQ applies the EQ predicate on the "inserted_at" field.
 func InsertedAtEQ(v time.Time) predicate.Record {
 	return predicate.Record(sql.FieldEQ(FieldInsertedAt, v))
@@ -100,6 +175,56 @@ func InsertedAtLTE(v time.Time) predicate.Record {
 	return predicate.Record(sql.FieldLTE(FieldInsertedAt, v))
 }
 
+// DataEQ applies the EQ predicate on the "data" field.
+func DataEQ(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldEQ(FieldData, v))
+}
+
+// DataNEQ applies the NEQ predicate on the "data" field.
+func DataNEQ(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldNEQ(FieldData, v))
+}
+
+// DataIn applies the In predicate on the "data" field.
+func DataIn(vs ...[]byte) predicate.Record {
+	return predicate.Record(sql.FieldIn(FieldData, vs...))
+}
+
+// DataNotIn applies the NotIn predicate on the "data" field.
+func DataNotIn(vs ...[]byte) predicate.Record {
+	return predicate.Record(sql.FieldNotIn(FieldData, vs...))
+}
+
+// DataGT applies the GT predicate on the "data" field.
+func DataGT(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldGT(FieldData, v))
+}
+
+// DataGTE applies the GTE predicate on the "data" field.
+func DataGTE(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldGTE(FieldData, v))
+}
+
+// DataLT applies the LT predicate on the "data" field.
+func DataLT(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldLT(FieldData, v))
+}
+
+// DataLTE applies the LTE predicate on the "data" field.
+func DataLTE(v []byte) predicate.Record {
+	return predicate.Record(sql.FieldLTE(FieldData, v))
+}
+
+// DataIsNil applies the IsNil predicate on the "data" field.
+func DataIsNil() predicate.Record {
+	return predicate.Record(sql.FieldIsNull(FieldData))
+}
+
+// DataNotNil applies the NotNil predicate on the "data" field.
+func DataNotNil() predicate.Record {
+	return predicate.Record(sql.FieldNotNull(FieldData))
+}
+
 // HasSignature applies the HasEdge predicate on the "signature" edge.
 func HasSignature() predicate.Record {
 	return predicate.Record(func(s *sql.Selector) {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
 	"github.com/jurshsmith/vaultstream/database/record"
@@ -19,6 +20,21 @@ type RecordCreate struct {
 	config
 	mutation *RecordMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (rc *RecordCreate) SetTenantID(s string) *RecordCreate {
+	rc.mutation.SetTenantID(s)
+	return rc
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (rc *RecordCreate) SetNillableTenantID(s *string) *RecordCreate {
+	if s != nil {
+		rc.SetTenantID(*s)
+	}
+	return rc
 }
 
 // SetInsertedAt sets the "inserted_at" field.
@@ -35,6 +51,12 @@ func (rc *RecordCreate) SetNillableInsertedAt(t *time.Time) *RecordCreate {
 	return rc
 }
 
+// SetData sets the "data" field.
+func (rc *RecordCreate) SetData(b []byte) *RecordCreate {
+	rc.mutation.SetData(b)
+	return rc
+}
+
 // SetID sets the "id" field.
 func (rc *RecordCreate) SetID(i int) *RecordCreate {
 	rc.mutation.SetID(i)
@@ -95,6 +117,10 @@ func (rc *RecordCreate) ExecX(ctx context.Context) {
 
 // defaults sets the default values of the builder before save.
 func (rc *RecordCreate) defaults() {
+	if _, ok := rc.mutation.TenantID(); !ok {
+		v := record.DefaultTenantID
+		rc.mutation.SetTenantID(v)
+	}
 	if _, ok := rc.mutation.InsertedAt(); !ok {
 		v := record.DefaultInsertedAt()
 		rc.mutation.SetInsertedAt(v)
@@ -103,6 +129,14 @@ func (rc *RecordCreate) defaults() {
 
 // check runs all checks and user-defined validators on the builder.
 func (rc *RecordCreate) check() error {
+	if _, ok := rc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`database: missing required field "Record.tenant_id"`)}
+	}
+	if v, ok := rc.mutation.TenantID(); ok {
+		if err := record.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`database: validator failed for field "Record.tenant_id": %w`, err)}
+		}
+	}
 	if _, ok := rc.mutation.InsertedAt(); !ok {
 		return &ValidationError{Name: "inserted_at", err: errors.New(`database: missing required field "Record.inserted_at"`)}
 	}
@@ -134,14 +168,23 @@ func (rc *RecordCreate) createSpec() (*Record, *sqlgraph.CreateSpec) {
 		_node = &Record{config: rc.config}
 		_spec = sqlgraph.NewCreateSpec(record.Table, sqlgraph.NewFieldSpec(record.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = rc.conflict
 	if id, ok := rc.mutation.ID(); ok {
 		_node.ID = id
 		_spec.ID.Value = id
 	}
+	if value, ok := rc.mutation.TenantID(); ok {
+		_spec.SetField(record.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
 	if value, ok := rc.mutation.InsertedAt(); ok {
 		_spec.SetField(record.FieldInsertedAt, field.TypeTime, value)
 		_node.InsertedAt = value
 	}
+	if value, ok := rc.mutation.Data(); ok {
+		_spec.SetField(record.FieldData, field.TypeBytes, value)
+		_node.Data = value
+	}
 	if nodes := rc.mutation.SignatureIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2O,
@@ -161,11 +204,174 @@ func (rc *RecordCreate) createSpec() (*Record, *sqlgraph.CreateSpec) {
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Record.Create().
+//		SetTenantID(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.RecordUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (rc *RecordCreate) OnConflict(opts ...sql.ConflictOption) *RecordUpsertOne {
+	rc.conflict = opts
+	return &RecordUpsertOne{
+		create: rc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (rc *RecordCreate) OnConflictColumns(columns ...string) *RecordUpsertOne {
+	rc.conflict = append(rc.conflict, sql.ConflictColumns(columns...))
+	return &RecordUpsertOne{
+		create: rc,
+	}
+}
+
+type (
+	// RecordUpsertOne is the builder for "upsert"-ing
+	//  one Record node.
+	RecordUpsertOne struct {
+		create *RecordCreate
+	}
+
+	// RecordUpsert is the "OnConflict" setter.
+	RecordUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *RecordUpsert) SetInsertedAt(v time.Time) *RecordUpsert {
+	u.Set(record.FieldInsertedAt, v)
+	return u
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *RecordUpsert) UpdateInsertedAt() *RecordUpsert {
+	u.SetExcluded(record.FieldInsertedAt)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create except the ID field.
+// Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(record.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *RecordUpsertOne) UpdateNewValues() *RecordUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.ID(); exists {
+			s.SetIgnore(record.FieldID)
+		}
+		if _, exists := u.create.mutation.TenantID(); exists {
+			s.SetIgnore(record.FieldTenantID)
+		}
+		if _, exists := u.create.mutation.Data(); exists {
+			s.SetIgnore(record.FieldData)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *RecordUpsertOne) Ignore() *RecordUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *RecordUpsertOne) DoNothing() *RecordUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the RecordCreate.OnConflict
+// documentation for more info.
+func (u *RecordUpsertOne) Update(set func(*RecordUpsert)) *RecordUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&RecordUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *RecordUpsertOne) SetInsertedAt(v time.Time) *RecordUpsertOne {
+	return u.Update(func(s *RecordUpsert) {
+		s.SetInsertedAt(v)
+	})
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *RecordUpsertOne) UpdateInsertedAt() *RecordUpsertOne {
+	return u.Update(func(s *RecordUpsert) {
+		s.UpdateInsertedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *RecordUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for RecordCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *RecordUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *RecordUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *RecordUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // RecordCreateBulk is the builder for creating many Record entities in bulk.
 type RecordCreateBulk struct {
 	config
 	err      error
 	builders []*RecordCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the Record entities in the database.
@@ -195,6 +401,7 @@ func (rcb *RecordCreateBulk) Save(ctx context.Context) ([]*Record, error) {
 					_, err = mutators[i+1].Mutate(root, rcb.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = rcb.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, rcb.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -248,3 +455,137 @@ func (rcb *RecordCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Record.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.RecordUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (rcb *RecordCreateBulk) OnConflict(opts ...sql.ConflictOption) *RecordUpsertBulk {
+	rcb.conflict = opts
+	return &RecordUpsertBulk{
+		create: rcb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (rcb *RecordCreateBulk) OnConflictColumns(columns ...string) *RecordUpsertBulk {
+	rcb.conflict = append(rcb.conflict, sql.ConflictColumns(columns...))
+	return &RecordUpsertBulk{
+		create: rcb,
+	}
+}
+
+// RecordUpsertBulk is the builder for "upsert"-ing
+// a bulk of Record nodes.
+type RecordUpsertBulk struct {
+	create *RecordCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//			sql.ResolveWith(func(u *sql.UpdateSet) {
+//				u.SetIgnore(record.FieldID)
+//			}),
+//		).
+//		Exec(ctx)
+func (u *RecordUpsertBulk) UpdateNewValues() *RecordUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.ID(); exists {
+				s.SetIgnore(record.FieldID)
+			}
+			if _, exists := b.mutation.TenantID(); exists {
+				s.SetIgnore(record.FieldTenantID)
+			}
+			if _, exists := b.mutation.Data(); exists {
+				s.SetIgnore(record.FieldData)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Record.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *RecordUpsertBulk) Ignore() *RecordUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *RecordUpsertBulk) DoNothing() *RecordUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the RecordCreateBulk.OnConflict
+// documentation for more info.
+func (u *RecordUpsertBulk) Update(set func(*RecordUpsert)) *RecordUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&RecordUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetInsertedAt sets the "inserted_at" field.
+func (u *RecordUpsertBulk) SetInsertedAt(v time.Time) *RecordUpsertBulk {
+	return u.Update(func(s *RecordUpsert) {
+		s.SetInsertedAt(v)
+	})
+}
+
+// UpdateInsertedAt sets the "inserted_at" field to the value that was provided on create.
+func (u *RecordUpsertBulk) UpdateInsertedAt() *RecordUpsertBulk {
+	return u.Update(func(s *RecordUpsert) {
+		s.UpdateInsertedAt()
+	})
+}
+
+// Exec executes the query.
+func (u *RecordUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("database: OnConflict was set for builder %d. Set it on the RecordCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for RecordCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *RecordUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
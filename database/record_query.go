@@ -299,12 +299,12 @@ func (rq *RecordQuery) WithSignature(opts ...func(*SignatureQuery)) *RecordQuery
 // Example:
 //
 //	var v []struct {
-//		InsertedAt time.Time `json:"inserted_at"`
+//		TenantID string `json:"tenant_id"`
 //		Count int `json:"count,omitempty"`
 //	}
 //
 //	client.Record.Query().
-//		GroupBy(record.FieldInsertedAt).
+//		GroupBy(record.FieldTenantID).
 //		Aggregate(database.Count()).
 //		Scan(ctx, &v)
 func (rq *RecordQuery) GroupBy(field string, fields ...string) *RecordGroupBy {
@@ -322,11 +322,11 @@ func (rq *RecordQuery) GroupBy(field string, fields ...string) *RecordGroupBy {
 // Example:
 //
 //	var v []struct {
-//		InsertedAt time.Time `json:"inserted_at"`
+//		TenantID string `json:"tenant_id"`
 //	}
 //
 //	client.Record.Query().
-//		Select(record.FieldInsertedAt).
+//		Select(record.FieldTenantID).
 //		Scan(ctx, &v)
 func (rq *RecordQuery) Select(fields ...string) *RecordSelect {
 	rq.ctx.Fields = append(rq.ctx.Fields, fields...)
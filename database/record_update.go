@@ -112,6 +112,9 @@ func (ru *RecordUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := ru.mutation.InsertedAt(); ok {
 		_spec.SetField(record.FieldInsertedAt, field.TypeTime, value)
 	}
+	if ru.mutation.DataCleared() {
+		_spec.ClearField(record.FieldData, field.TypeBytes)
+	}
 	if ru.mutation.SignatureCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2O,
@@ -274,6 +277,9 @@ func (ruo *RecordUpdateOne) sqlSave(ctx context.Context) (_node *Record, err err
 	if value, ok := ruo.mutation.InsertedAt(); ok {
 		_spec.SetField(record.FieldInsertedAt, field.TypeTime, value)
 	}
+	if ruo.mutation.DataCleared() {
+		_spec.ClearField(record.FieldData, field.TypeBytes)
+	}
 	if ruo.mutation.SignatureCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2O,
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+)
+
+const (
+	createRevisions = `CREATE TABLE IF NOT EXISTS schema_revisions (
+	version character varying PRIMARY KEY,
+	description character varying NOT NULL,
+	type bigint NOT NULL,
+	applied bigint NOT NULL,
+	total bigint NOT NULL,
+	executed_at timestamptz NOT NULL,
+	execution_time bigint NOT NULL,
+	error text NOT NULL,
+	error_stmt text NOT NULL,
+	hash character varying NOT NULL,
+	partial_hashes jsonb,
+	operator_version character varying NOT NULL
+)`
+	selectRevisions = `SELECT version, description, type, applied, total, executed_at, execution_time,
+	error, error_stmt, hash, partial_hashes, operator_version FROM schema_revisions`
+	writeRevision = `INSERT INTO schema_revisions (version, description, type, applied, total, executed_at,
+	execution_time, error, error_stmt, hash, partial_hashes, operator_version)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	ON CONFLICT (version) DO UPDATE SET description = EXCLUDED.description, type = EXCLUDED.type,
+	applied = EXCLUDED.applied, total = EXCLUDED.total, executed_at = EXCLUDED.executed_at,
+	execution_time = EXCLUDED.execution_time, error = EXCLUDED.error, error_stmt = EXCLUDED.error_stmt,
+	hash = EXCLUDED.hash, partial_hashes = EXCLUDED.partial_hashes, operator_version = EXCLUDED.operator_version`
+	deleteRevision = `DELETE FROM schema_revisions WHERE version = $1`
+)
+
+// revisions stores the applied migrations in the schema_revisions table, for
+// the Atlas executor, through the database or the transaction of a migration.
+type revisions struct {
+	db interface {
+		ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+		QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	}
+}
+
+var _ migrate.RevisionReadWriter = revisions{}
+
+func (r revisions) init(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, createRevisions)
+	return err
+}
+
+// Ident is the only table a database Up has not migrated yet may have.
+func (revisions) Ident() *migrate.TableIdent {
+	return &migrate.TableIdent{Name: "schema_revisions", Schema: "public"}
+}
+
+func (r revisions) ReadRevisions(ctx context.Context) ([]*migrate.Revision, error) {
+	rows, err := r.db.QueryContext(ctx, selectRevisions+" ORDER BY version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var revs []*migrate.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+	return revs, rows.Err()
+}
+
+func (r revisions) ReadRevision(ctx context.Context, version string) (*migrate.Revision, error) {
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, selectRevisions+" WHERE version = $1", version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, migrate.ErrRevisionNotExist
+	}
+	return rev, err
+}
+
+func (r revisions) WriteRevision(ctx context.Context, rev *migrate.Revision) error {
+	var partial []byte
+	if len(rev.PartialHashes) > 0 {
+		var err error
+		if partial, err = json.Marshal(rev.PartialHashes); err != nil {
+			return err
+		}
+	}
+	_, err := r.db.ExecContext(ctx, writeRevision,
+		rev.Version, rev.Description, int64(rev.Type), rev.Applied, rev.Total, rev.ExecutedAt,
+		int64(rev.ExecutionTime), rev.Error, rev.ErrorStmt, rev.Hash, nullJSON(partial), rev.OperatorVersion)
+	return err
+}
+
+func (r revisions) DeleteRevision(ctx context.Context, version string) error {
+	_, err := r.db.ExecContext(ctx, deleteRevision, version)
+	return err
+}
+
+func scanRevision(row interface{ Scan(...any) error }) (*migrate.Revision, error) {
+	var (
+		rev      migrate.Revision
+		typ      int64
+		execTime int64
+		partial  []byte
+	)
+	err := row.Scan(&rev.Version, &rev.Description, &typ, &rev.Applied, &rev.Total, &rev.ExecutedAt,
+		&execTime, &rev.Error, &rev.ErrorStmt, &rev.Hash, &partial, &rev.OperatorVersion)
+	if err != nil {
+		return nil, err
+	}
+	rev.Type = migrate.RevisionType(typ)
+	rev.ExecutionTime = time.Duration(execTime)
+	if partial != nil {
+		if err := json.Unmarshal(partial, &rev.PartialHashes); err != nil {
+			return nil, err
+		}
+	}
+	return &rev, nil
+}
+
+// nullJSON passes b as text, which both drivers cast to jsonb, or NULL.
+func nullJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
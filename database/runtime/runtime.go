@@ -2,7 +2,216 @@
 
 package runtime
 
-// The schema-stitching logic is generated in github.com/jurshsmith/vaultstream/database/runtime.go
+import (
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database/key"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/record"
+	"github.com/jurshsmith/vaultstream/database/schema"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/database/webhook"
+	"github.com/jurshsmith/vaultstream/database/webhookdelivery"
+)
+
+// The init function reads all schema descriptors with runtime code
+// (default values, validators, hooks and policies) and stitches it
+// to their package variables.
+func init() {
+	keyMixin := schema.Key{}.Mixin()
+	keyMixinFields0 := keyMixin[0].Fields()
+	_ = keyMixinFields0
+	keyFields := schema.Key{}.Fields()
+	_ = keyFields
+	// keyDescTenantID is the schema descriptor for tenant_id field.
+	keyDescTenantID := keyMixinFields0[0].Descriptor()
+	// key.DefaultTenantID holds the default value on creation for the tenant_id field.
+	key.DefaultTenantID = keyDescTenantID.Default.(string)
+	// key.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	key.TenantIDValidator = func() func(string) error {
+		validators := keyDescTenantID.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(tenant_id string) error {
+			for _, fn := range fns {
+				if err := fn(tenant_id); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// keyDescAlgorithm is the schema descriptor for algorithm field.
+	keyDescAlgorithm := keyFields[1].Descriptor()
+	// key.AlgorithmValidator is a validator for the "algorithm" field. It is called by the builders before save.
+	key.AlgorithmValidator = keyDescAlgorithm.Validators[0].(func(string) error)
+	// keyDescPublicKey is the schema descriptor for public_key field.
+	keyDescPublicKey := keyFields[2].Descriptor()
+	// key.PublicKeyValidator is a validator for the "public_key" field. It is called by the builders before save.
+	key.PublicKeyValidator = keyDescPublicKey.Validators[0].(func(string) error)
+	// keyDescInsertedAt is the schema descriptor for inserted_at field.
+	keyDescInsertedAt := keyFields[5].Descriptor()
+	// key.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	key.DefaultInsertedAt = keyDescInsertedAt.Default.(func() time.Time)
+	// keyDescID is the schema descriptor for id field.
+	keyDescID := keyFields[0].Descriptor()
+	// key.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	key.IDValidator = keyDescID.Validators[0].(func(int) error)
+	ledgerentryMixin := schema.LedgerEntry{}.Mixin()
+	ledgerentryMixinHooks0 := ledgerentryMixin[0].Hooks()
+	ledgerentry.Hooks[0] = ledgerentryMixinHooks0[0]
+	ledgerentryFields := schema.LedgerEntry{}.Fields()
+	_ = ledgerentryFields
+	// ledgerentryDescPrevHash is the schema descriptor for prev_hash field.
+	ledgerentryDescPrevHash := ledgerentryFields[1].Descriptor()
+	// ledgerentry.PrevHashValidator is a validator for the "prev_hash" field. It is called by the builders before save.
+	ledgerentry.PrevHashValidator = ledgerentryDescPrevHash.Validators[0].(func(string) error)
+	// ledgerentryDescBatchHash is the schema descriptor for batch_hash field.
+	ledgerentryDescBatchHash := ledgerentryFields[2].Descriptor()
+	// ledgerentry.BatchHashValidator is a validator for the "batch_hash" field. It is called by the builders before save.
+	ledgerentry.BatchHashValidator = ledgerentryDescBatchHash.Validators[0].(func(string) error)
+	// ledgerentryDescSize is the schema descriptor for size field.
+	ledgerentryDescSize := ledgerentryFields[3].Descriptor()
+	// ledgerentry.SizeValidator is a validator for the "size" field. It is called by the builders before save.
+	ledgerentry.SizeValidator = ledgerentryDescSize.Validators[0].(func(int) error)
+	// ledgerentryDescHash is the schema descriptor for hash field.
+	ledgerentryDescHash := ledgerentryFields[4].Descriptor()
+	// ledgerentry.HashValidator is a validator for the "hash" field. It is called by the builders before save.
+	ledgerentry.HashValidator = ledgerentryDescHash.Validators[0].(func(string) error)
+	// ledgerentryDescInsertedAt is the schema descriptor for inserted_at field.
+	ledgerentryDescInsertedAt := ledgerentryFields[5].Descriptor()
+	// ledgerentry.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	ledgerentry.DefaultInsertedAt = ledgerentryDescInsertedAt.Default.(func() time.Time)
+	// ledgerentryDescID is the schema descriptor for id field.
+	ledgerentryDescID := ledgerentryFields[0].Descriptor()
+	// ledgerentry.IDValidator is a validator for the "id" field. It is called by the builders before save.
+	ledgerentry.IDValidator = ledgerentryDescID.Validators[0].(func(int) error)
+	recordMixin := schema.Record{}.Mixin()
+	recordMixinFields0 := recordMixin[0].Fields()
+	_ = recordMixinFields0
+	recordFields := schema.Record{}.Fields()
+	_ = recordFields
+	// recordDescTenantID is the schema descriptor for tenant_id field.
+	recordDescTenantID := recordMixinFields0[0].Descriptor()
+	// record.DefaultTenantID holds the default value on creation for the tenant_id field.
+	record.DefaultTenantID = recordDescTenantID.Default.(string)
+	// record.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	record.TenantIDValidator = func() func(string) error {
+		validators := recordDescTenantID.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(tenant_id string) error {
+			for _, fn := range fns {
+				if err := fn(tenant_id); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// recordDescInsertedAt is the schema descriptor for inserted_at field.
+	recordDescInsertedAt := recordFields[1].Descriptor()
+	// record.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	record.DefaultInsertedAt = recordDescInsertedAt.Default.(func() time.Time)
+	signatureMixin := schema.Signature{}.Mixin()
+	signatureMixinHooks0 := signatureMixin[0].Hooks()
+	signature.Hooks[0] = signatureMixinHooks0[0]
+	signatureMixinFields1 := signatureMixin[1].Fields()
+	_ = signatureMixinFields1
+	signatureFields := schema.Signature{}.Fields()
+	_ = signatureFields
+	// signatureDescTenantID is the schema descriptor for tenant_id field.
+	signatureDescTenantID := signatureMixinFields1[0].Descriptor()
+	// signature.DefaultTenantID holds the default value on creation for the tenant_id field.
+	signature.DefaultTenantID = signatureDescTenantID.Default.(string)
+	// signature.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	signature.TenantIDValidator = func() func(string) error {
+		validators := signatureDescTenantID.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(tenant_id string) error {
+			for _, fn := range fns {
+				if err := fn(tenant_id); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// signatureDescKeyID is the schema descriptor for key_id field.
+	signatureDescKeyID := signatureFields[1].Descriptor()
+	// signature.KeyIDValidator is a validator for the "key_id" field. It is called by the builders before save.
+	signature.KeyIDValidator = signatureDescKeyID.Validators[0].(func(int) error)
+	// signatureDescAlgorithm is the schema descriptor for algorithm field.
+	signatureDescAlgorithm := signatureFields[2].Descriptor()
+	// signature.DefaultAlgorithm holds the default value on creation for the algorithm field.
+	signature.DefaultAlgorithm = signatureDescAlgorithm.Default.(string)
+	// signatureDescValue is the schema descriptor for value field.
+	signatureDescValue := signatureFields[3].Descriptor()
+	// signature.ValueValidator is a validator for the "value" field. It is called by the builders before save.
+	signature.ValueValidator = signatureDescValue.Validators[0].(func(string) error)
+	// signatureDescInsertedAt is the schema descriptor for inserted_at field.
+	signatureDescInsertedAt := signatureFields[10].Descriptor()
+	// signature.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	signature.DefaultInsertedAt = signatureDescInsertedAt.Default.(func() time.Time)
+	webhookMixin := schema.Webhook{}.Mixin()
+	webhookMixinFields0 := webhookMixin[0].Fields()
+	_ = webhookMixinFields0
+	webhookFields := schema.Webhook{}.Fields()
+	_ = webhookFields
+	// webhookDescTenantID is the schema descriptor for tenant_id field.
+	webhookDescTenantID := webhookMixinFields0[0].Descriptor()
+	// webhook.DefaultTenantID holds the default value on creation for the tenant_id field.
+	webhook.DefaultTenantID = webhookDescTenantID.Default.(string)
+	// webhook.TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	webhook.TenantIDValidator = func() func(string) error {
+		validators := webhookDescTenantID.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(tenant_id string) error {
+			for _, fn := range fns {
+				if err := fn(tenant_id); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// webhookDescURL is the schema descriptor for url field.
+	webhookDescURL := webhookFields[0].Descriptor()
+	// webhook.URLValidator is a validator for the "url" field. It is called by the builders before save.
+	webhook.URLValidator = webhookDescURL.Validators[0].(func(string) error)
+	// webhookDescSecret is the schema descriptor for secret field.
+	webhookDescSecret := webhookFields[1].Descriptor()
+	// webhook.SecretValidator is a validator for the "secret" field. It is called by the builders before save.
+	webhook.SecretValidator = webhookDescSecret.Validators[0].(func(string) error)
+	// webhookDescActive is the schema descriptor for active field.
+	webhookDescActive := webhookFields[2].Descriptor()
+	// webhook.DefaultActive holds the default value on creation for the active field.
+	webhook.DefaultActive = webhookDescActive.Default.(bool)
+	// webhookDescInsertedAt is the schema descriptor for inserted_at field.
+	webhookDescInsertedAt := webhookFields[3].Descriptor()
+	// webhook.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	webhook.DefaultInsertedAt = webhookDescInsertedAt.Default.(func() time.Time)
+	webhookdeliveryFields := schema.WebhookDelivery{}.Fields()
+	_ = webhookdeliveryFields
+	// webhookdeliveryDescAttempt is the schema descriptor for attempt field.
+	webhookdeliveryDescAttempt := webhookdeliveryFields[2].Descriptor()
+	// webhookdelivery.AttemptValidator is a validator for the "attempt" field. It is called by the builders before save.
+	webhookdelivery.AttemptValidator = webhookdeliveryDescAttempt.Validators[0].(func(int) error)
+	// webhookdeliveryDescInsertedAt is the schema descriptor for inserted_at field.
+	webhookdeliveryDescInsertedAt := webhookdeliveryFields[6].Descriptor()
+	// webhookdelivery.DefaultInsertedAt holds the default value on creation for the inserted_at field.
+	webhookdelivery.DefaultInsertedAt = webhookdeliveryDescInsertedAt.Default.(func() time.Time)
+}
 
 const (
 	Version = "v0.14.4"                                         // Version of ent codegen.
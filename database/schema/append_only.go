@@ -0,0 +1,34 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/mixin"
+)
+
+// ErrAppendOnly is returned when an update or delete targets an append-only entity.
+var ErrAppendOnly = errors.New("append-only entity cannot be updated or deleted")
+
+// AppendOnly rejects every update and delete made through the ent client, so
+// rows of the schemas that mix it in can only be inserted. Raw SQL bypasses
+// it; the signature ledger is what detects such tampering.
+type AppendOnly struct {
+	mixin.Schema
+}
+
+// Hooks of the AppendOnly mixin.
+func (AppendOnly) Hooks() []ent.Hook {
+	return []ent.Hook{
+		func(next ent.Mutator) ent.Mutator {
+			return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+				if m.Op().Is(ent.OpUpdate | ent.OpUpdateOne | ent.OpDelete | ent.OpDeleteOne) {
+					return nil, fmt.Errorf("%w: %s on %s", ErrAppendOnly, m.Op(), m.Type())
+				}
+				return next.Mutate(ctx, m)
+			})
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+)
+
+// Key holds the schema definition for the Key entity. Only public material is
+// stored; private keys stay with the signer backend.
+type Key struct {
+	ent.Schema
+}
+
+// Mixin of the Key.
+func (Key) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Tenant{Mutable: true},
+	}
+}
+
+// Fields of the Key.
+func (Key) Fields() []ent.Field {
+	return []ent.Field{
+		// The key ID is drawn from the table's identity sequence by keys-service, so that it is
+		// never reused, and matches the keys.<tenant>.<id> subject.
+		field.Int("id").
+			Positive().
+			Immutable().
+			StructTag(`json:"id"`),
+		// JOSE algorithm name, e.g. ES256, ES384, EdDSA or PS256.
+		field.String("algorithm").
+			NotEmpty().
+			StructTag(`json:"algorithm"`),
+		// Base64 PKIX DER public key.
+		field.String("public_key").
+			NotEmpty().
+			StructTag(`json:"public_key"`),
+		// Base64 DER X.509 certificate for the public key.
+		field.String("certificate").
+			Optional().
+			StructTag(`json:"certificate,omitempty"`),
+		// Base64 DER certificates from the issuer up to the root CA.
+		field.Strings("certificate_chain").
+			Optional().
+			StructTag(`json:"certificate_chain,omitempty"`),
+		// Creation timestamp.
+		field.Time("inserted_at").
+			Default(time.Now).
+			StructTag(`json:"inserted_at"`),
+	}
+}
@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// LedgerEntry holds the schema definition for the LedgerEntry entity: one entry
+// per inserted signature batch, chained to the entry before it.
+type LedgerEntry struct {
+	ent.Schema
+}
+
+// Annotations of the LedgerEntry.
+func (LedgerEntry) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "ledger"},
+	}
+}
+
+// Mixin of the LedgerEntry.
+func (LedgerEntry) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		AppendOnly{},
+	}
+}
+
+// Fields of the LedgerEntry.
+func (LedgerEntry) Fields() []ent.Field {
+	return []ent.Field{
+		// The sequence number, starting at 1.
+		field.Int("id").
+			Positive().
+			Immutable().
+			StructTag(`json:"seq"`),
+		// Hex SHA-256 hash of the previous entry, zeros for the first one.
+		field.String("prev_hash").
+			NotEmpty().
+			Immutable().
+			StructTag(`json:"prev_hash"`),
+		// Hex SHA-256 hash of the batch's signatures.
+		field.String("batch_hash").
+			NotEmpty().
+			Immutable().
+			StructTag(`json:"batch_hash"`),
+		// Number of signatures in the batch.
+		field.Int("size").
+			NonNegative().
+			Immutable().
+			StructTag(`json:"size"`),
+		// Hex SHA-256 hash of this entry; the ledger head is the latest one.
+		field.String("hash").
+			NotEmpty().
+			Unique().
+			Immutable().
+			StructTag(`json:"hash"`),
+		// Creation timestamp.
+		field.Time("inserted_at").
+			Default(time.Now).
+			Immutable().
+			StructTag(`json:"inserted_at"`),
+	}
+}
+
+// Edges of the LedgerEntry.
+func (LedgerEntry) Edges() []ent.Edge {
+	return []ent.Edge{
+		// The signatures of the batch the entry commits to, by their ledger_seq.
+		edge.To("signatures", Signature.Type),
+	}
+}
@@ -13,6 +13,13 @@ type Record struct {
 	ent.Schema
 }
 
+// Mixin of the Record.
+func (Record) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Tenant{},
+	}
+}
+
 // Fields of the Record.
 func (Record) Fields() []ent.Field {
 	return []ent.Field{
@@ -23,6 +30,12 @@ func (Record) Fields() []ent.Field {
 		field.Time("inserted_at").
 			Default(time.Now).
 			StructTag(`json:"inserted_at"`),
+		// Optional content submitted through api-service; it is part of the
+		// signed payload.
+		field.Bytes("data").
+			Optional().
+			Immutable().
+			StructTag(`json:"data,omitempty"`),
 	}
 }
 
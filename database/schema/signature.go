@@ -14,6 +14,14 @@ type Signature struct {
 	ent.Schema
 }
 
+// Mixin of the Signature.
+func (Signature) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		AppendOnly{},
+		Tenant{},
+	}
+}
+
 // Fields of the Signature.
 func (Signature) Fields() []ent.Field {
 	return []ent.Field{
@@ -26,10 +34,49 @@ func (Signature) Fields() []ent.Field {
 		field.Int("key_id").
 			Positive().
 			StructTag(`json:"key_id"`),
-		// The signature value as non-empty text.
+		// JOSE algorithm name of the signing key; rows from before the
+		// column existed are ES256.
+		field.String("algorithm").
+			Default("ES256").
+			Immutable().
+			StructTag(`json:"algorithm"`),
+		// The signature value as non-empty text. In Merkle mode it signs the
+		// batch tree head, so every row of the batch shares it.
 		field.String("value").
 			NotEmpty().
 			StructTag(`json:"value"`),
+		// Base64 DER RFC 3161 time-stamp token over the raw signature value,
+		// present when signing-service runs with a TSA.
+		field.String("timestamp_token").
+			Optional().
+			Immutable().
+			StructTag(`json:"timestamp_token,omitempty"`),
+		// Merkle mode only: the signed base64 tree head and the inclusion proof
+		// of this record's leaf (index, tree size, base64 sibling hashes).
+		field.String("merkle_root").
+			Optional().
+			Immutable().
+			StructTag(`json:"merkle_root,omitempty"`),
+		field.Int("merkle_leaf_index").
+			Optional().
+			Nillable().
+			Immutable().
+			StructTag(`json:"merkle_leaf_index,omitempty"`),
+		field.Int("merkle_tree_size").
+			Optional().
+			Nillable().
+			Immutable().
+			StructTag(`json:"merkle_tree_size,omitempty"`),
+		field.Strings("merkle_path").
+			Optional().
+			Immutable().
+			StructTag(`json:"merkle_path,omitempty"`),
+		// Sequence number of the ledger entry that committed to this row.
+		field.Int("ledger_seq").
+			Optional().
+			Nillable().
+			Immutable().
+			StructTag(`json:"ledger_seq,omitempty"`),
 		// Creation timestamp.
 		field.Time("inserted_at").
 			Default(time.Now).
@@ -48,12 +95,21 @@ func (Signature) Edges() []ent.Edge {
 			Required().
 			Immutable(). // <-- Added to match the immutable field.
 			Field("record_id"),
+		// The ledger entry that committed to the signature, if any.
+		edge.From("ledger", LedgerEntry.Type).
+			Ref("signatures").
+			Unique().
+			Immutable().
+			Field("ledger_seq"),
 	}
 }
 
 // Indexes of the Signature.
 func (Signature) Indexes() []ent.Index {
 	return []ent.Index{
-		index.Fields("value").Unique(),
+		// Not unique: Merkle mode stores one signature value per batch.
+		index.Fields("value"),
+		index.Fields("merkle_root"),
+		index.Fields("ledger_seq"),
 	}
 }
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+)
+
+// Tenant scopes the rows of the schemas that mix it in to one tenant. Rows
+// from before tenants existed belong to the "default" tenant.
+type Tenant struct {
+	mixin.Schema
+	// Mutable lets rows move to another tenant. Only keys need it: their IDs
+	// are reassigned whenever keys-service provisions the pools again.
+	Mutable bool
+}
+
+// Fields of the Tenant mixin.
+func (t Tenant) Fields() []ent.Field {
+	// A single NATS subject token, see types.ValidateTenant.
+	tenantID := field.String("tenant_id").
+		NotEmpty().
+		MaxLen(64).
+		Default("default").
+		StructTag(`json:"tenant_id"`)
+	if !t.Mutable {
+		tenantID.Immutable()
+	}
+	return []ent.Field{tenantID}
+}
+
+// Indexes of the Tenant mixin.
+func (Tenant) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("tenant_id"),
+	}
+}
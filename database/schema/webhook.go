@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// Webhook holds the schema definition for the Webhook entity: a partner
+// endpoint notified when signatures of its tenant are committed.
+type Webhook struct {
+	ent.Schema
+}
+
+// Mixin of the Webhook.
+func (Webhook) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		Tenant{},
+	}
+}
+
+// Fields of the Webhook.
+func (Webhook) Fields() []ent.Field {
+	return []ent.Field{
+		// Endpoint receiving the signatures events as POST requests.
+		field.String("url").
+			NotEmpty().
+			StructTag(`json:"url"`),
+		// Shared secret the payload HMAC is computed with.
+		field.String("secret").
+			NotEmpty().
+			Sensitive(),
+		// Inactive webhooks are kept but not delivered to.
+		field.Bool("active").
+			Default(true).
+			StructTag(`json:"active"`),
+		// Creation timestamp.
+		field.Time("inserted_at").
+			Default(time.Now).
+			Immutable().
+			StructTag(`json:"inserted_at"`),
+	}
+}
+
+// Edges of the Webhook.
+func (Webhook) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("deliveries", WebhookDelivery.Type),
+	}
+}
@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// WebhookDelivery holds the schema definition for the WebhookDelivery entity:
+// one attempt at delivering a signatures event to a webhook.
+type WebhookDelivery struct {
+	ent.Schema
+}
+
+// Fields of the WebhookDelivery.
+func (WebhookDelivery) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int("webhook_id").
+			Immutable().
+			StructTag(`json:"webhook_id"`),
+		// Ledger sequence of the delivered batch, i.e. the event's signatures.<seq>.
+		field.Int("ledger_seq").
+			Immutable().
+			StructTag(`json:"ledger_seq"`),
+		// Attempt number, starting at 1.
+		field.Int("attempt").
+			Positive().
+			Immutable().
+			StructTag(`json:"attempt"`),
+		// HTTP status of the response, unset when no response was received.
+		field.Int("status_code").
+			Optional().
+			Nillable().
+			Immutable().
+			StructTag(`json:"status_code,omitempty"`),
+		field.String("error").
+			Optional().
+			Immutable().
+			StructTag(`json:"error,omitempty"`),
+		field.Bool("succeeded").
+			Immutable().
+			StructTag(`json:"succeeded"`),
+		// Creation timestamp, i.e. when the attempt was made.
+		field.Time("inserted_at").
+			Default(time.Now).
+			Immutable().
+			StructTag(`json:"inserted_at"`),
+	}
+}
+
+// Edges of the WebhookDelivery.
+func (WebhookDelivery) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("webhook", Webhook.Type).
+			Ref("deliveries").
+			Unique().
+			Required().
+			Immutable().
+			Field("webhook_id"),
+	}
+}
+
+// Indexes of the WebhookDelivery.
+func (WebhookDelivery) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("webhook_id", "ledger_seq"),
+	}
+}
@@ -3,12 +3,14 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
 	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/database/signature"
 )
@@ -18,12 +20,28 @@ type Signature struct {
 	config `json:"-"`
 	// ID of the ent.
 	ID int `json:"id,omitempty"`
+	// TenantID holds the value of the "tenant_id" field.
+	TenantID string `json:"tenant_id"`
 	// RecordID holds the value of the "record_id" field.
 	RecordID int `json:"record_id"`
 	// KeyID holds the value of the "key_id" field.
 	KeyID int `json:"key_id"`
+	// Algorithm holds the value of the "algorithm" field.
+	Algorithm string `json:"algorithm"`
 	// Value holds the value of the "value" field.
 	Value string `json:"value"`
+	// TimestampToken holds the value of the "timestamp_token" field.
+	TimestampToken string `json:"timestamp_token,omitempty"`
+	// MerkleRoot holds the value of the "merkle_root" field.
+	MerkleRoot string `json:"merkle_root,omitempty"`
+	// MerkleLeafIndex holds the value of the "merkle_leaf_index" field.
+	MerkleLeafIndex *int `json:"merkle_leaf_index,omitempty"`
+	// MerkleTreeSize holds the value of the "merkle_tree_size" field.
+	MerkleTreeSize *int `json:"merkle_tree_size,omitempty"`
+	// MerklePath holds the value of the "merkle_path" field.
+	MerklePath []string `json:"merkle_path,omitempty"`
+	// LedgerSeq holds the value of the "ledger_seq" field.
+	LedgerSeq *int `json:"ledger_seq,omitempty"`
 	// InsertedAt holds the value of the "inserted_at" field.
 	InsertedAt time.Time `json:"inserted_at"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -36,9 +54,11 @@ type Signature struct {
 type SignatureEdges struct {
 	// Record holds the value of the record edge.
 	Record *Record `json:"record,omitempty"`
+	// Ledger holds the value of the ledger edge.
+	Ledger *LedgerEntry `json:"ledger,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [1]bool
+	loadedTypes [2]bool
 }
 
 // RecordOrErr returns the Record value or an error if the edge
@@ -52,14 +72,27 @@ func (e SignatureEdges) RecordOrErr() (*Record, error) {
 	return nil, &NotLoadedError{edge: "record"}
 }
 
+// LedgerOrErr returns the Ledger value or an error if the edge
+// was not loaded in eager-loading, or loaded but was not found.
+func (e SignatureEdges) LedgerOrErr() (*LedgerEntry, error) {
+	if e.Ledger != nil {
+		return e.Ledger, nil
+	} else if e.loadedTypes[1] {
+		return nil, &NotFoundError{label: ledgerentry.Label}
+	}
+	return nil, &NotLoadedError{edge: "ledger"}
+}
+
 // scanValues returns the types for scanning values from sql.Rows.
 func (*Signature) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case signature.FieldID, signature.FieldRecordID, signature.FieldKeyID:
+		case signature.FieldMerklePath:
+			values[i] = new([]byte)
+		case signature.FieldID, signature.FieldRecordID, signature.FieldKeyID, signature.FieldMerkleLeafIndex, signature.FieldMerkleTreeSize, signature.FieldLedgerSeq:
 			values[i] = new(sql.NullInt64)
-		case signature.FieldValue:
+		case signature.FieldTenantID, signature.FieldAlgorithm, signature.FieldValue, signature.FieldTimestampToken, signature.FieldMerkleRoot:
 			values[i] = new(sql.NullString)
 		case signature.FieldInsertedAt:
 			values[i] = new(sql.NullTime)
@@ -84,6 +117,12 @@ func (s *Signature) assignValues(columns []string, values []any) error {
 				return fmt.Errorf("unexpected type %T for field id", value)
 			}
 			s.ID = int(value.Int64)
+		case signature.FieldTenantID:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field tenant_id", values[i])
+			} else if value.Valid {
+				s.TenantID = value.String
+			}
 		case signature.FieldRecordID:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field record_id", values[i])
@@ -96,12 +135,59 @@ func (s *Signature) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				s.KeyID = int(value.Int64)
 			}
+		case signature.FieldAlgorithm:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field algorithm", values[i])
+			} else if value.Valid {
+				s.Algorithm = value.String
+			}
 		case signature.FieldValue:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field value", values[i])
 			} else if value.Valid {
 				s.Value = value.String
 			}
+		case signature.FieldTimestampToken:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field timestamp_token", values[i])
+			} else if value.Valid {
+				s.TimestampToken = value.String
+			}
+		case signature.FieldMerkleRoot:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field merkle_root", values[i])
+			} else if value.Valid {
+				s.MerkleRoot = value.String
+			}
+		case signature.FieldMerkleLeafIndex:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field merkle_leaf_index", values[i])
+			} else if value.Valid {
+				s.MerkleLeafIndex = new(int)
+				*s.MerkleLeafIndex = int(value.Int64)
+			}
+		case signature.FieldMerkleTreeSize:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field merkle_tree_size", values[i])
+			} else if value.Valid {
+				s.MerkleTreeSize = new(int)
+				*s.MerkleTreeSize = int(value.Int64)
+			}
+		case signature.FieldMerklePath:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field merkle_path", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &s.MerklePath); err != nil {
+					return fmt.Errorf("unmarshal field merkle_path: %w", err)
+				}
+			}
+		case signature.FieldLedgerSeq:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field ledger_seq", values[i])
+			} else if value.Valid {
+				s.LedgerSeq = new(int)
+				*s.LedgerSeq = int(value.Int64)
+			}
 		case signature.FieldInsertedAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field inserted_at", values[i])
@@ -126,6 +212,11 @@ func (s *Signature) QueryRecord() *RecordQuery {
 	return NewSignatureClient(s.config).QueryRecord(s)
 }
 
+// QueryLedger queries the "ledger" edge of the Signature entity.
+func (s *Signature) QueryLedger() *LedgerEntryQuery {
+	return NewSignatureClient(s.config).QueryLedger(s)
+}
+
 // Update returns a builder for updating this Signature.
 // Note that you need to call Signature.Unwrap() before calling this method if this Signature
 // was returned from a transaction, and the transaction was committed or rolled back.
@@ -149,15 +240,45 @@ func (s *Signature) String() string {
 	var builder strings.Builder
 	builder.WriteString("Signature(")
 	builder.WriteString(fmt.Sprintf("id=%v, ", s.ID))
+	builder.WriteString("tenant_id=")
+	builder.WriteString(s.TenantID)
+	builder.WriteString(", ")
 	builder.WriteString("record_id=")
 	builder.WriteString(fmt.Sprintf("%v", s.RecordID))
 	builder.WriteString(", ")
 	builder.WriteString("key_id=")
 	builder.WriteString(fmt.Sprintf("%v", s.KeyID))
 	builder.WriteString(", ")
+	builder.WriteString("algorithm=")
+	builder.WriteString(s.Algorithm)
+	builder.WriteString(", ")
 	builder.WriteString("value=")
 	builder.WriteString(s.Value)
 	builder.WriteString(", ")
+	builder.WriteString("timestamp_token=")
+	builder.WriteString(s.TimestampToken)
+	builder.WriteString(", ")
+	builder.WriteString("merkle_root=")
+	builder.WriteString(s.MerkleRoot)
+	builder.WriteString(", ")
+	if v := s.MerkleLeafIndex; v != nil {
+		builder.WriteString("merkle_leaf_index=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	if v := s.MerkleTreeSize; v != nil {
+		builder.WriteString("merkle_tree_size=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("merkle_path=")
+	builder.WriteString(fmt.Sprintf("%v", s.MerklePath))
+	builder.WriteString(", ")
+	if v := s.LedgerSeq; v != nil {
+		builder.WriteString("ledger_seq=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
 	builder.WriteString("inserted_at=")
 	builder.WriteString(s.InsertedAt.Format(time.ANSIC))
 	builder.WriteByte(')')
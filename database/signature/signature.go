@@ -5,6 +5,7 @@ package signature
 import (
 	"time"
 
+	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 )
@@ -14,16 +15,34 @@ const (
 	Label = "signature"
 	// FieldID holds the string denoting the id field in the database.
 	FieldID = "id"
+	// FieldTenantID holds the string denoting the tenant_id field in the database.
+	FieldTenantID = "tenant_id"
 	// FieldRecordID holds the string denoting the record_id field in the database.
 	FieldRecordID = "record_id"
 	// FieldKeyID holds the string denoting the key_id field in the database.
 	FieldKeyID = "key_id"
+	// FieldAlgorithm holds the string denoting the algorithm field in the database.
+	FieldAlgorithm = "algorithm"
 	// FieldValue holds the string denoting the value field in the database.
 	FieldValue = "value"
+	// FieldTimestampToken holds the string denoting the timestamp_token field in the database.
+	FieldTimestampToken = "timestamp_token"
+	// FieldMerkleRoot holds the string denoting the merkle_root field in the database.
+	FieldMerkleRoot = "merkle_root"
+	// FieldMerkleLeafIndex holds the string denoting the merkle_leaf_index field in the database.
+	FieldMerkleLeafIndex = "merkle_leaf_index"
+	// FieldMerkleTreeSize holds the string denoting the merkle_tree_size field in the database.
+	FieldMerkleTreeSize = "merkle_tree_size"
+	// FieldMerklePath holds the string denoting the merkle_path field in the database.
+	FieldMerklePath = "merkle_path"
+	// FieldLedgerSeq holds the string denoting the ledger_seq field in the database.
+	FieldLedgerSeq = "ledger_seq"
 	// FieldInsertedAt holds the string denoting the inserted_at field in the database.
 	FieldInsertedAt = "inserted_at"
 	// EdgeRecord holds the string denoting the record edge name in mutations.
 	EdgeRecord = "record"
+	// EdgeLedger holds the string denoting the ledger edge name in mutations.
+	EdgeLedger = "ledger"
 	// Table holds the table name of the signature in the database.
 	Table = "signatures"
 	// RecordTable is the table that holds the record relation/edge.
@@ -33,14 +52,29 @@ const (
 	RecordInverseTable = "records"
 	// RecordColumn is the table column denoting the record relation/edge.
 	RecordColumn = "record_id"
+	// LedgerTable is the table that holds the ledger relation/edge.
+	LedgerTable = "signatures"
+	// LedgerInverseTable is the table name for the LedgerEntry entity.
+	// It exists in this package in order to avoid circular dependency with the "ledgerentry" package.
+	LedgerInverseTable = "ledger"
+	// LedgerColumn is the table column denoting the ledger relation/edge.
+	LedgerColumn = "ledger_seq"
 )
 
 // Columns holds all SQL columns for signature fields.
 var Columns = []string{
 	FieldID,
+	FieldTenantID,
 	FieldRecordID,
 	FieldKeyID,
+	FieldAlgorithm,
 	FieldValue,
+	FieldTimestampToken,
+	FieldMerkleRoot,
+	FieldMerkleLeafIndex,
+	FieldMerkleTreeSize,
+	FieldMerklePath,
+	FieldLedgerSeq,
 	FieldInsertedAt,
 }
 
@@ -54,9 +88,21 @@ func ValidColumn(column string) bool {
 	return false
 }
 
+// Note that the variables below are initialized by the runtime
+// package on the initialization of the application. Therefore,
+// it should be imported in the main as follows:
+//
+//	import _ "github.com/jurshsmith/vaultstream/database/runtime"
 var (
+	Hooks [1]ent.Hook
+	// DefaultTenantID holds the default value on creation for the "tenant_id" field.
+	DefaultTenantID string
+	// TenantIDValidator is a validator for the "tenant_id" field. It is called by the builders before save.
+	TenantIDValidator func(string) error
 	// KeyIDValidator is a validator for the "key_id" field. It is called by the builders before save.
 	KeyIDValidator func(int) error
+	// DefaultAlgorithm holds the default value on creation for the "algorithm" field.
+	DefaultAlgorithm string
 	// ValueValidator is a validator for the "value" field. It is called by the builders before save.
 	ValueValidator func(string) error
 	// DefaultInsertedAt holds the default value on creation for the "inserted_at" field.
@@ -71,6 +117,11 @@ func ByID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldID, opts...).ToFunc()
 }
 
+// ByTenantID orders the results by the tenant_id field.
+func ByTenantID(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTenantID, opts...).ToFunc()
+}
+
 // ByRecordID orders the results by the record_id field.
 func ByRecordID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldRecordID, opts...).ToFunc()
@@ -81,11 +132,41 @@ func ByKeyID(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldKeyID, opts...).ToFunc()
 }
 
+// ByAlgorithm orders the results by the algorithm field.
+func ByAlgorithm(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldAlgorithm, opts...).ToFunc()
+}
+
 // ByValue orders the results by the value field.
 func ByValue(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldValue, opts...).ToFunc()
 }
 
+// ByTimestampToken orders the results by the timestamp_token field.
+func ByTimestampToken(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldTimestampToken, opts...).ToFunc()
+}
+
+// ByMerkleRoot orders the results by the merkle_root field.
+func ByMerkleRoot(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMerkleRoot, opts...).ToFunc()
+}
+
+// ByMerkleLeafIndex orders the results by the merkle_leaf_index field.
+func ByMerkleLeafIndex(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMerkleLeafIndex, opts...).ToFunc()
+}
+
+// ByMerkleTreeSize orders the results by the merkle_tree_size field.
+func ByMerkleTreeSize(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldMerkleTreeSize, opts...).ToFunc()
+}
+
+// ByLedgerSeq orders the results by the ledger_seq field.
+func ByLedgerSeq(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldLedgerSeq, opts...).ToFunc()
+}
+
 // ByInsertedAt orders the results by the inserted_at field.
 func ByInsertedAt(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldInsertedAt, opts...).ToFunc()
@@ -97,6 +178,13 @@ func ByRecordField(field string, opts ...sql.OrderTermOption) OrderOption {
 		sqlgraph.OrderByNeighborTerms(s, newRecordStep(), sql.OrderByField(field, opts...))
 	}
 }
+
+// ByLedgerField orders the results by ledger field.
+func ByLedgerField(field string, opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newLedgerStep(), sql.OrderByField(field, opts...))
+	}
+}
 func newRecordStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
@@ -104,3 +192,10 @@ func newRecordStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.O2O, true, RecordTable, RecordColumn),
 	)
 }
+func newLedgerStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(LedgerInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.M2O, true, LedgerTable, LedgerColumn),
+	)
+}
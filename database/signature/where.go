@@ -55,6 +55,11 @@ func IDLTE(id int) predicate.Signature {
 	return predicate.Signature(sql.FieldLTE(FieldID, id))
 }
 
+// TenantID applies equality check predicate on the "tenant_id" field. It's identical to TenantIDEQ.
+func TenantID(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldTenantID, v))
+}
+
 // RecordID applies equality check predicate on the "record_id" field. It's identical to RecordIDEQ.
 func RecordID(v int) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldRecordID, v))
@@ -65,16 +70,111 @@ func KeyID(v int) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldKeyID, v))
 }
 
+// Algorithm applies equality check predicate on the "algorithm" field. It's identical to AlgorithmEQ.
+func Algorithm(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldAlgorithm, v))
+}
+
 // Value applies equality check predicate on the "value" field. It's identical to ValueEQ.
 func Value(v string) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldValue, v))
 }
 
+// TimestampToken applies equality check predicate on the "timestamp_token" field. It's identical to TimestampTokenEQ.
+func TimestampToken(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldTimestampToken, v))
+}
+
+// MerkleRoot applies equality check predicate on the "merkle_root" field. It's identical to MerkleRootEQ.
+func MerkleRoot(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleRoot, v))
+}
+
+// MerkleLeafIndex applies equality check predicate on the "merkle_leaf_index" field. It's identical to MerkleLeafIndexEQ.
+func MerkleLeafIndex(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleLeafIndex, v))
+}
+
+// MerkleTreeSize applies equality check predicate on the "merkle_tree_size" field. It's identical to MerkleTreeSizeEQ.
+func MerkleTreeSize(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleTreeSize, v))
+}
+
+// LedgerSeq applies equality check predicate on the "ledger_seq" field. It's identical to LedgerSeqEQ.
+func LedgerSeq(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldLedgerSeq, v))
+}
+
 // InsertedAt applies equality check predicate on the "inserted_at" field. It's identical to InsertedAtEQ.
 func InsertedAt(v time.Time) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldInsertedAt, v))
 }
 
+// TenantIDEQ applies the EQ predicate on the "tenant_id" field.
+func TenantIDEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldTenantID, v))
+}
+
+// TenantIDNEQ applies the NEQ predicate on the "tenant_id" field.
+func TenantIDNEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldTenantID, v))
+}
+
+// TenantIDIn applies the In predicate on the "tenant_id" field.
+func TenantIDIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldTenantID, vs...))
+}
+
+// TenantIDNotIn applies the NotIn predicate on the "tenant_id" field.
+func TenantIDNotIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldTenantID, vs...))
+}
+
+// TenantIDGT applies the GT predicate on the "tenant_id" field.
+func TenantIDGT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldTenantID, v))
+}
+
+// TenantIDGTE applies the GTE predicate on the "tenant_id" field.
+func TenantIDGTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldTenantID, v))
+}
+
+// TenantIDLT applies the LT predicate on the "tenant_id" field.
+func TenantIDLT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldTenantID, v))
+}
+
+// TenantIDLTE applies the LTE predicate on the "tenant_id" field.
+func TenantIDLTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldTenantID, v))
+}
+
+// TenantIDContains applies the Contains predicate on the "tenant_id" field.
+func TenantIDContains(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContains(FieldTenantID, v))
+}
+
+// TenantIDHasPrefix applies the HasPrefix predicate on the "tenant_id" field.
+func TenantIDHasPrefix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasPrefix(FieldTenantID, v))
+}
+
+// TenantIDHasSuffix applies the HasSuffix predicate on the "tenant_id" field.
+func TenantIDHasSuffix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasSuffix(FieldTenantID, v))
+}
+
+// TenantIDEqualFold applies the EqualFold predicate on the "tenant_id" field.
+func TenantIDEqualFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEqualFold(FieldTenantID, v))
+}
+
+// TenantIDContainsFold applies the ContainsFold predicate on the "tenant_id" field.
+func TenantIDContainsFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContainsFold(FieldTenantID, v))
+}
+
 // RecordIDEQ applies the EQ predicate on the "record_id" field.
 func RecordIDEQ(v int) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldRecordID, v))
@@ -135,6 +235,71 @@ func KeyIDLTE(v int) predicate.Signature {
 	return predicate.Signature(sql.FieldLTE(FieldKeyID, v))
 }
 
+// AlgorithmEQ applies the EQ predicate on the "algorithm" field.
+func AlgorithmEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmNEQ applies the NEQ predicate on the "algorithm" field.
+func AlgorithmNEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldAlgorithm, v))
+}
+
+// AlgorithmIn applies the In predicate on the "algorithm" field.
+func AlgorithmIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldAlgorithm, vs...))
+}
+
+// AlgorithmNotIn applies the NotIn predicate on the "algorithm" field.
+func AlgorithmNotIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldAlgorithm, vs...))
+}
+
+// AlgorithmGT applies the GT predicate on the "algorithm" field.
+func AlgorithmGT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldAlgorithm, v))
+}
+
+// AlgorithmGTE applies the GTE predicate on the "algorithm" field.
+func AlgorithmGTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldAlgorithm, v))
+}
+
+// AlgorithmLT applies the LT predicate on the "algorithm" field.
+func AlgorithmLT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldAlgorithm, v))
+}
+
+// AlgorithmLTE applies the LTE predicate on the "algorithm" field.
+func AlgorithmLTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldAlgorithm, v))
+}
+
+// AlgorithmContains applies the Contains predicate on the "algorithm" field.
+func AlgorithmContains(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContains(FieldAlgorithm, v))
+}
+
+// AlgorithmHasPrefix applies the HasPrefix predicate on the "algorithm" field.
+func AlgorithmHasPrefix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasPrefix(FieldAlgorithm, v))
+}
+
+// AlgorithmHasSuffix applies the HasSuffix predicate on the "algorithm" field.
+func AlgorithmHasSuffix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasSuffix(FieldAlgorithm, v))
+}
+
+// AlgorithmEqualFold applies the EqualFold predicate on the "algorithm" field.
+func AlgorithmEqualFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEqualFold(FieldAlgorithm, v))
+}
+
+// AlgorithmContainsFold applies the ContainsFold predicate on the "algorithm" field.
+func AlgorithmContainsFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContainsFold(FieldAlgorithm, v))
+}
+
 // ValueEQ applies the EQ predicate on the "value" field.
 func ValueEQ(v string) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldValue, v))
@@ -200,6 +365,296 @@ func ValueContainsFold(v string) predicate.Signature {
 	return predicate.Signature(sql.FieldContainsFold(FieldValue, v))
 }
 
+// TimestampTokenEQ applies the EQ predicate on the "timestamp_token" field.
+func TimestampTokenEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldTimestampToken, v))
+}
+
+// TimestampTokenNEQ applies the NEQ predicate on the "timestamp_token" field.
+func TimestampTokenNEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldTimestampToken, v))
+}
+
+// TimestampTokenIn applies the In predicate on the "timestamp_token" field.
+func TimestampTokenIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldTimestampToken, vs...))
+}
+
+// TimestampTokenNotIn applies the NotIn predicate on the "timestamp_token" field.
+func TimestampTokenNotIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldTimestampToken, vs...))
+}
+
+// TimestampTokenGT applies the GT predicate on the "timestamp_token" field.
+func TimestampTokenGT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldTimestampToken, v))
+}
+
+// TimestampTokenGTE applies the GTE predicate on the "timestamp_token" field.
+func TimestampTokenGTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldTimestampToken, v))
+}
+
+// TimestampTokenLT applies the LT predicate on the "timestamp_token" field.
+func TimestampTokenLT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldTimestampToken, v))
+}
+
+// TimestampTokenLTE applies the LTE predicate on the "timestamp_token" field.
+func TimestampTokenLTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldTimestampToken, v))
+}
+
+// TimestampTokenContains applies the Contains predicate on the "timestamp_token" field.
+func TimestampTokenContains(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContains(FieldTimestampToken, v))
+}
+
+// TimestampTokenHasPrefix applies the HasPrefix predicate on the "timestamp_token" field.
+func TimestampTokenHasPrefix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasPrefix(FieldTimestampToken, v))
+}
+
+// TimestampTokenHasSuffix applies the HasSuffix predicate on the "timestamp_token" field.
+func TimestampTokenHasSuffix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasSuffix(FieldTimestampToken, v))
+}
+
+// TimestampTokenIsNil applies the IsNil predicate on the "timestamp_token" field.
+func TimestampTokenIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldTimestampToken))
+}
+
+// TimestampTokenNotNil applies the NotNil predicate on the "timestamp_token" field.
+func TimestampTokenNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldTimestampToken))
+}
+
+// TimestampTokenEqualFold applies the EqualFold predicate on the "timestamp_token" field.
+func TimestampTokenEqualFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEqualFold(FieldTimestampToken, v))
+}
+
+// TimestampTokenContainsFold applies the ContainsFold predicate on the "timestamp_token" field.
+func TimestampTokenContainsFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContainsFold(FieldTimestampToken, v))
+}
+
+// MerkleRootEQ applies the EQ predicate on the "merkle_root" field.
+func MerkleRootEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleRoot, v))
+}
+
+// MerkleRootNEQ applies the NEQ predicate on the "merkle_root" field.
+func MerkleRootNEQ(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldMerkleRoot, v))
+}
+
+// MerkleRootIn applies the In predicate on the "merkle_root" field.
+func MerkleRootIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldMerkleRoot, vs...))
+}
+
+// MerkleRootNotIn applies the NotIn predicate on the "merkle_root" field.
+func MerkleRootNotIn(vs ...string) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldMerkleRoot, vs...))
+}
+
+// MerkleRootGT applies the GT predicate on the "merkle_root" field.
+func MerkleRootGT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldMerkleRoot, v))
+}
+
+// MerkleRootGTE applies the GTE predicate on the "merkle_root" field.
+func MerkleRootGTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldMerkleRoot, v))
+}
+
+// MerkleRootLT applies the LT predicate on the "merkle_root" field.
+func MerkleRootLT(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldMerkleRoot, v))
+}
+
+// MerkleRootLTE applies the LTE predicate on the "merkle_root" field.
+func MerkleRootLTE(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldMerkleRoot, v))
+}
+
+// MerkleRootContains applies the Contains predicate on the "merkle_root" field.
+func MerkleRootContains(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContains(FieldMerkleRoot, v))
+}
+
+// MerkleRootHasPrefix applies the HasPrefix predicate on the "merkle_root" field.
+func MerkleRootHasPrefix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasPrefix(FieldMerkleRoot, v))
+}
+
+// MerkleRootHasSuffix applies the HasSuffix predicate on the "merkle_root" field.
+func MerkleRootHasSuffix(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldHasSuffix(FieldMerkleRoot, v))
+}
+
+// MerkleRootIsNil applies the IsNil predicate on the "merkle_root" field.
+func MerkleRootIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldMerkleRoot))
+}
+
+// MerkleRootNotNil applies the NotNil predicate on the "merkle_root" field.
+func MerkleRootNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldMerkleRoot))
+}
+
+// MerkleRootEqualFold applies the EqualFold predicate on the "merkle_root" field.
+func MerkleRootEqualFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldEqualFold(FieldMerkleRoot, v))
+}
+
+// MerkleRootContainsFold applies the ContainsFold predicate on the "merkle_root" field.
+func MerkleRootContainsFold(v string) predicate.Signature {
+	return predicate.Signature(sql.FieldContainsFold(FieldMerkleRoot, v))
+}
+
+// MerkleLeafIndexEQ applies the EQ predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexNEQ applies the NEQ predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexNEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexIn applies the In predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldMerkleLeafIndex, vs...))
+}
+
+// MerkleLeafIndexNotIn applies the NotIn predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexNotIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldMerkleLeafIndex, vs...))
+}
+
+// MerkleLeafIndexGT applies the GT predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexGT(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexGTE applies the GTE predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexGTE(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexLT applies the LT predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexLT(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexLTE applies the LTE predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexLTE(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldMerkleLeafIndex, v))
+}
+
+// MerkleLeafIndexIsNil applies the IsNil predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldMerkleLeafIndex))
+}
+
+// MerkleLeafIndexNotNil applies the NotNil predicate on the "merkle_leaf_index" field.
+func MerkleLeafIndexNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldMerkleLeafIndex))
+}
+
+// MerkleTreeSizeEQ applies the EQ predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeNEQ applies the NEQ predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeNEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeIn applies the In predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldMerkleTreeSize, vs...))
+}
+
+// MerkleTreeSizeNotIn applies the NotIn predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeNotIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldMerkleTreeSize, vs...))
+}
+
+// MerkleTreeSizeGT applies the GT predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeGT(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldGT(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeGTE applies the GTE predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeGTE(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldGTE(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeLT applies the LT predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeLT(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldLT(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeLTE applies the LTE predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeLTE(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldLTE(FieldMerkleTreeSize, v))
+}
+
+// MerkleTreeSizeIsNil applies the IsNil predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldMerkleTreeSize))
+}
+
+// MerkleTreeSizeNotNil applies the NotNil predicate on the "merkle_tree_size" field.
+func MerkleTreeSizeNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldMerkleTreeSize))
+}
+
+// MerklePathIsNil applies the IsNil predicate on the "merkle_path" field.
+func MerklePathIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldMerklePath))
+}
+
+// MerklePathNotNil applies the NotNil predicate on the "merkle_path" field.
+func MerklePathNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldMerklePath))
+}
+
+// LedgerSeqEQ applies the EQ predicate on the "ledger_seq" field.
+func LedgerSeqEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldEQ(FieldLedgerSeq, v))
+}
+
+// LedgerSeqNEQ applies the NEQ predicate on the "ledger_seq" field.
+func LedgerSeqNEQ(v int) predicate.Signature {
+	return predicate.Signature(sql.FieldNEQ(FieldLedgerSeq, v))
+}
+
+// LedgerSeqIn applies the In predicate on the "ledger_seq" field.
+func LedgerSeqIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldIn(FieldLedgerSeq, vs...))
+}
+
+// LedgerSeqNotIn applies the NotIn predicate on the "ledger_seq" field.
+func LedgerSeqNotIn(vs ...int) predicate.Signature {
+	return predicate.Signature(sql.FieldNotIn(FieldLedgerSeq, vs...))
+}
+
+// LedgerSeqIsNil applies the IsNil predicate on the "ledger_seq" field.
+func LedgerSeqIsNil() predicate.Signature {
+	return predicate.Signature(sql.FieldIsNull(FieldLedgerSeq))
+}
+
+// LedgerSeqNotNil applies the NotNil predicate on the "ledger_seq" field.
+func LedgerSeqNotNil() predicate.Signature {
+	return predicate.Signature(sql.FieldNotNull(FieldLedgerSeq))
+}
+
 // InsertedAtEQ applies the EQ predicate on the "inserted_at" field.
 func InsertedAtEQ(v time.Time) predicate.Signature {
 	return predicate.Signature(sql.FieldEQ(FieldInsertedAt, v))
@@ -263,6 +718,29 @@ func HasRecordWith(preds ...predicate.Record) predicate.Signature {
 	})
 }
 
+// HasLedger applies the HasEdge predicate on the "ledger" edge.
+func HasLedger() predicate.Signature {
+	return predicate.Signature(func(s *sql.Selector) {
+		step := sqlgraph.NewStep(
+			sqlgraph.From(Table, FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, LedgerTable, LedgerColumn),
+		)
+		sqlgraph.HasNeighbors(s, step)
+	})
+}
+
+// HasLedgerWith applies the HasEdge predicate on the "ledger" edge with a given conditions (other predicates).
+func HasLedgerWith(preds ...predicate.LedgerEntry) predicate.Signature {
+	return predicate.Signature(func(s *sql.Selector) {
+		step := newLedgerStep()
+		sqlgraph.HasNeighborsWith(s, step, func(s *sql.Selector) {
+			for _, p := range preds {
+				p(s)
+			}
+		})
+	})
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Signature) predicate.Signature {
 	return predicate.Signature(sql.AndPredicates(predicates...))
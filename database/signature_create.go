@@ -8,8 +8,10 @@ import (
 	"fmt"
 	"time"
 
+	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
 	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/database/signature"
 )
@@ -19,6 +21,21 @@ type SignatureCreate struct {
 	config
 	mutation *SignatureMutation
 	hooks    []Hook
+	conflict []sql.ConflictOption
+}
+
+// SetTenantID sets the "tenant_id" field.
+func (sc *SignatureCreate) SetTenantID(s string) *SignatureCreate {
+	sc.mutation.SetTenantID(s)
+	return sc
+}
+
+// SetNillableTenantID sets the "tenant_id" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableTenantID(s *string) *SignatureCreate {
+	if s != nil {
+		sc.SetTenantID(*s)
+	}
+	return sc
 }
 
 // SetRecordID sets the "record_id" field.
@@ -33,12 +50,102 @@ func (sc *SignatureCreate) SetKeyID(i int) *SignatureCreate {
 	return sc
 }
 
+// SetAlgorithm sets the "algorithm" field.
+func (sc *SignatureCreate) SetAlgorithm(s string) *SignatureCreate {
+	sc.mutation.SetAlgorithm(s)
+	return sc
+}
+
+// SetNillableAlgorithm sets the "algorithm" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableAlgorithm(s *string) *SignatureCreate {
+	if s != nil {
+		sc.SetAlgorithm(*s)
+	}
+	return sc
+}
+
 // SetValue sets the "value" field.
 func (sc *SignatureCreate) SetValue(s string) *SignatureCreate {
 	sc.mutation.SetValue(s)
 	return sc
 }
 
+// SetTimestampToken sets the "timestamp_token" field.
+func (sc *SignatureCreate) SetTimestampToken(s string) *SignatureCreate {
+	sc.mutation.SetTimestampToken(s)
+	return sc
+}
+
+// SetNillableTimestampToken sets the "timestamp_token" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableTimestampToken(s *string) *SignatureCreate {
+	if s != nil {
+		sc.SetTimestampToken(*s)
+	}
+	return sc
+}
+
+// SetMerkleRoot sets the "merkle_root" field.
+func (sc *SignatureCreate) SetMerkleRoot(s string) *SignatureCreate {
+	sc.mutation.SetMerkleRoot(s)
+	return sc
+}
+
+// SetNillableMerkleRoot sets the "merkle_root" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableMerkleRoot(s *string) *SignatureCreate {
+	if s != nil {
+		sc.SetMerkleRoot(*s)
+	}
+	return sc
+}
+
+// SetMerkleLeafIndex sets the "merkle_leaf_index" field.
+func (sc *SignatureCreate) SetMerkleLeafIndex(i int) *SignatureCreate {
+	sc.mutation.SetMerkleLeafIndex(i)
+	return sc
+}
+
+// SetNillableMerkleLeafIndex sets the "merkle_leaf_index" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableMerkleLeafIndex(i *int) *SignatureCreate {
+	if i != nil {
+		sc.SetMerkleLeafIndex(*i)
+	}
+	return sc
+}
+
+// SetMerkleTreeSize sets the "merkle_tree_size" field.
+func (sc *SignatureCreate) SetMerkleTreeSize(i int) *SignatureCreate {
+	sc.mutation.SetMerkleTreeSize(i)
+	return sc
+}
+
+// SetNillableMerkleTreeSize sets the "merkle_tree_size" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableMerkleTreeSize(i *int) *SignatureCreate {
+	if i != nil {
+		sc.SetMerkleTreeSize(*i)
+	}
+	return sc
+}
+
+// SetMerklePath sets the "merkle_path" field.
+func (sc *SignatureCreate) SetMerklePath(s []string) *SignatureCreate {
+	sc.mutation.SetMerklePath(s)
+	return sc
+}
+
+// SetLedgerSeq sets the "ledger_seq" field.
+func (sc *SignatureCreate) SetLedgerSeq(i int) *SignatureCreate {
+	sc.mutation.SetLedgerSeq(i)
+	return sc
+}
+
+// SetNillableLedgerSeq sets the "ledger_seq" field if the given value is not nil.
+func (sc *SignatureCreate) SetNillableLedgerSeq(i *int) *SignatureCreate {
+	if i != nil {
+		sc.SetLedgerSeq(*i)
+	}
+	return sc
+}
+
 // SetInsertedAt sets the "inserted_at" field.
 func (sc *SignatureCreate) SetInsertedAt(t time.Time) *SignatureCreate {
 	sc.mutation.SetInsertedAt(t)
@@ -58,6 +165,25 @@ func (sc *SignatureCreate) SetRecord(r *Record) *SignatureCreate {
 	return sc.SetRecordID(r.ID)
 }
 
+// SetLedgerID sets the "ledger" edge to the LedgerEntry entity by ID.
+func (sc *SignatureCreate) SetLedgerID(id int) *SignatureCreate {
+	sc.mutation.SetLedgerID(id)
+	return sc
+}
+
+// SetNillableLedgerID sets the "ledger" edge to the LedgerEntry entity by ID if the given value is not nil.
+func (sc *SignatureCreate) SetNillableLedgerID(id *int) *SignatureCreate {
+	if id != nil {
+		sc = sc.SetLedgerID(*id)
+	}
+	return sc
+}
+
+// SetLedger sets the "ledger" edge to the LedgerEntry entity.
+func (sc *SignatureCreate) SetLedger(l *LedgerEntry) *SignatureCreate {
+	return sc.SetLedgerID(l.ID)
+}
+
 // Mutation returns the SignatureMutation object of the builder.
 func (sc *SignatureCreate) Mutation() *SignatureMutation {
 	return sc.mutation
@@ -65,7 +191,9 @@ func (sc *SignatureCreate) Mutation() *SignatureMutation {
 
 // Save creates the Signature in the database.
 func (sc *SignatureCreate) Save(ctx context.Context) (*Signature, error) {
-	sc.defaults()
+	if err := sc.defaults(); err != nil {
+		return nil, err
+	}
 	return withHooks(ctx, sc.sqlSave, sc.mutation, sc.hooks)
 }
 
@@ -92,15 +220,35 @@ func (sc *SignatureCreate) ExecX(ctx context.Context) {
 }
 
 // defaults sets the default values of the builder before save.
-func (sc *SignatureCreate) defaults() {
+func (sc *SignatureCreate) defaults() error {
+	if _, ok := sc.mutation.TenantID(); !ok {
+		v := signature.DefaultTenantID
+		sc.mutation.SetTenantID(v)
+	}
+	if _, ok := sc.mutation.Algorithm(); !ok {
+		v := signature.DefaultAlgorithm
+		sc.mutation.SetAlgorithm(v)
+	}
 	if _, ok := sc.mutation.InsertedAt(); !ok {
+		if signature.DefaultInsertedAt == nil {
+			return fmt.Errorf("database: uninitialized signature.DefaultInsertedAt (forgotten import database/runtime?)")
+		}
 		v := signature.DefaultInsertedAt()
 		sc.mutation.SetInsertedAt(v)
 	}
+	return nil
 }
 
 // check runs all checks and user-defined validators on the builder.
 func (sc *SignatureCreate) check() error {
+	if _, ok := sc.mutation.TenantID(); !ok {
+		return &ValidationError{Name: "tenant_id", err: errors.New(`database: missing required field "Signature.tenant_id"`)}
+	}
+	if v, ok := sc.mutation.TenantID(); ok {
+		if err := signature.TenantIDValidator(v); err != nil {
+			return &ValidationError{Name: "tenant_id", err: fmt.Errorf(`database: validator failed for field "Signature.tenant_id": %w`, err)}
+		}
+	}
 	if _, ok := sc.mutation.RecordID(); !ok {
 		return &ValidationError{Name: "record_id", err: errors.New(`database: missing required field "Signature.record_id"`)}
 	}
@@ -112,6 +260,9 @@ func (sc *SignatureCreate) check() error {
 			return &ValidationError{Name: "key_id", err: fmt.Errorf(`database: validator failed for field "Signature.key_id": %w`, err)}
 		}
 	}
+	if _, ok := sc.mutation.Algorithm(); !ok {
+		return &ValidationError{Name: "algorithm", err: errors.New(`database: missing required field "Signature.algorithm"`)}
+	}
 	if _, ok := sc.mutation.Value(); !ok {
 		return &ValidationError{Name: "value", err: errors.New(`database: missing required field "Signature.value"`)}
 	}
@@ -152,14 +303,43 @@ func (sc *SignatureCreate) createSpec() (*Signature, *sqlgraph.CreateSpec) {
 		_node = &Signature{config: sc.config}
 		_spec = sqlgraph.NewCreateSpec(signature.Table, sqlgraph.NewFieldSpec(signature.FieldID, field.TypeInt))
 	)
+	_spec.OnConflict = sc.conflict
+	if value, ok := sc.mutation.TenantID(); ok {
+		_spec.SetField(signature.FieldTenantID, field.TypeString, value)
+		_node.TenantID = value
+	}
 	if value, ok := sc.mutation.KeyID(); ok {
 		_spec.SetField(signature.FieldKeyID, field.TypeInt, value)
 		_node.KeyID = value
 	}
+	if value, ok := sc.mutation.Algorithm(); ok {
+		_spec.SetField(signature.FieldAlgorithm, field.TypeString, value)
+		_node.Algorithm = value
+	}
 	if value, ok := sc.mutation.Value(); ok {
 		_spec.SetField(signature.FieldValue, field.TypeString, value)
 		_node.Value = value
 	}
+	if value, ok := sc.mutation.TimestampToken(); ok {
+		_spec.SetField(signature.FieldTimestampToken, field.TypeString, value)
+		_node.TimestampToken = value
+	}
+	if value, ok := sc.mutation.MerkleRoot(); ok {
+		_spec.SetField(signature.FieldMerkleRoot, field.TypeString, value)
+		_node.MerkleRoot = value
+	}
+	if value, ok := sc.mutation.MerkleLeafIndex(); ok {
+		_spec.SetField(signature.FieldMerkleLeafIndex, field.TypeInt, value)
+		_node.MerkleLeafIndex = &value
+	}
+	if value, ok := sc.mutation.MerkleTreeSize(); ok {
+		_spec.SetField(signature.FieldMerkleTreeSize, field.TypeInt, value)
+		_node.MerkleTreeSize = &value
+	}
+	if value, ok := sc.mutation.MerklePath(); ok {
+		_spec.SetField(signature.FieldMerklePath, field.TypeJSON, value)
+		_node.MerklePath = value
+	}
 	if value, ok := sc.mutation.InsertedAt(); ok {
 		_spec.SetField(signature.FieldInsertedAt, field.TypeTime, value)
 		_node.InsertedAt = value
@@ -181,14 +361,251 @@ func (sc *SignatureCreate) createSpec() (*Signature, *sqlgraph.CreateSpec) {
 		_node.RecordID = nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
+	if nodes := sc.mutation.LedgerIDs(); len(nodes) > 0 {
+		edge := &sqlgraph.EdgeSpec{
+			Rel:     sqlgraph.M2O,
+			Inverse: true,
+			Table:   signature.LedgerTable,
+			Columns: []string{signature.LedgerColumn},
+			Bidi:    false,
+			Target: &sqlgraph.EdgeTarget{
+				IDSpec: sqlgraph.NewFieldSpec(ledgerentry.FieldID, field.TypeInt),
+			},
+		}
+		for _, k := range nodes {
+			edge.Target.Nodes = append(edge.Target.Nodes, k)
+		}
+		_node.LedgerSeq = &nodes[0]
+		_spec.Edges = append(_spec.Edges, edge)
+	}
 	return _node, _spec
 }
 
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Signature.Create().
+//		SetTenantID(v).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.SignatureUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (sc *SignatureCreate) OnConflict(opts ...sql.ConflictOption) *SignatureUpsertOne {
+	sc.conflict = opts
+	return &SignatureUpsertOne{
+		create: sc,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (sc *SignatureCreate) OnConflictColumns(columns ...string) *SignatureUpsertOne {
+	sc.conflict = append(sc.conflict, sql.ConflictColumns(columns...))
+	return &SignatureUpsertOne{
+		create: sc,
+	}
+}
+
+type (
+	// SignatureUpsertOne is the builder for "upsert"-ing
+	//  one Signature node.
+	SignatureUpsertOne struct {
+		create *SignatureCreate
+	}
+
+	// SignatureUpsert is the "OnConflict" setter.
+	SignatureUpsert struct {
+		*sql.UpdateSet
+	}
+)
+
+// SetKeyID sets the "key_id" field.
+func (u *SignatureUpsert) SetKeyID(v int) *SignatureUpsert {
+	u.Set(signature.FieldKeyID, v)
+	return u
+}
+
+// UpdateKeyID sets the "key_id" field to the value that was provided on create.
+func (u *SignatureUpsert) UpdateKeyID() *SignatureUpsert {
+	u.SetExcluded(signature.FieldKeyID)
+	return u
+}
+
+// AddKeyID adds v to the "key_id" field.
+func (u *SignatureUpsert) AddKeyID(v int) *SignatureUpsert {
+	u.Add(signature.FieldKeyID, v)
+	return u
+}
+
+// SetValue sets the "value" field.
+func (u *SignatureUpsert) SetValue(v string) *SignatureUpsert {
+	u.Set(signature.FieldValue, v)
+	return u
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *SignatureUpsert) UpdateValue() *SignatureUpsert {
+	u.SetExcluded(signature.FieldValue)
+	return u
+}
+
+// UpdateNewValues updates the mutable fields using the new values that were set on create.
+// Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *SignatureUpsertOne) UpdateNewValues() *SignatureUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		if _, exists := u.create.mutation.TenantID(); exists {
+			s.SetIgnore(signature.FieldTenantID)
+		}
+		if _, exists := u.create.mutation.RecordID(); exists {
+			s.SetIgnore(signature.FieldRecordID)
+		}
+		if _, exists := u.create.mutation.Algorithm(); exists {
+			s.SetIgnore(signature.FieldAlgorithm)
+		}
+		if _, exists := u.create.mutation.TimestampToken(); exists {
+			s.SetIgnore(signature.FieldTimestampToken)
+		}
+		if _, exists := u.create.mutation.MerkleRoot(); exists {
+			s.SetIgnore(signature.FieldMerkleRoot)
+		}
+		if _, exists := u.create.mutation.MerkleLeafIndex(); exists {
+			s.SetIgnore(signature.FieldMerkleLeafIndex)
+		}
+		if _, exists := u.create.mutation.MerkleTreeSize(); exists {
+			s.SetIgnore(signature.FieldMerkleTreeSize)
+		}
+		if _, exists := u.create.mutation.MerklePath(); exists {
+			s.SetIgnore(signature.FieldMerklePath)
+		}
+		if _, exists := u.create.mutation.LedgerSeq(); exists {
+			s.SetIgnore(signature.FieldLedgerSeq)
+		}
+		if _, exists := u.create.mutation.InsertedAt(); exists {
+			s.SetIgnore(signature.FieldInsertedAt)
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//	    OnConflict(sql.ResolveWithIgnore()).
+//	    Exec(ctx)
+func (u *SignatureUpsertOne) Ignore() *SignatureUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *SignatureUpsertOne) DoNothing() *SignatureUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the SignatureCreate.OnConflict
+// documentation for more info.
+func (u *SignatureUpsertOne) Update(set func(*SignatureUpsert)) *SignatureUpsertOne {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&SignatureUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetKeyID sets the "key_id" field.
+func (u *SignatureUpsertOne) SetKeyID(v int) *SignatureUpsertOne {
+	return u.Update(func(s *SignatureUpsert) {
+		s.SetKeyID(v)
+	})
+}
+
+// AddKeyID adds v to the "key_id" field.
+func (u *SignatureUpsertOne) AddKeyID(v int) *SignatureUpsertOne {
+	return u.Update(func(s *SignatureUpsert) {
+		s.AddKeyID(v)
+	})
+}
+
+// UpdateKeyID sets the "key_id" field to the value that was provided on create.
+func (u *SignatureUpsertOne) UpdateKeyID() *SignatureUpsertOne {
+	return u.Update(func(s *SignatureUpsert) {
+		s.UpdateKeyID()
+	})
+}
+
+// SetValue sets the "value" field.
+func (u *SignatureUpsertOne) SetValue(v string) *SignatureUpsertOne {
+	return u.Update(func(s *SignatureUpsert) {
+		s.SetValue(v)
+	})
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *SignatureUpsertOne) UpdateValue() *SignatureUpsertOne {
+	return u.Update(func(s *SignatureUpsert) {
+		s.UpdateValue()
+	})
+}
+
+// Exec executes the query.
+func (u *SignatureUpsertOne) Exec(ctx context.Context) error {
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for SignatureCreate.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *SignatureUpsertOne) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
+
+// Exec executes the UPSERT query and returns the inserted/updated ID.
+func (u *SignatureUpsertOne) ID(ctx context.Context) (id int, err error) {
+	node, err := u.create.Save(ctx)
+	if err != nil {
+		return id, err
+	}
+	return node.ID, nil
+}
+
+// IDX is like ID, but panics if an error occurs.
+func (u *SignatureUpsertOne) IDX(ctx context.Context) int {
+	id, err := u.ID(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
 // SignatureCreateBulk is the builder for creating many Signature entities in bulk.
 type SignatureCreateBulk struct {
 	config
 	err      error
 	builders []*SignatureCreate
+	conflict []sql.ConflictOption
 }
 
 // Save creates the Signature entities in the database.
@@ -218,6 +635,7 @@ func (scb *SignatureCreateBulk) Save(ctx context.Context) ([]*Signature, error)
 					_, err = mutators[i+1].Mutate(root, scb.builders[i+1].mutation)
 				} else {
 					spec := &sqlgraph.BatchCreateSpec{Nodes: specs}
+					spec.OnConflict = scb.conflict
 					// Invoke the actual operation on the latest mutation in the chain.
 					if err = sqlgraph.BatchCreate(ctx, scb.driver, spec); err != nil {
 						if sqlgraph.IsConstraintError(err) {
@@ -271,3 +689,176 @@ func (scb *SignatureCreateBulk) ExecX(ctx context.Context) {
 		panic(err)
 	}
 }
+
+// OnConflict allows configuring the `ON CONFLICT` / `ON DUPLICATE KEY` clause
+// of the `INSERT` statement. For example:
+//
+//	client.Signature.CreateBulk(builders...).
+//		OnConflict(
+//			// Update the row with the new values
+//			// the was proposed for insertion.
+//			sql.ResolveWithNewValues(),
+//		).
+//		// Override some of the fields with custom
+//		// update values.
+//		Update(func(u *ent.SignatureUpsert) {
+//			SetTenantID(v+v).
+//		}).
+//		Exec(ctx)
+func (scb *SignatureCreateBulk) OnConflict(opts ...sql.ConflictOption) *SignatureUpsertBulk {
+	scb.conflict = opts
+	return &SignatureUpsertBulk{
+		create: scb,
+	}
+}
+
+// OnConflictColumns calls `OnConflict` and configures the columns
+// as conflict target. Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//		OnConflict(sql.ConflictColumns(columns...)).
+//		Exec(ctx)
+func (scb *SignatureCreateBulk) OnConflictColumns(columns ...string) *SignatureUpsertBulk {
+	scb.conflict = append(scb.conflict, sql.ConflictColumns(columns...))
+	return &SignatureUpsertBulk{
+		create: scb,
+	}
+}
+
+// SignatureUpsertBulk is the builder for "upsert"-ing
+// a bulk of Signature nodes.
+type SignatureUpsertBulk struct {
+	create *SignatureCreateBulk
+}
+
+// UpdateNewValues updates the mutable fields using the new values that
+// were set on create. Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//		OnConflict(
+//			sql.ResolveWithNewValues(),
+//		).
+//		Exec(ctx)
+func (u *SignatureUpsertBulk) UpdateNewValues() *SignatureUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithNewValues())
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(s *sql.UpdateSet) {
+		for _, b := range u.create.builders {
+			if _, exists := b.mutation.TenantID(); exists {
+				s.SetIgnore(signature.FieldTenantID)
+			}
+			if _, exists := b.mutation.RecordID(); exists {
+				s.SetIgnore(signature.FieldRecordID)
+			}
+			if _, exists := b.mutation.Algorithm(); exists {
+				s.SetIgnore(signature.FieldAlgorithm)
+			}
+			if _, exists := b.mutation.TimestampToken(); exists {
+				s.SetIgnore(signature.FieldTimestampToken)
+			}
+			if _, exists := b.mutation.MerkleRoot(); exists {
+				s.SetIgnore(signature.FieldMerkleRoot)
+			}
+			if _, exists := b.mutation.MerkleLeafIndex(); exists {
+				s.SetIgnore(signature.FieldMerkleLeafIndex)
+			}
+			if _, exists := b.mutation.MerkleTreeSize(); exists {
+				s.SetIgnore(signature.FieldMerkleTreeSize)
+			}
+			if _, exists := b.mutation.MerklePath(); exists {
+				s.SetIgnore(signature.FieldMerklePath)
+			}
+			if _, exists := b.mutation.LedgerSeq(); exists {
+				s.SetIgnore(signature.FieldLedgerSeq)
+			}
+			if _, exists := b.mutation.InsertedAt(); exists {
+				s.SetIgnore(signature.FieldInsertedAt)
+			}
+		}
+	}))
+	return u
+}
+
+// Ignore sets each column to itself in case of conflict.
+// Using this option is equivalent to using:
+//
+//	client.Signature.Create().
+//		OnConflict(sql.ResolveWithIgnore()).
+//		Exec(ctx)
+func (u *SignatureUpsertBulk) Ignore() *SignatureUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWithIgnore())
+	return u
+}
+
+// DoNothing configures the conflict_action to `DO NOTHING`.
+// Supported only by SQLite and PostgreSQL.
+func (u *SignatureUpsertBulk) DoNothing() *SignatureUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.DoNothing())
+	return u
+}
+
+// Update allows overriding fields `UPDATE` values. See the SignatureCreateBulk.OnConflict
+// documentation for more info.
+func (u *SignatureUpsertBulk) Update(set func(*SignatureUpsert)) *SignatureUpsertBulk {
+	u.create.conflict = append(u.create.conflict, sql.ResolveWith(func(update *sql.UpdateSet) {
+		set(&SignatureUpsert{UpdateSet: update})
+	}))
+	return u
+}
+
+// SetKeyID sets the "key_id" field.
+func (u *SignatureUpsertBulk) SetKeyID(v int) *SignatureUpsertBulk {
+	return u.Update(func(s *SignatureUpsert) {
+		s.SetKeyID(v)
+	})
+}
+
+// AddKeyID adds v to the "key_id" field.
+func (u *SignatureUpsertBulk) AddKeyID(v int) *SignatureUpsertBulk {
+	return u.Update(func(s *SignatureUpsert) {
+		s.AddKeyID(v)
+	})
+}
+
+// UpdateKeyID sets the "key_id" field to the value that was provided on create.
+func (u *SignatureUpsertBulk) UpdateKeyID() *SignatureUpsertBulk {
+	return u.Update(func(s *SignatureUpsert) {
+		s.UpdateKeyID()
+	})
+}
+
+// SetValue sets the "value" field.
+func (u *SignatureUpsertBulk) SetValue(v string) *SignatureUpsertBulk {
+	return u.Update(func(s *SignatureUpsert) {
+		s.SetValue(v)
+	})
+}
+
+// UpdateValue sets the "value" field to the value that was provided on create.
+func (u *SignatureUpsertBulk) UpdateValue() *SignatureUpsertBulk {
+	return u.Update(func(s *SignatureUpsert) {
+		s.UpdateValue()
+	})
+}
+
+// Exec executes the query.
+func (u *SignatureUpsertBulk) Exec(ctx context.Context) error {
+	if u.create.err != nil {
+		return u.create.err
+	}
+	for i, b := range u.create.builders {
+		if len(b.conflict) != 0 {
+			return fmt.Errorf("database: OnConflict was set for builder %d. Set it on the SignatureCreateBulk instead", i)
+		}
+	}
+	if len(u.create.conflict) == 0 {
+		return errors.New("database: missing options for SignatureCreateBulk.OnConflict")
+	}
+	return u.create.Exec(ctx)
+}
+
+// ExecX is like Exec, but panics if an error occurs.
+func (u *SignatureUpsertBulk) ExecX(ctx context.Context) {
+	if err := u.create.Exec(ctx); err != nil {
+		panic(err)
+	}
+}
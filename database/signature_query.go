@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent/dialect/sql"
 	"entgo.io/ent/dialect/sql/sqlgraph"
 	"entgo.io/ent/schema/field"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
 	"github.com/jurshsmith/vaultstream/database/predicate"
 	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/database/signature"
@@ -24,6 +25,7 @@ type SignatureQuery struct {
 	inters     []Interceptor
 	predicates []predicate.Signature
 	withRecord *RecordQuery
+	withLedger *LedgerEntryQuery
 	// intermediate query (i.e. traversal path).
 	sql  *sql.Selector
 	path func(context.Context) (*sql.Selector, error)
@@ -82,6 +84,28 @@ func (sq *SignatureQuery) QueryRecord() *RecordQuery {
 	return query
 }
 
+// QueryLedger chains the current query on the "ledger" edge.
+func (sq *SignatureQuery) QueryLedger() *LedgerEntryQuery {
+	query := (&LedgerEntryClient{config: sq.config}).Query()
+	query.path = func(ctx context.Context) (fromU *sql.Selector, err error) {
+		if err := sq.prepareQuery(ctx); err != nil {
+			return nil, err
+		}
+		selector := sq.sqlQuery(ctx)
+		if err := selector.Err(); err != nil {
+			return nil, err
+		}
+		step := sqlgraph.NewStep(
+			sqlgraph.From(signature.Table, signature.FieldID, selector),
+			sqlgraph.To(ledgerentry.Table, ledgerentry.FieldID),
+			sqlgraph.Edge(sqlgraph.M2O, true, signature.LedgerTable, signature.LedgerColumn),
+		)
+		fromU = sqlgraph.SetNeighbors(sq.driver.Dialect(), step)
+		return fromU, nil
+	}
+	return query
+}
+
 // First returns the first Signature entity from the query.
 // Returns a *NotFoundError when no Signature was found.
 func (sq *SignatureQuery) First(ctx context.Context) (*Signature, error) {
@@ -275,6 +299,7 @@ func (sq *SignatureQuery) Clone() *SignatureQuery {
 		inters:     append([]Interceptor{}, sq.inters...),
 		predicates: append([]predicate.Signature{}, sq.predicates...),
 		withRecord: sq.withRecord.Clone(),
+		withLedger: sq.withLedger.Clone(),
 		// clone intermediate query.
 		sql:  sq.sql.Clone(),
 		path: sq.path,
@@ -292,18 +317,29 @@ func (sq *SignatureQuery) WithRecord(opts ...func(*RecordQuery)) *SignatureQuery
 	return sq
 }
 
+// WithLedger tells the query-builder to eager-load the nodes that are connected to
+// the "ledger" edge. The optional arguments are used to configure the query builder of the edge.
+func (sq *SignatureQuery) WithLedger(opts ...func(*LedgerEntryQuery)) *SignatureQuery {
+	query := (&LedgerEntryClient{config: sq.config}).Query()
+	for _, opt := range opts {
+		opt(query)
+	}
+	sq.withLedger = query
+	return sq
+}
+
 // GroupBy is used to group vertices by one or more fields/columns.
 // It is often used with aggregate functions, like: count, max, mean, min, sum.
 //
 // Example:
 //
 //	var v []struct {
-//		RecordID int `json:"record_id"`
+//		TenantID string `json:"tenant_id"`
 //		Count int `json:"count,omitempty"`
 //	}
 //
 //	client.Signature.Query().
-//		GroupBy(signature.FieldRecordID).
+//		GroupBy(signature.FieldTenantID).
 //		Aggregate(database.Count()).
 //		Scan(ctx, &v)
 func (sq *SignatureQuery) GroupBy(field string, fields ...string) *SignatureGroupBy {
@@ -321,11 +357,11 @@ func (sq *SignatureQuery) GroupBy(field string, fields ...string) *SignatureGrou
 // Example:
 //
 //	var v []struct {
-//		RecordID int `json:"record_id"`
+//		TenantID string `json:"tenant_id"`
 //	}
 //
 //	client.Signature.Query().
-//		Select(signature.FieldRecordID).
+//		Select(signature.FieldTenantID).
 //		Scan(ctx, &v)
 func (sq *SignatureQuery) Select(fields ...string) *SignatureSelect {
 	sq.ctx.Fields = append(sq.ctx.Fields, fields...)
@@ -370,8 +406,9 @@ func (sq *SignatureQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Si
 	var (
 		nodes       = []*Signature{}
 		_spec       = sq.querySpec()
-		loadedTypes = [1]bool{
+		loadedTypes = [2]bool{
 			sq.withRecord != nil,
+			sq.withLedger != nil,
 		}
 	)
 	_spec.ScanValues = func(columns []string) ([]any, error) {
@@ -398,6 +435,12 @@ func (sq *SignatureQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Si
 			return nil, err
 		}
 	}
+	if query := sq.withLedger; query != nil {
+		if err := sq.loadLedger(ctx, query, nodes, nil,
+			func(n *Signature, e *LedgerEntry) { n.Edges.Ledger = e }); err != nil {
+			return nil, err
+		}
+	}
 	return nodes, nil
 }
 
@@ -430,6 +473,38 @@ func (sq *SignatureQuery) loadRecord(ctx context.Context, query *RecordQuery, no
 	}
 	return nil
 }
+func (sq *SignatureQuery) loadLedger(ctx context.Context, query *LedgerEntryQuery, nodes []*Signature, init func(*Signature), assign func(*Signature, *LedgerEntry)) error {
+	ids := make([]int, 0, len(nodes))
+	nodeids := make(map[int][]*Signature)
+	for i := range nodes {
+		if nodes[i].LedgerSeq == nil {
+			continue
+		}
+		fk := *nodes[i].LedgerSeq
+		if _, ok := nodeids[fk]; !ok {
+			ids = append(ids, fk)
+		}
+		nodeids[fk] = append(nodeids[fk], nodes[i])
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	query.Where(ledgerentry.IDIn(ids...))
+	neighbors, err := query.All(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range neighbors {
+		nodes, ok := nodeids[n.ID]
+		if !ok {
+			return fmt.Errorf(`unexpected foreign-key "ledger_seq" returned %v`, n.ID)
+		}
+		for i := range nodes {
+			assign(nodes[i], n)
+		}
+	}
+	return nil
+}
 
 func (sq *SignatureQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := sq.querySpec()
@@ -459,6 +534,9 @@ func (sq *SignatureQuery) querySpec() *sqlgraph.QuerySpec {
 		if sq.withRecord != nil {
 			_spec.Node.AddColumnOnce(signature.FieldRecordID)
 		}
+		if sq.withLedger != nil {
+			_spec.Node.AddColumnOnce(signature.FieldLedgerSeq)
+		}
 	}
 	if ps := sq.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
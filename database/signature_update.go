@@ -133,6 +133,21 @@ func (su *SignatureUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := su.mutation.Value(); ok {
 		_spec.SetField(signature.FieldValue, field.TypeString, value)
 	}
+	if su.mutation.TimestampTokenCleared() {
+		_spec.ClearField(signature.FieldTimestampToken, field.TypeString)
+	}
+	if su.mutation.MerkleRootCleared() {
+		_spec.ClearField(signature.FieldMerkleRoot, field.TypeString)
+	}
+	if su.mutation.MerkleLeafIndexCleared() {
+		_spec.ClearField(signature.FieldMerkleLeafIndex, field.TypeInt)
+	}
+	if su.mutation.MerkleTreeSizeCleared() {
+		_spec.ClearField(signature.FieldMerkleTreeSize, field.TypeInt)
+	}
+	if su.mutation.MerklePathCleared() {
+		_spec.ClearField(signature.FieldMerklePath, field.TypeJSON)
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, su.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{signature.Label}
@@ -289,6 +304,21 @@ func (suo *SignatureUpdateOne) sqlSave(ctx context.Context) (_node *Signature, e
 	if value, ok := suo.mutation.Value(); ok {
 		_spec.SetField(signature.FieldValue, field.TypeString, value)
 	}
+	if suo.mutation.TimestampTokenCleared() {
+		_spec.ClearField(signature.FieldTimestampToken, field.TypeString)
+	}
+	if suo.mutation.MerkleRootCleared() {
+		_spec.ClearField(signature.FieldMerkleRoot, field.TypeString)
+	}
+	if suo.mutation.MerkleLeafIndexCleared() {
+		_spec.ClearField(signature.FieldMerkleLeafIndex, field.TypeInt)
+	}
+	if suo.mutation.MerkleTreeSizeCleared() {
+		_spec.ClearField(signature.FieldMerkleTreeSize, field.TypeInt)
+	}
+	if suo.mutation.MerklePathCleared() {
+		_spec.ClearField(signature.FieldMerklePath, field.TypeJSON)
+	}
 	_node = &Signature{config: suo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
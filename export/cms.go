@@ -0,0 +1,195 @@
+package export
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jurshsmith/vaultstream/signer"
+)
+
+var (
+	oidData         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidSHA256       = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384       = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512       = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+	oidECDSAWithSHA = map[signer.Algorithm]asn1.ObjectIdentifier{
+		signer.ES256: {1, 2, 840, 10045, 4, 3, 2},
+		signer.ES384: {1, 2, 840, 10045, 4, 3, 3},
+	}
+	oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+	oidRSAPSS  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+	oidMGF1    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 8}
+
+	// id-aa-signatureTimeStampToken (RFC 3161, appendix A)
+	oidTimeStampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+
+	sha256AlgID = pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}
+)
+
+var errNoCertificate = errors.New("CMS export needs the signing key's certificate")
+
+// The structures below follow RFC 5652. Only what a detached SignedData with a
+// single signer, no signed attributes and an optional time-stamp needs is
+// modelled.
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// encapsulatedContentInfo omits eContent: the content is detached.
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+}
+
+type signerInfo struct {
+	Version            int
+	SID                issuerAndSerialNumber
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      []attribute `asn1:"optional,tag:1,set"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type issuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// pssParameters is RSASSA-PSS-params (RFC 4055) for SHA-256 with MGF1-SHA-256.
+type pssParameters struct {
+	Hash       pkix.AlgorithmIdentifier `asn1:"explicit,tag:0"`
+	MGF        pkix.AlgorithmIdentifier `asn1:"explicit,tag:1"`
+	SaltLength int                      `asn1:"explicit,tag:2"`
+}
+
+// CMS returns a DER CMS SignedData (RFC 5652) with detached content, the
+// signer certificate and its chain up to the VaultStream root. There are no
+// signed attributes, so the signature covers Content() directly, exactly as it
+// was produced by signing-service. A time-stamp token, if any, is carried as
+// an unsigned signature time-stamp attribute.
+func (s *Signed) CMS() ([]byte, error) {
+	if s.Key.Certificate == "" {
+		return nil, errNoCertificate
+	}
+	certDER, err := base64.StdEncoding.DecodeString(s.Key.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("certificate of key %d is not base64: %w", s.Key.ID, err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing certificate of key %d: %w", s.Key.ID, err)
+	}
+
+	certificates := append([]byte(nil), certDER...)
+	for _, encoded := range s.Key.CertificateChain {
+		chainDER, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("certificate chain of key %d is not base64: %w", s.Key.ID, err)
+		}
+		certificates = append(certificates, chainDER...)
+	}
+
+	sig, err := s.signatureBytes()
+	if err != nil {
+		return nil, err
+	}
+	// Refuse to export a Merkle signature for a record its proof does not cover.
+	if _, err := s.Content(); err != nil {
+		return nil, err
+	}
+	digestAlg, sigAlg, err := cmsAlgorithms(s.algorithm())
+	if err != nil {
+		return nil, err
+	}
+	var unsignedAttrs []attribute
+	if s.Signature.TimestampToken != "" {
+		token, err := base64.StdEncoding.DecodeString(s.Signature.TimestampToken)
+		if err != nil {
+			return nil, fmt.Errorf("timestamp token of record %d is not base64: %w", s.Record.ID, err)
+		}
+		// The token covers the raw signature value, which is exactly what the
+		// signature time-stamp attribute expects.
+		unsignedAttrs = []attribute{{Type: oidTimeStampToken, Values: []asn1.RawValue{{FullBytes: token}}}}
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: oidData},
+		Certificates: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      certificates,
+		},
+		SignerInfos: []signerInfo{{
+			Version: 1,
+			SID: issuerAndSerialNumber{
+				Issuer:       asn1.RawValue{FullBytes: cert.RawIssuer},
+				SerialNumber: cert.SerialNumber,
+			},
+			DigestAlgorithm:    digestAlg,
+			SignatureAlgorithm: sigAlg,
+			Signature:          sig,
+			UnsignedAttrs:      unsignedAttrs,
+		}},
+	}
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, fmt.Errorf("failed encoding SignedData: %w", err)
+	}
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		// encoding/asn1 does not apply explicit tags to RawValues, so wrap by hand.
+		Content: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// cmsAlgorithms returns the digest and signature algorithm identifiers CMS uses
+// for alg. Ed25519 pairs with SHA-512 as required by RFC 8419.
+func cmsAlgorithms(alg signer.Algorithm) (pkix.AlgorithmIdentifier, pkix.AlgorithmIdentifier, error) {
+	switch alg {
+	case signer.ES256:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSHA256}, pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA[alg]}, nil
+	case signer.ES384:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSHA384}, pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA[alg]}, nil
+	case signer.EdDSA:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSHA512}, pkix.AlgorithmIdentifier{Algorithm: oidEd25519}, nil
+	case signer.PS256:
+		mgfParams, err := asn1.Marshal(sha256AlgID)
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, pkix.AlgorithmIdentifier{}, err
+		}
+		params, err := asn1.Marshal(pssParameters{
+			Hash:       sha256AlgID,
+			MGF:        pkix.AlgorithmIdentifier{Algorithm: oidMGF1, Parameters: asn1.RawValue{FullBytes: mgfParams}},
+			SaltLength: 32,
+		})
+		if err != nil {
+			return pkix.AlgorithmIdentifier{}, pkix.AlgorithmIdentifier{}, err
+		}
+		return pkix.AlgorithmIdentifier{Algorithm: oidSHA256}, pkix.AlgorithmIdentifier{Algorithm: oidRSAPSS, Parameters: asn1.RawValue{FullBytes: params}}, nil
+	default:
+		return pkix.AlgorithmIdentifier{}, pkix.AlgorithmIdentifier{}, fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// Exporter turns stored signatures into detached signatures in standard
+// formats (JWS, CMS) that downstream systems can verify with off-the-shelf
+// tooling.
+type Exporter struct {
+	client *database.Client
+}
+
+func New(client *database.Client) *Exporter {
+	return &Exporter{client: client}
+}
+
+// Signed is a record together with its signature and signing key, the input to
+// every export format.
+type Signed struct {
+	Record    types.Record
+	Signature types.Signature
+	Key       types.Key
+}
+
+// Load fetches the signature of a record along with the record and key it refers to.
+func (e *Exporter) Load(ctx context.Context, recordID int) (*Signed, error) {
+	sig, err := e.client.Signature.Query().
+		Where(signature.RecordID(recordID)).
+		WithRecord().
+		Only(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading signature of record %d: %w", recordID, err)
+	}
+	key, err := e.client.Key.Get(ctx, sig.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading key %d: %w", sig.KeyID, err)
+	}
+
+	var merkleProof *types.MerkleProof
+	if sig.MerkleRoot != "" && sig.MerkleLeafIndex != nil && sig.MerkleTreeSize != nil {
+		merkleProof = &types.MerkleProof{
+			Root:      sig.MerkleRoot,
+			LeafIndex: *sig.MerkleLeafIndex,
+			TreeSize:  *sig.MerkleTreeSize,
+			Path:      sig.MerklePath,
+		}
+	}
+
+	var ledgerSeq int
+	if sig.LedgerSeq != nil {
+		ledgerSeq = *sig.LedgerSeq
+	}
+
+	return &Signed{
+		Record: types.Record{
+			ID:         sig.Edges.Record.ID,
+			TenantID:   sig.Edges.Record.TenantID,
+			InsertedAt: sig.Edges.Record.InsertedAt,
+			Data:       sig.Edges.Record.Data,
+		},
+		Signature: types.Signature{
+			ID:             sig.ID,
+			TenantID:       sig.TenantID,
+			RecordID:       sig.RecordID,
+			KeyID:          sig.KeyID,
+			Algorithm:      sig.Algorithm,
+			Value:          sig.Value,
+			TimestampToken: sig.TimestampToken,
+			MerkleProof:    merkleProof,
+			LedgerSeq:      ledgerSeq,
+			InsertedAt:     sig.InsertedAt,
+		},
+		Key: types.Key{
+			ID:               key.ID,
+			TenantID:         key.TenantID,
+			Algorithm:        key.Algorithm,
+			PublicKey:        key.PublicKey,
+			Certificate:      key.Certificate,
+			CertificateChain: key.CertificateChain,
+		},
+	}, nil
+}
+
+// JWSCompact exports the signature of a record as a compact JWS with a detached payload.
+func (e *Exporter) JWSCompact(ctx context.Context, recordID int) (string, error) {
+	signed, err := e.Load(ctx, recordID)
+	if err != nil {
+		return "", err
+	}
+	return signed.JWSCompact(true)
+}
+
+// JWSJSON exports the signature of a record as a flattened JWS JSON serialization
+// carrying the payload.
+func (e *Exporter) JWSJSON(ctx context.Context, recordID int) ([]byte, error) {
+	signed, err := e.Load(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	return signed.JWSJSON(false)
+}
+
+// CMS exports the signature of a record as a detached DER CMS SignedData
+// carrying the signer certificate chain. The signed content is Signed.Content.
+func (e *Exporter) CMS(ctx context.Context, recordID int) ([]byte, error) {
+	signed, err := e.Load(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	return signed.CMS()
+}
+
+// Content returns the exact bytes covered by the signature, i.e. the JWS
+// signing input. Verifiers of a detached CMS export need it as the content.
+// For Merkle signatures it is the signed tree head, once the record's proof
+// has been checked against it.
+func (s *Signed) Content() ([]byte, error) {
+	return signer.SignatureSigningInput(s.algorithm(), s.Record, s.Signature)
+}
+
+// Payload returns the JWS payload the signature covers: the record itself, or
+// the Merkle tree head the record's proof leads to.
+func (s *Signed) Payload() ([]byte, error) {
+	proof := s.Signature.MerkleProof
+	if proof == nil {
+		return signer.Payload(s.Record), nil
+	}
+	if _, err := s.Content(); err != nil {
+		return nil, err
+	}
+	root, _ := base64.StdEncoding.DecodeString(proof.Root) // checked by Content.
+	return signer.MerklePayload(root, proof.TreeSize), nil
+}
+
+func (s *Signed) algorithm() signer.Algorithm {
+	alg, err := signer.ParseAlgorithm(s.Signature.Algorithm)
+	if err != nil {
+		return signer.Algorithm(s.Signature.Algorithm)
+	}
+	return alg
+}
+
+func (s *Signed) signatureBytes() ([]byte, error) {
+	sig, err := base64.StdEncoding.DecodeString(s.Signature.Value)
+	if err != nil {
+		return nil, fmt.Errorf("signature of record %d is not base64: %w", s.Record.ID, err)
+	}
+	return sig, nil
+}
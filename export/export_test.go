@@ -0,0 +1,375 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/tsa"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+var allAlgorithms = []signer.Algorithm{signer.ES256, signer.ES384, signer.EdDSA, signer.PS256}
+
+// newSigned signs a record the way signing-service does and returns it with a
+// CA-issued key certificate, as keys-service would store it.
+func newSigned(t *testing.T, alg signer.Algorithm) (*Signed, signer.Signer) {
+	signed, keySigner, _ := newSignedWithCA(t, alg)
+	return signed, keySigner
+}
+
+func newSignedWithCA(t *testing.T, alg signer.Algorithm) (*Signed, signer.Signer, *signer.CA) {
+	t.Helper()
+	ctx := context.Background()
+
+	ca, err := signer.OpenCA(filepath.Join(t.TempDir(), "root.json"), "test")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+
+	backend := signer.NewMemoryBackend()
+	key, err := backend.GenerateKey(ctx, 12, alg)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+	cert, err := ca.Issue(key, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(cert)
+	key.CertificateChain = ca.Chain()
+
+	record := types.Record{ID: 99, InsertedAt: time.Now().Truncate(time.Microsecond)}
+	sig, err := keySigner.Sign(ctx, alg.Digest(signer.SigningInput(alg, key.ID, record)))
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+
+	return &Signed{
+		Record: record,
+		Signature: types.Signature{
+			RecordID:  record.ID,
+			KeyID:     key.ID,
+			Algorithm: string(alg),
+			Value:     base64.StdEncoding.EncodeToString(sig),
+		},
+		Key: *key,
+	}, keySigner, ca
+}
+
+func TestJWSCompactVerifiesWithGoJose(t *testing.T) {
+	for _, alg := range allAlgorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			signed, keySigner := newSigned(t, alg)
+
+			compact, err := signed.JWSCompact(true)
+			if err != nil {
+				t.Fatalf("JWSCompact() unexpected error: %v", err)
+			}
+
+			jws, err := jose.ParseDetached(compact, signer.Payload(signed.Record), []jose.SignatureAlgorithm{jose.SignatureAlgorithm(alg)})
+			if err != nil {
+				t.Fatalf("jose.ParseDetached() unexpected error: %v", err)
+			}
+			if _, err := jws.Verify(keySigner.Public()); err != nil {
+				t.Errorf("detached JWS does not verify: %v", err)
+			}
+			if kid := jws.Signatures[0].Protected.KeyID; kid != "12" {
+				t.Errorf("JWS kid = %q, want %q", kid, "12")
+			}
+		})
+	}
+}
+
+func TestJWSJSONVerifiesWithGoJose(t *testing.T) {
+	for _, alg := range allAlgorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			signed, keySigner := newSigned(t, alg)
+
+			serialized, err := signed.JWSJSON(false)
+			if err != nil {
+				t.Fatalf("JWSJSON() unexpected error: %v", err)
+			}
+
+			jws, err := jose.ParseSigned(string(serialized), []jose.SignatureAlgorithm{jose.SignatureAlgorithm(alg)})
+			if err != nil {
+				t.Fatalf("jose.ParseSigned() unexpected error: %v", err)
+			}
+			payload, err := jws.Verify(keySigner.Public())
+			if err != nil {
+				t.Fatalf("JWS JSON does not verify: %v", err)
+			}
+			if !bytes.Equal(payload, signer.Payload(signed.Record)) {
+				t.Errorf("JWS payload = %s, want %s", payload, signer.Payload(signed.Record))
+			}
+		})
+	}
+}
+
+func TestJWSRejectsTamperedRecord(t *testing.T) {
+	signed, keySigner := newSigned(t, signer.ES256)
+	compact, err := signed.JWSCompact(true)
+	if err != nil {
+		t.Fatalf("JWSCompact() unexpected error: %v", err)
+	}
+
+	tampered := signed.Record
+	tampered.ID++
+	jws, err := jose.ParseDetached(compact, signer.Payload(tampered), []jose.SignatureAlgorithm{jose.ES256})
+	if err != nil {
+		t.Fatalf("jose.ParseDetached() unexpected error: %v", err)
+	}
+	if _, err := jws.Verify(keySigner.Public()); err == nil {
+		t.Errorf("JWS verified for a different record")
+	}
+}
+
+func TestCMSRoundTrip(t *testing.T) {
+	for _, alg := range allAlgorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			signed, keySigner := newSigned(t, alg)
+
+			der, err := signed.CMS()
+			if err != nil {
+				t.Fatalf("CMS() unexpected error: %v", err)
+			}
+
+			var ci contentInfo
+			if _, err := asn1.Unmarshal(der, &ci); err != nil {
+				t.Fatalf("failed decoding ContentInfo: %v", err)
+			}
+			if !ci.ContentType.Equal(oidSignedData) {
+				t.Fatalf("ContentInfo type = %v, want SignedData", ci.ContentType)
+			}
+			var sd signedData
+			if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+				t.Fatalf("failed decoding SignedData: %v", err)
+			}
+			if len(sd.SignerInfos) != 1 {
+				t.Fatalf("SignedData has %d signers, want 1", len(sd.SignerInfos))
+			}
+
+			sig := sd.SignerInfos[0].Signature
+			content, err := signed.Content()
+			if err != nil {
+				t.Fatalf("Content() unexpected error: %v", err)
+			}
+			if err := signer.Verify(alg, keySigner.Public(), alg.Digest(content), sig); err != nil {
+				t.Errorf("CMS signature does not verify over Content(): %v", err)
+			}
+		})
+	}
+}
+
+func TestCMSEmbedsChain(t *testing.T) {
+	signed, _, ca := newSignedWithCA(t, signer.ES256)
+	der, err := signed.CMS()
+	if err != nil {
+		t.Fatalf("CMS() unexpected error: %v", err)
+	}
+
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		t.Fatalf("failed decoding ContentInfo: %v", err)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatalf("failed decoding SignedData: %v", err)
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		t.Fatalf("failed parsing SignedData certificates: %v", err)
+	}
+	if len(certs) != 1+len(signed.Key.CertificateChain) {
+		t.Fatalf("SignedData has %d certificates, want the key's and %d of its chain", len(certs), len(signed.Key.CertificateChain))
+	}
+
+	// The certificates alone must lead from the signer to the root.
+	root := ca.Certificate()
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	intermediates := x509.NewCertPool()
+	var hasRoot bool
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+		hasRoot = hasRoot || cert.Equal(root)
+	}
+	if !hasRoot {
+		t.Errorf("SignedData certificates do not include the root")
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		t.Errorf("signer certificate does not verify against the root: %v", err)
+	}
+}
+
+// TestCMSVerifiesWithOpenSSL checks the export against OpenSSL when it is installed.
+func TestCMSVerifiesWithOpenSSL(t *testing.T) {
+	openssl, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("Skipping OpenSSL interop test: openssl not found")
+	}
+
+	for _, alg := range allAlgorithms {
+		t.Run(string(alg), func(t *testing.T) {
+			if alg == signer.EdDSA {
+				// OpenSSL streams the content through a digest when there are no
+				// signed attributes, which pure Ed25519 (RFC 8419) cannot do.
+				t.Skip("OpenSSL cannot verify Ed25519 SignedData without signed attributes")
+			}
+			signed, _, ca := newSignedWithCA(t, alg)
+			der, err := signed.CMS()
+			if err != nil {
+				t.Fatalf("CMS() unexpected error: %v", err)
+			}
+
+			dir := t.TempDir()
+			sigPath := filepath.Join(dir, "signature.p7s")
+			contentPath := filepath.Join(dir, "content")
+			rootPath := filepath.Join(dir, "root.pem")
+			rootPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Certificate().Raw})
+			if err := os.WriteFile(rootPath, rootPEM, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(sigPath, der, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			content, err := signed.Content()
+			if err != nil {
+				t.Fatalf("Content() unexpected error: %v", err)
+			}
+			if err := os.WriteFile(contentPath, content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			// Signing key certificates carry no extended key usage, so accept any purpose.
+			cmd := exec.Command(openssl, "cms", "-verify", "-binary", "-CAfile", rootPath, "-purpose", "any",
+				"-inform", "DER", "-in", sigPath, "-content", contentPath, "-out", os.DevNull)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("openssl cms -verify failed: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestCMSCarriesTimestampToken(t *testing.T) {
+	tsaServer, err := tsa.NewLocalServer()
+	if err != nil {
+		t.Fatalf("tsa.NewLocalServer() unexpected error: %v", err)
+	}
+	httpServer := httptest.NewServer(tsaServer)
+	defer httpServer.Close()
+
+	signed, _ := newSigned(t, signer.ES256)
+	sig, _ := signed.signatureBytes()
+	token, err := tsa.NewClient(httpServer.URL).Timestamp(context.Background(), sig)
+	if err != nil {
+		t.Fatalf("Timestamp() unexpected error: %v", err)
+	}
+	signed.Signature.TimestampToken = base64.StdEncoding.EncodeToString(token)
+
+	der, err := signed.CMS()
+	if err != nil {
+		t.Fatalf("CMS() unexpected error: %v", err)
+	}
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		t.Fatalf("failed decoding ContentInfo: %v", err)
+	}
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatalf("failed decoding SignedData: %v", err)
+	}
+	attrs := sd.SignerInfos[0].UnsignedAttrs
+	if len(attrs) != 1 || !attrs[0].Type.Equal(oidTimeStampToken) || len(attrs[0].Values) != 1 {
+		t.Fatalf("SignerInfo unsigned attributes = %+v, want one time-stamp token", attrs)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tsaServer.Certificate())
+	if _, err := tsa.Verify(attrs[0].Values[0].FullBytes, sd.SignerInfos[0].Signature, roots); err != nil {
+		t.Errorf("embedded time-stamp token does not verify over the signature: %v", err)
+	}
+}
+
+func TestCMSRequiresCertificate(t *testing.T) {
+	signed, _ := newSigned(t, signer.ES256)
+	signed.Key.Certificate = ""
+	if _, err := signed.CMS(); err == nil {
+		t.Errorf("CMS() without a certificate should fail")
+	}
+}
+
+func TestMerkleSignatureExport(t *testing.T) {
+	signed, keySigner := newSigned(t, signer.ES256)
+	ctx := context.Background()
+
+	// Re-sign as the middle record of a three record Merkle batch.
+	records := []types.Record{{ID: 98}, signed.Record, {ID: 100}}
+	leaves := make([][]byte, len(records))
+	for i, record := range records {
+		leaves[i] = signer.MerkleLeaf(record)
+	}
+	tree := signer.NewMerkleTree(leaves)
+	sig, err := keySigner.Sign(ctx, signer.ES256.Digest(signer.MerkleSigningInput(signer.ES256, 12, tree.Root(), tree.Size())))
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+	var path []string
+	for _, hash := range tree.Proof(1) {
+		path = append(path, base64.StdEncoding.EncodeToString(hash))
+	}
+	signed.Signature.Value = base64.StdEncoding.EncodeToString(sig)
+	signed.Signature.MerkleProof = &types.MerkleProof{
+		Root:      base64.StdEncoding.EncodeToString(tree.Root()),
+		LeafIndex: 1,
+		TreeSize:  tree.Size(),
+		Path:      path,
+	}
+
+	serialized, err := signed.JWSJSON(false)
+	if err != nil {
+		t.Fatalf("JWSJSON() unexpected error: %v", err)
+	}
+	jws, err := jose.ParseSigned(string(serialized), []jose.SignatureAlgorithm{jose.ES256})
+	if err != nil {
+		t.Fatalf("jose.ParseSigned() unexpected error: %v", err)
+	}
+	payload, err := jws.Verify(keySigner.Public())
+	if err != nil {
+		t.Fatalf("Merkle JWS does not verify: %v", err)
+	}
+	if want := signer.MerklePayload(tree.Root(), tree.Size()); !bytes.Equal(payload, want) {
+		t.Errorf("JWS payload = %s, want %s", payload, want)
+	}
+	if _, ok := jws.Signatures[0].Unprotected.ExtraHeaders["merkle_proof"]; !ok {
+		t.Errorf("JWS unprotected header lacks the Merkle proof")
+	}
+
+	if _, err := signed.CMS(); err != nil {
+		t.Errorf("CMS() unexpected error: %v", err)
+	}
+
+	// A record outside the batch must not export under the batch signature.
+	signed.Record.ID = 101
+	if _, err := signed.JWSJSON(false); err == nil {
+		t.Errorf("JWSJSON() exported a record that is not in the signed tree")
+	}
+	if _, err := signed.CMS(); err == nil {
+		t.Errorf("CMS() exported a record that is not in the signed tree")
+	}
+}
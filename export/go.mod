@@ -0,0 +1,54 @@
+module github.com/jurshsmith/vaultstream/export
+
+go 1.24.1
+
+require (
+	github.com/go-jose/go-jose/v4 v4.1.5
+	github.com/jurshsmith/vaultstream/database v0.0.0
+	github.com/jurshsmith/vaultstream/signer v0.0.0
+	github.com/jurshsmith/vaultstream/tsa v0.0.0
+	github.com/jurshsmith/vaultstream/types v0.0.0
+)
+
+require (
+	ariga.io/atlas v0.31.1-0.20250212144724-069be8033e83 // indirect
+	entgo.io/ent v0.14.4 // indirect
+	github.com/ThalesIgnite/crypto11 v1.2.5 // indirect
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
+	github.com/bmatcuk/doublestar v1.3.4 // indirect
+	github.com/digitorus/pkcs7 v0.0.0-20230713084857-e76b763bdc49 // indirect
+	github.com/digitorus/timestamp v0.0.0-20250524132541-c45532741eea // indirect
+	github.com/go-openapi/inflect v0.19.0 // indirect
+	github.com/google/go-cmp v0.6.0 // indirect
+	github.com/google/uuid v1.3.0 // indirect
+	github.com/hashicorp/hcl/v2 v2.13.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.7.2 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/jurshsmith/vaultstream/config v0.0.0 // indirect
+	github.com/lib/pq v1.10.9 // indirect
+	github.com/miekg/pkcs11 v1.0.3-0.20190429190417-a667d056470f // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/thales-e-security/pool v0.0.2 // indirect
+	github.com/zclconf/go-cty v1.14.4 // indirect
+	github.com/zclconf/go-cty-yaml v1.1.0 // indirect
+	golang.org/x/crypto v0.33.0 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.12.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+)
+
+replace github.com/jurshsmith/vaultstream/config => ../config
+
+replace github.com/jurshsmith/vaultstream/database => ../database
+
+replace github.com/jurshsmith/vaultstream/signer => ../signer
+
+replace github.com/jurshsmith/vaultstream/tsa => ../tsa
+
+replace github.com/jurshsmith/vaultstream/types => ../types
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// flattenedJWS is the flattened JWS JSON serialization (RFC 7515, section 7.2.2).
+type flattenedJWS struct {
+	Payload   *string    `json:"payload,omitempty"`
+	Protected string     `json:"protected"`
+	Header    *jwsHeader `json:"header,omitempty"`
+	Signature string     `json:"signature"`
+}
+
+// jwsHeader is the unprotected header. It carries the Merkle proof that ties
+// the record to a signed tree head, which verifiers check themselves.
+type jwsHeader struct {
+	MerkleProof *types.MerkleProof `json:"merkle_proof,omitempty"`
+}
+
+// JWSCompact returns the JWS compact serialization. A detached JWS leaves the
+// payload segment empty (RFC 7515, appendix F); verifiers supply Payload()
+// themselves. The compact form has no room for a Merkle proof, so use JWSJSON
+// to hand one out with a Merkle signature.
+func (s *Signed) JWSCompact(detached bool) (string, error) {
+	jws, err := s.flattened(detached)
+	if err != nil {
+		return "", err
+	}
+	payload := ""
+	if jws.Payload != nil {
+		payload = *jws.Payload
+	}
+	return jws.Protected + "." + payload + "." + jws.Signature, nil
+}
+
+// JWSJSON returns the flattened JWS JSON serialization.
+func (s *Signed) JWSJSON(detached bool) ([]byte, error) {
+	jws, err := s.flattened(detached)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jws)
+}
+
+func (s *Signed) flattened(detached bool) (*flattenedJWS, error) {
+	sig, err := s.signatureBytes()
+	if err != nil {
+		return nil, err
+	}
+	alg := s.algorithm()
+	sig, err = jwsSignature(alg, sig)
+	if err != nil {
+		return nil, fmt.Errorf("signature of record %d: %w", s.Record.ID, err)
+	}
+
+	payload, err := s.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	jws := &flattenedJWS{
+		Protected: signer.ProtectedHeader(alg, s.Signature.KeyID),
+		Signature: base64.RawURLEncoding.EncodeToString(sig),
+	}
+	if s.Signature.MerkleProof != nil {
+		jws.Header = &jwsHeader{MerkleProof: s.Signature.MerkleProof}
+	}
+	if !detached {
+		encoded := base64.RawURLEncoding.EncodeToString(payload)
+		jws.Payload = &encoded
+	}
+	return jws, nil
+}
+
+// jwsSignature converts a stored signature to its JWS encoding. ECDSA
+// signatures are stored ASN.1 DER encoded, JWS wants fixed-size R || S
+// (RFC 7518, section 3.4); the other algorithms are identical in both.
+func jwsSignature(alg signer.Algorithm, sig []byte) ([]byte, error) {
+	var size int
+	switch alg {
+	case signer.ES256:
+		size = 32
+	case signer.ES384:
+		size = 48
+	default:
+		return sig, nil
+	}
+
+	var ecdsaSig struct{ R, S *big.Int }
+	if rest, err := asn1.Unmarshal(sig, &ecdsaSig); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("malformed ECDSA signature")
+	}
+	if ecdsaSig.R.Sign() <= 0 || ecdsaSig.S.Sign() <= 0 || ecdsaSig.R.BitLen() > size*8 || ecdsaSig.S.BitLen() > size*8 {
+		return nil, fmt.Errorf("ECDSA signature out of range for %s", alg)
+	}
+	raw := make([]byte, 2*size)
+	ecdsaSig.R.FillBytes(raw[:size])
+	ecdsaSig.S.FillBytes(raw[size:])
+	return raw, nil
+}
@@ -2,19 +2,18 @@ package main
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"crypto/elliptic"
-	"crypto/rand"
-	"crypto/x509"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/database"
 	"github.com/jurshsmith/vaultstream/logger"
 	vaultStreamNats "github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/signer"
 	"github.com/jurshsmith/vaultstream/types"
 	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
@@ -22,6 +21,8 @@ import (
 
 var log *zap.Logger
 
+const certificateValidity = 365 * 24 * time.Hour
+
 func main() {
 	log = logger.New()
 	defer log.Sync()
@@ -32,14 +33,61 @@ func main() {
 
 	totalKeys := config.TotalKeys()
 
-	jetstreamClient, natsConn := vaultStreamNats.Connect()
+	tenants := config.Tenants()
+	for _, tenant := range tenants {
+		if err := types.ValidateTenant(tenant); err != nil {
+			log.Fatal("Invalid TENANTS", zap.Error(err))
+		}
+	}
+
+	dbClient, err := database.Connect(config.KeysMaxConcurrency())
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
+	defer dbClient.Close()
+	log.Debug("Database connection established")
+
+	jetstreamClient, natsConn, err := vaultStreamNats.Connect("keys-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
 	defer natsConn.Close()
 
-	keys, err := generateKeys(totalKeys)
+	backend, err := signer.Open()
+	if err != nil {
+		log.Fatal("Error opening signer backend", zap.Error(err))
+	}
+	defer backend.Close()
+
+	algorithms, err := tenantKeyAlgorithms(backend, tenants, config.KeyAlgorithms(), config.TenantKeyAlgorithms())
+	if err != nil {
+		log.Fatal("Invalid KEY_ALGORITHMS or TENANT_KEY_ALGORITHMS", zap.Error(err))
+	}
+
+	ca, err := signer.OpenCA(config.CAFile(), config.CAPassphrase())
+	if err != nil {
+		log.Fatal("Error opening certificate authority", zap.Error(err))
+	}
+	if err := ca.WriteCertificate(config.CACertFile()); err != nil {
+		log.Fatal("Error publishing the root certificate", zap.Error(err))
+	}
+
+	ctx := context.Background()
+
+	ids, err := reserveKeyIDs(ctx, dbClient, len(tenants)*totalKeys)
+	if err != nil {
+		log.Fatal("Error reserving key IDs", zap.Error(err))
+	}
+
+	keys, err := generateKeys(ctx, backend, ca, algorithms, tenants, ids)
 	if err != nil {
 		log.Fatal("Error generating keys", zap.Error(err))
 	}
 
+	if err := saveKeys(ctx, dbClient, keys); err != nil {
+		log.Fatal("Error saving keys", zap.Error(err))
+	}
+
 	enqueueAllKeys(jetstreamClient, keys)
 }
 
@@ -75,7 +123,7 @@ func enqueueKey(jetstreamClient jetstream.JetStream, key *types.Key, ctx context
 		log.Fatal("Error marshaling key", zap.Error(err))
 	}
 
-	subject := fmt.Sprintf("keys.%d", key.ID)
+	subject := fmt.Sprintf("keys.%s.%d", key.TenantID, key.ID)
 	pubAck, err := jetstreamClient.Publish(ctx, subject, keyInBytes, jetstream.WithMsgID(subject))
 	if err != nil {
 		log.Fatal("Error publishing message", zap.String("subject", subject), zap.Error(err))
@@ -84,33 +132,122 @@ func enqueueKey(jetstreamClient jetstream.JetStream, key *types.Key, ctx context
 	log.Debug("Published message", zap.String("stream", pubAck.Stream), zap.Uint64("sequence", pubAck.Sequence))
 }
 
-func generateKeys(totalKeys int) ([]*types.Key, error) {
-	keys := make([]*types.Key, totalKeys)
-	for i := range totalKeys {
-		key, err := generateKey(i + 1)
+// reserveKeyIDs draws n IDs from the keys table's identity sequence, so that
+// a rerun adds keys under new IDs instead of replacing the existing ones,
+// whose signatures must stay verifiable.
+func reserveKeyIDs(ctx context.Context, client *database.Client, n int) ([]int, error) {
+	rows, err := client.Query(ctx, "SELECT nextval(pg_get_serial_sequence('keys', 'id')) FROM generate_series(1, $1)", n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := make([]int, 0, n)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// generateKeys creates a pool of keys for every tenant from ids, which holds
+// the same number of IDs for every tenant in tenant order, assigning each
+// tenant's algorithms round-robin over its pool.
+func generateKeys(ctx context.Context, backend signer.Backend, ca *signer.CA, algorithms map[string][]signer.Algorithm, tenants []string, ids []int) ([]*types.Key, error) {
+	if len(tenants) == 0 || len(ids)%len(tenants) != 0 {
+		return nil, fmt.Errorf("%d key IDs cannot be shared by %d tenants", len(ids), len(tenants))
+	}
+	keysPerTenant := len(ids) / len(tenants)
+	keys := make([]*types.Key, len(ids))
+	for i, id := range ids {
+		tenant := tenants[i/keysPerTenant]
+		tenantAlgorithms := algorithms[tenant]
+		if len(tenantAlgorithms) == 0 {
+			return nil, fmt.Errorf("no key algorithms for tenant %s", tenant)
+		}
+		key, err := generateKey(ctx, backend, ca, tenant, id, tenantAlgorithms[i%keysPerTenant%len(tenantAlgorithms)])
 		if err != nil {
-			return nil, fmt.Errorf("failed generating key %d: %w", i+1, err)
+			return nil, fmt.Errorf("failed generating key %d: %w", id, err)
 		}
 		keys[i] = key
 	}
 	return keys, nil
 }
 
-func generateKey(id int) (*types.Key, error) {
-	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+// generateKey creates key material inside the configured signer backend and
+// has the CA certify its public key for the tenant. Only the memory backend
+// returns the private key; the others publish the public half and keep the
+// private key to themselves.
+func generateKey(ctx context.Context, backend signer.Backend, ca *signer.CA, tenant string, id int, alg signer.Algorithm) (*types.Key, error) {
+	key, err := backend.GenerateKey(ctx, id, alg)
 	if err != nil {
-		return nil, fmt.Errorf("failed generating key %d: %w", id, err)
+		return nil, err
 	}
-	derBytes, err := x509.MarshalECPrivateKey(ecdsaKey)
+	key.TenantID = tenant
+	certificate, err := ca.Issue(key, certificateValidity)
 	if err != nil {
-		return nil, fmt.Errorf("failed marshaling key %d: %w", id, err)
+		return nil, err
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(certificate)
+	key.CertificateChain = ca.Chain()
+	return key, nil
+}
+
+// saveKeys records each key's tenant, algorithm, public key and certificates so signatures can be
+// verified later. Their IDs are reserved by reserveKeyIDs, and a key already saved fails the
+// insert rather than being replaced.
+func saveKeys(ctx context.Context, client *database.Client, keys []*types.Key) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return client.Key.MapCreateBulk(keys, func(c *database.KeyCreate, i int) {
+		c.SetID(keys[i].ID).
+			SetTenantID(keys[i].TenantID).
+			SetAlgorithm(keys[i].Algorithm).
+			SetPublicKey(keys[i].PublicKey).
+			SetCertificate(keys[i].Certificate).
+			SetCertificateChain(keys[i].CertificateChain)
+	}).Exec(ctx)
+}
+
+// tenantKeyAlgorithms returns the algorithms of every tenant's keys: its own
+// from overrides, or defaults.
+func tenantKeyAlgorithms(backend signer.Backend, tenants, defaults []string, overrides map[string][]string) (map[string][]signer.Algorithm, error) {
+	algorithms := make(map[string][]signer.Algorithm, len(tenants))
+	for _, tenant := range tenants {
+		names, ok := overrides[tenant]
+		if !ok {
+			names = defaults
+		}
+		tenantAlgorithms, err := keyAlgorithms(backend, names)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		algorithms[tenant] = tenantAlgorithms
+	}
+	for tenant := range overrides {
+		if _, ok := algorithms[tenant]; !ok {
+			return nil, fmt.Errorf("key algorithms set for tenant %s, which is not in TENANTS", tenant)
+		}
 	}
-	encodedKey := base64.StdEncoding.EncodeToString(derBytes)
-	k := &types.Key{
-		ID:         id,
-		Value:      encodedKey,
-		IsInUse:    false,
-		LastUsedAt: time.Unix(0, 0), // initial value indicating never used.
+	return algorithms, nil
+}
+
+// keyAlgorithms parses names, refusing algorithms backend cannot generate
+// keys for.
+func keyAlgorithms(backend signer.Backend, names []string) ([]signer.Algorithm, error) {
+	algorithms := make([]signer.Algorithm, 0, len(names))
+	for _, name := range names {
+		alg, err := signer.ParseAlgorithm(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if err := backend.ValidateAlgorithm(alg); err != nil {
+			return nil, err
+		}
+		algorithms = append(algorithms, alg)
 	}
-	return k, nil
+	return algorithms, nil
 }
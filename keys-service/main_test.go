@@ -4,14 +4,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/signer"
 	"github.com/jurshsmith/vaultstream/types"
 	"go.uber.org/zap"
 )
 
+// newTestCA creates a throwaway root CA for issuing key certificates.
+func newTestCA(t *testing.T) *signer.CA {
+	t.Helper()
+	ca, err := signer.OpenCA(filepath.Join(t.TempDir(), "root.json"), "test")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error = %v", err)
+	}
+	return ca
+}
+
 func TestGenerateKey(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -30,9 +43,11 @@ func TestGenerateKey(t *testing.T) {
 		},
 	}
 
+	ca := newTestCA(t)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			key, err := generateKey(tt.id)
+			key, err := generateKey(context.Background(), signer.NewMemoryBackend(), ca, "acme", tt.id, signer.ES256)
 
 			if tt.wantErr {
 				if err == nil {
@@ -49,9 +64,18 @@ func TestGenerateKey(t *testing.T) {
 				if key.ID != tt.id {
 					t.Errorf("generateKey() key.ID = %v, want %v", key.ID, tt.id)
 				}
+				if key.TenantID != "acme" {
+					t.Errorf("generateKey() key.TenantID = %v, want acme", key.TenantID)
+				}
 				if key.Value == "" {
 					t.Errorf("generateKey() key.Value is empty")
 				}
+				if key.PublicKey == "" {
+					t.Errorf("generateKey() key.PublicKey is empty")
+				}
+				if _, err := signer.NewVerifier(ca.Certificate()).VerifyKey(key, time.Now()); err != nil {
+					t.Errorf("generateKey() key certificate does not verify against the CA: %v", err)
+				}
 				if key.IsInUse {
 					t.Errorf("generateKey() key.IsInUse = true, want false")
 				}
@@ -71,30 +95,32 @@ func TestGenerateKey(t *testing.T) {
 
 func TestGenerateKeys(t *testing.T) {
 	tests := []struct {
-		name      string
-		totalKeys int
-		wantErr   bool
+		name    string
+		ids     []int
+		wantErr bool
 	}{
 		{
-			name:      "Generate 0 keys",
-			totalKeys: 0,
-			wantErr:   false,
+			name:    "Generate 0 keys",
+			ids:     nil,
+			wantErr: false,
 		},
 		{
-			name:      "Generate 1 key",
-			totalKeys: 1,
-			wantErr:   false,
+			name:    "Generate 1 key",
+			ids:     []int{1},
+			wantErr: false,
 		},
 		{
-			name:      "Generate 5 keys",
-			totalKeys: 5,
-			wantErr:   false,
+			name:    "Generate 5 keys after existing ones",
+			ids:     []int{6, 7, 8, 9, 10},
+			wantErr: false,
 		},
 	}
 
+	ca := newTestCA(t)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			keys, err := generateKeys(tt.totalKeys)
+			keys, err := generateKeys(context.Background(), signer.NewMemoryBackend(), ca, map[string][]signer.Algorithm{types.DefaultTenant: {signer.ES256}}, []string{types.DefaultTenant}, tt.ids)
 
 			if tt.wantErr {
 				if err == nil {
@@ -104,14 +130,14 @@ func TestGenerateKeys(t *testing.T) {
 				if err != nil {
 					t.Errorf("generateKeys() unexpected error = %v", err)
 				}
-				if len(keys) != tt.totalKeys {
-					t.Errorf("generateKeys() len = %v, want %v", len(keys), tt.totalKeys)
+				if len(keys) != len(tt.ids) {
+					t.Errorf("generateKeys() len = %v, want %v", len(keys), len(tt.ids))
 				}
 
-				// Check that each key has the correct ID
+				// Check that each key has the ID reserved for it
 				for i, key := range keys {
-					if key.ID != i+1 {
-						t.Errorf("generateKeys() key[%d].ID = %v, want %v", i, key.ID, i+1)
+					if key.ID != tt.ids[i] {
+						t.Errorf("generateKeys() key[%d].ID = %v, want %v", i, key.ID, tt.ids[i])
 					}
 					if key.Value == "" {
 						t.Errorf("generateKeys() key[%d].Value is empty", i)
@@ -122,6 +148,73 @@ func TestGenerateKeys(t *testing.T) {
 	}
 }
 
+func TestGenerateKeysAssignsAlgorithmsRoundRobin(t *testing.T) {
+	backend := signer.NewMemoryBackend()
+	algorithms, err := tenantKeyAlgorithms(backend, []string{"acme", "globex"}, []string{"ES256", " EdDSA", "ES384"}, map[string][]string{"globex": {"PS256"}})
+	if err != nil {
+		t.Fatalf("tenantKeyAlgorithms() unexpected error = %v", err)
+	}
+
+	keys, err := generateKeys(context.Background(), backend, newTestCA(t), algorithms, []string{"acme", "globex"}, []int{1, 2, 3, 4, 5, 6, 7, 8})
+	if err != nil {
+		t.Fatalf("generateKeys() unexpected error = %v", err)
+	}
+
+	// Every tenant's pool cycles through its own algorithms.
+	want := []string{"ES256", "EdDSA", "ES384", "ES256", "PS256", "PS256", "PS256", "PS256"}
+	for i, key := range keys {
+		if key.Algorithm != want[i] {
+			t.Errorf("generateKeys() key[%d].Algorithm = %v, want %v", i, key.Algorithm, want[i])
+		}
+	}
+}
+
+func TestGenerateKeysPerTenant(t *testing.T) {
+	keys, err := generateKeys(context.Background(), signer.NewMemoryBackend(), newTestCA(t), map[string][]signer.Algorithm{"acme": {signer.ES256}, "globex": {signer.ES256}}, []string{"acme", "globex"}, []int{5, 6, 7, 8})
+	if err != nil {
+		t.Fatalf("generateKeys() unexpected error = %v", err)
+	}
+	want := []struct {
+		id     int
+		tenant string
+	}{{5, "acme"}, {6, "acme"}, {7, "globex"}, {8, "globex"}}
+	if len(keys) != len(want) {
+		t.Fatalf("generateKeys() len = %v, want %v", len(keys), len(want))
+	}
+	for i, key := range keys {
+		if key.ID != want[i].id || key.TenantID != want[i].tenant {
+			t.Errorf("generateKeys() key[%d] = %d of %q, want %d of %q", i, key.ID, key.TenantID, want[i].id, want[i].tenant)
+		}
+	}
+}
+
+func TestGenerateKeysRejectsUnevenIDs(t *testing.T) {
+	_, err := generateKeys(context.Background(), signer.NewMemoryBackend(), newTestCA(t), map[string][]signer.Algorithm{"acme": {signer.ES256}, "globex": {signer.ES256}}, []string{"acme", "globex"}, []int{1, 2, 3})
+	if err == nil {
+		t.Errorf("generateKeys() expected error for 3 IDs across 2 tenants")
+	}
+}
+
+func TestKeyAlgorithmsRejectsUnknown(t *testing.T) {
+	if _, err := keyAlgorithms(signer.NewMemoryBackend(), []string{"ES256", "HS256"}); err == nil {
+		t.Errorf("keyAlgorithms() expected error for HS256")
+	}
+}
+
+func TestKeyAlgorithmsRejectsUnsupportedByBackend(t *testing.T) {
+	_, err := keyAlgorithms(&signer.PKCS11Backend{}, []string{"ES256", "EdDSA"})
+	if err == nil || !strings.Contains(err.Error(), "PKCS#11") {
+		t.Errorf("keyAlgorithms() = %v, want EdDSA refused by the PKCS#11 backend", err)
+	}
+}
+
+func TestTenantKeyAlgorithmsRejectsUnknownTenant(t *testing.T) {
+	_, err := tenantKeyAlgorithms(signer.NewMemoryBackend(), []string{"acme"}, []string{"ES256"}, map[string][]string{"globex": {"PS256"}})
+	if err == nil {
+		t.Errorf("tenantKeyAlgorithms() expected error for a tenant not in TENANTS")
+	}
+}
+
 func TestEnqueueKey(t *testing.T) {
 	// Create a logger for testing
 	oldLog := log
@@ -139,6 +232,7 @@ func TestEnqueueKey(t *testing.T) {
 			name: "Successfully enqueue key",
 			key: &types.Key{
 				ID:         1,
+				TenantID:   types.DefaultTenant,
 				Value:      "mock-key-value",
 				IsInUse:    false,
 				LastUsedAt: time.Unix(0, 0),
@@ -149,6 +243,7 @@ func TestEnqueueKey(t *testing.T) {
 			name: "Error publishing key",
 			key: &types.Key{
 				ID:         2,
+				TenantID:   types.DefaultTenant,
 				Value:      "mock-key-value",
 				IsInUse:    false,
 				LastUsedAt: time.Unix(0, 0),
@@ -159,7 +254,10 @@ func TestEnqueueKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			js, conn := nats.Connect()
+			js, conn, err := nats.Connect("keys-service")
+			if err != nil {
+				t.Fatalf("Failed connecting to NATS: %v", err)
+			}
 			defer conn.Close()
 
 			ctx := context.Background()
@@ -201,9 +299,9 @@ func TestEnqueueAllKeys(t *testing.T) {
 		{
 			name: "Enqueue multiple keys",
 			keys: []*types.Key{
-				{ID: 1, Value: "key1", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
-				{ID: 2, Value: "key2", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
-				{ID: 3, Value: "key3", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
+				{ID: 1, TenantID: types.DefaultTenant, Value: "key1", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
+				{ID: 2, TenantID: types.DefaultTenant, Value: "key2", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
+				{ID: 3, TenantID: types.DefaultTenant, Value: "key3", IsInUse: false, LastUsedAt: time.Unix(0, 0)},
 			},
 		},
 		{
@@ -214,7 +312,10 @@ func TestEnqueueAllKeys(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			js, conn := nats.Connect()
+			js, conn, err := nats.Connect("keys-service")
+			if err != nil {
+				t.Fatalf("Failed connecting to NATS: %v", err)
+			}
 			defer conn.Close()
 
 			defer func() {
@@ -241,7 +342,7 @@ func TestMainIntegration(t *testing.T) {
 	// For now, we'll just verify that key components compile
 	t.Run("key_components_compile", func(t *testing.T) {
 		// Just a compilation check
-		_, err := generateKey(1)
+		_, err := generateKey(context.Background(), signer.NewMemoryBackend(), newTestCA(t), types.DefaultTenant, 1, signer.ES256)
 		if err != nil {
 			t.Errorf("generateKey() unexpected error: %v", err)
 		}
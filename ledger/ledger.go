@@ -0,0 +1,183 @@
+// Package ledger keeps the signatures table tamper-evident. Every inserted
+// batch of signatures gets a ledger entry whose hash commits to the batch and
+// to the previous entry, so changing, removing or adding a signature row
+// outside of Append breaks the chain that Verify walks.
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// GenesisHash is the previous hash of the first ledger entry.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+const (
+	// advisoryLockKey serializes appends across signing-service instances, so
+	// two batches never claim the same head.
+	advisoryLockKey = 0x7661756c74 // "vault"
+
+	// chunkSize keeps each bulk insert under the PostgreSQL limit of 65535
+	// bind parameters per statement.
+	chunkSize = 4000
+)
+
+// leaf is the canonical form of a signature row inside a batch hash. It only
+// holds stored columns, normalized the way they read back from the database.
+type leaf struct {
+	TenantID        string   `json:"tenant_id,omitempty"`
+	RecordID        int      `json:"record_id"`
+	KeyID           int      `json:"key_id"`
+	Algorithm       string   `json:"algorithm"`
+	Value           string   `json:"value"`
+	TimestampToken  string   `json:"timestamp_token,omitempty"`
+	MerkleRoot      string   `json:"merkle_root,omitempty"`
+	MerkleLeafIndex *int     `json:"merkle_leaf_index,omitempty"`
+	MerkleTreeSize  *int     `json:"merkle_tree_size,omitempty"`
+	MerklePath      []string `json:"merkle_path,omitempty"`
+	InsertedAt      string   `json:"inserted_at"`
+}
+
+func newLeaf(sig types.Signature) leaf {
+	l := leaf{
+		RecordID:       sig.RecordID,
+		KeyID:          sig.KeyID,
+		Algorithm:      sig.Algorithm,
+		Value:          sig.Value,
+		TimestampToken: sig.TimestampToken,
+		InsertedAt:     sig.InsertedAt.UTC().Format(time.RFC3339Nano),
+	}
+	// Rows from before tenants read back as the default tenant; they were
+	// hashed without one.
+	if sig.TenantID != types.DefaultTenant {
+		l.TenantID = sig.TenantID
+	}
+	if l.Algorithm == "" {
+		l.Algorithm = signature.DefaultAlgorithm
+	}
+	if proof := sig.MerkleProof; proof != nil {
+		l.MerkleRoot = proof.Root
+		l.MerkleLeafIndex = &proof.LeafIndex
+		l.MerkleTreeSize = &proof.TreeSize
+		l.MerklePath = proof.Path
+	}
+	return l
+}
+
+// BatchHash returns the hex SHA-256 hash of a batch of signatures. Signatures
+// are hashed in record ID order, so the hash does not depend on the order they
+// were inserted or read in.
+func BatchHash(sigs []types.Signature) string {
+	sorted := slices.Clone(sigs)
+	slices.SortFunc(sorted, func(a, b types.Signature) int { return a.RecordID - b.RecordID })
+
+	h := sha256.New()
+	for _, sig := range sorted {
+		encoded, _ := json.Marshal(newLeaf(sig)) // always marshals.
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(encoded)))
+		h.Write(length[:])
+		h.Write(encoded)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// EntryHash returns the hex SHA-256 hash of ledger entry seq, which commits to
+// the previous entry hash, the batch hash and the batch size.
+func EntryHash(prevHash, batchHash string, seq, size int) string {
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write([]byte(batchHash))
+	var numbers [16]byte
+	binary.BigEndian.PutUint64(numbers[:8], uint64(seq))
+	binary.BigEndian.PutUint64(numbers[8:], uint64(size))
+	h.Write(numbers[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Append inserts sigs as one ledger batch within tx: it chains a new entry to
+// the current head and stores the signatures with its sequence number. Each
+// signature is stamped with InsertedAt, at the microsecond precision
+// PostgreSQL keeps, and LedgerSeq, and signatures without a tenant get the
+// default one. The caller commits tx.
+func Append(ctx context.Context, tx *database.Tx, sigs []types.Signature) (*database.LedgerEntry, error) {
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", advisoryLockKey); err != nil {
+		return nil, fmt.Errorf("failed locking the ledger: %w", err)
+	}
+	prevHash, seq := GenesisHash, 1
+	head, err := tx.LedgerEntry.Query().Order(database.Desc(ledgerentry.FieldID)).First(ctx)
+	switch {
+	case err == nil:
+		prevHash, seq = head.Hash, head.ID+1
+	case !database.IsNotFound(err):
+		return nil, fmt.Errorf("failed reading the ledger head: %w", err)
+	}
+
+	now := time.Now().Truncate(time.Microsecond)
+	for i := range sigs {
+		if sigs[i].TenantID == "" {
+			sigs[i].TenantID = types.DefaultTenant
+		}
+		sigs[i].InsertedAt = now
+		sigs[i].LedgerSeq = seq
+	}
+	batchHash := BatchHash(sigs)
+	entry, err := tx.LedgerEntry.Create().
+		SetID(seq).
+		SetPrevHash(prevHash).
+		SetBatchHash(batchHash).
+		SetSize(len(sigs)).
+		SetHash(EntryHash(prevHash, batchHash, seq, len(sigs))).
+		SetInsertedAt(now).
+		Save(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed appending ledger entry %d: %w", seq, err)
+	}
+
+	for chunk := range slices.Chunk(sigs, chunkSize) {
+		bulk := make([]*database.SignatureCreate, 0, len(chunk))
+		for _, sig := range chunk {
+			create := tx.Signature.Create().
+				SetTenantID(sig.TenantID).
+				SetRecordID(sig.RecordID).
+				SetKeyID(sig.KeyID).
+				SetNillableAlgorithm(nilIfEmpty(sig.Algorithm)).
+				SetValue(sig.Value).
+				SetNillableTimestampToken(nilIfEmpty(sig.TimestampToken)).
+				SetLedgerSeq(seq).
+				SetInsertedAt(sig.InsertedAt)
+			if proof := sig.MerkleProof; proof != nil {
+				create.SetMerkleRoot(proof.Root).
+					SetMerkleLeafIndex(proof.LeafIndex).
+					SetMerkleTreeSize(proof.TreeSize).
+					SetMerklePath(proof.Path)
+			}
+			bulk = append(bulk, create)
+		}
+		if _, err := tx.Signature.CreateBulk(bulk...).Save(ctx); err != nil {
+			return nil, fmt.Errorf("failed to bulk insert signatures: %w", err)
+		}
+	}
+	return entry, nil
+}
+
+// nilIfEmpty leaves a column unset for empty strings, so the schema default
+// applies to a missing algorithm and an untimestamped signature stores NULL.
+func nilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,175 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+var insertedAt = time.Date(2026, 10, 19, 17, 50, 0, 123456000, time.UTC)
+
+func testBatch(recordIDs ...int) []types.Signature {
+	sigs := make([]types.Signature, len(recordIDs))
+	for i, id := range recordIDs {
+		sigs[i] = types.Signature{RecordID: id, KeyID: 1, Algorithm: "ES256", Value: "sig", InsertedAt: insertedAt}
+	}
+	return sigs
+}
+
+// testLedger chains an entry for each batch the way Append does.
+func testLedger(batches ...[]types.Signature) []*database.LedgerEntry {
+	prevHash := GenesisHash
+	entries := make([]*database.LedgerEntry, len(batches))
+	for i, sigs := range batches {
+		batchHash := BatchHash(sigs)
+		entries[i] = &database.LedgerEntry{
+			ID:        i + 1,
+			PrevHash:  prevHash,
+			BatchHash: batchHash,
+			Size:      len(sigs),
+			Hash:      EntryHash(prevHash, batchHash, i+1, len(sigs)),
+		}
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+func loader(batches ...[]types.Signature) func(int) ([]types.Signature, error) {
+	return func(seq int) ([]types.Signature, error) {
+		return batches[seq-1], nil
+	}
+}
+
+func TestBatchHash(t *testing.T) {
+	base := BatchHash(testBatch(1, 2, 3))
+	if BatchHash(testBatch(3, 1, 2)) != base {
+		t.Errorf("BatchHash() depends on the signature order")
+	}
+
+	// Rows read back from the database carry the schema default algorithm,
+	// a local time zone and an empty rather than missing Merkle path.
+	withDefaults := testBatch(1, 2, 3)
+	withDefaults[0].Algorithm = ""
+	withDefaults[2].TenantID = types.DefaultTenant
+	withDefaults[1].InsertedAt = insertedAt.In(time.FixedZone("CEST", 2*60*60))
+	proofBatch, readBack := testBatch(1, 2, 3), testBatch(1, 2, 3)
+	proofBatch[2].MerkleProof = &types.MerkleProof{Root: "root", TreeSize: 1}
+	readBack[2].MerkleProof = &types.MerkleProof{Root: "root", TreeSize: 1, Path: []string{}}
+	if BatchHash(withDefaults) != base {
+		t.Errorf("BatchHash() differs for normalized columns")
+	}
+	if BatchHash(proofBatch) != BatchHash(readBack) {
+		t.Errorf("BatchHash() differs for a nil and an empty Merkle path")
+	}
+
+	tests := []struct {
+		name   string
+		modify func(sigs []types.Signature)
+	}{
+		{name: "value", modify: func(sigs []types.Signature) { sigs[0].Value = "forged" }},
+		{name: "key", modify: func(sigs []types.Signature) { sigs[1].KeyID = 2 }},
+		{name: "record", modify: func(sigs []types.Signature) { sigs[2].RecordID = 4 }},
+		{name: "tenant", modify: func(sigs []types.Signature) { sigs[1].TenantID = "acme" }},
+		{name: "timestamp token", modify: func(sigs []types.Signature) { sigs[0].TimestampToken = "token" }},
+		{name: "inserted at", modify: func(sigs []types.Signature) { sigs[0].InsertedAt = insertedAt.Add(time.Microsecond) }},
+		{name: "merkle proof", modify: func(sigs []types.Signature) { sigs[0].MerkleProof = &types.MerkleProof{Root: "root", TreeSize: 1} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := testBatch(1, 2, 3)
+			tt.modify(sigs)
+			if BatchHash(sigs) == base {
+				t.Errorf("BatchHash() does not cover the %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	batches := [][]types.Signature{testBatch(1, 2), testBatch(3), testBatch(4, 5, 6)}
+	entries := testLedger(batches...)
+
+	head, err := walk(entries, loader(batches...))
+	if err != nil {
+		t.Fatalf("walk() unexpected error: %v", err)
+	}
+	if head != entries[2] {
+		t.Errorf("walk() head = %+v, want entry 3", head)
+	}
+	if head, err := walk(nil, loader()); head != nil || err != nil {
+		t.Errorf("walk() of an empty ledger = %+v, %v, want nil, nil", head, err)
+	}
+}
+
+func TestWalkDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry
+	}{
+		{
+			name: "modified signature",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				batches[1][0].Value = "forged"
+				return entries
+			},
+		},
+		{
+			name: "deleted signature",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				batches[0] = batches[0][:1]
+				return entries
+			},
+		},
+		{
+			name: "inserted signature",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				batches[2] = append(batches[2], testBatch(7)...)
+				return entries
+			},
+		},
+		{
+			name: "rehashed batch",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				batches[1][0].Value = "forged"
+				entries[1].BatchHash = BatchHash(batches[1])
+				return entries
+			},
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				batches[1][0].Value = "forged"
+				entries[1].BatchHash = BatchHash(batches[1])
+				entries[1].Hash = EntryHash(entries[1].PrevHash, entries[1].BatchHash, 2, entries[1].Size)
+				return entries
+			},
+		},
+		{
+			name: "deleted entry",
+			tamper: func(entries []*database.LedgerEntry, batches [][]types.Signature) []*database.LedgerEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := [][]types.Signature{testBatch(1, 2), testBatch(3), testBatch(4, 5, 6)}
+			entries := tt.tamper(testLedger(batches...), batches)
+			if _, err := walk(entries, loader(batches...)); err == nil {
+				t.Errorf("walk() should fail")
+			}
+		})
+	}
+}
+
+func TestWalkReturnsLoadErrors(t *testing.T) {
+	errLoad := errors.New("connection lost")
+	entries := testLedger(testBatch(1))
+	_, err := walk(entries, func(int) ([]types.Signature, error) { return nil, errLoad })
+	if !errors.Is(err, errLoad) {
+		t.Errorf("walk() error = %v, want %v", err, errLoad)
+	}
+}
@@ -0,0 +1,93 @@
+package ledger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/ledgerentry"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// Verify walks the ledger from its first entry, recomputing every batch hash
+// from the signature rows and every entry hash from its predecessor. It
+// returns the head entry, or nil for an empty ledger, or an error describing
+// the first inconsistency found.
+//
+// The chain cannot tell a truncated ledger from a shorter one: compare the
+// returned head against a previously published head to detect that.
+func Verify(ctx context.Context, client *database.Client) (*database.LedgerEntry, error) {
+	entries, err := client.LedgerEntry.Query().Order(database.Asc(ledgerentry.FieldID)).All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the ledger: %w", err)
+	}
+	return walk(entries, func(seq int) ([]types.Signature, error) {
+		rows, err := client.Signature.Query().Where(signature.LedgerSeq(seq)).All(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading signatures of ledger entry %d: %w", seq, err)
+		}
+		sigs := make([]types.Signature, len(rows))
+		for i, row := range rows {
+			sigs[i] = signatureOf(row)
+		}
+		return sigs, nil
+	})
+}
+
+// walk checks entries, sorted by sequence number, against the batches
+// returned by load.
+func walk(entries []*database.LedgerEntry, load func(seq int) ([]types.Signature, error)) (*database.LedgerEntry, error) {
+	prevHash := GenesisHash
+	for i, entry := range entries {
+		if entry.ID != i+1 {
+			return nil, fmt.Errorf("ledger entry %d is missing", i+1)
+		}
+		if entry.PrevHash != prevHash {
+			return nil, fmt.Errorf("ledger entry %d does not chain to entry %d", entry.ID, entry.ID-1)
+		}
+		if hash := EntryHash(entry.PrevHash, entry.BatchHash, entry.ID, entry.Size); entry.Hash != hash {
+			return nil, fmt.Errorf("ledger entry %d hash mismatch", entry.ID)
+		}
+		sigs, err := load(entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(sigs) != entry.Size {
+			return nil, fmt.Errorf("ledger entry %d has %d signatures, want %d", entry.ID, len(sigs), entry.Size)
+		}
+		if BatchHash(sigs) != entry.BatchHash {
+			return nil, fmt.Errorf("signatures of ledger entry %d were modified", entry.ID)
+		}
+		prevHash = entry.Hash
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return entries[len(entries)-1], nil
+}
+
+func signatureOf(row *database.Signature) types.Signature {
+	sig := types.Signature{
+		ID:             row.ID,
+		TenantID:       row.TenantID,
+		RecordID:       row.RecordID,
+		KeyID:          row.KeyID,
+		Algorithm:      row.Algorithm,
+		Value:          row.Value,
+		TimestampToken: row.TimestampToken,
+		InsertedAt:     row.InsertedAt,
+	}
+	if row.LedgerSeq != nil {
+		sig.LedgerSeq = *row.LedgerSeq
+	}
+	if row.MerkleRoot != "" && row.MerkleLeafIndex != nil && row.MerkleTreeSize != nil {
+		sig.MerkleProof = &types.MerkleProof{
+			Root:      row.MerkleRoot,
+			LeafIndex: *row.MerkleLeafIndex,
+			TreeSize:  *row.MerkleTreeSize,
+			Path:      row.MerklePath,
+		}
+	}
+	return sig
+}
@@ -0,0 +1,3 @@
+module github.com/jurshsmith/vaultstream/metrics
+
+go 1.24.1
@@ -0,0 +1,118 @@
+// Package metrics publishes service metrics through expvar, so they can be
+// scraped as JSON from /debug/vars without any dependency beyond the standard
+// library.
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// DefaultBuckets are upper bounds in seconds suited to latencies from
+// milliseconds to minutes.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300}
+
+// Histogram counts observations into buckets, like a Prometheus histogram:
+// bucket i counts the observations up to Bounds[i], cumulatively.
+type Histogram struct {
+	mu     sync.Mutex
+	bounds []float64
+	counts []uint64 // per bucket, with a last one for +Inf
+	count  uint64
+	sum    float64
+}
+
+func NewHistogram(bounds ...float64) *Histogram {
+	bounds = slices.Clone(bounds)
+	slices.Sort(bounds)
+	return &Histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+// Observe adds one observation.
+func (h *Histogram) Observe(v float64) {
+	i, _ := slices.BinarySearch(h.bounds, v)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.counts[i]++
+	h.count++
+	h.sum += v
+}
+
+// Snapshot is a point-in-time copy of a histogram.
+type Snapshot struct {
+	Count uint64  `json:"count"`
+	Sum   float64 `json:"sum"`
+	// Buckets maps each upper bound, and "+Inf", to the cumulative count.
+	Buckets map[string]uint64 `json:"buckets"`
+}
+
+func (h *Histogram) Snapshot() Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := Snapshot{Count: h.count, Sum: h.sum, Buckets: make(map[string]uint64, len(h.counts))}
+	var cumulative uint64
+	for i, n := range h.counts {
+		cumulative += n
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = jsonNumber(h.bounds[i])
+		}
+		s.Buckets[le] = cumulative
+	}
+	return s
+}
+
+// String implements expvar.Var.
+func (h *Histogram) String() string {
+	out, _ := json.Marshal(h.Snapshot()) // always marshals.
+	return string(out)
+}
+
+// HistogramVec is a family of histograms published under one expvar name and
+// told apart by a label value, e.g. a tenant.
+type HistogramVec struct {
+	bounds []float64
+	vars   *expvar.Map
+	mu     sync.Mutex
+}
+
+// NewHistogramVec publishes an empty family under name. Like expvar.Publish,
+// it panics if the name is already in use.
+func NewHistogramVec(name string, bounds ...float64) *HistogramVec {
+	return &HistogramVec{bounds: bounds, vars: expvar.NewMap(name)}
+}
+
+// With returns the histogram of a label value, creating it on first use.
+func (v *HistogramVec) With(label string) *Histogram {
+	if h, ok := v.vars.Get(label).(*Histogram); ok {
+		return h
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if h, ok := v.vars.Get(label).(*Histogram); ok {
+		return h
+	}
+	h := NewHistogram(v.bounds...)
+	v.vars.Set(label, h)
+	return h
+}
+
+// Handler serves every published metric as JSON.
+func Handler() http.Handler {
+	return expvar.Handler()
+}
+
+// ListenAndServe serves the metrics on addr at /debug/vars until it fails.
+func ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", Handler())
+	return http.ListenAndServe(addr, mux)
+}
+
+func jsonNumber(f float64) string {
+	out, _ := json.Marshal(f) // finite bounds always marshal.
+	return string(out)
+}
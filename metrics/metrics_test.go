@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram(1, 0.1, 10)
+	for _, v := range []float64{0.05, 0.1, 0.5, 3, 60} {
+		h.Observe(v)
+	}
+
+	s := h.Snapshot()
+	if s.Count != 5 || s.Sum != 63.65 {
+		t.Errorf("Snapshot() count, sum = %d, %v; want 5, 63.65", s.Count, s.Sum)
+	}
+	want := map[string]uint64{"0.1": 2, "1": 3, "10": 4, "+Inf": 5}
+	for le, n := range want {
+		if s.Buckets[le] != n {
+			t.Errorf("Snapshot() bucket %s = %d, want %d", le, s.Buckets[le], n)
+		}
+	}
+
+	var decoded Snapshot
+	if err := json.Unmarshal([]byte(h.String()), &decoded); err != nil || decoded.Count != 5 {
+		t.Errorf("String() = %s, want the JSON snapshot", h.String())
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	v := NewHistogramVec("test_wait_seconds", DefaultBuckets...)
+	v.With("acme").Observe(1)
+	v.With("acme").Observe(2)
+	v.With("globex").Observe(3)
+
+	var published map[string]Snapshot
+	if err := json.Unmarshal([]byte(expvar.Get("test_wait_seconds").String()), &published); err != nil {
+		t.Fatalf("published metric is not JSON: %v", err)
+	}
+	if published["acme"].Count != 2 || published["globex"].Sum != 3 {
+		t.Errorf("published metric = %+v", published)
+	}
+}
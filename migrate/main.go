@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	atlas "ariga.io/atlas/sql/migrate"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql/schema"
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/migrate"
+	"github.com/jurshsmith/vaultstream/logger"
+	"go.uber.org/zap"
+)
+
+var log *zap.Logger
+
+const usage = `Usage: migrate <command> [arguments]
+
+Commands:
+  up [-baseline version] [n]  apply the next n pending migrations, all by default
+  down [n]                    revert the last n applied migrations, 1 by default, 0 for all
+  status                      list the migrations and what has been applied of them
+  drift                       print what the database lacks to match database/schema
+  diff [-dir dir] <name>      generate the migration from the last one to database/schema
+  hash [-dir dir]             rewrite atlas.sum after editing a migration by hand
+`
+
+// main manages the versioned migrations of database/migrations, which
+// services expect to be applied.
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	log = logger.New()
+	defer log.Sync()
+
+	config.Setup()
+
+	ctx := context.Background()
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+	switch cmd {
+	case "up":
+		up(ctx, args)
+	case "down":
+		down(ctx, args)
+	case "status":
+		status(ctx)
+	case "drift":
+		drift(ctx)
+	case "diff":
+		diff(ctx, args)
+	case "hash":
+		hash(args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func up(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	baseline := fs.String("baseline", "", "version of the last migration the database already has, for databases from before migrations were versioned")
+	fs.Parse(args)
+	n := count(fs, 0)
+
+	m := migrator()
+	defer m.Close()
+	switch err := m.Up(ctx, n, *baseline); {
+	case errors.Is(err, database.ErrNoChanges):
+		log.Info("Database is up to date")
+	case err != nil:
+		log.Fatal("Error applying migrations", zap.Error(err))
+	default:
+		log.Info("Migrations applied")
+	}
+}
+
+func down(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	fs.Parse(args)
+	n := count(fs, 1)
+
+	m := migrator()
+	defer m.Close()
+	switch reverted, err := m.Down(ctx, n); {
+	case errors.Is(err, database.ErrNoChanges):
+		log.Info("No migrations to revert")
+	case err != nil:
+		log.Fatal("Error reverting migrations", zap.Strings("reverted", reverted), zap.Error(err))
+	default:
+		log.Info("Migrations reverted", zap.Strings("versions", reverted))
+	}
+}
+
+func status(ctx context.Context) {
+	m := migrator()
+	defer m.Close()
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		log.Fatal("Error reading migrations", zap.Error(err))
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tDESCRIPTION\tSTATE\tEXECUTED AT\tERROR")
+	for _, s := range migrations {
+		executedAt := ""
+		if !s.ExecutedAt.IsZero() {
+			executedAt = s.ExecutedAt.Format("2006-01-02 15:04:05")
+		}
+		state := s.State
+		if s.State == "partial" || s.State == "failed" {
+			state = fmt.Sprintf("%s (%d/%d)", s.State, s.Applied, s.Total)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Version, s.Description, state, executedAt, s.Error)
+	}
+	w.Flush()
+}
+
+func drift(ctx context.Context) {
+	m := migrator()
+	defer m.Close()
+	stmts, err := m.Drift(ctx)
+	if err != nil {
+		log.Fatal("Error comparing the database to database/schema", zap.Error(err))
+	}
+	if len(stmts) == 0 {
+		log.Info("Database matches database/schema")
+		return
+	}
+	for _, stmt := range stmts {
+		fmt.Println(stmt)
+	}
+	log.Fatal("Database differs from database/schema", zap.Int("statements", len(stmts)))
+}
+
+// diff replays the migrations on DEV_DATABASE_URL and writes the changes
+// database/schema makes on top of them to a new migration file.
+func diff(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	path := fs.String("dir", "database/migrations", "migration directory")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		log.Fatal("diff needs the name of the migration, e.g. add_record_labels")
+	}
+	name := fs.Arg(0)
+
+	dir, err := atlas.NewLocalDir(*path)
+	if err != nil {
+		log.Fatal("Failed opening the migration directory", zap.Error(err))
+	}
+	err = migrate.NamedDiff(ctx, config.DevDatabaseURL(), name,
+		schema.WithDir(dir),
+		schema.WithMigrationMode(schema.ModeReplay),
+		schema.WithDialect(dialect.Postgres),
+		schema.WithFormatter(atlas.DefaultFormatter),
+		schema.WithErrNoPlan(true),
+	)
+	switch {
+	case errors.Is(err, atlas.ErrNoPlan):
+		log.Info("Migrations already match database/schema")
+	case err != nil:
+		log.Fatal("Error generating the migration", zap.Error(err))
+	default:
+		log.Info("Migration generated; write its revert to down/ under the same name", zap.String("dir", *path))
+	}
+}
+
+func hash(args []string) {
+	fs := flag.NewFlagSet("hash", flag.ExitOnError)
+	path := fs.String("dir", "database/migrations", "migration directory")
+	fs.Parse(args)
+
+	dir, err := atlas.NewLocalDir(*path)
+	if err != nil {
+		log.Fatal("Failed opening the migration directory", zap.Error(err))
+	}
+	sum, err := dir.Checksum()
+	if err != nil {
+		log.Fatal("Failed hashing the migrations", zap.Error(err))
+	}
+	if err := atlas.WriteSumFile(dir, sum); err != nil {
+		log.Fatal("Failed writing atlas.sum", zap.Error(err))
+	}
+	log.Info("atlas.sum updated", zap.String("dir", *path))
+}
+
+func migrator() *database.Migrator {
+	m, err := database.NewMigrator()
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
+	return m
+}
+
+// count reads the optional number of migrations after the flags of fs.
+func count(fs *flag.FlagSet, fallback int) int {
+	if fs.NArg() == 0 {
+		return fallback
+	}
+	n, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || n < 0 {
+		log.Fatal("Invalid number of migrations", zap.String("n", fs.Arg(0)))
+	}
+	return n
+}
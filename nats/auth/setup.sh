@@ -0,0 +1,54 @@
+#!/bin/sh
+# Generates what server.conf needs for a local server with authentication:
+# a CA with server and client certificates in tls/, an NKey seed per service
+# in nkeys/, users.env with their public keys for the server, and client.env
+# with the matching VaultStream settings to append to .env. Existing
+# certificates and seeds are kept.
+set -eu
+
+cd "$(dirname "$0")"
+dir=$(pwd)
+services="streams keys-service records-service api-service signing-service webhooks-service controller-service"
+
+mkdir -p tls nkeys
+GOBIN="$dir/bin" go install github.com/nats-io/nkeys/nk@v0.4.9
+nk="$dir/bin/nk"
+
+if [ ! -f tls/ca.pem ]; then
+	openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes -days 365 \
+		-subj "/CN=VaultStream NATS CA" -keyout tls/ca-key.pem -out tls/ca.pem
+fi
+
+for name in server client; do
+	[ -f "tls/$name.pem" ] && continue
+	if [ "$name" = server ]; then
+		printf 'subjectAltName=DNS:localhost,DNS:nats,IP:127.0.0.1\nextendedKeyUsage=serverAuth\n' >"tls/$name.ext"
+	else
+		printf 'extendedKeyUsage=clientAuth\n' >"tls/$name.ext"
+	fi
+	openssl req -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+		-subj "/CN=vaultstream-$name" -keyout "tls/$name-key.pem" -out "tls/$name.csr"
+	openssl x509 -req -days 365 -in "tls/$name.csr" -CA tls/ca.pem -CAkey tls/ca-key.pem \
+		-CAcreateserial -extfile "tls/$name.ext" -out "tls/$name.pem"
+	rm "tls/$name.csr" "tls/$name.ext"
+done
+
+: >users.env
+cat >client.env <<EOF
+VAULTSTREAM_NATS_URL=tls://localhost:4222
+VAULTSTREAM_NATS_TLS_CA=$dir/tls/ca.pem
+VAULTSTREAM_NATS_TLS_CERT=$dir/tls/client.pem
+VAULTSTREAM_NATS_TLS_KEY=$dir/tls/client-key.pem
+EOF
+
+for service in $services; do
+	if [ ! -f "nkeys/$service.nk" ]; then
+		"$nk" -gen user >"nkeys/$service.nk"
+		chmod 600 "nkeys/$service.nk"
+	fi
+	var=$(echo "$service" | tr 'a-z-' 'A-Z_')
+	echo "${var}_NKEY=$("$nk" -inkey "nkeys/$service.nk" -pubout)" >>users.env
+	echo "${var}_NATS_NKEY=$dir/nkeys/$service.nk" >>client.env
+done
+
+echo "Wrote $dir/users.env and $dir/client.env; append client.env to .env to connect with them."
@@ -0,0 +1,100 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+var recreatedConsumers = expvar.NewInt("nats_consumers_recreated")
+
+// consumerCreator is the part of jetstream.JetStream a Durable needs.
+type consumerCreator interface {
+	CreateOrUpdateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error)
+}
+
+// Durable is a durable pull consumer that resumes after the server lost it,
+// e.g. one restarted without its storage: a fetch failing because the
+// consumer is gone creates it again, and the next fetch uses the new one,
+// which starts over as configured. Consumers that survive a reconnect need
+// nothing; their next fetch just succeeds.
+type Durable struct {
+	js     consumerCreator
+	stream string
+	config jetstream.ConsumerConfig
+
+	mu       sync.Mutex
+	consumer jetstream.Consumer
+}
+
+// NewDurable creates or updates the consumer of stream.
+func NewDurable(ctx context.Context, js consumerCreator, stream string, cfg jetstream.ConsumerConfig) (*Durable, error) {
+	consumer, err := js.CreateOrUpdateConsumer(ctx, stream, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Durable{js: js, stream: stream, config: cfg, consumer: consumer}, nil
+}
+
+// Next fetches a single message, as jetstream.Consumer.Next does.
+func (d *Durable) Next(opts ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	msg, err := d.current().Next(opts...)
+	d.check(err)
+	return msg, err
+}
+
+// FetchNoWait fetches up to batch messages that are available right away;
+// the batch reports a lost consumer through its Error.
+func (d *Durable) FetchNoWait(batch int) (jetstream.MessageBatch, error) {
+	msgs, err := d.current().FetchNoWait(batch)
+	if err != nil {
+		d.check(err)
+		return nil, err
+	}
+	return checkedBatch{MessageBatch: msgs, durable: d}, nil
+}
+
+func (d *Durable) current() jetstream.Consumer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.consumer
+}
+
+// check creates the consumer again if err shows it is gone. A server that
+// lost the consumer no longer answers its pull requests, so no responders
+// count as lost too.
+func (d *Durable) check(err error) {
+	if !errors.Is(err, jetstream.ErrConsumerNotFound) && !errors.Is(err, jetstream.ErrConsumerDeleted) && !errors.Is(err, nats.ErrNoResponders) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	consumer, err := d.js.CreateOrUpdateConsumer(ctx, d.stream, d.config)
+	if err != nil {
+		log.Printf("Failed recreating consumer %s of stream %s: %v", d.config.Durable, d.stream, err)
+		return
+	}
+	d.mu.Lock()
+	d.consumer = consumer
+	d.mu.Unlock()
+	recreatedConsumers.Add(1)
+	log.Printf("Recreated consumer %s of stream %s", d.config.Durable, d.stream)
+}
+
+// checkedBatch checks the error a fetch ended with.
+type checkedBatch struct {
+	jetstream.MessageBatch
+	durable *Durable
+}
+
+func (b checkedBatch) Error() error {
+	err := b.MessageBatch.Error()
+	b.durable.check(err)
+	return err
+}
@@ -0,0 +1,73 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeConsumer fails every fetch with err, or returns msg.
+type fakeConsumer struct {
+	jetstream.Consumer
+	err error
+	msg jetstream.Msg
+}
+
+func (c fakeConsumer) Next(opts ...jetstream.FetchOpt) (jetstream.Msg, error) {
+	return c.msg, c.err
+}
+
+// fakeCreator hands out the next of consumers on every create.
+type fakeCreator struct {
+	consumers []jetstream.Consumer
+	creates   int
+}
+
+func (f *fakeCreator) CreateOrUpdateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+	if f.creates == len(f.consumers) {
+		return nil, errors.New("no more consumers")
+	}
+	f.creates++
+	return f.consumers[f.creates-1], nil
+}
+
+type fakeMsg struct{ jetstream.Msg }
+
+func TestDurableRecreatesLostConsumer(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCreates int
+	}{
+		{name: "consumer not found", err: jetstream.ErrConsumerNotFound, wantCreates: 2},
+		{name: "consumer deleted", err: jetstream.ErrConsumerDeleted, wantCreates: 2},
+		{name: "no responders", err: nats.ErrNoResponders, wantCreates: 2},
+		{name: "timeout", err: nats.ErrTimeout, wantCreates: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := fakeMsg{}
+			js := &fakeCreator{consumers: []jetstream.Consumer{fakeConsumer{err: tt.err}, fakeConsumer{msg: msg}}}
+			d, err := NewDurable(context.Background(), js, "records", jetstream.ConsumerConfig{Durable: "test"})
+			if err != nil {
+				t.Fatalf("NewDurable() returned an unexpected error: %v", err)
+			}
+			if _, err := d.Next(jetstream.FetchMaxWait(time.Second)); !errors.Is(err, tt.err) {
+				t.Fatalf("Next() error = %v, want %v", err, tt.err)
+			}
+			if js.creates != tt.wantCreates {
+				t.Errorf("consumer created %d times, want %d", js.creates, tt.wantCreates)
+			}
+			if tt.wantCreates == 1 {
+				return
+			}
+			if got, err := d.Next(); err != nil || got != msg {
+				t.Errorf("Next() after recreating = %v, %v; want the message of the new consumer", got, err)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package nats
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"log"
 	"time"
 
@@ -10,62 +13,162 @@ import (
 	"github.com/nats-io/nats.go/jetstream"
 )
 
-// Connect establishes a connection to the NATS server, ensures the stream is created/updated,
-// and returns a JetStream API (new API) along with the underlying NATS connection.
-func Connect() (jetstream.JetStream, *nats.Conn) {
-	natsURL := vaultStreamConfig.VaultStreamNatsURL()
-	natsPassword := vaultStreamConfig.VaultStreamNatsPassword()
-
-	opts := []nats.Option{nats.UserInfo("admin", natsPassword)}
+var (
+	connected   = expvar.NewInt("nats_connected")
+	disconnects = expvar.NewInt("nats_disconnects")
+	reconnects  = expvar.NewInt("nats_reconnects")
+)
 
-	nc, err := nats.Connect(natsURL, opts...)
+// Connect establishes a connection to the NATS server as service, checks the
+// records, keys and events streams against their configuration, and returns
+// a JetStream API (new API) along with the underlying NATS connection.
+// Services never change streams: they fail when one is missing and only warn
+// when one differs from its configuration, leaving the streams command to
+// apply the changes.
+func Connect(service string) (jetstream.JetStream, *nats.Conn, error) {
+	js, nc, err := Dial(service)
 	if err != nil {
-		log.Fatalf("Failed connecting to NATS: %v", err)
-	}
-
-	if _, err := createOrUpdateStream(nc); err != nil {
-		log.Fatalf("Error creating/updating stream: %v", err)
+		return nil, nil, err
 	}
 
-	js, err := jetstream.New(nc)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	plan, err := Check(ctx, js)
 	if err != nil {
-		log.Fatalf("Error creating new JetStream API: %v", err)
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed checking streams, run the streams command: %w", err)
+	}
+	if plan.Changed() {
+		log.Printf("Warning: streams differ from their configuration, run the streams command to apply:\n%s", plan)
 	}
 
-	return js, nc
+	return js, nc, nil
 }
 
-// createOrUpdateStream ensures a stream named "vaultstream-streams" exists with the given subjects.
-func createOrUpdateStream(nc *nats.Conn) (*nats.StreamInfo, error) {
-	// Create a context with a timeout for the stream operation.
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// Dial connects to the NATS server as service without touching any stream.
+// While the server is unreachable it retries for up to the startup timeout;
+// once connected, the connection reconnects by itself as configured by
+// VAULTSTREAM_NATS_MAX_RECONNECTS, logging and counting every disconnect.
+func Dial(service string) (jetstream.JetStream, *nats.Conn, error) {
+	natsURL := vaultStreamConfig.VaultStreamNatsURL()
+	reconnect := vaultStreamConfig.VaultStreamNatsReconnect()
 
-	jsc, err := nc.JetStream()
+	opts, err := options(service, vaultStreamConfig.VaultStreamNatsAuth(service))
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("invalid NATS credentials of %s: %w", service, err)
 	}
+	opts = append(opts, reconnectOptions(reconnect)...)
 
-	// Monolith stream config for all VaultStream Signer streams
-	streamConfig := &nats.StreamConfig{
-		Name:      vaultStreamConfig.EventsStreamName(),
-		Subjects:  []string{"records.>", "keys.>"},
-		Storage:   nats.FileStorage,
-		Retention: nats.LimitsPolicy,
-		MaxMsgs:   -1, // No limit on the number of messages.
-		MaxBytes:  -1, // No limit on the total size of messages.
-		MaxAge:    0,  // Retain messages indefinitely.
-		Replicas:  1,
+	deadline := time.Now().Add(reconnect.StartupTimeout)
+	var nc *nats.Conn
+	for {
+		nc, err = nats.Connect(natsURL, opts...)
+		// Only an unreachable server is worth waiting for; bad credentials
+		// will not get better.
+		if err == nil || !errors.Is(err, nats.ErrNoServers) || time.Now().Add(reconnect.Wait).After(deadline) {
+			break
+		}
+		log.Printf("NATS not ready yet, retrying in %s: %v", reconnect.Wait, err)
+		time.Sleep(reconnect.Wait)
 	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed connecting to NATS: %w", err)
+	}
+	connected.Set(1)
 
-	// Attempt to add the stream. If it already exists, update its configuration.
-	streamInfo, err := jsc.AddStream(streamConfig, nats.Context(ctx))
+	js, err := jetstream.New(nc)
 	if err != nil {
-		streamInfo, err = jsc.UpdateStream(streamConfig, nats.Context(ctx))
+		nc.Close()
+		return nil, nil, fmt.Errorf("failed creating JetStream API: %w", err)
+	}
+
+	return js, nc, nil
+}
+
+// reconnectOptions applies the reconnect policy and reports the connection
+// state through the log and the nats_* metrics.
+func reconnectOptions(reconnect vaultStreamConfig.NatsReconnect) []nats.Option {
+	return []nats.Option{
+		nats.MaxReconnects(reconnect.MaxReconnects),
+		nats.ReconnectWait(reconnect.Wait),
+		nats.ReconnectJitter(reconnect.Jitter, reconnect.Jitter),
+		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
+			connected.Set(0)
+			disconnects.Add(1)
+			log.Printf("Disconnected from NATS: %v", err)
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			connected.Set(1)
+			reconnects.Add(1)
+			log.Printf("Reconnected to NATS at %s", nc.ConnectedUrlRedacted())
+		}),
+		nats.ClosedHandler(func(nc *nats.Conn) {
+			connected.Set(0)
+			if err := nc.LastError(); err != nil {
+				log.Printf("NATS connection closed: %v", err)
+			}
+		}),
+		nats.ErrorHandler(func(nc *nats.Conn, sub *nats.Subscription, err error) {
+			if sub != nil {
+				log.Printf("NATS error on subscription %s: %v", sub.Subject, err)
+				return
+			}
+			log.Printf("NATS error: %v", err)
+		}),
+	}
+}
+
+// options authenticates with the first of a JWT credentials file, an NKey
+// seed or a password that is set, and adds TLS when a CA or client
+// certificate is.
+func options(service string, auth vaultStreamConfig.NatsAuth) ([]nats.Option, error) {
+	opts := []nats.Option{nats.Name(service)}
+	switch {
+	case auth.Creds != "" && auth.NKey != "":
+		return nil, errors.New("both a credentials file and an NKey seed are set")
+	case auth.Creds != "":
+		opts = append(opts, nats.UserCredentials(auth.Creds))
+	case auth.NKey != "":
+		opt, err := nats.NkeyOptionFromSeed(auth.NKey)
 		if err != nil {
 			return nil, err
 		}
+		opts = append(opts, opt)
+	case auth.Password != "":
+		opts = append(opts, nats.UserInfo(auth.User, auth.Password))
 	}
 
-	return streamInfo, nil
+	if (auth.TLSCert == "") != (auth.TLSKey == "") {
+		return nil, errors.New("a client certificate needs both TLS_CERT and TLS_KEY")
+	}
+	if auth.TLSCA != "" {
+		opts = append(opts, nats.RootCAs(auth.TLSCA))
+	}
+	if auth.TLSCert != "" {
+		opts = append(opts, nats.ClientCert(auth.TLSCert, auth.TLSKey))
+	}
+	return opts, nil
+}
+
+// Check plans the configured streams against the server's without changing
+// any. It fails when a stream is missing or the plan cannot be applied, and
+// otherwise returns the plan, whose changes are drift.
+func Check(ctx context.Context, js streamAPI) (Plan, error) {
+	desired, err := Streams()
+	if err != nil {
+		return nil, err
+	}
+	plan, err := PlanStreams(ctx, js, desired)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.Err(); err != nil {
+		return plan, err
+	}
+	for _, s := range plan {
+		if s.Create {
+			return plan, fmt.Errorf("stream %s does not exist", s.Config.Name)
+		}
+	}
+	return plan, nil
 }
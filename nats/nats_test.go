@@ -0,0 +1,142 @@
+package nats
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+// writeCert writes a self-signed certificate and its key to dir.
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "vaultstream-test"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestOptions(t *testing.T) {
+	dir := t.TempDir()
+	user, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, _ := user.Seed()
+	publicKey, _ := user.PublicKey()
+	seedFile := filepath.Join(dir, "user.nk")
+	if err := os.WriteFile(seedFile, seed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	credsFile := filepath.Join(dir, "user.creds")
+	creds := fmt.Sprintf("-----BEGIN NATS USER JWT-----\neyJ0eXAiOiJKV1QifQ.e30.c2ln\n------END NATS USER JWT------\n\n-----BEGIN USER NKEY SEED-----\n%s\n------END USER NKEY SEED------\n", seed)
+	if err := os.WriteFile(credsFile, []byte(creds), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile := writeCert(t, dir)
+
+	tests := []struct {
+		name    string
+		auth    vaultStreamConfig.NatsAuth
+		check   func(nats.Options) bool
+		wantErr bool
+	}{
+		{
+			name:  "password",
+			auth:  vaultStreamConfig.NatsAuth{User: "admin", Password: "secret"},
+			check: func(o nats.Options) bool { return o.User == "admin" && o.Password == "secret" && !o.Secure },
+		},
+		{
+			name:  "nkey wins over password",
+			auth:  vaultStreamConfig.NatsAuth{NKey: seedFile, User: "admin", Password: "secret"},
+			check: func(o nats.Options) bool { return o.Nkey == publicKey && o.User == "" },
+		},
+		{
+			name:  "credentials",
+			auth:  vaultStreamConfig.NatsAuth{Creds: credsFile},
+			check: func(o nats.Options) bool { return o.UserJWT != nil && o.Nkey == "" },
+		},
+		{
+			name:  "mutual tls",
+			auth:  vaultStreamConfig.NatsAuth{TLSCA: certFile, TLSCert: certFile, TLSKey: keyFile},
+			check: func(o nats.Options) bool { return o.Secure && o.RootCAsCB != nil && o.TLSCertCB != nil },
+		},
+		{name: "credentials and nkey", auth: vaultStreamConfig.NatsAuth{Creds: credsFile, NKey: seedFile}, wantErr: true},
+		{name: "missing seed", auth: vaultStreamConfig.NatsAuth{NKey: filepath.Join(dir, "missing.nk")}, wantErr: true},
+		{name: "certificate without key", auth: vaultStreamConfig.NatsAuth{TLSCert: certFile}, wantErr: true},
+		{name: "missing ca", auth: vaultStreamConfig.NatsAuth{TLSCA: filepath.Join(dir, "missing.pem")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := options("keys-service", tt.auth)
+			o := nats.GetDefaultOptions()
+			for _, opt := range opts {
+				if err == nil {
+					err = opt(&o)
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("options() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if o.Name != "keys-service" || !tt.check(o) {
+				t.Errorf("options() set %+v", o)
+			}
+		})
+	}
+}
+
+func TestDialGivesUpOnUnreachableServer(t *testing.T) {
+	t.Setenv("VAULTSTREAM_NATS_URL", "nats://127.0.0.1:1")
+	t.Setenv("VAULTSTREAM_NATS_STARTUP_TIMEOUT", "300ms")
+	t.Setenv("VAULTSTREAM_NATS_RECONNECT_WAIT", "100ms")
+
+	start := time.Now()
+	_, _, err := Dial("test")
+	if !errors.Is(err, nats.ErrNoServers) {
+		t.Fatalf("Dial() error = %v, want no servers", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Dial() gave up after %s, want about the 300ms startup timeout", elapsed)
+	}
+}
@@ -0,0 +1,159 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// permissionsTenant is the tenant the permissions test publishes for. No
+// service consumes its records or keys, and the test purges what it
+// published.
+const permissionsTenant = "permissions-test"
+
+// permitted reports whether service may publish to subject, or subscribe to
+// it when subscribe is set, on a fresh connection, so that a violation is
+// the connection's last error once the server answered a flush.
+func permitted(t *testing.T, service string, subscribe bool, subject string) bool {
+	t.Helper()
+	opts, err := options(service, vaultStreamConfig.VaultStreamNatsAuth(service))
+	if err != nil {
+		t.Fatalf("invalid NATS credentials of %s: %v", service, err)
+	}
+	nc, err := nats.Connect(vaultStreamConfig.VaultStreamNatsURL(), opts...)
+	if err != nil {
+		t.Fatalf("failed connecting as %s: %v", service, err)
+	}
+	defer nc.Close()
+
+	if subscribe {
+		_, err = nc.SubscribeSync(subject)
+	} else {
+		err = nc.Publish(subject, []byte("{}"))
+	}
+	if err != nil {
+		t.Fatalf("%s failed on %s: %v", service, subject, err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("%s failed flushing: %v", service, err)
+	}
+	return !errors.Is(nc.LastError(), nats.ErrPermissionViolation)
+}
+
+// TestPermissions checks the users of nats/auth/server.conf against a server
+// running it, as make nats.auth.start does: every service may publish and
+// subscribe to what it uses, and is denied the subjects of the others.
+func TestPermissions(t *testing.T) {
+	if os.Getenv("STREAMS_NATS_NKEY") == "" {
+		t.Skip("STREAMS_NATS_NKEY not set; run make nats.auth.start and load nats/auth/client.env")
+	}
+	records := "records." + permissionsTenant + ".normal.1"
+	keys := "keys." + permissionsTenant + ".1"
+	signatures := "signatures." + permissionsTenant + ".1"
+	signRequest := "sign.request." + permissionsTenant
+
+	tests := []struct {
+		service                    string
+		publish, subscribe         []string // allowed
+		denyPublish, denySubscribe []string
+	}{
+		{
+			service:       "keys-service",
+			publish:       []string{keys, "$JS.API.INFO", "$JS.API.STREAM.INFO.vaultstream-keys"},
+			subscribe:     []string{"_INBOX.permissions"},
+			denyPublish:   []string{records, signatures, signRequest, "$JS.API.STREAM.DELETE.vaultstream-keys", "$JS.API.CONSUMER.CREATE.vaultstream-keys.signing"},
+			denySubscribe: []string{"keys.>", "signatures.>", signRequest},
+		},
+		{
+			service:       "records-service",
+			publish:       []string{records, "$JS.API.STREAM.INFO.vaultstream-records", "$JS.API.STREAM.INFO.OBJ_vaultstream-claims"},
+			subscribe:     []string{"_INBOX.permissions", "signatures.>"},
+			denyPublish:   []string{keys, signatures, "control.scaling", "$JS.API.STREAM.PURGE.vaultstream-records"},
+			denySubscribe: []string{"records.>", signRequest},
+		},
+		{
+			service:       "api-service",
+			publish:       []string{records, "$JS.API.STREAM.INFO.vaultstream-records"},
+			subscribe:     []string{"_INBOX.permissions"},
+			denyPublish:   []string{keys, signatures, signRequest, "$JS.API.CONSUMER.CREATE.vaultstream-records.signing"},
+			denySubscribe: []string{"signatures.>", signRequest},
+		},
+		{
+			service: "signing-service",
+			publish: []string{signatures, "$JS.API.CONSUMER.INFO.vaultstream-records.signing", "$JS.API.CONSUMER.MSG.NEXT.vaultstream-keys.signing",
+				"$JS.API.STREAM.INFO.OBJ_vaultstream-claims"},
+			subscribe:     []string{"_INBOX.permissions", "sign.request.*"},
+			denyPublish:   []string{records, keys, "control.scaling", "$JS.API.STREAM.PURGE.vaultstream-records", "$JS.API.CONSUMER.CREATE.vaultstream-events.webhooks"},
+			denySubscribe: []string{"records.>", "keys.>", "signatures.>"},
+		},
+		{
+			service:       "webhooks-service",
+			publish:       []string{"$JS.API.CONSUMER.INFO.vaultstream-events.webhooks", "$JS.API.CONSUMER.MSG.NEXT.vaultstream-events.webhooks"},
+			subscribe:     []string{"_INBOX.permissions"},
+			denyPublish:   []string{records, keys, signatures, "$JS.API.CONSUMER.CREATE.vaultstream-records.signing"},
+			denySubscribe: []string{"signatures.>", signRequest},
+		},
+		{
+			service:       "controller-service",
+			publish:       []string{"control.scaling", "$JS.API.CONSUMER.INFO.vaultstream-records.signing", "$JS.API.CONSUMER.INFO.vaultstream-keys.signing"},
+			subscribe:     []string{"_INBOX.permissions", "signatures.>"},
+			denyPublish:   []string{records, keys, signatures, "$JS.API.CONSUMER.MSG.NEXT.vaultstream-records.signing"},
+			denySubscribe: []string{"records.>", signRequest},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			for _, subject := range tt.publish {
+				if !permitted(t, tt.service, false, subject) {
+					t.Errorf("%s may not publish to %s, want allowed", tt.service, subject)
+				}
+			}
+			for _, subject := range tt.subscribe {
+				if !permitted(t, tt.service, true, subject) {
+					t.Errorf("%s may not subscribe to %s, want allowed", tt.service, subject)
+				}
+			}
+			for _, subject := range tt.denyPublish {
+				if permitted(t, tt.service, false, subject) {
+					t.Errorf("%s may publish to %s, want denied", tt.service, subject)
+				}
+			}
+			for _, subject := range tt.denySubscribe {
+				if permitted(t, tt.service, true, subject) {
+					t.Errorf("%s may subscribe to %s, want denied", tt.service, subject)
+				}
+			}
+		})
+	}
+
+	// Clean up what the allowed publishes stored, as the streams user.
+	js, nc, err := Dial("streams")
+	if err != nil {
+		t.Fatalf("failed connecting as streams: %v", err)
+	}
+	defer nc.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for stream, subject := range map[string]string{
+		vaultStreamConfig.RecordsStreamName(): "records." + permissionsTenant + ".>",
+		vaultStreamConfig.KeysStreamName():    "keys." + permissionsTenant + ".>",
+		vaultStreamConfig.EventsStreamName():  "signatures." + permissionsTenant + ".>",
+	} {
+		s, err := js.Stream(ctx, stream)
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			continue
+		}
+		if err == nil {
+			err = s.Purge(ctx, jetstream.WithPurgeSubject(subject))
+		}
+		if err != nil {
+			t.Errorf("failed purging %s from stream %s: %v", subject, stream, err)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package nats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Streams returns the desired records, keys and events streams, as
+// configured by their <KIND>_STREAM_* variables.
+func Streams() ([]jetstream.StreamConfig, error) {
+	streams := []struct {
+		config  vaultStreamConfig.Stream
+		subject string
+	}{
+		{vaultStreamConfig.RecordsStream(), "records.>"},
+		{vaultStreamConfig.KeysStream(), "keys.>"},
+		{vaultStreamConfig.EventsStream(), "signatures.>"},
+	}
+	configs := make([]jetstream.StreamConfig, len(streams))
+	for i, s := range streams {
+		cfg, err := streamConfig(s.config, s.subject)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s stream: %w", s.subject, err)
+		}
+		for _, other := range configs[:i] {
+			if other.Name == cfg.Name {
+				return nil, fmt.Errorf("streams of %s and %s are both named %s", other.Subjects[0], s.subject, cfg.Name)
+			}
+		}
+		configs[i] = cfg
+	}
+	return configs, nil
+}
+
+func streamConfig(s vaultStreamConfig.Stream, subjects ...string) (jetstream.StreamConfig, error) {
+	cfg := jetstream.StreamConfig{
+		Name:     s.Name,
+		Subjects: subjects,
+		MaxAge:   s.MaxAge,
+		MaxBytes: s.MaxBytes,
+		MaxMsgs:  -1,
+		Replicas: s.Replicas,
+	}
+	if cfg.Name == "" || strings.ContainsAny(cfg.Name, " .*>") {
+		return cfg, fmt.Errorf("invalid name %q", cfg.Name)
+	}
+	switch s.Retention {
+	case "limits":
+		cfg.Retention = jetstream.LimitsPolicy
+	case "workqueue":
+		cfg.Retention = jetstream.WorkQueuePolicy
+	case "interest":
+		cfg.Retention = jetstream.InterestPolicy
+	default:
+		return cfg, fmt.Errorf("unknown retention %q, want limits, workqueue or interest", s.Retention)
+	}
+	switch s.Storage {
+	case "file":
+		cfg.Storage = jetstream.FileStorage
+	case "memory":
+		cfg.Storage = jetstream.MemoryStorage
+	default:
+		return cfg, fmt.Errorf("unknown storage %q, want file or memory", s.Storage)
+	}
+	switch s.Discard {
+	case "old":
+		cfg.Discard = jetstream.DiscardOld
+	case "new":
+		cfg.Discard = jetstream.DiscardNew
+	default:
+		return cfg, fmt.Errorf("unknown discard policy %q, want old or new", s.Discard)
+	}
+	if cfg.MaxAge < 0 {
+		return cfg, fmt.Errorf("negative max age %s", cfg.MaxAge)
+	}
+	if cfg.MaxBytes == 0 || cfg.MaxBytes < -1 {
+		return cfg, fmt.Errorf("max bytes %d, want a positive limit or -1", cfg.MaxBytes)
+	}
+	if cfg.Replicas < 1 || cfg.Replicas > 5 {
+		return cfg, fmt.Errorf("%d replicas, want 1 to 5", cfg.Replicas)
+	}
+	return cfg, nil
+}
+
+// Change is a setting of a stream that differs from its configuration.
+type Change struct {
+	Setting  string
+	From, To string
+	// Blocked changes cannot be made to an existing stream; it would have to
+	// be recreated, losing its messages.
+	Blocked bool
+	// Lossy changes tighten a limit, deleting the messages now over it.
+	Lossy bool
+}
+
+// StreamPlan is what reconciling one stream takes.
+type StreamPlan struct {
+	Config jetstream.StreamConfig // to create or update the stream with
+	Create bool
+	// Changes of an existing stream; settings VaultStream does not manage,
+	// such as MaxMsgSize, are kept as they are.
+	Changes []Change
+	// Conflicts are subjects of the stream held by another stream, which
+	// has to be removed or changed first.
+	Conflicts []string
+}
+
+// Plan reconciles streams with their configuration: it creates missing
+// streams and updates existing ones in place, but never deletes or
+// recreates a stream.
+type Plan []StreamPlan
+
+// streamAPI is the part of jetstream.JetStream reconciling streams needs.
+type streamAPI interface {
+	Stream(ctx context.Context, name string) (jetstream.Stream, error)
+	StreamNameBySubject(ctx context.Context, subject string) (string, error)
+	CreateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error)
+	UpdateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error)
+}
+
+// PlanStreams compares the desired streams with the server's.
+func PlanStreams(ctx context.Context, js streamAPI, desired []jetstream.StreamConfig) (Plan, error) {
+	names := make(map[string]bool, len(desired))
+	for _, cfg := range desired {
+		names[cfg.Name] = true
+	}
+
+	plan := make(Plan, len(desired))
+	for i, cfg := range desired {
+		stream, err := js.Stream(ctx, cfg.Name)
+		switch {
+		case errors.Is(err, jetstream.ErrStreamNotFound):
+			plan[i] = StreamPlan{Config: cfg, Create: true, Changes: diff(jetstream.StreamConfig{}, cfg)}
+		case err != nil:
+			return nil, fmt.Errorf("failed loading stream %s: %w", cfg.Name, err)
+		default:
+			current := stream.CachedInfo().Config
+			plan[i] = StreamPlan{Config: merge(current, cfg), Changes: diff(current, cfg)}
+		}
+
+		// A subject held by another managed stream is released when that
+		// stream is updated, which happens before anything is created.
+		for _, subject := range cfg.Subjects {
+			owner, err := js.StreamNameBySubject(ctx, subject)
+			if errors.Is(err, jetstream.ErrStreamNotFound) || owner == cfg.Name {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed looking up stream of %s: %w", subject, err)
+			}
+			if !names[owner] {
+				plan[i].Conflicts = append(plan[i].Conflicts, fmt.Sprintf("%s is held by stream %s", subject, owner))
+			}
+		}
+	}
+	return plan, nil
+}
+
+// merge returns current with the settings VaultStream manages taken from
+// desired.
+func merge(current, desired jetstream.StreamConfig) jetstream.StreamConfig {
+	current.Subjects = desired.Subjects
+	current.Retention = desired.Retention
+	current.Storage = desired.Storage
+	current.Discard = desired.Discard
+	current.MaxAge = desired.MaxAge
+	current.MaxBytes = desired.MaxBytes
+	current.Replicas = desired.Replicas
+	return current
+}
+
+// diff lists the managed settings that differ between current and desired.
+// For a stream to be created, current is the zero config and nothing is
+// blocked.
+func diff(current, desired jetstream.StreamConfig) []Change {
+	create := current.Name == ""
+	var changes []Change
+	add := func(setting, from, to string, blocked, lossy bool) {
+		if create {
+			from, blocked, lossy = "", false, false
+		}
+		if from != to {
+			changes = append(changes, Change{Setting: setting, From: from, To: to, Blocked: blocked, Lossy: lossy})
+		}
+	}
+
+	currentSubjects, desiredSubjects := slices.Sorted(slices.Values(current.Subjects)), slices.Sorted(slices.Values(desired.Subjects))
+	add("subjects", strings.Join(currentSubjects, ","), strings.Join(desiredSubjects, ","), false, false)
+	add("retention", current.Retention.String(), desired.Retention.String(), true, false)
+	add("storage", current.Storage.String(), desired.Storage.String(), true, false)
+	add("discard", current.Discard.String(), desired.Discard.String(), false, false)
+	add("max_age", current.MaxAge.String(), desired.MaxAge.String(), false,
+		desired.MaxAge > 0 && (current.MaxAge == 0 || desired.MaxAge < current.MaxAge))
+	add("max_bytes", formatLimit(current.MaxBytes), formatLimit(desired.MaxBytes), false,
+		desired.MaxBytes > 0 && (current.MaxBytes <= 0 || desired.MaxBytes < current.MaxBytes))
+	add("replicas", strconv.Itoa(current.Replicas), strconv.Itoa(desired.Replicas), false, false)
+	return changes
+}
+
+func formatLimit(n int64) string {
+	if n <= 0 {
+		return "unlimited"
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// Changed reports whether applying the plan changes anything.
+func (p Plan) Changed() bool {
+	for _, s := range p {
+		if s.Create || len(s.Changes) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Err describes the blocked changes and conflicts that keep the plan from
+// being applied, if any.
+func (p Plan) Err() error {
+	var problems []string
+	for _, s := range p {
+		for _, c := range s.Changes {
+			if c.Blocked {
+				problems = append(problems, fmt.Sprintf("stream %s: %s cannot change from %s to %s without recreating it", s.Config.Name, c.Setting, c.From, c.To))
+			}
+		}
+		for _, conflict := range s.Conflicts {
+			problems = append(problems, fmt.Sprintf("stream %s: subject %s", s.Config.Name, conflict))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// Lossy reports whether applying the plan may delete messages.
+func (p Plan) Lossy() bool {
+	for _, s := range p {
+		for _, c := range s.Changes {
+			if c.Lossy {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply updates streams, then creates missing ones, so that subjects moved
+// between streams are released before they are taken. Nothing is applied
+// when any change is blocked, nor when one is lossy unless allowLossy is set.
+func (p Plan) Apply(ctx context.Context, js streamAPI, allowLossy bool) error {
+	if err := p.Err(); err != nil {
+		return err
+	}
+	if p.Lossy() && !allowLossy {
+		return errors.New("plan tightens a limit, deleting the messages over it; allow lossy changes to apply it")
+	}
+	for _, s := range p {
+		if s.Create || len(s.Changes) == 0 {
+			continue
+		}
+		if _, err := js.UpdateStream(ctx, s.Config); err != nil {
+			return fmt.Errorf("failed updating stream %s: %w", s.Config.Name, err)
+		}
+	}
+	for _, s := range p {
+		if !s.Create {
+			continue
+		}
+		// Another service may have created it meanwhile.
+		_, err := js.CreateStream(ctx, s.Config)
+		if errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+			_, err = js.UpdateStream(ctx, s.Config)
+		}
+		if err != nil {
+			return fmt.Errorf("failed creating stream %s: %w", s.Config.Name, err)
+		}
+	}
+	return nil
+}
+
+// String renders the plan for a dry run.
+func (p Plan) String() string {
+	var b strings.Builder
+	for _, s := range p {
+		switch {
+		case s.Create:
+			fmt.Fprintf(&b, "stream %s: create\n", s.Config.Name)
+		case len(s.Changes) > 0:
+			fmt.Fprintf(&b, "stream %s: update\n", s.Config.Name)
+		default:
+			fmt.Fprintf(&b, "stream %s: up to date\n", s.Config.Name)
+		}
+		for _, c := range s.Changes {
+			var note string
+			switch {
+			case c.Blocked:
+				note = " (blocked: needs the stream recreated)"
+			case c.Lossy:
+				note = " (may delete messages)"
+			}
+			if s.Create {
+				fmt.Fprintf(&b, "  %s: %s\n", c.Setting, c.To)
+			} else {
+				fmt.Fprintf(&b, "  %s: %s -> %s%s\n", c.Setting, c.From, c.To, note)
+			}
+		}
+		for _, conflict := range s.Conflicts {
+			fmt.Fprintf(&b, "  subject %s (blocked)\n", conflict)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,220 @@
+package nats
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakeStream is a stream as loaded from the server.
+type fakeStream struct {
+	jetstream.Stream
+	cfg jetstream.StreamConfig
+}
+
+func (s fakeStream) CachedInfo() *jetstream.StreamInfo {
+	return &jetstream.StreamInfo{Config: s.cfg}
+}
+
+// fakeServer keeps streams in memory and logs the calls changing them.
+type fakeServer struct {
+	streams map[string]jetstream.StreamConfig
+	calls   []string
+}
+
+func (f *fakeServer) Stream(ctx context.Context, name string) (jetstream.Stream, error) {
+	cfg, ok := f.streams[name]
+	if !ok {
+		return nil, jetstream.ErrStreamNotFound
+	}
+	return fakeStream{cfg: cfg}, nil
+}
+
+func (f *fakeServer) StreamNameBySubject(ctx context.Context, subject string) (string, error) {
+	for name, cfg := range f.streams {
+		if slices.Contains(cfg.Subjects, subject) {
+			return name, nil
+		}
+	}
+	return "", jetstream.ErrStreamNotFound
+}
+
+func (f *fakeServer) CreateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
+	f.calls = append(f.calls, "create "+cfg.Name)
+	f.streams[cfg.Name] = cfg
+	return fakeStream{cfg: cfg}, nil
+}
+
+func (f *fakeServer) UpdateStream(ctx context.Context, cfg jetstream.StreamConfig) (jetstream.Stream, error) {
+	f.calls = append(f.calls, "update "+cfg.Name)
+	f.streams[cfg.Name] = cfg
+	return fakeStream{cfg: cfg}, nil
+}
+
+func TestStreamConfig(t *testing.T) {
+	valid := vaultStreamConfig.Stream{Name: "records", Retention: "workqueue", Storage: "file", Discard: "old", MaxBytes: -1, Replicas: 1}
+	tests := []struct {
+		name    string
+		modify  func(*vaultStreamConfig.Stream)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(*vaultStreamConfig.Stream) {}},
+		{name: "limits in memory", modify: func(s *vaultStreamConfig.Stream) { s.Retention, s.Storage, s.MaxBytes = "limits", "memory", 1<<30 }},
+		{name: "empty name", modify: func(s *vaultStreamConfig.Stream) { s.Name = "" }, wantErr: true},
+		{name: "name with a dot", modify: func(s *vaultStreamConfig.Stream) { s.Name = "vault.records" }, wantErr: true},
+		{name: "unknown retention", modify: func(s *vaultStreamConfig.Stream) { s.Retention = "forever" }, wantErr: true},
+		{name: "unknown storage", modify: func(s *vaultStreamConfig.Stream) { s.Storage = "disk" }, wantErr: true},
+		{name: "unknown discard", modify: func(s *vaultStreamConfig.Stream) { s.Discard = "all" }, wantErr: true},
+		{name: "zero max bytes", modify: func(s *vaultStreamConfig.Stream) { s.MaxBytes = 0 }, wantErr: true},
+		{name: "negative max age", modify: func(s *vaultStreamConfig.Stream) { s.MaxAge = -time.Second }, wantErr: true},
+		{name: "no replicas", modify: func(s *vaultStreamConfig.Stream) { s.Replicas = 0 }, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			tt.modify(&s)
+			if _, err := streamConfig(s, "records.>"); (err != nil) != tt.wantErr {
+				t.Errorf("streamConfig(%+v) error = %v, want error %t", s, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	current := jetstream.StreamConfig{
+		Name:      "events",
+		Subjects:  []string{"signatures.>"},
+		Retention: jetstream.LimitsPolicy,
+		Storage:   jetstream.FileStorage,
+		MaxAge:    time.Hour,
+		MaxBytes:  -1,
+		Replicas:  1,
+	}
+	tests := []struct {
+		name   string
+		modify func(*jetstream.StreamConfig)
+		want   []Change
+	}{
+		{name: "unchanged", modify: func(*jetstream.StreamConfig) {}},
+		{name: "longer max age", modify: func(c *jetstream.StreamConfig) { c.MaxAge = 2 * time.Hour },
+			want: []Change{{Setting: "max_age", From: "1h0m0s", To: "2h0m0s"}}},
+		{name: "shorter max age", modify: func(c *jetstream.StreamConfig) { c.MaxAge = time.Minute },
+			want: []Change{{Setting: "max_age", From: "1h0m0s", To: "1m0s", Lossy: true}}},
+		{name: "byte limit", modify: func(c *jetstream.StreamConfig) { c.MaxBytes = 1024 },
+			want: []Change{{Setting: "max_bytes", From: "unlimited", To: "1024", Lossy: true}}},
+		{name: "work queue", modify: func(c *jetstream.StreamConfig) { c.Retention = jetstream.WorkQueuePolicy },
+			want: []Change{{Setting: "retention", From: "Limits", To: "WorkQueue", Blocked: true}}},
+		{name: "memory", modify: func(c *jetstream.StreamConfig) { c.Storage = jetstream.MemoryStorage },
+			want: []Change{{Setting: "storage", From: "File", To: "Memory", Blocked: true}}},
+		{name: "replicas and subjects", modify: func(c *jetstream.StreamConfig) { c.Replicas, c.Subjects = 3, []string{"signatures.>", "control.>"} },
+			want: []Change{{Setting: "subjects", From: "signatures.>", To: "control.>,signatures.>"}, {Setting: "replicas", From: "1", To: "3"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := current
+			desired.Subjects = slices.Clone(current.Subjects)
+			tt.modify(&desired)
+			if got := diff(current, desired); !slices.Equal(got, tt.want) {
+				t.Errorf("diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReconcile moves records.> out of an existing stream into a new one and
+// refuses to take subjects of a stream it does not manage.
+func TestReconcile(t *testing.T) {
+	records := jetstream.StreamConfig{Name: "records", Subjects: []string{"records.>"}, Retention: jetstream.WorkQueuePolicy, MaxBytes: -1, Replicas: 1}
+	events := jetstream.StreamConfig{Name: "events", Subjects: []string{"signatures.>"}, MaxBytes: -1, Replicas: 1}
+	existing := events
+	existing.Subjects = []string{"records.>", "signatures.>"}
+	existing.MaxMsgSize = 1 << 20
+	server := &fakeServer{streams: map[string]jetstream.StreamConfig{"events": existing}}
+
+	plan, err := PlanStreams(context.Background(), server, []jetstream.StreamConfig{records, events})
+	if err != nil {
+		t.Fatalf("PlanStreams() returned an unexpected error: %v", err)
+	}
+	if !plan[0].Create || plan[1].Create || len(plan[1].Changes) != 1 || plan[0].Conflicts != nil {
+		t.Fatalf("PlanStreams() = %+v, want records created and the events subjects changed", plan)
+	}
+	if out := plan.String(); !strings.Contains(out, "stream records: create") || !strings.Contains(out, "subjects: records.>,signatures.> -> signatures.>") {
+		t.Errorf("plan output:\n%s", out)
+	}
+	if err := plan.Apply(context.Background(), server, false); err != nil {
+		t.Fatalf("Apply() returned an unexpected error: %v", err)
+	}
+	if want := []string{"update events", "create records"}; !slices.Equal(server.calls, want) {
+		t.Errorf("Apply() calls = %v, want %v", server.calls, want)
+	}
+	if got := server.streams["events"]; got.MaxMsgSize != 1<<20 || !slices.Equal(got.Subjects, events.Subjects) {
+		t.Errorf("updated events stream = %+v, want its own max message size kept", got)
+	}
+
+	plan, err = PlanStreams(context.Background(), server, []jetstream.StreamConfig{records, events})
+	if err != nil || plan.Changed() {
+		t.Errorf("PlanStreams() after Apply() = %v, %v; want nothing to change", plan, err)
+	}
+
+	server.streams = map[string]jetstream.StreamConfig{"legacy": {Name: "legacy", Subjects: []string{"records.>"}}}
+	server.calls = nil
+	plan, err = PlanStreams(context.Background(), server, []jetstream.StreamConfig{records})
+	if err != nil {
+		t.Fatalf("PlanStreams() returned an unexpected error: %v", err)
+	}
+	if err := plan.Apply(context.Background(), server, true); err == nil || !strings.Contains(err.Error(), "held by stream legacy") || server.calls != nil {
+		t.Errorf("Apply() with a foreign stream on records.> = %v after %v, want a conflict and no calls", err, server.calls)
+	}
+}
+
+// TestApplyLossy verifies a plan tightening a limit is only applied when
+// lossy changes are allowed.
+func TestApplyLossy(t *testing.T) {
+	events := jetstream.StreamConfig{Name: "events", Subjects: []string{"signatures.>"}, MaxBytes: -1, Replicas: 1}
+	server := &fakeServer{streams: map[string]jetstream.StreamConfig{"events": events}}
+	tightened := events
+	tightened.MaxAge = time.Hour
+
+	plan, err := PlanStreams(context.Background(), server, []jetstream.StreamConfig{tightened})
+	if err != nil || !plan.Lossy() {
+		t.Fatalf("PlanStreams() = %+v, %v; want a lossy plan", plan, err)
+	}
+	if err := plan.Apply(context.Background(), server, false); err == nil || server.calls != nil {
+		t.Errorf("Apply() without lossy changes allowed = %v after %v, want an error and no calls", err, server.calls)
+	}
+	if err := plan.Apply(context.Background(), server, true); err != nil || server.streams["events"].MaxAge != time.Hour {
+		t.Errorf("Apply() with lossy changes allowed = %v, events stream %+v; want max age 1h", err, server.streams["events"])
+	}
+}
+
+// TestCheck verifies checking streams changes none: it fails on a missing
+// stream and reports drift in the plan.
+func TestCheck(t *testing.T) {
+	desired, err := Streams()
+	if err != nil {
+		t.Fatalf("Streams() returned an unexpected error: %v", err)
+	}
+	server := &fakeServer{streams: map[string]jetstream.StreamConfig{}}
+	for _, cfg := range desired[1:] {
+		server.streams[cfg.Name] = cfg
+	}
+	if _, err := Check(context.Background(), server); err == nil || !strings.Contains(err.Error(), desired[0].Name) {
+		t.Errorf("Check() with stream %s missing = %v, want an error naming it", desired[0].Name, err)
+	}
+
+	drifted := desired[0]
+	drifted.MaxAge = desired[0].MaxAge + time.Hour
+	server.streams[drifted.Name] = drifted
+	plan, err := Check(context.Background(), server)
+	if err != nil || !plan.Changed() {
+		t.Errorf("Check() with stream %s drifted = %v, %v; want the drift planned", drifted.Name, plan, err)
+	}
+	if server.calls != nil {
+		t.Errorf("Check() calls = %v, want none", server.calls)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/jurshsmith/vaultstream/recordbatch
+
+go 1.24.1
+
+require (
+	github.com/jurshsmith/vaultstream/types v0.0.0
+	github.com/klauspost/compress v1.18.0
+	github.com/nats-io/nats.go v1.40.1
+)
+
+require (
+	github.com/nats-io/nkeys v0.4.9 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.23.0 // indirect
+)
+
+replace github.com/jurshsmith/vaultstream/types => ../types
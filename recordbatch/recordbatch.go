@@ -0,0 +1,255 @@
+// Package recordbatch turns batches of records into JetStream messages that
+// fit the server's message limit, and back. A batch is JSON, optionally
+// compressed, with the EncodingHeader naming the compression. Batches over
+// the limit are split in halves or, with a claim check store, put in a
+// JetStream object store that the message refers to by its ClaimHeader.
+package recordbatch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	// EncodingHeader names the compression of a batch; batches without it
+	// are plain JSON.
+	EncodingHeader = "VaultStream-Encoding"
+	// ClaimHeader names the object a claim checked batch is stored as. The
+	// message body is empty.
+	ClaimHeader = "VaultStream-Claim"
+
+	// maxDecodedBytes bounds a decompressed batch, so a corrupt or hostile
+	// message cannot exhaust memory.
+	maxDecodedBytes = 256 << 20
+)
+
+// Compression is how a batch is compressed.
+type Compression string
+
+const (
+	None Compression = "none"
+	S2   Compression = "s2"
+	Zstd Compression = "zstd"
+)
+
+// ParseCompression parses a compression name; empty means None.
+func ParseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case "", None:
+		return None, nil
+	case S2, Zstd:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown compression %q, want none, s2 or zstd", s)
+	}
+}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecodedBytes))
+)
+
+// ObjectStore is the part of jetstream.ObjectStore claim checks use.
+type ObjectStore interface {
+	PutBytes(ctx context.Context, name string, data []byte) (*jetstream.ObjectInfo, error)
+	GetBytes(ctx context.Context, name string, opts ...jetstream.GetObjectOpt) ([]byte, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// OpenClaims creates or updates the claim check object store bucket, whose
+// objects expire after ttl.
+func OpenClaims(ctx context.Context, js jetstream.JetStream, bucket string, ttl time.Duration) (jetstream.ObjectStore, error) {
+	store, err := js.CreateOrUpdateObjectStore(ctx, jetstream.ObjectStoreConfig{
+		Bucket:      bucket,
+		Description: "Record batches too large for a message",
+		TTL:         ttl,
+		Storage:     jetstream.FileStorage,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed opening claim check bucket %s: %w", bucket, err)
+	}
+	return store, nil
+}
+
+// MaxMessageBytes is the largest message stream accepts: the server's max
+// payload, or the stream's own limit if lower.
+func MaxMessageBytes(ctx context.Context, js jetstream.JetStream, stream string, maxPayload int64) (int, error) {
+	s, err := js.Stream(ctx, stream)
+	if err != nil {
+		return 0, fmt.Errorf("failed loading stream %s: %w", stream, err)
+	}
+	limit := int(maxPayload)
+	if maxMsgSize := int(s.CachedInfo().Config.MaxMsgSize); maxMsgSize > 0 {
+		limit = min(limit, maxMsgSize)
+	}
+	return limit, nil
+}
+
+// publisher is the part of jetstream.JetStream a Publisher needs.
+type publisher interface {
+	PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// Part is one message a batch was published as.
+type Part struct {
+	Subject string
+	Records []types.Record
+	Bytes   int  // encoded size of the records
+	Claim   bool // stored in the claim check bucket
+}
+
+// Publisher publishes record batches as messages of at most maxBytes.
+type Publisher struct {
+	js          publisher
+	compression Compression
+	maxBytes    int
+	claims      ObjectStore // nil splits oversized batches instead
+}
+
+func NewPublisher(js publisher, compression Compression, maxBytes int, claims ObjectStore) *Publisher {
+	return &Publisher{js: js, compression: compression, maxBytes: maxBytes, claims: claims}
+}
+
+// Publish publishes records on prefix.<first record ID>, the subject doubling
+// as the message ID, and returns the messages it took. A batch too large for
+// one message is claim checked when the Publisher has a store, and split in
+// halves otherwise; only a single record that does not fit fails then.
+func (p *Publisher) Publish(ctx context.Context, prefix string, records []types.Record) ([]Part, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	data, err := Encode(records, p.compression)
+	if err != nil {
+		return nil, err
+	}
+	subject := prefix + "." + strconv.Itoa(records[0].ID)
+	msg := nats.NewMsg(subject)
+	if p.compression != None {
+		msg.Header.Set(EncodingHeader, string(p.compression))
+	}
+	part := Part{Subject: subject, Records: records, Bytes: len(data)}
+
+	if msgSize(msg, data) > p.maxBytes {
+		switch {
+		case p.claims != nil:
+			// Published again after a failure, the object is overwritten
+			// with the same batch.
+			if _, err := p.claims.PutBytes(ctx, subject, data); err != nil {
+				return nil, fmt.Errorf("failed storing batch %s: %w", subject, err)
+			}
+			msg.Header.Set(ClaimHeader, subject)
+			data = nil
+			part.Claim = true
+		case len(records) > 1:
+			half := len(records) / 2
+			first, err := p.Publish(ctx, prefix, records[:half])
+			if err != nil {
+				return first, err
+			}
+			rest, err := p.Publish(ctx, prefix, records[half:])
+			return append(first, rest...), err
+		default:
+			return nil, fmt.Errorf("record %d takes %d bytes, more than the %d a message allows", records[0].ID, len(data), p.maxBytes)
+		}
+	}
+
+	msg.Data = data
+	if _, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(subject)); err != nil {
+		return nil, fmt.Errorf("failed publishing batch %s: %w", subject, err)
+	}
+	return []Part{part}, nil
+}
+
+// msgSize is the size of msg with data as sent, headers included.
+func msgSize(msg *nats.Msg, data []byte) int {
+	size := len("NATS/1.0\r\n\r\n") + len(jetstream.MsgIDHeader+": \r\n") + len(msg.Subject)
+	for k, vs := range msg.Header {
+		for _, v := range vs {
+			size += len(k) + len(v) + len(": \r\n")
+		}
+	}
+	return size + len(data)
+}
+
+// Encode marshals records and compresses them.
+func Encode(records []types.Record, compression Compression) ([]byte, error) {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling records: %w", err)
+	}
+	switch compression {
+	case None:
+		return data, nil
+	case S2:
+		return s2.Encode(nil, data), nil
+	case Zstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown compression %q", compression)
+	}
+}
+
+// Decode returns the records of a batch message, loading claim checked
+// batches from claims.
+func Decode(ctx context.Context, header nats.Header, data []byte, claims ObjectStore) ([]types.Record, error) {
+	if name := header.Get(ClaimHeader); name != "" {
+		if claims == nil {
+			return nil, fmt.Errorf("batch is claim checked as %s, but no claim check bucket is configured", name)
+		}
+		var err error
+		if data, err = claims.GetBytes(ctx, name); err != nil {
+			return nil, fmt.Errorf("failed loading batch %s: %w", name, err)
+		}
+	}
+
+	switch c := Compression(header.Get(EncodingHeader)); c {
+	case "", None:
+	case S2:
+		n, err := s2.DecodedLen(data)
+		if err != nil {
+			return nil, fmt.Errorf("corrupt s2 batch: %w", err)
+		}
+		if n > maxDecodedBytes {
+			return nil, fmt.Errorf("s2 batch of %d bytes decoded exceeds the limit of %d", n, maxDecodedBytes)
+		}
+		if data, err = s2.Decode(nil, data); err != nil {
+			return nil, fmt.Errorf("failed decompressing s2 batch: %w", err)
+		}
+	case Zstd:
+		var err error
+		if data, err = zstdDecoder.DecodeAll(data, nil); err != nil {
+			return nil, fmt.Errorf("failed decompressing zstd batch: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown batch encoding %q", c)
+	}
+
+	var records []types.Record
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed unmarshaling records: %w", err)
+	}
+	return records, nil
+}
+
+// Release deletes the stored batch of a claim checked message once it is
+// processed. Other messages have nothing to release.
+func Release(ctx context.Context, header nats.Header, claims ObjectStore) error {
+	name := header.Get(ClaimHeader)
+	if name == "" || claims == nil {
+		return nil
+	}
+	if err := claims.Delete(ctx, name); err != nil && !errors.Is(err, jetstream.ErrObjectNotFound) {
+		return fmt.Errorf("failed deleting batch %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package recordbatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// fakePublisher keeps published messages instead of sending them to NATS.
+type fakePublisher struct {
+	msgs []*nats.Msg
+}
+
+func (p *fakePublisher) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	p.msgs = append(p.msgs, msg)
+	return &jetstream.PubAck{Stream: "test"}, nil
+}
+
+// fakeStore is an in-memory object store.
+type fakeStore map[string][]byte
+
+func (s fakeStore) PutBytes(ctx context.Context, name string, data []byte) (*jetstream.ObjectInfo, error) {
+	s[name] = data
+	return &jetstream.ObjectInfo{}, nil
+}
+
+func (s fakeStore) GetBytes(ctx context.Context, name string, opts ...jetstream.GetObjectOpt) ([]byte, error) {
+	data, ok := s[name]
+	if !ok {
+		return nil, jetstream.ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (s fakeStore) Delete(ctx context.Context, name string) error {
+	if _, ok := s[name]; !ok {
+		return jetstream.ErrObjectNotFound
+	}
+	delete(s, name)
+	return nil
+}
+
+// testRecords returns n records with compressible data of size bytes each.
+func testRecords(n, size int) []types.Record {
+	records := make([]types.Record, n)
+	for i := range records {
+		records[i] = types.Record{
+			ID:         i + 1,
+			TenantID:   types.DefaultTenant,
+			InsertedAt: time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC),
+			Data:       bytes.Repeat([]byte{byte(i)}, size),
+		}
+	}
+	return records
+}
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Compression
+		wantErr bool
+	}{
+		{in: "", want: None},
+		{in: "none", want: None},
+		{in: "s2", want: S2},
+		{in: "zstd", want: Zstd},
+		{in: "gzip", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCompression(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseCompression(%q) = %q, %v; want %q, error %t", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestPublishDecode round-trips a batch through every compression.
+func TestPublishDecode(t *testing.T) {
+	records := testRecords(20, 100)
+	plain, _ := Encode(records, None)
+	for _, compression := range []Compression{None, S2, Zstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			js := &fakePublisher{}
+			parts, err := NewPublisher(js, compression, 1<<20, nil).Publish(context.Background(), "records.default.normal", records)
+			if err != nil || len(parts) != 1 || len(js.msgs) != 1 {
+				t.Fatalf("Publish() = %d parts, %d messages, %v; want one", len(parts), len(js.msgs), err)
+			}
+			msg := js.msgs[0]
+			if msg.Subject != "records.default.normal.1" {
+				t.Errorf("subject = %s, want records.default.normal.1", msg.Subject)
+			}
+			if compression != None && len(msg.Data) >= len(plain) {
+				t.Errorf("%s batch is %d bytes, want less than the %d of plain JSON", compression, len(msg.Data), len(plain))
+			}
+			got, err := Decode(context.Background(), msg.Header, msg.Data, nil)
+			if err != nil || !reflect.DeepEqual(got, records) {
+				t.Errorf("Decode() = %d records, %v; want the published records", len(got), err)
+			}
+		})
+	}
+}
+
+// TestPublishSplits verifies oversized batches are split into messages under
+// the limit, keeping every record once and in order.
+func TestPublishSplits(t *testing.T) {
+	records := testRecords(10, 1000)
+	js := &fakePublisher{}
+	parts, err := NewPublisher(js, None, 3000, nil).Publish(context.Background(), "records.acme.high", records)
+	if err != nil {
+		t.Fatalf("Publish() returned an unexpected error: %v", err)
+	}
+	if len(parts) < 4 || len(parts) != len(js.msgs) {
+		t.Fatalf("Publish() = %d parts, %d messages; want at least 4 of each", len(parts), len(js.msgs))
+	}
+	var got []types.Record
+	for i, msg := range js.msgs {
+		if size := msgSize(msg, msg.Data); size > 3000 {
+			t.Errorf("message %s is %d bytes, over the limit", msg.Subject, size)
+		}
+		decoded, err := Decode(context.Background(), msg.Header, msg.Data, nil)
+		if err != nil {
+			t.Fatalf("Decode(%s) returned an unexpected error: %v", msg.Subject, err)
+		}
+		if want := fmt.Sprintf("records.acme.high.%d", decoded[0].ID); msg.Subject != want || parts[i].Subject != want {
+			t.Errorf("message subject = %s, part subject = %s; want %s", msg.Subject, parts[i].Subject, want)
+		}
+		got = append(got, decoded...)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("split batches hold %d records, want the %d published in order", len(got), len(records))
+	}
+
+	if _, err := NewPublisher(js, None, 500, nil).Publish(context.Background(), "records.acme.high", testRecords(1, 1000)); err == nil {
+		t.Errorf("Publish() of a record larger than a message should fail without a claim check bucket")
+	}
+}
+
+// TestPublishClaimCheck verifies oversized batches go to the object store and
+// are loaded and released from it.
+func TestPublishClaimCheck(t *testing.T) {
+	records := testRecords(10, 1000)
+	js := &fakePublisher{}
+	store := fakeStore{}
+	parts, err := NewPublisher(js, None, 500, store).Publish(context.Background(), "records.acme.low", records)
+	if err != nil || len(parts) != 1 || !parts[0].Claim {
+		t.Fatalf("Publish() = %d parts, %v; want one claim checked part", len(parts), err)
+	}
+	msg := js.msgs[0]
+	if len(msg.Data) != 0 || msg.Header.Get(ClaimHeader) != "records.acme.low.1" || len(store) != 1 {
+		t.Fatalf("message body = %d bytes, claim = %q, objects = %d; want an empty reference to one object", len(msg.Data), msg.Header.Get(ClaimHeader), len(store))
+	}
+
+	got, err := Decode(context.Background(), msg.Header, msg.Data, store)
+	if err != nil || !reflect.DeepEqual(got, records) {
+		t.Errorf("Decode() = %d records, %v; want the published records", len(got), err)
+	}
+	if _, err := Decode(context.Background(), msg.Header, msg.Data, nil); err == nil {
+		t.Errorf("Decode() of a claim check without a bucket should fail")
+	}
+
+	if err := Release(context.Background(), msg.Header, store); err != nil || len(store) != 0 {
+		t.Errorf("Release() = %v with %d objects left, want the object deleted", err, len(store))
+	}
+	if err := Release(context.Background(), msg.Header, store); err != nil {
+		t.Errorf("Release() of a deleted object = %v, want nil", err)
+	}
+}
+
+func TestDecodeRejectsUnknownEncoding(t *testing.T) {
+	header := nats.Header{}
+	header.Set(EncodingHeader, "gzip")
+	if _, err := Decode(context.Background(), header, []byte("[]"), nil); err == nil {
+		t.Errorf("Decode() of a gzip batch should fail")
+	}
+}
+
+// TestDecodeRejectsInvalidS2 verifies corrupt and oversized s2 batches fail
+// before they are decompressed, each with its own error.
+func TestDecodeRejectsInvalidS2(t *testing.T) {
+	header := nats.Header{}
+	header.Set(EncodingHeader, string(S2))
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{name: "corrupt", data: bytes.Repeat([]byte{0xff}, 11), wantErr: "corrupt s2 batch"},
+		{name: "oversized", data: binary.AppendUvarint(nil, maxDecodedBytes+1), wantErr: "exceeds the limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(context.Background(), header, tt.data, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || strings.Contains(err.Error(), "%!") {
+				t.Errorf("Decode() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
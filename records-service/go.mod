@@ -3,11 +3,12 @@ module github.com/jurshsmith/vaultstream/records-service
 go 1.24.1
 
 require (
-	entgo.io/ent v0.14.4
 	github.com/jurshsmith/vaultstream/config v0.0.0
 	github.com/jurshsmith/vaultstream/database v0.0.0
 	github.com/jurshsmith/vaultstream/logger v0.0.0
+	github.com/jurshsmith/vaultstream/metrics v0.0.0
 	github.com/jurshsmith/vaultstream/nats v0.0.0
+	github.com/jurshsmith/vaultstream/recordbatch v0.0.0
 	github.com/jurshsmith/vaultstream/types v0.0.0
 	github.com/nats-io/nats.go v1.40.1
 	go.uber.org/zap v1.27.0
@@ -19,12 +20,17 @@ replace github.com/jurshsmith/vaultstream/database => ../database
 
 replace github.com/jurshsmith/vaultstream/logger => ../logger
 
+replace github.com/jurshsmith/vaultstream/metrics => ../metrics
+
 replace github.com/jurshsmith/vaultstream/nats => ../nats
 
+replace github.com/jurshsmith/vaultstream/recordbatch => ../recordbatch
+
 replace github.com/jurshsmith/vaultstream/types => ../types
 
 require (
 	ariga.io/atlas v0.31.1-0.20250212144724-069be8033e83 // indirect
+	entgo.io/ent v0.14.4 // indirect
 	github.com/agext/levenshtein v1.2.1 // indirect
 	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
@@ -33,6 +39,10 @@ require (
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/hashicorp/hcl/v2 v2.13.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.7.2 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/lib/pq v1.10.9 // indirect
@@ -44,6 +54,7 @@ require (
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.12.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 )
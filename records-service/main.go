@@ -2,18 +2,17 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
 
-	"entgo.io/ent/dialect/sql"
 	"github.com/jurshsmith/vaultstream/config"
 	"github.com/jurshsmith/vaultstream/database"
 	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/metrics"
 	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/recordbatch"
 	"github.com/jurshsmith/vaultstream/types"
-	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
 )
 
@@ -28,77 +27,128 @@ func main() {
 	config.Setup()
 
 	batchSize := config.RecordsBatchSize()
+	priority := config.RecordsPriority()
+	if err := types.ValidatePriority(priority); err != nil {
+		log.Fatal("Invalid RECORDS_PRIORITY", zap.Error(err))
+	}
+	compression, err := recordbatch.ParseCompression(config.RecordsCompression())
+	if err != nil {
+		log.Fatal("Invalid RECORDS_COMPRESSION", zap.Error(err))
+	}
 
-	dbClient := database.Connect()
+	dbClient, err := database.Connect(config.RecordsMaxConcurrency())
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
 	defer dbClient.Close()
 	log.Debug("Database connection established")
 
-	jetstreamClient, natsConn := nats.Connect()
+	jetstreamClient, natsConn, err := nats.Connect("records-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
 	defer natsConn.Close()
 	log.Debug("NATS JetStream connection established")
 
 	mainContext := context.Background()
+	maxBytes, err := recordbatch.MaxMessageBytes(mainContext, jetstreamClient, config.RecordsStreamName(), natsConn.MaxPayload())
+	if err != nil {
+		log.Fatal("Error loading message limit", zap.Error(err))
+	}
 
-	var waitGroup sync.WaitGroup
-	semaphoreQueue := make(chan struct{}, config.RecordsMaxConcurrency())
-
-	totalBatches := config.TotalRecordBatches()
-
-	for batchID := 1; batchID <= totalBatches; batchID++ {
-		semaphoreQueue <- struct{}{} // acquire
-		waitGroup.Add(1)
-
-		go func(batchID int) {
-			defer waitGroup.Done()
-			defer func() { <-semaphoreQueue }() // release
-
-			dbRecords, err := dbClient.Record.
-				Query().
-				Where(func(s *sql.Selector) {
-					s.Where(sql.ExprP("mod((id-1), $1)+1 = $2", totalBatches, batchID))
-				}).
-				All(mainContext)
+	// Batches too large for a message are stored in the claim check bucket
+	// when there is one, and split otherwise.
+	var claims recordbatch.ObjectStore
+	if bucket := config.ClaimCheckBucket(); bucket != "" {
+		store, err := recordbatch.OpenClaims(mainContext, jetstreamClient, bucket, config.ClaimCheckTTL())
+		if err != nil {
+			log.Fatal("Error opening claim check bucket", zap.Error(err))
+		}
+		claims = store
+	}
+	publisher := recordbatch.NewPublisher(jetstreamClient, compression, maxBytes, claims)
+
+	// Batch sizes adapt to how fast signing-service gets through them, as
+	// reported by its signatures events.
+	sizer := newBatchSizer(batchSize, config.RecordsMinBatchSize(), config.RecordsMaxBatchSize(),
+		config.BatchTargetLatency(), config.BatchTargetInsert(), maxBytes)
+	signed := newFeedback(sizer)
+	signaturesSub, err := natsConn.Subscribe("signatures.>", signed.handle)
+	if err != nil {
+		log.Fatal("Error subscribing to signatures events", zap.Error(err))
+	}
+	defer signaturesSub.Unsubscribe()
 
-			if err != nil {
-				log.Error("Batch query error", zap.Int("batchID", batchID), zap.Error(err))
-				return
+	if addr := config.RecordsMetricsAddr(); addr != "" {
+		go func() {
+			if err := metrics.ListenAndServe(addr); err != nil {
+				log.Error("Error serving metrics", zap.Error(err))
 			}
+		}()
+		log.Info("Serving metrics", zap.String("addr", addr))
+	}
 
-			records := dbRecordsToBytes(dbRecords)
-
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
-
-			subject := fmt.Sprintf("records.%d", batchID)
-			pubAck, err := jetstreamClient.Publish(ctx, subject, records, jetstream.WithMsgID(subject))
-			if err != nil {
-				log.Fatal("Error publishing message", zap.String("subject", subject), zap.Error(err))
-			}
+	var waitGroup sync.WaitGroup
+	semaphoreQueue := make(chan struct{}, config.RecordsMaxConcurrency())
 
-			log.Debug("Published message", zap.String("stream", pubAck.Stream), zap.Uint64("sequence", pubAck.Sequence))
-			log.Debug("Batch published", zap.Int("batchID", batchID), zap.Int("recordCount", len(dbRecords)))
-		}(batchID)
+	// Stream the records by ID, each batch as large as the sizer currently
+	// allows.
+	records := newRecordStream(dbClient, 0)
+	for {
+		batch, err := records.next(mainContext, sizer.next())
+		if err != nil {
+			log.Fatal("Error reading records", zap.Int("afterID", records.lastID), zap.Error(err))
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		// A batch never mixes tenants: every tenant's share of it is published
+		// on its own subject, in the configured priority lane, and signed with
+		// that tenant's keys.
+		for tenant, tenantRecords := range tenantBatches(batch) {
+			semaphoreQueue <- struct{}{} // acquire
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+				defer func() { <-semaphoreQueue }() // release
+
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				publishBatch(ctx, publisher, sizer, signed, tenant, priority, tenantRecords)
+			}()
+		}
+	}
+	if err := records.close(); err != nil {
+		log.Error("Error closing the records cursor", zap.Error(err))
 	}
 
 	waitGroup.Wait()
-	log.Info("All records enqueued successfully!", zap.Int("batchSize", batchSize))
+	log.Info("All records enqueued successfully!", zap.Int("finalBatchSize", sizer.next()))
 }
 
-func dbRecordsToBytes(records []*database.Record) []byte {
-	var recordList []types.Record
-
-	for _, r := range records {
-		recordList = append(recordList, types.Record{
-			ID:         r.ID,
-			InsertedAt: r.InsertedAt,
-		})
-	}
-
-	data, err := json.Marshal(recordList)
+// publishBatch publishes a tenant's records on
+// records.<tenant>.<priority>.<first record ID>, split or claim checked by
+// the publisher should they not fit a message, and reports every message to
+// the sizer.
+func publishBatch(ctx context.Context, publisher *recordbatch.Publisher, sizer *batchSizer, signed *feedback, tenant, priority string, records []types.Record) {
+	parts, err := publisher.Publish(ctx, fmt.Sprintf("records.%s.%s", tenant, priority), records)
 	if err != nil {
-		log.Error("Error marshaling records", zap.Error(err))
-		return []byte{}
+		log.Fatal("Error publishing records", zap.String("tenant", tenant), zap.Int("firstRecordID", records[0].ID), zap.Error(err))
 	}
+	for _, part := range parts {
+		signed.sent(part.Records[0].ID, time.Now())
+		sizer.published(len(part.Records), part.Bytes)
+		log.Debug("Published message", zap.String("subject", part.Subject), zap.Int("recordCount", len(part.Records)), zap.Int("bytes", part.Bytes), zap.Bool("claimCheck", part.Claim))
+	}
+}
 
-	return data
+// tenantBatches splits a batch of records by tenant, keeping their order.
+func tenantBatches(records []types.Record) map[string][]types.Record {
+	batches := make(map[string][]types.Record)
+	for _, r := range records {
+		batches[r.TenantID] = append(batches[r.TenantID], r)
+	}
+	return batches
 }
@@ -3,152 +3,172 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"os"
 	"testing"
-	"time"
 
 	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/record"
 	"github.com/jurshsmith/vaultstream/types"
-	"github.com/nats-io/nats.go/jetstream"
 )
 
-// --- Tests for dbRecordsToBytes ---
+// --- Tests for tenantBatches ---
 
-func TestDBRecordsToBytes(t *testing.T) {
-	// Use a fixed time to avoid issues with sub-second differences.
-	now := time.Now().UTC().Truncate(time.Second)
-	records := []*database.Record{
-		{ID: 1, InsertedAt: now},
-		{ID: 2, InsertedAt: now.Add(time.Second)},
+func TestTenantBatches(t *testing.T) {
+	records := []types.Record{
+		{ID: 1, TenantID: "acme"},
+		{ID: 2, TenantID: "globex"},
+		{ID: 3, TenantID: "acme"},
 	}
-	data := dbRecordsToBytes(records)
+	batches := tenantBatches(records)
 
-	var recordsOut []types.Record
-	err := json.Unmarshal(data, &recordsOut)
-	if err != nil {
-		t.Fatalf("Failed to unmarshal JSON: %v", err)
-	}
-
-	if len(recordsOut) != 2 {
-		t.Fatalf("Expected 2 records, got %d", len(recordsOut))
+	want := map[string][]int{"acme": {1, 3}, "globex": {2}}
+	if len(batches) != len(want) {
+		t.Fatalf("tenantBatches() = %d tenants, want %d", len(batches), len(want))
 	}
-	if recordsOut[0].ID != 1 || !recordsOut[0].InsertedAt.Equal(now) {
-		t.Errorf("Unexpected first record: %+v", recordsOut[0])
-	}
-	if recordsOut[1].ID != 2 || !recordsOut[1].InsertedAt.Equal(now.Add(time.Second)) {
-		t.Errorf("Unexpected second record: %+v", recordsOut[1])
+	for tenant, ids := range want {
+		if len(batches[tenant]) != len(ids) {
+			t.Errorf("tenantBatches()[%s] = %d records, want %d", tenant, len(batches[tenant]), len(ids))
+			continue
+		}
+		for i, r := range batches[tenant] {
+			if r.ID != ids[i] || r.TenantID != tenant {
+				t.Errorf("tenantBatches()[%s][%d] = record %d of %q, want record %d", tenant, i, r.ID, r.TenantID, ids[i])
+			}
+		}
 	}
 }
 
-func TestDBRecordsToBytesEmpty(t *testing.T) {
-	data := dbRecordsToBytes([]*database.Record{})
-	var recordsOut []types.Record
-	err := json.Unmarshal(data, &recordsOut)
+// --- Tests and benchmarks for the records cursor ---
+
+// seedRecords inserts count records within tx and returns the first ID.
+func seedRecords(ctx context.Context, tb testing.TB, tx *database.Tx, count int) int {
+	tb.Helper()
+	rows, err := tx.Query(ctx, `
+		WITH inserted AS (
+			INSERT INTO records (tenant_id, inserted_at)
+			SELECT 'default', now() FROM generate_series(1, $1)
+			RETURNING id
+		)
+		SELECT min(id) FROM inserted`, count)
 	if err != nil {
-		t.Fatalf("Failed to unmarshal JSON: %v", err)
+		tb.Fatalf("Failed seeding %d records: %v", count, err)
 	}
-	if len(recordsOut) != 0 {
-		t.Errorf("Expected 0 records, got %d", len(recordsOut))
+	defer rows.Close()
+	var firstID int
+	if !rows.Next() {
+		tb.Fatalf("Failed seeding %d records: %v", count, rows.Err())
 	}
-}
-
-// --- Refactored Batch Processing Logic and Its Test ---
-//
-// To test the batch processing that occurs concurrently in main,
-// we recommend refactoring the logic in the goroutine into a separate function.
-// For example, you might extract a function similar to this:
-//
-//   func processBatch(ctx context.Context, batchID, totalBatches int, records []*database.Record, jsClient JetStreamPublisher) error { … }
-//
-// where JetStreamPublisher is an interface you define with a Publish method.
-// This lets you swap in a fake implementation during testing.
-
-//
-// Here’s an example of a refactored batch processing function and its test.
-
-// Define an interface so that we can pass a fake in tests.
-type JetStreamPublisher interface {
-	Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
-}
-
-// processBatch contains the core logic from the goroutine in main.
-func processBatch(ctx context.Context, batchID, totalBatches int, records []*database.Record, jsClient JetStreamPublisher) error {
-	// In the original code, the DB query uses this formula:
-	//   mod((id-1), totalBatches)+1 == batchID
-	// For testing purposes, we simulate filtering the records.
-	var filtered []*database.Record
-	for _, r := range records {
-		if ((r.ID-1)%totalBatches)+1 == batchID {
-			filtered = append(filtered, r)
-		}
+	if err := rows.Scan(&firstID); err != nil {
+		tb.Fatalf("Failed seeding %d records: %v", count, err)
 	}
-
-	data := dbRecordsToBytes(filtered)
-	subject := fmt.Sprintf("records.%d", batchID)
-	_, err := jsClient.Publish(ctx, subject, data)
-	return err
-}
-
-// fakeJetStreamClient is a fake implementation of JetStreamPublisher for testing.
-type fakeJetStreamClient struct {
-	// published maps subject to published data.
-	published map[string][]byte
+	return firstID
 }
 
-func (f *fakeJetStreamClient) Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
-	if f.published == nil {
-		f.published = make(map[string][]byte)
+// beginTx opens a transaction the caller rolls back, so that seeded records
+// never reach other tests or services.
+func beginTx(tb testing.TB) (*database.Tx, context.Context) {
+	tb.Helper()
+	if os.Getenv("DATABASE_URL") == "" {
+		tb.Skip("Skipping integration test: DATABASE_URL not set")
 	}
-	f.published[subject] = data
-	return &jetstream.PubAck{
-		Stream:   "test-stream",
-		Sequence: uint64(len(f.published)),
-	}, nil
-}
-
-func TestProcessBatch(t *testing.T) {
-	totalBatches := 3
-	// Create records with IDs 1 through 6.
-	var records []*database.Record
-	now := time.Now().UTC()
-	for i := 1; i <= 6; i++ {
-		records = append(records, &database.Record{
-			ID:         i,
-			InsertedAt: now,
-		})
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		tb.Skipf("Skipping integration test: %v", err)
 	}
-
-	jsClient := &fakeJetStreamClient{}
-
-	// Test for batchID = 2.
-	err := processBatch(context.Background(), 2, totalBatches, records, jsClient)
+	tb.Cleanup(func() { dbClient.Close() })
+	ctx := context.Background()
+	tx, err := dbClient.Tx(ctx)
 	if err != nil {
-		t.Fatalf("processBatch returned error: %v", err)
+		tb.Fatalf("Failed starting a transaction: %v", err)
 	}
+	tb.Cleanup(func() { tx.Rollback() })
+	return tx, ctx
+}
 
-	// For batchID = 2, the filtering condition is:
-	//   ((ID - 1) mod 3) + 1 == 2
-	// This should select records with IDs 2 and 5.
-	subject := "records.2"
-	publishedData, ok := jsClient.published[subject]
-	if !ok {
-		t.Fatalf("No message published for subject %s", subject)
+func TestFetchRecords(t *testing.T) {
+	tx, ctx := beginTx(t)
+	firstID := seedRecords(ctx, t, tx, 10)
+	if err := declareRecords(ctx, tx, firstID-1); err != nil {
+		t.Fatalf("declareRecords(%d) returned an unexpected error: %v", firstID-1, err)
 	}
 
-	var out []types.Record
-	err = json.Unmarshal(publishedData, &out)
-	if err != nil {
-		t.Fatalf("Failed to unmarshal published data: %v", err)
+	var got []types.Record
+	for {
+		batch, err := fetchRecords(ctx, tx, 4)
+		if err != nil {
+			t.Fatalf("fetchRecords(4) returned an unexpected error: %v", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if len(batch) > 4 {
+			t.Fatalf("fetchRecords(4) = %d records, want at most 4", len(batch))
+		}
+		got = append(got, batch...)
 	}
-	if len(out) != 2 {
-		t.Errorf("Expected 2 records in published message, got %d", len(out))
+
+	if len(got) != 10 {
+		t.Fatalf("cursor returned %d records, want 10", len(got))
 	}
-	// Verify the IDs of the published records.
-	expectedIDs := []int{2, 5}
-	for i, rec := range out {
-		if rec.ID != expectedIDs[i] {
-			t.Errorf("Expected record ID %d, got %d", expectedIDs[i], rec.ID)
+	for i, r := range got {
+		if r.ID != firstID+i || r.TenantID != "default" || r.InsertedAt.IsZero() || r.Data != nil {
+			t.Errorf("record %d of the cursor = %+v, want record %d of the default tenant", i, r, firstID+i)
 		}
 	}
 }
+
+// BenchmarkRecordsBatch times reading the last batch of a growing records
+// table. The cursor stays flat; the OFFSET and modulo queries of the paging
+// before it, which skip every record before the batch and pick every
+// batches-th record, grow with the table.
+func BenchmarkRecordsBatch(b *testing.B) {
+	const batchSize = 500
+	for _, total := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprintf("cursor/records=%d", total), func(b *testing.B) {
+			tx, ctx := beginTx(b)
+			afterID := seedRecords(ctx, b, tx, total) + total - batchSize - 1
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				if err := declareRecords(ctx, tx, afterID); err != nil {
+					b.Fatalf("declareRecords() returned an unexpected error: %v", err)
+				}
+				batch, err := fetchRecords(ctx, tx, batchSize)
+				if err != nil || len(batch) != batchSize {
+					b.Fatalf("fetchRecords() = %d records, %v; want %d", len(batch), err, batchSize)
+				}
+				if _, err := tx.Exec(ctx, "CLOSE "+recordsCursor); err != nil {
+					b.Fatalf("closing the cursor returned an unexpected error: %v", err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("offset/records=%d", total), func(b *testing.B) {
+			tx, ctx := beginTx(b)
+			seedRecords(ctx, b, tx, total)
+			client := tx.Client()
+			b.ResetTimer()
+			for b.Loop() {
+				page, err := client.Record.Query().Order(record.ByID()).Offset(total - batchSize).Limit(batchSize).All(ctx)
+				if err != nil || len(page) != batchSize {
+					b.Fatalf("offset query = %d records, %v; want %d", len(page), err, batchSize)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("modulo/records=%d", total), func(b *testing.B) {
+			tx, ctx := beginTx(b)
+			firstID := seedRecords(ctx, b, tx, total)
+			batches := total / batchSize
+			b.ResetTimer()
+			for b.Loop() {
+				rows, err := tx.Query(ctx, "SELECT id FROM records WHERE id >= $1 AND mod((id - $1), $2) + 1 = $2", firstID, batches)
+				if err != nil {
+					b.Fatalf("modulo query returned an unexpected error: %v", err)
+				}
+				for rows.Next() {
+				}
+				rows.Close()
+			}
+		})
+	}
+}
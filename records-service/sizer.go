@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"expvar"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/metrics"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+const (
+	// sizeDecrease is the factor a batch size is cut by when a batch misses
+	// a target; otherwise it grows by sizeIncrease of itself, at least 1.
+	sizeDecrease = 0.75
+	sizeIncrease = 0.1
+
+	// byteHeadroom keeps encoded batches this share below the message limit,
+	// as record sizes vary around their average.
+	byteHeadroom = 0.9
+	// recordBytesWeight is the weight of the latest batch in the moving
+	// average of encoded bytes per record.
+	recordBytesWeight = 0.2
+
+	// feedbackExpiry is how long a published batch waits for its signatures
+	// event. A batch signed before, e.g. by a previous run, gets none.
+	feedbackExpiry = 10 * time.Minute
+)
+
+var (
+	batchSizes       = metrics.NewHistogram(1, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000)
+	currentBatchSize = new(expvar.Int)
+)
+
+func init() {
+	expvar.Publish("records_batch_size", batchSizes)
+	expvar.Publish("records_batch_size_current", currentBatchSize)
+}
+
+// batchSizer adapts the records per batch, additively increasing the size
+// while batches are signed within targetLatency and committed within
+// targetInsert, and multiplicatively decreasing it when one is not. The size
+// stays within [minSize, maxSize], and is capped further so that an encoded
+// batch fits the largest message JetStream accepts.
+type batchSizer struct {
+	mu            sync.Mutex
+	size          int
+	minSize       int
+	maxSize       int
+	targetLatency time.Duration
+	targetInsert  time.Duration
+	maxBytes      int
+	recordBytes   float64 // moving average of encoded bytes per record
+}
+
+func newBatchSizer(initial, minSize, maxSize int, targetLatency, targetInsert time.Duration, maxBytes int) *batchSizer {
+	s := &batchSizer{
+		size:          min(max(initial, minSize), maxSize),
+		minSize:       minSize,
+		maxSize:       maxSize,
+		targetLatency: targetLatency,
+		targetInsert:  targetInsert,
+		maxBytes:      maxBytes,
+	}
+	currentBatchSize.Set(int64(s.size))
+	return s
+}
+
+// next returns the size of the next batch.
+func (s *batchSizer) next() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.recordBytes > 0 {
+		fitting := int(float64(s.maxBytes) * byteHeadroom / s.recordBytes)
+		return max(1, min(s.size, fitting))
+	}
+	return s.size
+}
+
+// published records the size of a batch as published.
+func (s *batchSizer) published(records, bytes int) {
+	batchSizes.Observe(float64(records))
+	if records == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	perRecord := float64(bytes) / float64(records)
+	if s.recordBytes == 0 {
+		s.recordBytes = perRecord
+	} else {
+		s.recordBytes += recordBytesWeight * (perRecord - s.recordBytes)
+	}
+}
+
+// signed adjusts the size from how long a batch took from publish to its
+// signatures event, and how long signing-service took to commit it.
+func (s *batchSizer) signed(latency, insert time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if latency > s.targetLatency || insert > s.targetInsert {
+		s.size = int(math.Floor(float64(s.size) * sizeDecrease))
+	} else {
+		s.size += max(1, int(float64(s.size)*sizeIncrease))
+	}
+	s.size = min(max(s.size, s.minSize), s.maxSize)
+	currentBatchSize.Set(int64(s.size))
+}
+
+// feedback matches signatures events to the batches records-service
+// published, by their first record, and reports their timings to the sizer.
+type feedback struct {
+	sizer     *batchSizer
+	mu        sync.Mutex
+	published map[int]time.Time // by first record ID
+	order     []int             // first record IDs in the order published
+}
+
+func newFeedback(sizer *batchSizer) *feedback {
+	return &feedback{sizer: sizer, published: make(map[int]time.Time)}
+}
+
+// sent records a batch published at, and forgets those published more than
+// feedbackExpiry before it.
+func (f *feedback) sent(firstRecordID int, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.order) > 0 {
+		published, ok := f.published[f.order[0]]
+		if ok && at.Sub(published) < feedbackExpiry {
+			break
+		}
+		delete(f.published, f.order[0])
+		f.order = f.order[1:]
+	}
+	f.published[firstRecordID] = at
+	f.order = append(f.order, firstRecordID)
+}
+
+// handle receives a signatures event. Events of batches published by others,
+// or without timings, are ignored.
+func (f *feedback) handle(msg *nats.Msg) {
+	var event types.SignaturesEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		log.Warn("Error unmarshaling signatures event", zap.String("subject", msg.Subject), zap.Error(err))
+		return
+	}
+	if event.BatchFirstRecordID == 0 || event.Timings == nil {
+		return
+	}
+	f.mu.Lock()
+	at, ok := f.published[event.BatchFirstRecordID]
+	delete(f.published, event.BatchFirstRecordID)
+	f.mu.Unlock()
+	if ok {
+		f.sizer.signed(time.Since(at), event.Timings.Insert)
+	}
+}
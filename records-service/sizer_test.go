@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop()
+}
+
+func TestBatchSizerSigned(t *testing.T) {
+	tests := []struct {
+		name            string
+		initial         int
+		latency, insert time.Duration
+		want            int
+	}{
+		{name: "within targets", initial: 50, latency: time.Second, insert: 100 * time.Millisecond, want: 55},
+		{name: "small size grows by one", initial: 5, latency: time.Second, insert: 100 * time.Millisecond, want: 6},
+		{name: "slow signing", initial: 100, latency: 3 * time.Second, insert: 100 * time.Millisecond, want: 75},
+		{name: "slow insert", initial: 100, latency: time.Second, insert: time.Second, want: 75},
+		{name: "at most max", initial: 200, latency: time.Second, want: 200},
+		{name: "at least min", initial: 10, latency: time.Minute, want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newBatchSizer(tt.initial, 10, 200, 2*time.Second, 500*time.Millisecond, 1<<20)
+			s.minSize = min(s.minSize, tt.initial)
+			s.size = tt.initial
+			s.signed(tt.latency, tt.insert)
+			if got := s.next(); got != tt.want {
+				t.Errorf("size after signed(%s, %s) = %d, want %d", tt.latency, tt.insert, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBatchSizerFitsMessages verifies batches are kept below the message
+// limit by the average encoded record size.
+func TestBatchSizerFitsMessages(t *testing.T) {
+	s := newBatchSizer(500, 1, 1000, time.Second, time.Second, 10_000)
+	if got := s.next(); got != 500 {
+		t.Fatalf("next() before any batch = %d, want 500", got)
+	}
+	s.published(10, 1000) // 100 bytes per record
+	if got := s.next(); got != 90 {
+		t.Errorf("next() with 100 byte records = %d, want 90", got)
+	}
+	s.published(10, 6000) // the average moves to 200
+	if got := s.next(); got != 45 {
+		t.Errorf("next() with larger records = %d, want 50", got)
+	}
+}
+
+// TestFeedback verifies signatures events of published batches adjust the
+// size, and others are ignored.
+func TestFeedback(t *testing.T) {
+	s := newBatchSizer(50, 1, 1000, time.Second, time.Second, 1<<20)
+	f := newFeedback(s)
+	event := func(firstRecordID int, timings *types.BatchTimings) *nats.Msg {
+		// The first record was signed before; only the second is in the event.
+		data, _ := json.Marshal(types.SignaturesEvent{
+			Signatures:         []types.Signature{{RecordID: firstRecordID + 1}},
+			Timings:            timings,
+			BatchFirstRecordID: firstRecordID,
+		})
+		return &nats.Msg{Subject: "signatures.default.1", Data: data}
+	}
+
+	f.sent(1, time.Now())
+	f.sent(2, time.Now().Add(-time.Minute))
+	f.handle(event(3, &types.BatchTimings{})) // not published here
+	f.handle(event(1, nil))                   // on-demand, no timings
+	f.handle(&nats.Msg{Data: []byte("{")})    // malformed
+	if got := s.next(); got != 50 {
+		t.Fatalf("size after unrelated events = %d, want 50", got)
+	}
+	f.handle(event(2, &types.BatchTimings{Insert: time.Millisecond}))
+	if got := s.next(); got != 37 {
+		t.Errorf("size after a late batch = %d, want 37", got)
+	}
+	f.handle(event(2, &types.BatchTimings{})) // already handled
+	if got := s.next(); got != 37 {
+		t.Errorf("size after a repeated event = %d, want 37", got)
+	}
+}
+
+// TestFeedbackExpiry verifies batches that never get a signatures event are
+// forgotten once feedbackExpiry has passed.
+func TestFeedbackExpiry(t *testing.T) {
+	f := newFeedback(newBatchSizer(50, 1, 1000, time.Second, time.Second, 1<<20))
+	start := time.Now()
+	f.sent(1, start)
+	f.sent(3, start.Add(time.Minute))
+	f.sent(5, start.Add(feedbackExpiry))
+	if _, ok := f.published[1]; ok || len(f.published) != 2 || len(f.order) != 2 {
+		t.Errorf("published = %v in order %v, want batch 1 expired", f.published, f.order)
+	}
+	f.sent(7, start.Add(time.Minute+feedbackExpiry))
+	if len(f.published) != 2 || len(f.order) != 2 {
+		t.Errorf("published = %v in order %v, want batches 5 and 7", f.published, f.order)
+	}
+}
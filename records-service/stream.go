@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+const (
+	recordsCursor = "records_export"
+	// cursorRows is how many records a cursor reads before the stream commits
+	// its transaction and declares the next one after the last record, so
+	// that no snapshot stays open long enough to hold back vacuum.
+	cursorRows = 1_000_000
+)
+
+// recordStream reads the records table in ID order through a server-side
+// cursor. Postgres sends the rows as they are fetched, a batch at a time,
+// and they are scanned straight into the types.Record batches that get
+// published, so memory stays bounded by the batches in flight however many
+// records there are.
+type recordStream struct {
+	client  *database.Client
+	tx      *database.Tx
+	lastID  int
+	fetched int
+}
+
+// newRecordStream returns a stream of the records with IDs above afterID.
+func newRecordStream(client *database.Client, afterID int) *recordStream {
+	return &recordStream{client: client, lastID: afterID}
+}
+
+// next returns up to limit records following those it returned before, none
+// once every record has been read.
+func (s *recordStream) next(ctx context.Context, limit int) ([]types.Record, error) {
+	if s.tx != nil && s.fetched >= cursorRows {
+		if err := s.close(); err != nil {
+			return nil, err
+		}
+	}
+	if s.tx == nil {
+		tx, err := s.client.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, err
+		}
+		if err := declareRecords(ctx, tx, s.lastID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		s.tx, s.fetched = tx, 0
+	}
+
+	records, err := fetchRecords(ctx, s.tx, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.fetched += len(records)
+	if len(records) > 0 {
+		s.lastID = records[len(records)-1].ID
+	}
+	return records, nil
+}
+
+// close ends the transaction of the current cursor, if any.
+func (s *recordStream) close() error {
+	if s.tx == nil {
+		return nil
+	}
+	tx := s.tx
+	s.tx = nil
+	return tx.Commit()
+}
+
+// declareRecords declares the records cursor within tx over the records with
+// IDs above afterID. DECLARE and FETCH take no bind parameters, hence the
+// formatted integers.
+func declareRecords(ctx context.Context, tx *database.Tx, afterID int) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(
+		"DECLARE %s NO SCROLL CURSOR FOR SELECT id, tenant_id, inserted_at, data FROM records WHERE id > %d ORDER BY id",
+		recordsCursor, afterID))
+	return err
+}
+
+// fetchRecords fetches the next limit records from the records cursor of tx.
+func fetchRecords(ctx context.Context, tx *database.Tx, limit int) ([]types.Record, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", limit, recordsCursor))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]types.Record, 0, limit)
+	for rows.Next() {
+		var r types.Record
+		if err := rows.Scan(&r.ID, &r.TenantID, &r.InsertedAt, &r.Data); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
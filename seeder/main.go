@@ -7,7 +7,8 @@ import (
 	"github.com/jurshsmith/vaultstream/config"
 	db "github.com/jurshsmith/vaultstream/database"
 	vaultStreamLogger "github.com/jurshsmith/vaultstream/logger"
-	_ "github.com/lib/pq"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -24,7 +25,17 @@ func main() {
 	totalRecords := config.TotalRecords()
 	logger.Info("Total records to seed", zap.Int("totalRecords", totalRecords))
 
-	client := db.Connect()
+	tenants := config.Tenants()
+	for _, tenant := range tenants {
+		if err := types.ValidateTenant(tenant); err != nil {
+			logger.Fatal("Invalid TENANTS", zap.Error(err))
+		}
+	}
+
+	client, err := db.Connect(0)
+	if err != nil {
+		logger.Fatal("Failed connecting to the database", zap.Error(err))
+	}
 	defer client.Close()
 
 	ctx := context.Background()
@@ -37,7 +48,7 @@ func main() {
 		logger.Debug("Failed to clear records table", zap.Error(err))
 	}
 
-	if err := seedRecords(ctx, client, totalRecords); err != nil {
+	if err := seedRecords(ctx, client, tenants, totalRecords); err != nil {
 		logger.Fatal("Failed seeding records", zap.Error(err))
 	}
 
@@ -53,15 +64,16 @@ func clearTable(ctx context.Context, client *db.Client, table string) error {
 	return err
 }
 
-func seedRecords(ctx context.Context, client *db.Client, totalRecords int) error {
-	logger.Info("Seeding records", zap.Int("totalRecords", totalRecords))
+// seedRecords inserts totalRecords records, assigning tenants round-robin.
+func seedRecords(ctx context.Context, client *db.Client, tenants []string, totalRecords int) error {
+	logger.Info("Seeding records", zap.Int("totalRecords", totalRecords), zap.Strings("tenants", tenants))
 	query := fmt.Sprintf(`
-		INSERT INTO records (inserted_at)
-		SELECT now()
-		FROM generate_series(1, %d);
+		INSERT INTO records (tenant_id, inserted_at)
+		SELECT ($1::text[])[1 + (i - 1) %% cardinality($1::text[])], now()
+		FROM generate_series(1, %d) AS i;
 	`, totalRecords)
 
-	_, err := client.Exec(ctx, query)
+	_, err := client.Exec(ctx, query, pq.Array(tenants))
 	if err != nil {
 		logger.Error("Error seeding records", zap.Int("totalRecords", totalRecords), zap.Error(err))
 	}
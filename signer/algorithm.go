@@ -0,0 +1,129 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+)
+
+// Algorithm names a signature scheme. The names follow JOSE (RFC 7518/8037)
+// so they can be reused verbatim when exporting signatures.
+type Algorithm string
+
+const (
+	ES256 Algorithm = "ES256" // ECDSA P-256 with SHA-256
+	ES384 Algorithm = "ES384" // ECDSA P-384 with SHA-384
+	EdDSA Algorithm = "EdDSA" // Ed25519
+	PS256 Algorithm = "PS256" // RSASSA-PSS with SHA-256
+
+	// DefaultAlgorithm is assumed for keys and signatures that predate the algorithm column.
+	DefaultAlgorithm = ES256
+
+	rsaKeyBits = 3072
+)
+
+// ParseAlgorithm validates an algorithm name; the empty string maps to DefaultAlgorithm.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch alg := Algorithm(name); alg {
+	case "":
+		return DefaultAlgorithm, nil
+	case ES256, ES384, EdDSA, PS256:
+		return alg, nil
+	default:
+		return "", fmt.Errorf("unsupported signature algorithm %q", name)
+	}
+}
+
+// SignerOpts returns the crypto.SignerOpts a crypto.Signer expects for this algorithm.
+func (a Algorithm) SignerOpts() crypto.SignerOpts {
+	switch a {
+	case ES384:
+		return crypto.SHA384
+	case EdDSA:
+		return crypto.Hash(0)
+	case PS256:
+		return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	default:
+		return crypto.SHA256
+	}
+}
+
+// Digest returns what Signer.Sign expects for a message under this algorithm:
+// its hash, or the message itself for Ed25519, which hashes internally.
+func (a Algorithm) Digest(message []byte) []byte {
+	switch a {
+	case EdDSA:
+		return message
+	case ES384:
+		hash := sha512.Sum384(message)
+		return hash[:]
+	default:
+		hash := sha256.Sum256(message)
+		return hash[:]
+	}
+}
+
+// Verify checks sig over digest (as produced by Digest) with the public key.
+func Verify(alg Algorithm, public crypto.PublicKey, digest, sig []byte) error {
+	if err := checkKeyType(alg, public); err != nil {
+		return err
+	}
+	switch alg {
+	case ES256, ES384:
+		if !ecdsa.VerifyASN1(public.(*ecdsa.PublicKey), digest, sig) {
+			return errors.New("invalid ECDSA signature")
+		}
+		return nil
+	case EdDSA:
+		if !ed25519.Verify(public.(ed25519.PublicKey), digest, sig) {
+			return errors.New("invalid Ed25519 signature")
+		}
+		return nil
+	case PS256:
+		return rsa.VerifyPSS(public.(*rsa.PublicKey), crypto.SHA256, digest, sig, alg.SignerOpts().(*rsa.PSSOptions))
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+}
+
+// generatePrivateKey creates an in-process private key for the software backends.
+func generatePrivateKey(alg Algorithm) (crypto.Signer, error) {
+	switch alg {
+	case ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case EdDSA:
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		return privateKey, err
+	case PS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		return nil, fmt.Errorf("unsupported signature algorithm %q", alg)
+	}
+}
+
+// checkKeyType rejects keys that cannot produce signatures for alg, so a
+// mislabelled key fails loudly instead of producing unverifiable signatures.
+func checkKeyType(alg Algorithm, public crypto.PublicKey) error {
+	ok := false
+	switch key := public.(type) {
+	case *ecdsa.PublicKey:
+		ok = (alg == ES256 && key.Curve == elliptic.P256()) || (alg == ES384 && key.Curve == elliptic.P384())
+	case ed25519.PublicKey:
+		ok = alg == EdDSA
+	case *rsa.PublicKey:
+		ok = alg == PS256
+	}
+	if !ok {
+		return fmt.Errorf("%T cannot be used with algorithm %s", public, alg)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+const (
+	caCommonName = "VaultStream Root CA"
+	caAlgorithm  = ES384
+	caValidity   = 10 * 365 * 24 * time.Hour
+	caAAD        = "vaultstream-ca"
+)
+
+// CA is the internal certificate authority that certifies signing keys, so a
+// signature can be attributed to VaultStream rather than to an anonymous key.
+// The root private key lives in a single file, sealed like keystore entries
+// with AES-256-GCM under a scrypt-derived key.
+type CA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+type caFile struct {
+	Salt        []byte `json:"salt"`
+	N           int    `json:"n"`
+	R           int    `json:"r"`
+	P           int    `json:"p"`
+	Nonce       []byte `json:"nonce"`
+	Ciphertext  []byte `json:"ciphertext"`
+	Certificate []byte `json:"certificate"`
+}
+
+// OpenCA loads the root CA from path, creating a new root on first use. Only
+// keys-service opens the CA; other services load its certificate with
+// LoadCACertificate.
+func OpenCA(path, passphrase string) (*CA, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		ca, cerr := createCA(path, passphrase)
+		if !errors.Is(cerr, os.ErrExist) {
+			return ca, cerr
+		}
+		// Another process created the root first; use that one.
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading CA file: %w", err)
+	}
+
+	var file caFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed decoding CA file: %w", err)
+	}
+	aead, err := deriveAEAD(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	derBytes, err := aead.Open(nil, file.Nonce, file.Ciphertext, []byte(caAAD))
+	if err != nil {
+		return nil, errors.New("wrong CA passphrase")
+	}
+	defer clear(derBytes)
+
+	key, err := parsePrivateKey(derBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing CA key: %w", err)
+	}
+	cert, err := x509.ParseCertificate(file.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing CA certificate: %w", err)
+	}
+	if err := checkKeyType(caAlgorithm, cert.PublicKey); err != nil {
+		return nil, fmt.Errorf("CA certificate: %w", err)
+	}
+	return &CA{cert: cert, key: key}, nil
+}
+
+func createCA(path, passphrase string) (*CA, error) {
+	key, err := generatePrivateKey(caAlgorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating CA key: %w", err)
+	}
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"VaultStream"}, CommonName: caCommonName},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true, // signing keys are issued directly by the root.
+		SignatureAlgorithm:    X509SignatureAlgorithm(caAlgorithm),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed self-signing CA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, err
+	}
+
+	derBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling CA key: %w", err)
+	}
+	defer clear(derBytes)
+
+	file := caFile{Salt: make([]byte, 16), N: scryptN, R: scryptR, P: scryptP, Certificate: certDER}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := deriveAEAD(passphrase, file.Salt, file.N, file.R, file.P)
+	if err != nil {
+		return nil, err
+	}
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return nil, err
+	}
+	file.Ciphertext = aead.Seal(nil, file.Nonce, derBytes, []byte(caAAD))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed creating CA dir: %w", err)
+	}
+	if err := createJSON(path, file); err != nil {
+		return nil, fmt.Errorf("failed writing CA file: %w", err)
+	}
+	return &CA{cert: cert, key: key}, nil
+}
+
+// Certificate returns the root certificate verifiers should trust.
+func (ca *CA) Certificate() *x509.Certificate {
+	return ca.cert
+}
+
+// WriteCertificate writes the root certificate to path as PEM, for the
+// services that verify certificates without access to the CA key. It
+// replaces the file atomically, so a reader never sees part of it.
+func (ca *CA) WriteCertificate(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed creating CA certificate dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed writing CA certificate: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := pem.Encode(tmp, &pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed writing CA certificate: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadCACertificate loads the PEM root certificate CA.WriteCertificate wrote
+// to path. It needs neither the CA key nor its passphrase.
+func LoadCACertificate(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading CA certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s holds no PEM certificate", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing CA certificate: %w", err)
+	}
+	if !cert.IsCA {
+		return nil, fmt.Errorf("certificate in %s is not a CA", path)
+	}
+	if err := checkKeyType(caAlgorithm, cert.PublicKey); err != nil {
+		return nil, fmt.Errorf("CA certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// Chain returns the certificates between an issued key certificate and the
+// root, inclusive, in the base64 DER form of types.Key.CertificateChain.
+func (ca *CA) Chain() []string {
+	return []string{base64.StdEncoding.EncodeToString(ca.cert.Raw)}
+}
+
+// Issue certifies key's public key for digital signatures and returns the DER
+// certificate. The key's tenant is certified as the subject organizational
+// unit. The certificate never outlives the root.
+func (ca *CA) Issue(key *types.Key, validity time.Duration) ([]byte, error) {
+	alg, err := ParseAlgorithm(key.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	public, err := DecodePublicKey(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding public key %d: %w", key.ID, err)
+	}
+	if err := checkKeyType(alg, public); err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	notAfter := now.Add(validity)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	subject := pkix.Name{
+		Organization: []string{"VaultStream"},
+		CommonName:   fmt.Sprintf("VaultStream signing key %d", key.ID),
+		SerialNumber: fmt.Sprint(key.ID),
+	}
+	if key.TenantID != "" {
+		subject.OrganizationalUnit = []string{key.TenantID}
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               subject,
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		SignatureAlgorithm:    X509SignatureAlgorithm(caAlgorithm),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, public, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed issuing certificate for key %d: %w", key.ID, err)
+	}
+	return der, nil
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
@@ -0,0 +1,21 @@
+module github.com/jurshsmith/vaultstream/signer
+
+go 1.24.1
+
+require (
+	github.com/ThalesIgnite/crypto11 v1.2.5
+	github.com/jurshsmith/vaultstream/config v0.0.0
+	github.com/jurshsmith/vaultstream/types v0.0.0
+	golang.org/x/crypto v0.33.0
+)
+
+require (
+	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/miekg/pkcs11 v1.0.3-0.20190429190417-a667d056470f // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/thales-e-security/pool v0.0.2 // indirect
+)
+
+replace github.com/jurshsmith/vaultstream/config => ../config
+
+replace github.com/jurshsmith/vaultstream/types => ../types
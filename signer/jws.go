@@ -0,0 +1,30 @@
+package signer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// protectedHeader is the JWS protected header bound into every record signature.
+type protectedHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// ProtectedHeader returns the base64url-encoded JWS protected header for a
+// signature made by keyID with alg.
+func ProtectedHeader(alg Algorithm, keyID int) string {
+	header, _ := json.Marshal(protectedHeader{Alg: string(alg), Kid: strconv.Itoa(keyID)}) // always marshals.
+	return base64.RawURLEncoding.EncodeToString(header)
+}
+
+// SigningInput returns the bytes a record signature covers: the JWS signing
+// input (RFC 7515, section 5.1) of the record payload under ProtectedHeader.
+// Signing this rather than the bare payload lets any signature be exported as
+// a standard JWS without re-signing.
+func SigningInput(alg Algorithm, keyID int, record types.Record) []byte {
+	return []byte(ProtectedHeader(alg, keyID) + "." + base64.RawURLEncoding.EncodeToString(Payload(record)))
+}
@@ -0,0 +1,273 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keystoreMetaFile = "keystore.json"
+	keystoreCheck    = "vaultstream-keystore"
+
+	// scrypt parameters recommended for interactive logins (2017).
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Keystore is an on-disk directory of private keys, each sealed with
+// AES-256-GCM under a key derived from a passphrase via scrypt. Private keys
+// are only decrypted for the duration of a single Sign call.
+type Keystore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+type keystoreMeta struct {
+	Salt  []byte `json:"salt"`
+	N     int    `json:"n"`
+	R     int    `json:"r"`
+	P     int    `json:"p"`
+	Nonce []byte `json:"nonce"`
+	Check []byte `json:"check"`
+}
+
+type keystoreEntry struct {
+	ID         int    `json:"id"`
+	Algorithm  string `json:"algorithm"`
+	PublicKey  string `json:"public_key"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// OpenKeystore opens the keystore in dir, creating it on first use.
+func OpenKeystore(dir, passphrase string) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed creating keystore dir: %w", err)
+	}
+
+	metaPath := filepath.Join(dir, keystoreMetaFile)
+	data, err := os.ReadFile(metaPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return createKeystore(dir, metaPath, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading keystore metadata: %w", err)
+	}
+
+	var meta keystoreMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed decoding keystore metadata: %w", err)
+	}
+	aead, err := deriveAEAD(passphrase, meta.Salt, meta.N, meta.R, meta.P)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := aead.Open(nil, meta.Nonce, meta.Check, nil); err != nil {
+		return nil, errors.New("wrong keystore passphrase")
+	}
+
+	return &Keystore{dir: dir, aead: aead}, nil
+}
+
+func createKeystore(dir, metaPath, passphrase string) (*Keystore, error) {
+	meta := keystoreMeta{Salt: make([]byte, 16), N: scryptN, R: scryptR, P: scryptP}
+	if _, err := rand.Read(meta.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := deriveAEAD(passphrase, meta.Salt, meta.N, meta.R, meta.P)
+	if err != nil {
+		return nil, err
+	}
+	meta.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(meta.Nonce); err != nil {
+		return nil, err
+	}
+	meta.Check = aead.Seal(nil, meta.Nonce, []byte(keystoreCheck), nil)
+
+	if err := writeJSON(metaPath, meta); err != nil {
+		return nil, fmt.Errorf("failed writing keystore metadata: %w", err)
+	}
+	return &Keystore{dir: dir, aead: aead}, nil
+}
+
+func deriveAEAD(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	kek, err := scrypt.Key([]byte(passphrase), salt, n, r, p, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed deriving keystore key: %w", err)
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ValidateAlgorithm accepts every Algorithm.
+func (k *Keystore) ValidateAlgorithm(alg Algorithm) error {
+	return nil
+}
+
+func (k *Keystore) GenerateKey(ctx context.Context, id int, alg Algorithm) (*types.Key, error) {
+	privateKey, err := generatePrivateKey(alg)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating key %d: %w", id, err)
+	}
+	derBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling key %d: %w", id, err)
+	}
+	defer clear(derBytes)
+
+	publicKey, err := EncodePublicKey(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling public key %d: %w", id, err)
+	}
+
+	entry := keystoreEntry{ID: id, Algorithm: string(alg), PublicKey: publicKey, Nonce: make([]byte, k.aead.NonceSize())}
+	if _, err := rand.Read(entry.Nonce); err != nil {
+		return nil, err
+	}
+	entry.Ciphertext = k.aead.Seal(nil, entry.Nonce, derBytes, entryAAD(id))
+
+	if err := createJSON(k.entryPath(id), entry); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("key %d already exists in the keystore", id)
+		}
+		return nil, fmt.Errorf("failed storing key %d: %w", id, err)
+	}
+
+	return &types.Key{
+		ID:         id,
+		Algorithm:  string(alg),
+		PublicKey:  publicKey,
+		IsInUse:    false,
+		LastUsedAt: time.Unix(0, 0), // initial value indicating never used.
+	}, nil
+}
+
+func (k *Keystore) Signer(ctx context.Context, key *types.Key) (Signer, error) {
+	entry, err := k.readEntry(key.ID)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := ParseAlgorithm(entry.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	public, err := DecodePublicKey(entry.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding public key %d: %w", key.ID, err)
+	}
+	if err := checkKeyType(alg, public); err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	return &KeystoreSigner{keystore: k, id: key.ID, alg: alg, public: public}, nil
+}
+
+func (k *Keystore) Close() error {
+	return nil
+}
+
+func (k *Keystore) entryPath(id int) string {
+	return filepath.Join(k.dir, strconv.Itoa(id)+".json")
+}
+
+func (k *Keystore) readEntry(id int) (*keystoreEntry, error) {
+	data, err := os.ReadFile(k.entryPath(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading key %d: %w", id, err)
+	}
+	var entry keystoreEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed decoding key %d: %w", id, err)
+	}
+	if entry.ID != id {
+		return nil, fmt.Errorf("keystore entry %d holds key %d", id, entry.ID)
+	}
+	return &entry, nil
+}
+
+// KeystoreSigner decrypts its private key from the Keystore on every Sign
+// call and wipes the decrypted bytes afterwards.
+type KeystoreSigner struct {
+	keystore *Keystore
+	id       int
+	alg      Algorithm
+	public   crypto.PublicKey
+}
+
+func (s *KeystoreSigner) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	entry, err := s.keystore.readEntry(s.id)
+	if err != nil {
+		return nil, err
+	}
+	derBytes, err := s.keystore.aead.Open(nil, entry.Nonce, entry.Ciphertext, entryAAD(s.id))
+	if err != nil {
+		return nil, fmt.Errorf("failed decrypting key %d: %w", s.id, err)
+	}
+	defer clear(derBytes)
+
+	privateKey, err := parsePrivateKey(derBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing key %d: %w", s.id, err)
+	}
+	return privateKey.Sign(rand.Reader, digest, s.alg.SignerOpts())
+}
+
+func (s *KeystoreSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *KeystoreSigner) KeyID() int {
+	return s.id
+}
+
+func (s *KeystoreSigner) Algorithm() Algorithm {
+	return s.alg
+}
+
+// entryAAD binds a ciphertext to its key ID so entries cannot be swapped on disk.
+func entryAAD(id int) []byte {
+	return []byte("vaultstream-key-" + strconv.Itoa(id))
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// createJSON is writeJSON for a file that must not exist yet, so that a key
+// entry is never replaced.
+func createJSON(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
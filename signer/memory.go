@@ -0,0 +1,100 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// MemoryBackend keeps private keys inside types.Key.Value, i.e. in process
+// memory and on the wire. It is the original VaultStream behaviour and is
+// meant for local development.
+type MemoryBackend struct{}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{}
+}
+
+// ValidateAlgorithm accepts every Algorithm.
+func (b *MemoryBackend) ValidateAlgorithm(alg Algorithm) error {
+	return nil
+}
+
+func (b *MemoryBackend) GenerateKey(ctx context.Context, id int, alg Algorithm) (*types.Key, error) {
+	privateKey, err := generatePrivateKey(alg)
+	if err != nil {
+		return nil, fmt.Errorf("failed generating key %d: %w", id, err)
+	}
+	derBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling key %d: %w", id, err)
+	}
+	publicKey, err := EncodePublicKey(privateKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling public key %d: %w", id, err)
+	}
+	return &types.Key{
+		ID:         id,
+		Algorithm:  string(alg),
+		Value:      base64.StdEncoding.EncodeToString(derBytes),
+		PublicKey:  publicKey,
+		IsInUse:    false,
+		LastUsedAt: time.Unix(0, 0), // initial value indicating never used.
+	}, nil
+}
+
+func (b *MemoryBackend) Signer(ctx context.Context, key *types.Key) (Signer, error) {
+	return NewMemorySigner(key)
+}
+
+func (b *MemoryBackend) Close() error {
+	return nil
+}
+
+// MemorySigner signs with a private key decoded from types.Key.Value.
+type MemorySigner struct {
+	id         int
+	alg        Algorithm
+	privateKey crypto.Signer
+}
+
+func NewMemorySigner(key *types.Key) (*MemorySigner, error) {
+	alg, err := ParseAlgorithm(key.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	derBytes, err := base64.StdEncoding.DecodeString(key.Value)
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding key %d: %w", key.ID, err)
+	}
+	privateKey, err := parsePrivateKey(derBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing key %d: %w", key.ID, err)
+	}
+	if err := checkKeyType(alg, privateKey.Public()); err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	return &MemorySigner{id: key.ID, alg: alg, privateKey: privateKey}, nil
+}
+
+func (s *MemorySigner) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	return s.privateKey.Sign(rand.Reader, digest, s.alg.SignerOpts())
+}
+
+func (s *MemorySigner) Public() crypto.PublicKey {
+	return s.privateKey.Public()
+}
+
+func (s *MemorySigner) KeyID() int {
+	return s.id
+}
+
+func (s *MemorySigner) Algorithm() Algorithm {
+	return s.alg
+}
@@ -0,0 +1,156 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// The Merkle tree follows RFC 9162 (Certificate Transparency v2): leaves and
+// interior nodes are hashed with distinct prefixes so an interior node can
+// never be passed off as a leaf.
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+// MerkleLeaf returns the leaf hash of a record, computed over its Payload.
+func MerkleLeaf(record types.Record) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleLeafPrefix})
+	h.Write(Payload(record))
+	return h.Sum(nil)
+}
+
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{merkleNodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// MerkleTree is a Merkle tree over a non-empty list of leaf hashes.
+type MerkleTree struct {
+	leaves [][]byte
+}
+
+func NewMerkleTree(leaves [][]byte) *MerkleTree {
+	return &MerkleTree{leaves: leaves}
+}
+
+func (t *MerkleTree) Size() int {
+	return len(t.leaves)
+}
+
+// Root returns the tree head, MTH(D[n]) in RFC 9162 section 2.1.1.
+func (t *MerkleTree) Root() []byte {
+	return merkleRoot(t.leaves)
+}
+
+// Proof returns the inclusion proof of leaf i, PATH(m, D[n]) in RFC 9162
+// section 2.1.3.1, ordered from the leaf up to the root.
+func (t *MerkleTree) Proof(i int) [][]byte {
+	return merklePath(i, t.leaves)
+}
+
+func merkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := splitPoint(len(leaves))
+	return merkleNode(merkleRoot(leaves[:k]), merkleRoot(leaves[k:]))
+}
+
+func merklePath(m int, leaves [][]byte) [][]byte {
+	if len(leaves) == 1 {
+		return nil
+	}
+	k := splitPoint(len(leaves))
+	if m < k {
+		return append(merklePath(m, leaves[:k]), merkleRoot(leaves[k:]))
+	}
+	return append(merklePath(m-k, leaves[k:]), merkleRoot(leaves[:k]))
+}
+
+// splitPoint is the largest power of two smaller than n, for n > 1.
+func splitPoint(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// VerifyMerkleProof checks that leaf sits at index in a tree of size leaves
+// with the given root (RFC 9162 section 2.1.3.2).
+func VerifyMerkleProof(leaf []byte, index, size int, path [][]byte, root []byte) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+	fn, sn := index, size-1
+	r := leaf
+	for _, p := range path {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNode(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNode(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// merkleRootPayload is what gets signed in Merkle mode instead of a record.
+type merkleRootPayload struct {
+	Root     string `json:"merkle_root"`
+	TreeSize int    `json:"tree_size"`
+}
+
+// MerklePayload is the canonical byte representation of a signed tree head.
+func MerklePayload(root []byte, treeSize int) []byte {
+	payload, _ := json.Marshal(merkleRootPayload{Root: base64.RawURLEncoding.EncodeToString(root), TreeSize: treeSize}) // always marshals.
+	return payload
+}
+
+// MerkleSigningInput is the Merkle mode counterpart of SigningInput: the JWS
+// signing input of MerklePayload under ProtectedHeader.
+func MerkleSigningInput(alg Algorithm, keyID int, root []byte, treeSize int) []byte {
+	return []byte(ProtectedHeader(alg, keyID) + "." + base64.RawURLEncoding.EncodeToString(MerklePayload(root, treeSize)))
+}
+
+// SignatureSigningInput returns the bytes sig covers for record, whichever mode
+// produced it. Merkle signatures must carry a proof that places record under
+// the signed root; a record that is not in the tree yields an error.
+func SignatureSigningInput(alg Algorithm, record types.Record, sig types.Signature) ([]byte, error) {
+	if sig.MerkleProof == nil {
+		return SigningInput(alg, sig.KeyID, record), nil
+	}
+	proof := sig.MerkleProof
+	root, err := base64.StdEncoding.DecodeString(proof.Root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Merkle proof for record %d: root is not base64", record.ID)
+	}
+	path := make([][]byte, len(proof.Path))
+	for i, encoded := range proof.Path {
+		if path[i], err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("invalid Merkle proof for record %d: path is not base64", record.ID)
+		}
+	}
+	if !VerifyMerkleProof(MerkleLeaf(record), proof.LeafIndex, proof.TreeSize, path, root) {
+		return nil, fmt.Errorf("invalid Merkle proof for record %d: record is not in the signed tree", record.ID)
+	}
+	return MerkleSigningInput(alg, sig.KeyID, root, proof.TreeSize), nil
+}
@@ -0,0 +1,134 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/elliptic"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// PKCS11Backend keeps keys inside a PKCS#11 token (an HSM, or SoftHSM for
+// local testing). Keys are addressed by CKA_ID, which holds the VaultStream
+// key ID, and never leave the token.
+type PKCS11Backend struct {
+	ctx *crypto11.Context
+}
+
+func OpenPKCS11(modulePath, tokenLabel, pin string) (*PKCS11Backend, error) {
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       modulePath,
+		TokenLabel: tokenLabel,
+		Pin:        pin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed configuring PKCS#11 token %q: %w", tokenLabel, err)
+	}
+	return &PKCS11Backend{ctx: ctx}, nil
+}
+
+// ValidateAlgorithm rejects EdDSA, which crypto11 cannot generate keys for.
+func (b *PKCS11Backend) ValidateAlgorithm(alg Algorithm) error {
+	switch alg {
+	case ES256, ES384, PS256:
+		return nil
+	default:
+		return fmt.Errorf("algorithm %s is not supported by the PKCS#11 backend", alg)
+	}
+}
+
+func (b *PKCS11Backend) GenerateKey(ctx context.Context, id int, alg Algorithm) (*types.Key, error) {
+	if err := b.ValidateAlgorithm(alg); err != nil {
+		return nil, err
+	}
+	// A token may hold several objects with the same CKA_ID; never add a
+	// second key under an ID in use.
+	existing, err := b.ctx.FindKeyPair(pkcs11ID(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed finding key %d: %w", id, err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("key %d already exists in the PKCS#11 token", id)
+	}
+
+	var tokenKey crypto11.Signer
+	switch alg {
+	case ES256:
+		tokenKey, err = b.ctx.GenerateECDSAKeyPairWithLabel(pkcs11ID(id), pkcs11Label(id), elliptic.P256())
+	case ES384:
+		tokenKey, err = b.ctx.GenerateECDSAKeyPairWithLabel(pkcs11ID(id), pkcs11Label(id), elliptic.P384())
+	case PS256:
+		tokenKey, err = b.ctx.GenerateRSAKeyPairWithLabel(pkcs11ID(id), pkcs11Label(id), rsaKeyBits)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed generating key %d: %w", id, err)
+	}
+	publicKey, err := EncodePublicKey(tokenKey.Public())
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling public key %d: %w", id, err)
+	}
+	return &types.Key{
+		ID:         id,
+		Algorithm:  string(alg),
+		PublicKey:  publicKey,
+		IsInUse:    false,
+		LastUsedAt: time.Unix(0, 0), // initial value indicating never used.
+	}, nil
+}
+
+func (b *PKCS11Backend) Signer(ctx context.Context, key *types.Key) (Signer, error) {
+	alg, err := ParseAlgorithm(key.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	tokenKey, err := b.ctx.FindKeyPair(pkcs11ID(key.ID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed finding key %d: %w", key.ID, err)
+	}
+	if tokenKey == nil {
+		return nil, fmt.Errorf("key %d not found in PKCS#11 token", key.ID)
+	}
+	if err := checkKeyType(alg, tokenKey.Public()); err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+	return &PKCS11Signer{id: key.ID, alg: alg, key: tokenKey}, nil
+}
+
+func (b *PKCS11Backend) Close() error {
+	return b.ctx.Close()
+}
+
+// PKCS11Signer signs inside the token; the private key handle is all we hold.
+type PKCS11Signer struct {
+	id  int
+	alg Algorithm
+	key crypto11.Signer
+}
+
+func (s *PKCS11Signer) Sign(ctx context.Context, digest []byte) ([]byte, error) {
+	return s.key.Sign(nil, digest, s.alg.SignerOpts())
+}
+
+func (s *PKCS11Signer) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *PKCS11Signer) KeyID() int {
+	return s.id
+}
+
+func (s *PKCS11Signer) Algorithm() Algorithm {
+	return s.alg
+}
+
+func pkcs11ID(id int) []byte {
+	return []byte(strconv.Itoa(id))
+}
+
+func pkcs11Label(id int) []byte {
+	return []byte("vaultstream-key-" + strconv.Itoa(id))
+}
@@ -0,0 +1,92 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	vaultStreamConfig "github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// Signer signs pre-computed digests with a single key. Implementations may keep
+// the private key outside of process memory (on disk, in an HSM), so callers
+// only ever see the public half. The digest is whatever Algorithm().Digest
+// returns for the signed data.
+type Signer interface {
+	Sign(ctx context.Context, digest []byte) ([]byte, error)
+	Public() crypto.PublicKey
+	KeyID() int
+	Algorithm() Algorithm
+}
+
+// Backend generates keys and hands out Signers for them. keys-service uses
+// GenerateKey, after checking with ValidateAlgorithm that the backend can
+// generate keys of every configured algorithm; signing-service only ever asks
+// for Signers.
+type Backend interface {
+	ValidateAlgorithm(alg Algorithm) error
+	GenerateKey(ctx context.Context, id int, alg Algorithm) (*types.Key, error)
+	Signer(ctx context.Context, key *types.Key) (Signer, error)
+	Close() error
+}
+
+// Open returns the Backend selected by SIGNER_BACKEND.
+func Open() (Backend, error) {
+	switch backend := vaultStreamConfig.SignerBackend(); backend {
+	case "memory":
+		return NewMemoryBackend(), nil
+	case "keystore":
+		return OpenKeystore(vaultStreamConfig.KeystoreDir(), vaultStreamConfig.KeystorePassphrase())
+	case "pkcs11":
+		return OpenPKCS11(vaultStreamConfig.PKCS11ModulePath(), vaultStreamConfig.PKCS11TokenLabel(), vaultStreamConfig.PKCS11Pin())
+	default:
+		return nil, fmt.Errorf("unknown signer backend %q", backend)
+	}
+}
+
+// Payload is the canonical byte representation of a record that gets signed.
+// The tenant is signed too, except the default one, so that records from
+// before tenants existed keep their payload.
+func Payload(record types.Record) []byte {
+	record.InsertedAt = record.InsertedAt.UTC()
+	if record.TenantID == types.DefaultTenant {
+		record.TenantID = ""
+	}
+	payload, _ := json.Marshal(record) // types.Record always marshals.
+	return payload
+}
+
+// EncodePublicKey returns the base64 PKIX DER form used in types.Key.PublicKey.
+func EncodePublicKey(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(der), nil
+}
+
+// parsePrivateKey decodes PKCS#8 DER, falling back to the SEC 1 EC form that
+// keys generated before PKCS#8 were stored in.
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		privateKey, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("%T is not a signing key", key)
+		}
+		return privateKey, nil
+	}
+	return x509.ParseECPrivateKey(der)
+}
+
+// DecodePublicKey parses the base64 PKIX DER form used in types.Key.PublicKey.
+func DecodePublicKey(encoded string) (crypto.PublicKey, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParsePKIXPublicKey(der)
+}
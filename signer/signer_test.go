@@ -0,0 +1,653 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// assertSignsAndVerifies generates a key with the backend, signs a record
+// digest through a fresh Signer and checks the signature against the
+// published public key.
+func assertSignsAndVerifies(t *testing.T, backend Backend, id int, alg Algorithm) *types.Key {
+	t.Helper()
+	ctx := context.Background()
+
+	key, err := backend.GenerateKey(ctx, id, alg)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	if key.ID != id {
+		t.Errorf("GenerateKey() key.ID = %d, want %d", key.ID, id)
+	}
+	if key.Algorithm != string(alg) {
+		t.Errorf("GenerateKey() key.Algorithm = %q, want %q", key.Algorithm, alg)
+	}
+
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+	if keySigner.KeyID() != id {
+		t.Errorf("KeyID() = %d, want %d", keySigner.KeyID(), id)
+	}
+	if keySigner.Algorithm() != alg {
+		t.Errorf("Algorithm() = %q, want %q", keySigner.Algorithm(), alg)
+	}
+
+	digest := alg.Digest(SigningInput(alg, id, types.Record{ID: 7}))
+	sig, err := keySigner.Sign(ctx, digest)
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+
+	public, err := DecodePublicKey(key.PublicKey)
+	if err != nil {
+		t.Fatalf("DecodePublicKey() unexpected error: %v", err)
+	}
+	if err := Verify(alg, public, digest, sig); err != nil {
+		t.Errorf("signature does not verify against the published public key: %v", err)
+	}
+	if err := Verify(alg, public, alg.Digest(SigningInput(alg, id, types.Record{ID: 8})), sig); err == nil {
+		t.Errorf("signature unexpectedly verifies for a different record")
+	}
+	return key
+}
+
+func TestMemoryBackend(t *testing.T) {
+	for _, alg := range []Algorithm{ES256, ES384, EdDSA, PS256} {
+		t.Run(string(alg), func(t *testing.T) {
+			key := assertSignsAndVerifies(t, NewMemoryBackend(), 1, alg)
+			if key.Value == "" {
+				t.Errorf("memory backend should carry the private key in key.Value")
+			}
+		})
+	}
+}
+
+// TestMemorySignerLegacyKey checks keys from before the algorithm column:
+// SEC 1 EC private keys with no algorithm are treated as ES256.
+func TestMemorySignerLegacyKey(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey() unexpected error: %v", err)
+	}
+	derBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("x509.MarshalECPrivateKey() unexpected error: %v", err)
+	}
+
+	keySigner, err := NewMemorySigner(&types.Key{ID: 3, Value: base64.StdEncoding.EncodeToString(derBytes)})
+	if err != nil {
+		t.Fatalf("NewMemorySigner() unexpected error: %v", err)
+	}
+	if keySigner.Algorithm() != ES256 {
+		t.Errorf("Algorithm() = %q, want %q", keySigner.Algorithm(), ES256)
+	}
+}
+
+func TestMemorySignerRejectsMismatchedAlgorithm(t *testing.T) {
+	key, err := NewMemoryBackend().GenerateKey(context.Background(), 1, ES256)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	key.Algorithm = string(EdDSA)
+	if _, err := NewMemorySigner(key); err == nil {
+		t.Errorf("NewMemorySigner() should reject a P-256 key labelled EdDSA")
+	}
+}
+
+func TestParseAlgorithm(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Algorithm
+		wantErr bool
+	}{
+		{name: "", want: DefaultAlgorithm},
+		{name: "ES256", want: ES256},
+		{name: "ES384", want: ES384},
+		{name: "EdDSA", want: EdDSA},
+		{name: "PS256", want: PS256},
+		{name: "HS256", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAlgorithm(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseAlgorithm(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseAlgorithm(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSigningInput(t *testing.T) {
+	record := types.Record{ID: 42, InsertedAt: time.Date(2025, 3, 26, 19, 31, 13, 0, time.UTC)}
+
+	got := string(SigningInput(EdDSA, 7, record))
+	// {"alg":"EdDSA","kid":"7"} . {"id":42,"inserted_at":"2025-03-26T19:31:13Z"}
+	want := "eyJhbGciOiJFZERTQSIsImtpZCI6IjcifQ.eyJpZCI6NDIsImluc2VydGVkX2F0IjoiMjAyNS0wMy0yNlQxOTozMToxM1oifQ"
+	if got != want {
+		t.Errorf("SigningInput() = %q, want %q", got, want)
+	}
+
+	// The payload must not depend on the time zone the record was read in.
+	local := record
+	local.InsertedAt = record.InsertedAt.In(time.FixedZone("UTC+2", 2*60*60))
+	if string(SigningInput(EdDSA, 7, local)) != want {
+		t.Errorf("SigningInput() changed with the record's time zone")
+	}
+
+	// The default tenant is left out, so records from before tenants keep
+	// their signatures; any other tenant is signed.
+	defaultTenant := record
+	defaultTenant.TenantID = types.DefaultTenant
+	if string(SigningInput(EdDSA, 7, defaultTenant)) != want {
+		t.Errorf("SigningInput() changed for the default tenant")
+	}
+	acme := record
+	acme.TenantID = "acme"
+	if string(Payload(acme)) != `{"id":42,"tenant_id":"acme","inserted_at":"2025-03-26T19:31:13Z"}` {
+		t.Errorf("Payload() = %s, want the tenant signed", Payload(acme))
+	}
+}
+
+func TestCAIssue(t *testing.T) {
+	ca, err := OpenCA(filepath.Join(t.TempDir(), "root.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+
+	for _, alg := range []Algorithm{ES256, ES384, EdDSA, PS256} {
+		t.Run(string(alg), func(t *testing.T) {
+			key, err := NewMemoryBackend().GenerateKey(context.Background(), 9, alg)
+			if err != nil {
+				t.Fatalf("GenerateKey() unexpected error: %v", err)
+			}
+
+			// Asking for more than the root's lifetime is capped to it.
+			der, err := ca.Issue(key, 100*365*24*time.Hour)
+			if err != nil {
+				t.Fatalf("Issue() unexpected error: %v", err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatalf("x509.ParseCertificate() unexpected error: %v", err)
+			}
+			if err := cert.CheckSignatureFrom(ca.Certificate()); err != nil {
+				t.Errorf("certificate is not signed by the CA: %v", err)
+			}
+			if cert.KeyUsage != x509.KeyUsageDigitalSignature {
+				t.Errorf("certificate KeyUsage = %v, want digitalSignature only", cert.KeyUsage)
+			}
+			if cert.NotAfter.After(ca.Certificate().NotAfter) {
+				t.Errorf("certificate NotAfter %v outlives the root %v", cert.NotAfter, ca.Certificate().NotAfter)
+			}
+		})
+	}
+}
+
+func TestCAPersistsRoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca", "root.json")
+
+	ca, err := OpenCA(path, "ca passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	if !ca.Certificate().IsCA {
+		t.Errorf("root certificate is not a CA certificate")
+	}
+
+	reopened, err := OpenCA(path, "ca passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() reopen unexpected error: %v", err)
+	}
+	if !reopened.Certificate().Equal(ca.Certificate()) {
+		t.Errorf("OpenCA() reopen returned a different root")
+	}
+
+	if _, err := OpenCA(path, "wrong passphrase"); err == nil {
+		t.Errorf("OpenCA() with wrong passphrase should fail")
+	}
+}
+
+func TestOpenCAKeepsFirstRoot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "root.json")
+
+	// Processes opening a missing CA at once must end up with one root.
+	cas := make([]*CA, 4)
+	errs := make([]error, len(cas))
+	var wg sync.WaitGroup
+	for i := range cas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cas[i], errs[i] = OpenCA(path, "ca passphrase")
+		}()
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("OpenCA() unexpected error: %v", err)
+		}
+		if !cas[i].Certificate().Equal(cas[0].Certificate()) {
+			t.Errorf("OpenCA() created rival roots")
+		}
+	}
+	if err := createJSON(path, caFile{}); !errors.Is(err, os.ErrExist) {
+		t.Errorf("createJSON() over the CA file = %v, want %v", err, os.ErrExist)
+	}
+}
+
+func TestLoadCACertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := OpenCA(filepath.Join(dir, "root.json"), "ca passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "certs", "root.pem")
+	if _, err := LoadCACertificate(path); err == nil {
+		t.Errorf("LoadCACertificate() of a missing file should fail")
+	}
+	if err := ca.WriteCertificate(path); err != nil {
+		t.Fatalf("WriteCertificate() unexpected error: %v", err)
+	}
+	// Writing again replaces the file.
+	if err := ca.WriteCertificate(path); err != nil {
+		t.Fatalf("WriteCertificate() again unexpected error: %v", err)
+	}
+	cert, err := LoadCACertificate(path)
+	if err != nil {
+		t.Fatalf("LoadCACertificate() unexpected error: %v", err)
+	}
+	if !cert.Equal(ca.Certificate()) {
+		t.Errorf("LoadCACertificate() returned a different root")
+	}
+
+	if _, err := LoadCACertificate(filepath.Join(dir, "root.json")); err == nil {
+		t.Errorf("LoadCACertificate() of the sealed CA file should fail")
+	}
+}
+
+// signWithCertifiedKey generates a key, has ca certify it and signs record.
+func signWithCertifiedKey(t *testing.T, ca *CA, id int, alg Algorithm, record types.Record) (*types.Key, types.Signature) {
+	t.Helper()
+	ctx := context.Background()
+
+	backend := NewMemoryBackend()
+	key, err := backend.GenerateKey(ctx, id, alg)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	cert, err := ca.Issue(key, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(cert)
+	key.CertificateChain = ca.Chain()
+
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+	sig, err := keySigner.Sign(ctx, alg.Digest(SigningInput(alg, id, record)))
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+	return key, types.Signature{
+		RecordID:   record.ID,
+		KeyID:      id,
+		Algorithm:  string(alg),
+		Value:      base64.StdEncoding.EncodeToString(sig),
+		InsertedAt: time.Now(),
+	}
+}
+
+func TestVerifierAcceptsCertifiedKeys(t *testing.T) {
+	ca, err := OpenCA(filepath.Join(t.TempDir(), "root.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	verifier := NewVerifier(ca.Certificate())
+	record := types.Record{ID: 5, InsertedAt: time.Now()}
+
+	for _, alg := range []Algorithm{ES256, ES384, EdDSA, PS256} {
+		t.Run(string(alg), func(t *testing.T) {
+			key, sig := signWithCertifiedKey(t, ca, 3, alg, record)
+			if err := verifier.Verify(key, record, sig); err != nil {
+				t.Errorf("Verify() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifierRejects(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := OpenCA(filepath.Join(dir, "root.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	otherCA, err := OpenCA(filepath.Join(dir, "other.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	record := types.Record{ID: 5, InsertedAt: time.Now()}
+
+	tests := []struct {
+		name   string
+		mutate func(key *types.Key, record *types.Record, sig *types.Signature)
+	}{
+		{
+			name:   "tampered record",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) { record.ID++ },
+		},
+		{
+			name: "uncertified public key",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) {
+				other, _ := NewMemoryBackend().GenerateKey(context.Background(), key.ID, ES256)
+				key.PublicKey = other.PublicKey
+			},
+		},
+		{
+			name: "missing certificate",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) {
+				key.Certificate = ""
+			},
+		},
+		{
+			name: "signed after certificate expiry",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) {
+				sig.InsertedAt = time.Now().Add(2 * time.Hour)
+			},
+		},
+		{
+			name: "root certificate as key certificate",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) {
+				key.Certificate = ca.Chain()[0]
+			},
+		},
+		{
+			name: "issued by an untrusted CA",
+			mutate: func(key *types.Key, record *types.Record, sig *types.Signature) {
+				cert, _ := otherCA.Issue(key, time.Hour)
+				key.Certificate = base64.StdEncoding.EncodeToString(cert)
+				key.CertificateChain = otherCA.Chain()
+			},
+		},
+	}
+
+	verifier := NewVerifier(ca.Certificate())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, sig := signWithCertifiedKey(t, ca, 3, ES256, record)
+			record := record
+			tt.mutate(key, &record, &sig)
+			if err := verifier.Verify(key, record, sig); err == nil {
+				t.Errorf("Verify() should fail")
+			}
+		})
+	}
+}
+
+func TestVerifierBindsKeysToTenants(t *testing.T) {
+	ca, err := OpenCA(filepath.Join(t.TempDir(), "root.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	verifier := NewVerifier(ca.Certificate())
+	ctx := context.Background()
+
+	backend := NewMemoryBackend()
+	key, err := backend.GenerateKey(ctx, 3, ES256)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	key.TenantID = "acme"
+	cert, err := ca.Issue(key, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(cert)
+	key.CertificateChain = ca.Chain()
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		tenant  string
+		wantErr bool
+	}{
+		{tenant: "acme", wantErr: false},
+		{tenant: "globex", wantErr: true},
+		{tenant: "", wantErr: true}, // the default tenant
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenant, func(t *testing.T) {
+			record := types.Record{ID: 5, TenantID: tt.tenant, InsertedAt: time.Now()}
+			sig, err := keySigner.Sign(ctx, ES256.Digest(SigningInput(ES256, key.ID, record)))
+			if err != nil {
+				t.Fatalf("Sign() unexpected error: %v", err)
+			}
+			err = verifier.Verify(key, record, types.Signature{
+				RecordID:   record.ID,
+				KeyID:      key.ID,
+				Algorithm:  string(ES256),
+				Value:      base64.StdEncoding.EncodeToString(sig),
+				InsertedAt: time.Now(),
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() of a tenant %q record error = %v, wantErr %v", tt.tenant, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeystoreBackend(t *testing.T) {
+	dir := t.TempDir()
+
+	keystore, err := OpenKeystore(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("OpenKeystore() unexpected error: %v", err)
+	}
+	key := assertSignsAndVerifies(t, keystore, 2, EdDSA)
+	if key.Value != "" {
+		t.Errorf("keystore backend leaked the private key into key.Value")
+	}
+
+	// Reopening with the same passphrase must give access to existing keys.
+	reopened, err := OpenKeystore(dir, "correct horse")
+	if err != nil {
+		t.Fatalf("OpenKeystore() reopen unexpected error: %v", err)
+	}
+	if _, err := reopened.Signer(context.Background(), key); err != nil {
+		t.Errorf("Signer() after reopen unexpected error: %v", err)
+	}
+
+	if _, err := OpenKeystore(dir, "wrong horse"); err == nil {
+		t.Errorf("OpenKeystore() with wrong passphrase should fail")
+	}
+}
+
+func TestKeystoreRefusesExistingKeyID(t *testing.T) {
+	keystore, err := OpenKeystore(t.TempDir(), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenKeystore() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	key, err := keystore.GenerateKey(ctx, 1, ES256)
+	if err != nil {
+		t.Fatalf("GenerateKey(1) unexpected error: %v", err)
+	}
+	if _, err := keystore.GenerateKey(ctx, 1, ES256); err == nil {
+		t.Fatalf("GenerateKey(1) again should fail")
+	}
+	entry, err := keystore.readEntry(1)
+	if err != nil {
+		t.Fatalf("readEntry(1) unexpected error: %v", err)
+	}
+	if entry.PublicKey != key.PublicKey {
+		t.Errorf("GenerateKey(1) again replaced the public key of key 1")
+	}
+}
+
+func TestKeystoreRejectsSwappedEntries(t *testing.T) {
+	dir := t.TempDir()
+	keystore, err := OpenKeystore(dir, "passphrase")
+	if err != nil {
+		t.Fatalf("OpenKeystore() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	if _, err := keystore.GenerateKey(ctx, 1, ES256); err != nil {
+		t.Fatalf("GenerateKey(1) unexpected error: %v", err)
+	}
+	key2, err := keystore.GenerateKey(ctx, 2, ES256)
+	if err != nil {
+		t.Fatalf("GenerateKey(2) unexpected error: %v", err)
+	}
+
+	// Overwrite key 2's ciphertext with key 1's; decryption must fail.
+	entry1, _ := keystore.readEntry(1)
+	entry2, _ := keystore.readEntry(2)
+	entry2.Nonce, entry2.Ciphertext = entry1.Nonce, entry1.Ciphertext
+	if err := writeJSON(keystore.entryPath(2), entry2); err != nil {
+		t.Fatalf("writeJSON() unexpected error: %v", err)
+	}
+
+	keySigner, err := keystore.Signer(ctx, key2)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+	if _, err := keySigner.Sign(ctx, ES256.Digest(SigningInput(ES256, 2, types.Record{ID: 1}))); err == nil {
+		t.Errorf("Sign() with a swapped entry should fail")
+	}
+}
+
+// TestPKCS11Backend runs against SoftHSM (or any PKCS#11 module), e.g.:
+//
+//	softhsm2-util --init-token --free --label vaultstream --pin 1234 --so-pin 1234
+//	PKCS11_MODULE_PATH=/usr/lib/softhsm/libsofthsm2.so PKCS11_TOKEN_LABEL=vaultstream PKCS11_PIN=1234 go test ./signer
+func TestPKCS11Backend(t *testing.T) {
+	modulePath := os.Getenv("PKCS11_MODULE_PATH")
+	if modulePath == "" {
+		t.Skip("Skipping PKCS#11 test: PKCS11_MODULE_PATH not set")
+	}
+
+	backend, err := OpenPKCS11(modulePath, os.Getenv("PKCS11_TOKEN_LABEL"), os.Getenv("PKCS11_PIN"))
+	if err != nil {
+		t.Fatalf("OpenPKCS11() unexpected error: %v", err)
+	}
+	defer backend.Close()
+
+	for i, alg := range []Algorithm{ES256, ES384, PS256} {
+		t.Run(string(alg), func(t *testing.T) {
+			key := assertSignsAndVerifies(t, backend, 4242+i, alg)
+			if key.Value != "" {
+				t.Errorf("PKCS#11 backend leaked the private key into key.Value")
+			}
+		})
+	}
+}
+
+func TestMerkleTree(t *testing.T) {
+	for size := 1; size <= 17; size++ {
+		leaves := make([][]byte, size)
+		for i := range leaves {
+			leaves[i] = MerkleLeaf(types.Record{ID: i + 1})
+		}
+		tree := NewMerkleTree(leaves)
+		root := tree.Root()
+
+		for i := range leaves {
+			proof := tree.Proof(i)
+			if !VerifyMerkleProof(leaves[i], i, size, proof, root) {
+				t.Errorf("size %d: proof of leaf %d does not verify", size, i)
+			}
+			if size > 1 && VerifyMerkleProof(leaves[i], (i+1)%size, size, proof, root) {
+				t.Errorf("size %d: proof of leaf %d verifies at index %d", size, i, (i+1)%size)
+			}
+			if VerifyMerkleProof(MerkleLeaf(types.Record{ID: 100}), i, size, proof, root) {
+				t.Errorf("size %d: proof of leaf %d verifies for a foreign leaf", size, i)
+			}
+		}
+	}
+
+	// Hand-built tree of three leaves: node(node(l0, l1), l2).
+	leaves := [][]byte{MerkleLeaf(types.Record{ID: 1}), MerkleLeaf(types.Record{ID: 2}), MerkleLeaf(types.Record{ID: 3})}
+	want := merkleNode(merkleNode(leaves[0], leaves[1]), leaves[2])
+	if got := NewMerkleTree(leaves).Root(); string(got) != string(want) {
+		t.Errorf("Root() = %x, want %x", got, want)
+	}
+}
+
+func TestVerifierAcceptsMerkleSignatures(t *testing.T) {
+	ca, err := OpenCA(filepath.Join(t.TempDir(), "root.json"), "passphrase")
+	if err != nil {
+		t.Fatalf("OpenCA() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+	backend := NewMemoryBackend()
+	key, err := backend.GenerateKey(ctx, 4, EdDSA)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	cert, err := ca.Issue(key, time.Hour)
+	if err != nil {
+		t.Fatalf("Issue() unexpected error: %v", err)
+	}
+	key.Certificate = base64.StdEncoding.EncodeToString(cert)
+	keySigner, err := backend.Signer(ctx, key)
+	if err != nil {
+		t.Fatalf("Signer() unexpected error: %v", err)
+	}
+
+	records := []types.Record{{ID: 1}, {ID: 2}, {ID: 3}}
+	leaves := make([][]byte, len(records))
+	for i, record := range records {
+		leaves[i] = MerkleLeaf(record)
+	}
+	tree := NewMerkleTree(leaves)
+	sig, err := keySigner.Sign(ctx, EdDSA.Digest(MerkleSigningInput(EdDSA, 4, tree.Root(), tree.Size())))
+	if err != nil {
+		t.Fatalf("Sign() unexpected error: %v", err)
+	}
+
+	verifier := NewVerifier(ca.Certificate())
+	for i, record := range records {
+		path := make([]string, 0)
+		for _, hash := range tree.Proof(i) {
+			path = append(path, base64.StdEncoding.EncodeToString(hash))
+		}
+		signature := types.Signature{
+			RecordID:  record.ID,
+			KeyID:     4,
+			Algorithm: string(EdDSA),
+			Value:     base64.StdEncoding.EncodeToString(sig),
+			MerkleProof: &types.MerkleProof{
+				Root:      base64.StdEncoding.EncodeToString(tree.Root()),
+				LeafIndex: i,
+				TreeSize:  tree.Size(),
+				Path:      path,
+			},
+		}
+		if err := verifier.Verify(key, record, signature); err != nil {
+			t.Errorf("Verify() record %d unexpected error: %v", record.ID, err)
+		}
+
+		other := types.Record{ID: 99}
+		if err := verifier.Verify(key, other, signature); err == nil {
+			t.Errorf("Verify() accepted record %d's proof for record %d", record.ID, other.ID)
+		}
+	}
+}
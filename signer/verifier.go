@@ -0,0 +1,137 @@
+package signer
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// Verifier checks record signatures against keys certified by a trusted root,
+// rather than trusting whatever public key is stored next to the signature.
+type Verifier struct {
+	roots *x509.CertPool
+}
+
+func NewVerifier(roots ...*x509.Certificate) *Verifier {
+	pool := x509.NewCertPool()
+	for _, root := range roots {
+		pool.AddCert(root)
+	}
+	return &Verifier{roots: pool}
+}
+
+// VerifyKey validates the key's certificate chain up to a trusted root at the
+// given time, checks that the certificate allows digital signatures and that
+// it certifies key.PublicKey. It returns the key certificate.
+func (v *Verifier) VerifyKey(key *types.Key, at time.Time) (*x509.Certificate, error) {
+	if key.Certificate == "" {
+		return nil, fmt.Errorf("key %d has no certificate", key.ID)
+	}
+	cert, err := parseCertificate(key.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("key %d: %w", key.ID, err)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, encoded := range key.CertificateChain {
+		chainCert, err := parseCertificate(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %d chain: %w", key.ID, err)
+		}
+		intermediates.AddCert(chainCert)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		// Signing keys carry no extended key usage; KeyUsage is checked below.
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("key %d certificate: %w", key.ID, err)
+	}
+
+	if cert.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, fmt.Errorf("key %d certificate does not allow digital signatures", key.ID)
+	}
+	if cert.IsCA {
+		return nil, fmt.Errorf("key %d certificate is a CA certificate", key.ID)
+	}
+	certified, err := EncodePublicKey(cert.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("key %d certificate: %w", key.ID, err)
+	}
+	if certified != key.PublicKey {
+		return nil, fmt.Errorf("key %d certificate is for a different public key", key.ID)
+	}
+	return cert, nil
+}
+
+// Verify checks that sig is a signature of record by key, directly or through
+// a Merkle proof, and that key was certified for the record's tenant when the
+// signature was made.
+func (v *Verifier) Verify(key *types.Key, record types.Record, sig types.Signature) error {
+	if sig.KeyID != key.ID {
+		return fmt.Errorf("signature was made with key %d, not key %d", sig.KeyID, key.ID)
+	}
+	alg, err := ParseAlgorithm(sig.Algorithm)
+	if err != nil {
+		return err
+	}
+	if key.Algorithm != "" && Algorithm(key.Algorithm) != alg {
+		return fmt.Errorf("signature algorithm %s does not match key %d algorithm %s", alg, key.ID, key.Algorithm)
+	}
+
+	at := sig.InsertedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	cert, err := v.VerifyKey(key, at)
+	if err != nil {
+		return err
+	}
+	if keyTenant, recordTenant := certifiedTenant(cert), tenantOf(record); keyTenant != recordTenant {
+		return fmt.Errorf("key %d is certified for tenant %q, not for record %d of tenant %q", key.ID, keyTenant, record.ID, recordTenant)
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		return fmt.Errorf("signature of record %d is not base64: %w", record.ID, err)
+	}
+	input, err := SignatureSigningInput(alg, record, sig)
+	if err != nil {
+		return err
+	}
+	if err := Verify(alg, cert.PublicKey, alg.Digest(input), sigBytes); err != nil {
+		return fmt.Errorf("signature of record %d: %w", record.ID, err)
+	}
+	return nil
+}
+
+// certifiedTenant returns the tenant a key certificate was issued for.
+// Certificates from before tenants existed name none and belong to the
+// default tenant.
+func certifiedTenant(cert *x509.Certificate) string {
+	if len(cert.Subject.OrganizationalUnit) == 0 {
+		return types.DefaultTenant
+	}
+	return cert.Subject.OrganizationalUnit[0]
+}
+
+func tenantOf(record types.Record) string {
+	if record.TenantID == "" {
+		return types.DefaultTenant
+	}
+	return record.TenantID
+}
+
+func parseCertificate(encoded string) (*x509.Certificate, error) {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("certificate is not base64")
+	}
+	return x509.ParseCertificate(der)
+}
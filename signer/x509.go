@@ -0,0 +1,45 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"io"
+)
+
+// CryptoSigner adapts a Signer to crypto.Signer for APIs such as
+// x509.CreateCertificate. The SignerOpts passed by the caller are ignored: the
+// Signer always signs with its own algorithm, so callers must request the
+// matching scheme (see X509SignatureAlgorithm).
+func CryptoSigner(ctx context.Context, s Signer) crypto.Signer {
+	return &cryptoSigner{ctx: ctx, signer: s}
+}
+
+type cryptoSigner struct {
+	ctx    context.Context
+	signer Signer
+}
+
+func (c *cryptoSigner) Public() crypto.PublicKey {
+	return c.signer.Public()
+}
+
+func (c *cryptoSigner) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	return c.signer.Sign(c.ctx, digest)
+}
+
+// X509SignatureAlgorithm maps alg to the equivalent X.509 signature algorithm.
+func X509SignatureAlgorithm(alg Algorithm) x509.SignatureAlgorithm {
+	switch alg {
+	case ES256:
+		return x509.ECDSAWithSHA256
+	case ES384:
+		return x509.ECDSAWithSHA384
+	case EdDSA:
+		return x509.PureEd25519
+	case PS256:
+		return x509.SHA256WithRSAPSS
+	default:
+		return x509.UnknownSignatureAlgorithm
+	}
+}
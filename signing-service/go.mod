@@ -5,8 +5,13 @@ go 1.24.1
 require (
 	github.com/jurshsmith/vaultstream/config v0.0.0
 	github.com/jurshsmith/vaultstream/database v0.0.0
+	github.com/jurshsmith/vaultstream/ledger v0.0.0
 	github.com/jurshsmith/vaultstream/logger v0.0.0
+	github.com/jurshsmith/vaultstream/metrics v0.0.0
 	github.com/jurshsmith/vaultstream/nats v0.0.0
+	github.com/jurshsmith/vaultstream/recordbatch v0.0.0
+	github.com/jurshsmith/vaultstream/signer v0.0.0
+	github.com/jurshsmith/vaultstream/tsa v0.0.0
 	github.com/jurshsmith/vaultstream/types v0.0.0
 	github.com/nats-io/nats.go v1.40.1
 	go.uber.org/zap v1.27.0
@@ -17,29 +22,49 @@ replace github.com/jurshsmith/vaultstream/config => ../config
 
 replace github.com/jurshsmith/vaultstream/database => ../database
 
+replace github.com/jurshsmith/vaultstream/ledger => ../ledger
+
 replace github.com/jurshsmith/vaultstream/logger => ../logger
 
+replace github.com/jurshsmith/vaultstream/metrics => ../metrics
+
 replace github.com/jurshsmith/vaultstream/nats => ../nats
 
+replace github.com/jurshsmith/vaultstream/recordbatch => ../recordbatch
+
 replace github.com/jurshsmith/vaultstream/types => ../types
 
+replace github.com/jurshsmith/vaultstream/signer => ../signer
+
+replace github.com/jurshsmith/vaultstream/tsa => ../tsa
+
 require (
 	ariga.io/atlas v0.31.1-0.20250212144724-069be8033e83 // indirect
 	entgo.io/ent v0.14.4 // indirect
+	github.com/ThalesIgnite/crypto11 v1.2.5 // indirect
 	github.com/agext/levenshtein v1.2.1 // indirect
 	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
 	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
 	github.com/bmatcuk/doublestar v1.3.4 // indirect
+	github.com/digitorus/pkcs7 v0.0.0-20230713084857-e76b763bdc49 // indirect
+	github.com/digitorus/timestamp v0.0.0-20250524132541-c45532741eea // indirect
 	github.com/go-openapi/inflect v0.19.0 // indirect
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/hashicorp/hcl/v2 v2.13.0 // indirect
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/pgx/v5 v5.7.2 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/lib/pq v1.10.9 // indirect
+	github.com/miekg/pkcs11 v1.0.3-0.20190429190417-a667d056470f // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/nats-io/nkeys v0.4.9 // indirect
 	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/thales-e-security/pool v0.0.2 // indirect
 	github.com/zclconf/go-cty v1.14.4 // indirect
 	github.com/zclconf/go-cty-yaml v1.1.0 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// leaseKeyFunc hands out a free signing key of a tenant; release returns it to
+// the tenant's pool.
+type leaseKeyFunc func(ctx context.Context, tenant string) (key *types.Key, release func(), err error)
+
+// consumerCreator is the part of jetstream.JetStream keyPools needs.
+type consumerCreator interface {
+	CreateOrUpdateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error)
+}
+
+// keyPools leases keys from one keys consumer per tenant, filtered on
+// keys.<tenant>.>, so a key is only ever handed out for its own tenant's
+// records. Consumers are created on first use.
+type keyPools struct {
+	js        consumerCreator
+	mu        sync.Mutex
+	consumers map[string]*nats.Durable
+}
+
+func newKeyPools(js consumerCreator) *keyPools {
+	return &keyPools{js: js, consumers: make(map[string]*nats.Durable)}
+}
+
+func (p *keyPools) consumer(ctx context.Context, tenant string) (*nats.Durable, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.consumers[tenant]; ok {
+		return c, nil
+	}
+	c, err := nats.NewDurable(ctx, p.js, config.KeysStreamName(), jetstream.ConsumerConfig{
+		Durable:       "signing-keys-consumer-" + tenant,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: fmt.Sprintf("keys.%s.>", tenant),
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckWait:       leaseAckWait,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating keys consumer of tenant %s: %w", tenant, err)
+	}
+	p.consumers[tenant] = c
+	return c, nil
+}
+
+// leaser returns a leaseKeyFunc that waits up to wait for a free key. A key is
+// held, and its message kept in progress so no other replica leases it, until
+// release naks the message back onto the tenant's consumer.
+func (p *keyPools) leaser(wait time.Duration) leaseKeyFunc {
+	return func(ctx context.Context, tenant string) (*types.Key, func(), error) {
+		keysConsumer, err := p.consumer(ctx, tenant)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyMsg, err := keysConsumer.Next(jetstream.FetchMaxWait(wait))
+		if err != nil {
+			return nil, nil, fmt.Errorf("no free key of tenant %s: %w", tenant, err)
+		}
+		var key types.Key
+		if err := json.Unmarshal(keyMsg.Data(), &key); err != nil {
+			keyMsg.Nak()
+			return nil, nil, fmt.Errorf("failed unmarshaling key: %w", err)
+		}
+		if key.TenantID != tenant {
+			// Published on the wrong subject; it must never sign for this tenant.
+			keyMsg.Term()
+			return nil, nil, fmt.Errorf("key %d of tenant %q found in the pool of tenant %s", key.ID, key.TenantID, tenant)
+		}
+		stopProgress := keepInProgress(keyMsg)
+		return &key, func() {
+			stopProgress()
+			if err := keyMsg.Nak(); err != nil {
+				log.Error("Error re-enqueueing key", zap.Int("keyID", key.ID), zap.Error(err))
+			}
+		}, nil
+	}
+}
+
+// batchTenant returns the tenant of a records message, the second token of
+// records.<tenant>.<priority>.<batch>, after checking the priority and that
+// every record belongs to the tenant.
+func batchTenant(subject string, records []types.Record) (string, error) {
+	tokens := strings.Split(subject, ".")
+	if len(tokens) < 4 {
+		return "", fmt.Errorf("records subject %q has no tenant and priority", subject)
+	}
+	tenant := tokens[1]
+	if err := types.ValidateTenant(tenant); err != nil {
+		return "", err
+	}
+	if err := types.ValidatePriority(tokens[2]); err != nil {
+		return "", err
+	}
+	for _, record := range records {
+		if record.TenantID != tenant {
+			return "", fmt.Errorf("record %d of tenant %q published for tenant %s", record.ID, record.TenantID, tenant)
+		}
+	}
+	return tenant, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestBatchTenant(t *testing.T) {
+	acme := []types.Record{{ID: 1, TenantID: "acme"}, {ID: 2, TenantID: "acme"}}
+	tests := []struct {
+		name    string
+		subject string
+		records []types.Record
+		want    string
+		wantErr bool
+	}{
+		{name: "batch", subject: "records.acme.normal.3", records: acme, want: "acme"},
+		{name: "api submission", subject: "records.acme.high.api.1", records: acme, want: "acme"},
+		{name: "empty batch", subject: "records.globex.low.4", want: "globex"},
+		{name: "no tenant", subject: "records.3", records: acme, wantErr: true},
+		{name: "no priority", subject: "records.acme.3", records: acme, wantErr: true},
+		{name: "unknown priority", subject: "records.acme.urgent.3", records: acme, wantErr: true},
+		{name: "other tenant", subject: "records.globex.normal.3", records: acme, wantErr: true},
+		{name: "record without tenant", subject: "records.default.normal.3", records: []types.Record{{ID: 1}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := batchTenant(tt.subject, tt.records)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("batchTenant(%q) = %q, %v; want %q, error %v", tt.subject, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeConsumerCreator records the consumers keyPools asks for.
+type fakeConsumerCreator struct {
+	configs []jetstream.ConsumerConfig
+}
+
+func (f *fakeConsumerCreator) CreateOrUpdateConsumer(ctx context.Context, stream string, cfg jetstream.ConsumerConfig) (jetstream.Consumer, error) {
+	f.configs = append(f.configs, cfg)
+	return nil, nil
+}
+
+// TestKeyPoolsConsumers verifies every tenant gets its own keys consumer,
+// filtered on its subjects and created once.
+func TestKeyPoolsConsumers(t *testing.T) {
+	js := &fakeConsumerCreator{}
+	pools := newKeyPools(js)
+	for _, tenant := range []string{"acme", "globex", "acme"} {
+		if _, err := pools.consumer(context.Background(), tenant); err != nil {
+			t.Fatalf("consumer(%s) unexpected error: %v", tenant, err)
+		}
+	}
+
+	want := []struct{ durable, filter string }{
+		{"signing-keys-consumer-acme", "keys.acme.>"},
+		{"signing-keys-consumer-globex", "keys.globex.>"},
+	}
+	if len(js.configs) != len(want) {
+		t.Fatalf("created %d consumers, want %d", len(js.configs), len(want))
+	}
+	for i, cfg := range js.configs {
+		if cfg.Durable != want[i].durable || cfg.FilterSubject != want[i].filter {
+			t.Errorf("consumer %d = %s on %s, want %s on %s", i, cfg.Durable, cfg.FilterSubject, want[i].durable, want[i].filter)
+		}
+	}
+}
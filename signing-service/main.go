@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"sync"
-	"sync/atomic"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/jurshsmith/vaultstream/config"
 	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/ledger"
 	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/metrics"
 	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/tsa"
 	"github.com/jurshsmith/vaultstream/types"
 	"github.com/nats-io/nats.go/jetstream"
 	"go.uber.org/zap"
@@ -22,6 +27,15 @@ import (
 
 var log *zap.Logger
 
+const (
+	signingModeRecord = "record"
+	signingModeMerkle = "merkle"
+
+	// batchKeyLeaseWait is how long a batch waits for a free key of its
+	// tenant before it is redelivered.
+	batchKeyLeaseWait = 30 * time.Second
+)
+
 func main() {
 	log = logger.New()
 	defer log.Sync()
@@ -32,199 +46,286 @@ func main() {
 	// Load configuration
 	config.Setup()
 
+	signingMode := config.SigningMode()
+	if signingMode != signingModeRecord && signingMode != signingModeMerkle {
+		log.Fatal("Invalid SIGNING_MODE", zap.String("mode", signingMode))
+	}
+
 	// Setup database and NATS JetStream connections
-	dbClient := database.Connect()
+	dbClient, err := database.Connect(config.SignerMaxConcurrency())
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
 	defer dbClient.Close()
 	log.Debug("Database connection established")
 
 	ctx := context.Background()
 
-	jetstreamClient, natsConn := nats.Connect()
+	jetstreamClient, natsConn, err := nats.Connect("signing-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
 	defer natsConn.Close()
 	log.Debug("NATS JetStream connection established")
 
-	recordsConsumerName := "signing-records-consumer"
-	keysConsumerName := "signing-keys-consumer"
-
-	// Create (or update) consumer for the records stream.
-	recordsConsumerConfig := &jetstream.ConsumerConfig{
-		Durable:       recordsConsumerName,
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: "records.>",
-		DeliverPolicy: jetstream.DeliverAllPolicy,
-	}
-	recordsConsumer, err := jetstreamClient.CreateOrUpdateConsumer(ctx, config.EventsStreamName(), *recordsConsumerConfig)
+	backend, err := signer.Open()
 	if err != nil {
-		log.Fatal("Error creating/updating records consumer", zap.Error(err))
+		log.Fatal("Error opening signer backend", zap.Error(err))
 	}
+	defer backend.Close()
 
-	// Create (or update) consumer for the keys stream.
-	keysConsumerConfig := &jetstream.ConsumerConfig{
-		Durable:       keysConsumerName,
-		AckPolicy:     jetstream.AckExplicitPolicy,
-		FilterSubject: "keys.>",
-		DeliverPolicy: jetstream.DeliverAllPolicy,
+	// Timestamping is optional; without a TSA the signatures only carry inserted_at.
+	var tsaClient *tsa.Client
+	if tsaURL := config.TSAURL(); tsaURL != "" {
+		tsaClient = tsa.NewClient(tsaURL)
+		log.Info("Timestamping signatures", zap.String("tsa", tsaURL))
 	}
-	keysConsumer, err := jetstreamClient.CreateOrUpdateConsumer(ctx, config.EventsStreamName(), *keysConsumerConfig)
+
+	// Each tenant has a records consumer per priority lane. Lanes are drained
+	// highest first, tenants within a lane by weighted fair queuing within
+	// their rate limits.
+	limits := newRateLimits(config.TenantRateLimits(), time.Now())
+	lanes, err := recordsLanes(ctx, jetstreamClient, config.Tenants(), config.TenantWeights())
 	if err != nil {
-		log.Fatal("Error creating/updating keys consumer", zap.Error(err))
+		log.Fatal("Error creating/updating records consumers", zap.Error(err))
 	}
-
-	var (
-		totalSignedRecords   int64 // use atomic counter
-		expectedTotalBatches = config.TotalRecordBatches()
-		batchesEnqueuedSoFar = 0
-		procWG               sync.WaitGroup
-	)
-
-	// Main loop: keep pulling messages until we've signed enough records.
-	for {
-		if batchesEnqueuedSoFar == expectedTotalBatches {
-			break
-		}
-
-		// Pull one records message (blocking until available)
-		recordsMsg, err := recordsConsumer.Next()
-		if err != nil {
-			log.Error("Error fetching records message", zap.Error(err))
-			continue
-		}
-
-		// Decode the batch of records.
-		var records []types.Record
-		if err := json.Unmarshal(recordsMsg.Data(), &records); err != nil {
-			log.Error("Error unmarshaling records", zap.Error(err))
-			recordsMsg.Nak() // Negative acknowledgment so it can be retried
-			continue
-		}
-		log.Debug("Fetched records", zap.Int("RecordsBatchSize", len(records)))
-
-		// Pull one free key message (blocking until available)
-		keyMsg, err := keysConsumer.Next()
+	starvationLimit := config.PriorityStarvationLimit()
+	if starvationLimit < 1 {
+		log.Fatal("Invalid PRIORITY_STARVATION_LIMIT", zap.Int("starvationLimit", starvationLimit))
+	}
+	sched := newScheduler(lanes, limits, starvationLimit)
+	// Batches too large for a message are loaded from the claim check bucket.
+	if bucket := config.ClaimCheckBucket(); bucket != "" {
+		claims, err := recordbatch.OpenClaims(ctx, jetstreamClient, bucket, config.ClaimCheckTTL())
 		if err != nil {
-			log.Error("Error fetching key message", zap.Error(err))
-			recordsMsg.Nak()
-			continue
+			log.Fatal("Error opening claim check bucket", zap.Error(err))
 		}
+		sched.claims = claims
+	}
 
-		var key types.Key
-		if err := json.Unmarshal(keyMsg.Data(), &key); err != nil {
-			log.Error("Error unmarshaling key", zap.Error(err))
-			keyMsg.Nak()
-			recordsMsg.Nak()
-			continue
-		}
-		log.Debug("Fetched free key", zap.Int("keyID", key.ID))
-
-		// Spawn a goroutine to process signing and bulk insertion.
-		procWG.Add(1)
-		go func(recordsMsg jetstream.Msg, keyMsg jetstream.Msg, records []types.Record, key types.Key) {
-			defer procWG.Done()
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			defer cancel()
-
-			// Sign each record concurrently using the key.
-			signatures, err := signRecords(records, &key)
-			if err != nil {
-				log.Error("Error signing records", zap.Error(err))
-				return // Do not ack; message will be re-delivered.
-			}
-
-			// Bulk insert signatures into the database.
-			if err := insertSignatures(ctx, dbClient, signatures); err != nil {
-				log.Error("Error inserting signatures", zap.Error(err))
-				return
-			}
-
-			// After successful DB insert, acknowledge the key message and re-publish the key.
-			if err := keyMsg.Nak(); err != nil {
-				log.Error("Error re-enqueueing key", zap.Error(err))
-			}
-
-			if err := recordsMsg.Ack(); err != nil {
-				log.Error("Error acknowledging records being signed", zap.Error(err))
+	if addr := config.SigningMetricsAddr(); addr != "" {
+		go func() {
+			if err := metrics.ListenAndServe(addr); err != nil {
+				log.Error("Error serving metrics", zap.Error(err))
 			}
+		}()
+		log.Info("Serving metrics", zap.String("addr", addr))
+	}
 
-			// Update the counter.
-			atomic.AddInt64(&totalSignedRecords, int64(len(records)))
-			log.Info("Batch processed", zap.Int64("totalRecordsSigned", atomic.LoadInt64(&totalSignedRecords)))
-		}(recordsMsg, keyMsg, records, key)
+	// Keys are leased from per-tenant pools; batches block until a key is
+	// free, sign requests give up sooner.
+	pools := newKeyPools(jetstreamClient)
+	leaseBatchKey := pools.leaser(batchKeyLeaseWait)
 
-		batchesEnqueuedSoFar++
+	// Serve synchronous sign requests alongside the batch pipeline.
+	onDemand := newOnDemandSigner(dbClient, jetstreamClient, backend, tsaClient, pools.leaser(keyLeaseWait), limits, config.SignerMaxConcurrency())
+	signRequestSub, err := onDemand.serve(natsConn, config.SignRequestSubject())
+	if err != nil {
+		log.Fatal("Error subscribing to sign requests", zap.Error(err))
 	}
-
-	// Wait for all processing goroutines to complete.
-	procWG.Wait()
+	log.Info("Serving sign requests", zap.String("subject", config.SignRequestSubject()))
+
+	// Sign batches until every record is signed. Replicas share the
+	// consumers, so completion is read from the database rather than counted.
+	pipeline := &batchPipeline{
+		db:          dbClient,
+		js:          jetstreamClient,
+		backend:     backend,
+		tsaClient:   tsaClient,
+		sched:       sched,
+		leaseKey:    leaseBatchKey,
+		signingMode: signingMode,
+	}
+	pipelineCtx, stopPipeline := context.WithCancel(ctx)
+	go func() {
+		if err := waitAllSigned(pipelineCtx, dbClient, config.TotalRecords(), completionPollInterval); err == nil {
+			stopPipeline()
+		}
+	}()
+	pipeline.run(pipelineCtx)
+	stopPipeline()
 
 	elapsedTime := time.Since(startTime)
-	log.Info("Signing service completed signing all records", zap.Int64("totalSigned", atomic.LoadInt64(&totalSignedRecords)), zap.Duration("elapsed", elapsedTime))
+	log.Info("Signing service completed signing all records", zap.Int64("totalSigned", pipeline.signed.Load()), zap.Duration("elapsed", elapsedTime))
+
+	// Keep answering sign requests until asked to stop.
+	stopCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-stopCtx.Done()
+	if err := signRequestSub.Drain(); err != nil {
+		log.Error("Error draining sign requests", zap.Error(err))
+	}
 }
 
-// signRecords spawns goroutines to sign each record concurrently using the provided key.
+// signRecords spawns goroutines to sign each record concurrently using the provided signer.
 // This version pre-allocates a slice and assigns each signature by its index,
 // preserving the input order.
-func signRecords(records []types.Record, key *types.Key) ([]types.Signature, error) {
+func signRecords(ctx context.Context, records []types.Record, keySigner signer.Signer) ([]types.Signature, error) {
 	sigs := make([]types.Signature, len(records))
-	var wg sync.WaitGroup
+	eg, ctx := errgroup.WithContext(ctx)
 	for i, rec := range records {
-		wg.Add(1)
-		go func(i int, r types.Record) {
-			defer wg.Done()
-			sigs[i] = signRecord(r, key)
-		}(i, rec)
+		eg.Go(func() error {
+			sig, err := signRecord(ctx, rec, keySigner)
+			if err != nil {
+				return err
+			}
+			sigs[i] = sig
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
-	wg.Wait()
 	return sigs, nil
 }
 
-// signRecord simulates the creation of a signature by hashing the record ID and key value.
-func signRecord(record types.Record, key *types.Key) types.Signature {
-	data := fmt.Sprintf("%d:%s", record.ID, key.Value)
-	hash := sha256.Sum256([]byte(data))
-	signatureValue := base64.StdEncoding.EncodeToString(hash[:])
-	return types.Signature{
-		RecordID: record.ID,
-		KeyID:    key.ID,
-		Value:    signatureValue,
+// signRecord signs the record's JWS signing input, so the stored signature can be
+// exported as a standard JWS or CMS structure. The private key stays inside the
+// signer backend; only the resulting signature is returned.
+func signRecord(ctx context.Context, record types.Record, keySigner signer.Signer) (types.Signature, error) {
+	alg := keySigner.Algorithm()
+	sig, err := keySigner.Sign(ctx, alg.Digest(signer.SigningInput(alg, keySigner.KeyID(), record)))
+	if err != nil {
+		return types.Signature{}, fmt.Errorf("failed signing record %d: %w", record.ID, err)
 	}
+	return types.Signature{
+		TenantID:  record.TenantID,
+		RecordID:  record.ID,
+		KeyID:     keySigner.KeyID(),
+		Algorithm: string(alg),
+		Value:     base64.StdEncoding.EncodeToString(sig),
+	}, nil
 }
 
-// insertSignatures performs a bulk insert of signatures using the ent ORM client.
-func insertSignatures(ctx context.Context, client *database.Client, sigs []types.Signature) error {
-	log.Info("Inserting batch of signatures into the DB", zap.Int("InsertedSignaturesBatchSize", len(sigs)))
+// signBatch signs a single Merkle tree head over the records' leaves instead of
+// every record, and gives each record the inclusion proof that ties it to the
+// signed head. The signing cost no longer grows with the batch size.
+func signBatch(ctx context.Context, records []types.Record, keySigner signer.Signer) ([]types.Signature, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+	leaves := make([][]byte, len(records))
+	for i, record := range records {
+		leaves[i] = signer.MerkleLeaf(record)
+	}
+	tree := signer.NewMerkleTree(leaves)
+	root := tree.Root()
 
-	const (
-		chunkSize   = 10000 // maximum number of rows per bulk insert
-		maxParallel = 2     // maximum number of concurrent bulk inserts
-	)
+	alg := keySigner.Algorithm()
+	sig, err := keySigner.Sign(ctx, alg.Digest(signer.MerkleSigningInput(alg, keySigner.KeyID(), root, tree.Size())))
+	if err != nil {
+		return nil, fmt.Errorf("failed signing batch of %d records: %w", len(records), err)
+	}
 
-	eg, ctx := errgroup.WithContext(ctx)
-	eg.SetLimit(maxParallel)
+	value := base64.StdEncoding.EncodeToString(sig)
+	encodedRoot := base64.StdEncoding.EncodeToString(root)
+	sigs := make([]types.Signature, len(records))
+	for i, record := range records {
+		proof := tree.Proof(i)
+		path := make([]string, len(proof))
+		for j, hash := range proof {
+			path[j] = base64.StdEncoding.EncodeToString(hash)
+		}
+		sigs[i] = types.Signature{
+			TenantID:  record.TenantID,
+			RecordID:  record.ID,
+			KeyID:     keySigner.KeyID(),
+			Algorithm: string(alg),
+			Value:     value,
+			MerkleProof: &types.MerkleProof{
+				Root:      encodedRoot,
+				LeafIndex: i,
+				TreeSize:  tree.Size(),
+				Path:      path,
+			},
+		}
+	}
+	return sigs, nil
+}
 
-	n := len(sigs)
-	numChunks := (n + chunkSize - 1) / chunkSize
+// timestampSignatures attaches an RFC 3161 token over each raw signature value,
+// the same imprint a CMS signature time-stamp attribute uses. Signatures that
+// share a value, i.e. a Merkle batch, share a single token over the signed root.
+func timestampSignatures(ctx context.Context, tsaClient *tsa.Client, sigs []types.Signature) error {
+	const maxParallel = 8 // concurrent requests to the TSA
 
-	for i := range numChunks {
-		start := i * chunkSize
-		end := min(start+chunkSize, n)
-		chunk := sigs[start:end]
+	byValue := make(map[string][]int)
+	for i, sig := range sigs {
+		byValue[sig.Value] = append(byValue[sig.Value], i)
+	}
 
-		// Capture chunk in a local variable for the goroutine.
-		chunkCopy := chunk
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(maxParallel)
+	for value, indices := range byValue {
 		eg.Go(func() error {
-			bulk := make([]*database.SignatureCreate, 0, len(chunkCopy))
-			for _, sig := range chunkCopy {
-				bulk = append(bulk, client.Signature.Create().
-					SetRecordID(sig.RecordID).
-					SetKeyID(sig.KeyID).
-					SetValue(sig.Value))
+			raw, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("signature of record %d is not base64: %w", sigs[indices[0]].RecordID, err)
 			}
-			if _, err := client.Signature.CreateBulk(bulk...).Save(ctx); err != nil {
-				return fmt.Errorf("failed to bulk insert signatures: %w", err)
+			token, err := tsaClient.Timestamp(ctx, raw)
+			if err != nil {
+				return fmt.Errorf("failed timestamping signature of record %d: %w", sigs[indices[0]].RecordID, err)
+			}
+			encoded := base64.StdEncoding.EncodeToString(token)
+			for _, i := range indices {
+				sigs[i].TimestampToken = encoded
 			}
 			return nil
 		})
 	}
-
 	return eg.Wait()
 }
+
+// insertSignatures inserts a batch of signatures as one ledger entry, in a
+// single transaction so a failed batch leaves neither rows nor a dangling entry.
+func insertSignatures(ctx context.Context, client *database.Client, sigs []types.Signature) error {
+	log.Info("Inserting batch of signatures into the DB", zap.Int("InsertedSignaturesBatchSize", len(sigs)))
+
+	tx, err := client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed starting transaction: %w", err)
+	}
+	entry, err := ledger.Append(ctx, tx, sigs)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed committing signatures: %w", err)
+	}
+	log.Debug("Appended ledger entry", zap.Int("seq", entry.ID), zap.String("hash", entry.Hash))
+	return nil
+}
+
+// publisher is the part of jetstream.JetStream used to emit events.
+type publisher interface {
+	Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+// publishSignatures announces a committed batch, which belongs to a single
+// tenant, on signatures.<tenant>.<ledger seq>. The subject doubles as the
+// message ID, so a retried publish is deduplicated by the stream. Batches
+// from a records batch carry its first record ID and their timings; sign
+// requests pass 0 and nil.
+func publishSignatures(ctx context.Context, js publisher, sigs []types.Signature, batchFirstRecordID int, timings *types.BatchTimings) error {
+	if len(sigs) == 0 {
+		return nil
+	}
+	event := types.SignaturesEvent{
+		TenantID:           sigs[0].TenantID,
+		LedgerSeq:          sigs[0].LedgerSeq,
+		Signatures:         sigs,
+		CommittedAt:        sigs[0].InsertedAt,
+		Timings:            timings,
+		BatchFirstRecordID: batchFirstRecordID,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed marshaling signatures event: %w", err)
+	}
+	subject := fmt.Sprintf("signatures.%s.%d", event.TenantID, event.LedgerSeq)
+	if _, err := js.Publish(ctx, subject, data, jetstream.WithMsgID(subject)); err != nil {
+		return fmt.Errorf("failed publishing %s: %w", subject, err)
+	}
+	return nil
+}
@@ -3,43 +3,90 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
+	"crypto/x509"
 	"encoding/base64"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/ledger"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/tsa"
 	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 // ----------------------------
 // Tests for Signing Logic
 // ----------------------------
 
-// TestSignRecord verifies that signRecord produces the expected signature.
-func TestSignRecord(t *testing.T) {
-	// Arrange: set up a record and a key.
-	rec := types.Record{ID: 1}
-	key := types.Key{ID: 10, Value: "secret-key"}
-
-	// Act: sign the record.
-	sig := signRecord(rec, &key)
-
-	// Compute expected signature value.
-	data := fmt.Sprintf("%d:%s", rec.ID, key.Value)
-	hash := sha256.Sum256([]byte(data))
-	expectedValue := base64.StdEncoding.EncodeToString(hash[:])
+// newTestSigner generates an in-memory key for signing tests.
+func newTestSigner(t *testing.T, keyID int, alg signer.Algorithm) signer.Signer {
+	t.Helper()
+	backend := signer.NewMemoryBackend()
+	key, err := backend.GenerateKey(context.Background(), keyID, alg)
+	if err != nil {
+		t.Fatalf("failed generating test key: %v", err)
+	}
+	keySigner, err := backend.Signer(context.Background(), key)
+	if err != nil {
+		t.Fatalf("failed loading test signer: %v", err)
+	}
+	return keySigner
+}
 
-	// Assert: verify that all fields match.
-	if sig.RecordID != rec.ID {
-		t.Errorf("Expected RecordID %d, got %d", rec.ID, sig.RecordID)
+// verifySignature checks a signature produced by signRecord or signBatch against
+// the signer's public key.
+func verifySignature(t *testing.T, keySigner signer.Signer, rec types.Record, sig types.Signature) bool {
+	t.Helper()
+	raw, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil {
+		t.Fatalf("signature value is not base64: %v", err)
 	}
-	if sig.KeyID != key.ID {
-		t.Errorf("Expected KeyID %d, got %d", key.ID, sig.KeyID)
+	alg, err := signer.ParseAlgorithm(sig.Algorithm)
+	if err != nil {
+		t.Fatalf("signature has an invalid algorithm: %v", err)
 	}
-	if sig.Value != expectedValue {
-		t.Errorf("Expected signature value %q, got %q", expectedValue, sig.Value)
+	input, err := signer.SignatureSigningInput(alg, rec, sig)
+	if err != nil {
+		return false
+	}
+	return signer.Verify(alg, keySigner.Public(), alg.Digest(input), raw) == nil
+}
+
+// TestSignRecord verifies that signRecord produces a valid signature for the
+// record with every supported algorithm.
+func TestSignRecord(t *testing.T) {
+	for _, alg := range []signer.Algorithm{signer.ES256, signer.ES384, signer.EdDSA, signer.PS256} {
+		t.Run(string(alg), func(t *testing.T) {
+			// Arrange: set up a record and a key.
+			rec := types.Record{ID: 1}
+			keySigner := newTestSigner(t, 10, alg)
+
+			// Act: sign the record.
+			sig, err := signRecord(context.Background(), rec, keySigner)
+			if err != nil {
+				t.Fatalf("signRecord returned an unexpected error: %v", err)
+			}
+
+			// Assert: verify that all fields match.
+			if sig.RecordID != rec.ID {
+				t.Errorf("Expected RecordID %d, got %d", rec.ID, sig.RecordID)
+			}
+			if sig.KeyID != keySigner.KeyID() {
+				t.Errorf("Expected KeyID %d, got %d", keySigner.KeyID(), sig.KeyID)
+			}
+			if sig.Algorithm != string(alg) {
+				t.Errorf("Expected Algorithm %q, got %q", alg, sig.Algorithm)
+			}
+			if !verifySignature(t, keySigner, rec, sig) {
+				t.Errorf("Signature %q does not verify for record %d", sig.Value, rec.ID)
+			}
+		})
 	}
 }
 
@@ -48,14 +95,14 @@ func TestSignRecord(t *testing.T) {
 func TestSignRecords(t *testing.T) {
 	// Arrange: create several records.
 	records := []types.Record{
-		{ID: 1},
-		{ID: 2},
-		{ID: 3},
+		{ID: 1, TenantID: "acme"},
+		{ID: 2, TenantID: "acme"},
+		{ID: 3, TenantID: "acme"},
 	}
-	key := types.Key{ID: 5, Value: "test-key"}
+	keySigner := newTestSigner(t, 5, signer.ES256)
 
 	// Act: sign all records.
-	sigs, err := signRecords(records, &key)
+	sigs, err := signRecords(context.Background(), records, keySigner)
 	if err != nil {
 		t.Fatalf("signRecords returned an unexpected error: %v", err)
 	}
@@ -65,31 +112,179 @@ func TestSignRecords(t *testing.T) {
 		t.Errorf("Expected %d signatures, got %d", len(records), len(sigs))
 	}
 
-	// Check each signature is as expected and in the same order as the input records.
+	// Check each signature verifies and is in the same order as the input records.
+	for i, rec := range records {
+		if sigs[i].RecordID != rec.ID || sigs[i].TenantID != rec.TenantID || sigs[i].KeyID != keySigner.KeyID() {
+			t.Errorf("Signature mismatch for record %d, got %+v", rec.ID, sigs[i])
+		}
+		if !verifySignature(t, keySigner, rec, sigs[i]) {
+			t.Errorf("Signature for record %d does not verify", rec.ID)
+		}
+	}
+}
+
+// TestSignBatch verifies that signBatch signs one Merkle root for the whole
+// batch and that every record verifies through its inclusion proof.
+func TestSignBatch(t *testing.T) {
+	records := []types.Record{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	keySigner := newTestSigner(t, 7, signer.ES256)
+
+	sigs, err := signBatch(context.Background(), records, keySigner)
+	if err != nil {
+		t.Fatalf("signBatch returned an unexpected error: %v", err)
+	}
+	if len(sigs) != len(records) {
+		t.Fatalf("Expected %d signatures, got %d", len(records), len(sigs))
+	}
+
 	for i, rec := range records {
-		expected := signRecord(rec, &key)
-		if sigs[i].RecordID != expected.RecordID ||
-			sigs[i].KeyID != expected.KeyID ||
-			sigs[i].Value != expected.Value {
-			t.Errorf("Signature mismatch for record %d. Expected %+v, got %+v", rec.ID, expected, sigs[i])
+		if sigs[i].RecordID != rec.ID || sigs[i].KeyID != keySigner.KeyID() {
+			t.Errorf("Signature mismatch for record %d, got %+v", rec.ID, sigs[i])
+		}
+		if sigs[i].Value != sigs[0].Value {
+			t.Errorf("Record %d has its own signature; the batch should share one", rec.ID)
+		}
+		if sigs[i].MerkleProof == nil || sigs[i].MerkleProof.TreeSize != len(records) {
+			t.Fatalf("Record %d has proof %+v, want one for a tree of %d", rec.ID, sigs[i].MerkleProof, len(records))
+		}
+		if !verifySignature(t, keySigner, rec, sigs[i]) {
+			t.Errorf("Signature for record %d does not verify", rec.ID)
+		}
+		if verifySignature(t, keySigner, records[(i+1)%len(records)], sigs[i]) {
+			t.Errorf("Proof of record %d verifies for record %d", rec.ID, records[(i+1)%len(records)].ID)
+		}
+	}
+
+	if sigs, err := signBatch(context.Background(), nil, keySigner); err != nil || len(sigs) != 0 {
+		t.Errorf("signBatch(nil) = %v, %v; want no signatures", sigs, err)
+	}
+}
+
+// TestTimestampSignatures verifies that every signature gets a time-stamp token
+// over its raw value from the bundled local TSA.
+func TestTimestampSignatures(t *testing.T) {
+	tsaServer, err := tsa.NewLocalServer()
+	if err != nil {
+		t.Fatalf("tsa.NewLocalServer() unexpected error: %v", err)
+	}
+	httpServer := httptest.NewServer(tsaServer)
+	defer httpServer.Close()
+
+	records := []types.Record{{ID: 1}, {ID: 2}}
+	sigs, err := signRecords(context.Background(), records, newTestSigner(t, 5, signer.ES256))
+	if err != nil {
+		t.Fatalf("signRecords returned an unexpected error: %v", err)
+	}
+
+	if err := timestampSignatures(context.Background(), tsa.NewClient(httpServer.URL), sigs); err != nil {
+		t.Fatalf("timestampSignatures returned an unexpected error: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(tsaServer.Certificate())
+	verifyTokens(t, sigs, roots)
+
+	// A Merkle batch shares one signature value and therefore one token.
+	batch, err := signBatch(context.Background(), records, newTestSigner(t, 6, signer.EdDSA))
+	if err != nil {
+		t.Fatalf("signBatch returned an unexpected error: %v", err)
+	}
+	if err := timestampSignatures(context.Background(), tsa.NewClient(httpServer.URL), batch); err != nil {
+		t.Fatalf("timestampSignatures returned an unexpected error: %v", err)
+	}
+	verifyTokens(t, batch, roots)
+	if batch[0].TimestampToken != batch[1].TimestampToken {
+		t.Errorf("Merkle batch signatures got different timestamp tokens")
+	}
+}
+
+// verifyTokens checks every signature's time-stamp token against its raw value.
+func verifyTokens(t *testing.T, sigs []types.Signature, roots *x509.CertPool) {
+	t.Helper()
+	for _, sig := range sigs {
+		token, err := base64.StdEncoding.DecodeString(sig.TimestampToken)
+		if err != nil {
+			t.Fatalf("timestamp token of record %d is not base64: %v", sig.RecordID, err)
+		}
+		raw, _ := base64.StdEncoding.DecodeString(sig.Value)
+		if _, err := tsa.Verify(token, raw, roots); err != nil {
+			t.Errorf("timestamp token of record %d does not verify: %v", sig.RecordID, err)
 		}
 	}
 }
 
+// fakePublisher records published messages instead of sending them to NATS.
+type fakePublisher struct {
+	mu       sync.Mutex
+	subjects []string
+	messages [][]byte
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, subject string, data []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.subjects = append(p.subjects, subject)
+	p.messages = append(p.messages, data)
+	return &jetstream.PubAck{Stream: "test"}, nil
+}
+
+// TestPublishSignatures verifies a committed batch is announced as one event
+// on its ledger subject.
+func TestPublishSignatures(t *testing.T) {
+	committedAt := time.Now().UTC().Truncate(time.Microsecond)
+	sigs := []types.Signature{
+		{TenantID: "acme", RecordID: 1, KeyID: 10, Algorithm: "ES256", Value: "sig1", LedgerSeq: 7, InsertedAt: committedAt},
+		{TenantID: "acme", RecordID: 2, KeyID: 10, Algorithm: "ES256", Value: "sig2", LedgerSeq: 7, InsertedAt: committedAt},
+	}
+	js := &fakePublisher{}
+	timings := &types.BatchTimings{Sign: time.Millisecond, Insert: 2 * time.Millisecond}
+	if err := publishSignatures(context.Background(), js, sigs, 1, timings); err != nil {
+		t.Fatalf("publishSignatures returned an unexpected error: %v", err)
+	}
+	if len(js.subjects) != 1 || js.subjects[0] != "signatures.acme.7" {
+		t.Fatalf("Expected one event on signatures.acme.7, got subjects %v", js.subjects)
+	}
+
+	var event types.SignaturesEvent
+	if err := json.Unmarshal(js.messages[0], &event); err != nil {
+		t.Fatalf("event is not valid JSON: %v", err)
+	}
+	if event.TenantID != "acme" || event.LedgerSeq != 7 || !event.CommittedAt.Equal(committedAt) || len(event.Signatures) != 2 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if event.Signatures[1].KeyID != 10 || event.Signatures[1].Value != "sig2" {
+		t.Errorf("Unexpected event signature %+v", event.Signatures[1])
+	}
+	if event.Timings == nil || *event.Timings != *timings || event.BatchFirstRecordID != 1 {
+		t.Errorf("Unexpected event timings %+v", event.Timings)
+	}
+
+	if err := publishSignatures(context.Background(), js, nil, 0, nil); err != nil || len(js.subjects) != 1 {
+		t.Errorf("Expected no event for an empty batch, got %v and subjects %v", err, js.subjects)
+	}
+}
+
 // ----------------------------
 // Integration Tests for Bulk Insertion Using Actual Database Connection (ent)
 // ----------------------------
 
-// setupDB ensures that both the signatures and records tables are clean,
+// setupDB ensures that the signatures, ledger and records tables are clean,
 // and inserts dummy records with IDs 1, 2, and 3 so that foreign key constraints pass.
 func setupDB(t *testing.T) (*database.Client, context.Context) {
-	dbClient := database.Connect()
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		t.Skipf("Skipping integration test: %v", err)
+	}
 	ctx := context.Background()
 
-	// Clean the signatures table.
-	if _, err := dbClient.Signature.Delete().Exec(ctx); err != nil {
+	// Clean the append-only signatures and ledger tables with raw SQL, which
+	// the ent hooks do not intercept.
+	if _, err := dbClient.Exec(ctx, "DELETE FROM signatures"); err != nil {
 		t.Skipf("Skipping integration test: unable to clean signatures table: %v", err)
 	}
+	if _, err := dbClient.Exec(ctx, "DELETE FROM ledger"); err != nil {
+		t.Skipf("Skipping integration test: unable to clean ledger table: %v", err)
+	}
 
 	// Clean the records table.
 	if _, err := dbClient.Record.Delete().Exec(ctx); err != nil {
@@ -107,6 +302,11 @@ func setupDB(t *testing.T) (*database.Client, context.Context) {
 	if _, err := dbClient.Record.Create().SetID(3).Save(ctx); err != nil {
 		t.Skipf("Skipping integration test: unable to insert dummy record 3: %v", err)
 	}
+	// Records created without an ID, e.g. for sign request digests, must not
+	// collide with the dummy ones.
+	if _, err := dbClient.Exec(ctx, "SELECT setval(pg_get_serial_sequence('records', 'id'), 3)"); err != nil {
+		t.Skipf("Skipping integration test: unable to advance record IDs: %v", err)
+	}
 
 	return dbClient, ctx
 }
@@ -159,6 +359,53 @@ func TestInsertSignaturesSuccess(t *testing.T) {
 			t.Errorf("Expected signature %+v not found in DB", expected)
 		}
 	}
+
+	// The batch must be committed to by a valid ledger entry.
+	head, err := ledger.Verify(ctx, dbClient)
+	if err != nil {
+		t.Fatalf("ledger.Verify returned error: %v", err)
+	}
+	if head == nil || head.Size != len(sigs) {
+		t.Errorf("Expected a ledger head covering %d signatures, got %+v", len(sigs), head)
+	}
+}
+
+// TestSignaturesAreAppendOnly verifies that ent refuses to modify stored
+// signatures and that the ledger detects modifications made around it.
+func TestSignaturesAreAppendOnly(t *testing.T) {
+	dbClient, ctx := setupDB(t)
+	defer dbClient.Close()
+
+	sigs := []types.Signature{
+		{RecordID: 1, KeyID: 10, Value: "sig1"},
+		{RecordID: 2, KeyID: 10, Value: "sig2"},
+	}
+	if err := insertSignatures(ctx, dbClient, sigs); err != nil {
+		t.Fatalf("insertSignatures returned error: %v", err)
+	}
+	if err := insertSignatures(ctx, dbClient, []types.Signature{{RecordID: 3, KeyID: 10, Value: "sig3"}}); err != nil {
+		t.Fatalf("insertSignatures returned error: %v", err)
+	}
+
+	if _, err := dbClient.Signature.Update().SetKeyID(11).Save(ctx); !errors.Is(err, database.ErrAppendOnly) {
+		t.Errorf("Expected signature update to fail with ErrAppendOnly, got %v", err)
+	}
+	if _, err := dbClient.Signature.Delete().Exec(ctx); !errors.Is(err, database.ErrAppendOnly) {
+		t.Errorf("Expected signature delete to fail with ErrAppendOnly, got %v", err)
+	}
+	if _, err := dbClient.LedgerEntry.Delete().Exec(ctx); !errors.Is(err, database.ErrAppendOnly) {
+		t.Errorf("Expected ledger delete to fail with ErrAppendOnly, got %v", err)
+	}
+	if _, err := ledger.Verify(ctx, dbClient); err != nil {
+		t.Fatalf("ledger.Verify returned error on an untouched ledger: %v", err)
+	}
+
+	if _, err := dbClient.Exec(ctx, "UPDATE signatures SET value = 'forged' WHERE record_id = 2"); err != nil {
+		t.Fatalf("failed tampering with signatures: %v", err)
+	}
+	if _, err := ledger.Verify(ctx, dbClient); err == nil {
+		t.Errorf("Expected ledger.Verify to detect the modified signature")
+	}
 }
 
 // TestInsertSignaturesFailure simulates a failure during bulk insert by using a canceled context.
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/record"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/ledger"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/tsa"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+const (
+	signRequestQueue   = "signing-service"
+	signRequestTimeout = 10 * time.Second
+	keyLeaseWait       = 5 * time.Second
+
+	// maxDigestSize fits a SHA-512 digest, the largest one worth signing.
+	maxDigestSize = 64
+)
+
+// signRequest asks for a signature of a stored record, or of a digest that is
+// stored as the data of a new record first. Exactly one of them is set. The
+// record belongs, or is created for, the tenant of the request subject.
+type signRequest struct {
+	RecordID int    `json:"record_id,omitempty"`
+	Digest   []byte `json:"digest,omitempty"`
+}
+
+type signReply struct {
+	Record    *types.Record    `json:"record,omitempty"`
+	Signature *types.Signature `json:"signature,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// onDemandSigner serves synchronous signing requests next to the batch
+// pipeline, with the same key pools and the same persistence path.
+type onDemandSigner struct {
+	db        *database.Client
+	js        publisher
+	backend   signer.Backend
+	tsaClient *tsa.Client
+	leaseKey  leaseKeyFunc
+	limits    *rateLimits
+	slots     chan struct{}
+}
+
+func newOnDemandSigner(db *database.Client, js publisher, backend signer.Backend, tsaClient *tsa.Client, leaseKey leaseKeyFunc, limits *rateLimits, maxConcurrency int) *onDemandSigner {
+	return &onDemandSigner{
+		db:        db,
+		js:        js,
+		backend:   backend,
+		tsaClient: tsaClient,
+		leaseKey:  leaseKey,
+		limits:    limits,
+		slots:     make(chan struct{}, maxConcurrency),
+	}
+}
+
+// serve answers requests on <subject>.<tenant> in a queue group, so replicas
+// share them. The tenant is only ever taken from the subject, which NATS
+// permissions can restrict per client.
+func (s *onDemandSigner) serve(natsConn *nats.Conn, subject string) (*nats.Subscription, error) {
+	return natsConn.QueueSubscribe(subject+".*", signRequestQueue, func(msg *nats.Msg) {
+		s.slots <- struct{}{} // acquire
+		go func() {
+			defer func() { <-s.slots }() // release
+			s.handle(msg, strings.TrimPrefix(msg.Subject, subject+"."))
+		}()
+	})
+}
+
+func (s *onDemandSigner) handle(msg *nats.Msg, tenant string) {
+	ctx, cancel := context.WithTimeout(context.Background(), signRequestTimeout)
+	defer cancel()
+
+	var reply signReply
+	var req signRequest
+	if err := json.Unmarshal(msg.Data, &req); err != nil {
+		reply.Error = fmt.Sprintf("invalid sign request: %v", err)
+	} else if record, sig, err := s.sign(ctx, tenant, req); err != nil {
+		log.Error("Error signing on demand", zap.Error(err))
+		reply.Error = err.Error()
+	} else {
+		reply = signReply{Record: record, Signature: sig}
+	}
+
+	data, _ := json.Marshal(reply) // signReply always marshals.
+	if err := msg.Respond(data); err != nil {
+		log.Error("Error replying to sign request", zap.Error(err))
+	}
+}
+
+// sign resolves the request to a record, signs it with a leased key and stores
+// the signature. A digest record is created in the transaction that stores its
+// signature, so a failed request stores nothing and is simply retried.
+// Requests share their tenant's rate limit with the batch pipeline and are
+// refused, not queued, when it is exhausted.
+func (s *onDemandSigner) sign(ctx context.Context, tenant string, req signRequest) (*types.Record, *types.Signature, error) {
+	if err := types.ValidateTenant(tenant); err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if delay := s.limits.delay(tenant, now); delay > 0 {
+		return nil, nil, fmt.Errorf("tenant %s is over its rate limit, retry in %s", tenant, delay.Round(time.Millisecond))
+	}
+
+	tx, err := s.db.Tx(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed starting transaction: %w", err)
+	}
+	defer tx.Rollback() // a no-op once committed
+	record, err := s.resolve(ctx, tx.Client(), tenant, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	signed, err := tx.Signature.Query().Where(signature.RecordID(record.ID)).Exist(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed checking signature of record %d: %w", record.ID, err)
+	}
+	if signed {
+		return nil, nil, fmt.Errorf("record %d is already signed", record.ID)
+	}
+	s.limits.take(tenant, 1, now)
+
+	key, release, err := s.leaseKey(ctx, record.TenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	keySigner, err := s.backend.Signer(ctx, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed loading signer for key %d: %w", key.ID, err)
+	}
+
+	sig, err := signRecord(ctx, *record, keySigner)
+	if err != nil {
+		return nil, nil, err
+	}
+	sigs := []types.Signature{sig}
+	if s.tsaClient != nil {
+		if err := timestampSignatures(ctx, s.tsaClient, sigs); err != nil {
+			return nil, nil, err
+		}
+	}
+	entry, err := ledger.Append(ctx, tx, sigs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed committing signature: %w", err)
+	}
+	log.Debug("Appended ledger entry", zap.Int("seq", entry.ID), zap.String("hash", entry.Hash))
+	if err := publishSignatures(ctx, s.js, sigs, 0, nil); err != nil {
+		log.Error("Error publishing signatures event", zap.Error(err))
+	}
+	return record, &sigs[0], nil
+}
+
+// resolve loads or creates the record of tenant to sign through client.
+// Records of other tenants are reported as missing.
+func (s *onDemandSigner) resolve(ctx context.Context, client *database.Client, tenant string, req signRequest) (*types.Record, error) {
+	switch {
+	case req.RecordID != 0 && req.Digest != nil:
+		return nil, errors.New("sign request must set either record_id or digest, not both")
+	case req.RecordID != 0:
+		rec, err := client.Record.Query().
+			Where(record.ID(req.RecordID), record.TenantID(tenant)).
+			Only(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading record %d of tenant %s: %w", req.RecordID, tenant, err)
+		}
+		return &types.Record{ID: rec.ID, TenantID: rec.TenantID, InsertedAt: rec.InsertedAt, Data: rec.Data}, nil
+	case len(req.Digest) > 0 && len(req.Digest) <= maxDigestSize:
+		// inserted_at is signed, so it must already have the microsecond
+		// precision PostgreSQL stores.
+		rec, err := client.Record.Create().
+			SetTenantID(tenant).
+			SetInsertedAt(time.Now().Truncate(time.Microsecond)).
+			SetData(req.Digest).
+			Save(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed storing digest record: %w", err)
+		}
+		return &types.Record{ID: rec.ID, TenantID: rec.TenantID, InsertedAt: rec.InsertedAt, Data: rec.Data}, nil
+	case req.Digest != nil:
+		return nil, fmt.Errorf("digest must be between 1 and %d bytes", maxDigestSize)
+	default:
+		return nil, errors.New("sign request must set record_id or digest")
+	}
+}
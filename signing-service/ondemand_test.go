@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+)
+
+// testKeyLeaser leases a single in-memory key of the default tenant, whose
+// signer it also returns, and counts releases.
+func testKeyLeaser(t *testing.T, backend *signer.MemoryBackend, released *int) (leaseKeyFunc, signer.Signer) {
+	t.Helper()
+	key, err := backend.GenerateKey(context.Background(), 10, signer.ES256)
+	if err != nil {
+		t.Fatalf("failed generating test key: %v", err)
+	}
+	keySigner, err := backend.Signer(context.Background(), key)
+	if err != nil {
+		t.Fatalf("failed loading test signer: %v", err)
+	}
+	key.TenantID = types.DefaultTenant
+	return func(ctx context.Context, tenant string) (*types.Key, func(), error) {
+		if tenant != key.TenantID {
+			return nil, nil, fmt.Errorf("no key of tenant %s", tenant)
+		}
+		return key, func() { *released++ }, nil
+	}, keySigner
+}
+
+// TestResolveRejectsInvalidRequests covers requests refused before the database is touched.
+func TestResolveRejectsInvalidRequests(t *testing.T) {
+	s := newOnDemandSigner(nil, nil, signer.NewMemoryBackend(), nil, nil, nil, 1)
+	tests := []struct {
+		name string
+		req  signRequest
+	}{
+		{name: "empty", req: signRequest{}},
+		{name: "record and digest", req: signRequest{RecordID: 1, Digest: make([]byte, 32)}},
+		{name: "empty digest", req: signRequest{Digest: []byte{}}},
+		{name: "oversized digest", req: signRequest{Digest: make([]byte, maxDigestSize+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.resolve(context.Background(), nil, types.DefaultTenant, tt.req); err == nil {
+				t.Errorf("resolve(%+v) should fail", tt.req)
+			}
+		})
+	}
+}
+
+// TestSignOnDemand signs a stored record and a digest through the on-demand
+// path and checks the stored signatures.
+func TestSignOnDemand(t *testing.T) {
+	dbClient, ctx := setupDB(t)
+	defer dbClient.Close()
+
+	backend := signer.NewMemoryBackend()
+	released := 0
+	leaseKey, keySigner := testKeyLeaser(t, backend, &released)
+	js := &fakePublisher{}
+	s := newOnDemandSigner(dbClient, js, backend, nil, leaseKey, nil, 1)
+
+	record, sig, err := s.sign(ctx, types.DefaultTenant, signRequest{RecordID: 1})
+	if err != nil {
+		t.Fatalf("sign(record_id) returned an unexpected error: %v", err)
+	}
+	if record.ID != 1 || sig.RecordID != 1 || !verifySignature(t, keySigner, *record, *sig) {
+		t.Errorf("sign(record_id) = %+v, %+v, want a valid signature of record 1", record, sig)
+	}
+	if sig.LedgerSeq == 0 {
+		t.Errorf("sign(record_id) signature was not appended to the ledger")
+	}
+
+	if _, _, err := s.sign(ctx, types.DefaultTenant, signRequest{RecordID: 1}); err == nil || !strings.Contains(err.Error(), "already signed") {
+		t.Errorf("signing record 1 twice: got %v, want an already signed error", err)
+	}
+
+	digest := make([]byte, 32)
+	record, sig, err = s.sign(ctx, types.DefaultTenant, signRequest{Digest: digest})
+	if err != nil {
+		t.Fatalf("sign(digest) returned an unexpected error: %v", err)
+	}
+	if len(record.Data) != len(digest) || !verifySignature(t, keySigner, *record, *sig) {
+		t.Errorf("sign(digest) = %+v, %+v, want a valid signature of the digest record", record, sig)
+	}
+	stored, err := dbClient.Record.Get(ctx, record.ID)
+	if err != nil || !stored.InsertedAt.Equal(record.InsertedAt) {
+		t.Errorf("digest record was not stored as signed: %+v, %v", stored, err)
+	}
+
+	// Records of other tenants are not found, and their digests are signed
+	// with their own tenant's keys only.
+	if _, _, err := s.sign(ctx, "acme", signRequest{RecordID: 2}); !database.IsNotFound(err) {
+		t.Errorf("signing record 2 for tenant acme: got %v, want not found", err)
+	}
+	records, err := dbClient.Record.Query().Count(ctx)
+	if err != nil {
+		t.Fatalf("failed counting records: %v", err)
+	}
+	if _, _, err := s.sign(ctx, "acme", signRequest{Digest: digest}); err == nil || !strings.Contains(err.Error(), "no key of tenant acme") {
+		t.Errorf("signing an acme digest: got %v, want no acme key", err)
+	}
+	// The digest record of a failed request is rolled back with it.
+	if n, err := dbClient.Record.Query().Count(ctx); err != nil || n != records {
+		t.Errorf("records after a failed digest sign = %d, %v, want %d", n, err, records)
+	}
+
+	if released != 2 {
+		t.Errorf("keys released = %d, want 2", released)
+	}
+	if len(js.subjects) != 2 {
+		t.Errorf("signatures events = %v, want one per signed request", js.subjects)
+	}
+}
+
+// TestSignOnDemandWithoutKey verifies a lease failure is reported and nothing is stored.
+func TestSignOnDemandWithoutKey(t *testing.T) {
+	dbClient, ctx := setupDB(t)
+	defer dbClient.Close()
+
+	errNoKey := errors.New("no free key")
+	s := newOnDemandSigner(dbClient, &fakePublisher{}, signer.NewMemoryBackend(), nil, func(context.Context, string) (*types.Key, func(), error) {
+		return nil, nil, errNoKey
+	}, nil, 1)
+	if _, _, err := s.sign(ctx, types.DefaultTenant, signRequest{RecordID: 2}); !errors.Is(err, errNoKey) {
+		t.Errorf("sign() error = %v, want %v", err, errNoKey)
+	}
+	if n, err := dbClient.Signature.Query().Count(ctx); err != nil || n != 0 {
+		t.Errorf("signatures after failed sign = %d, %v, want 0", n, err)
+	}
+}
+
+// TestSignOnDemandOverRateLimit verifies requests of a tenant over its rate
+// limit are refused before anything is loaded or stored.
+func TestSignOnDemandOverRateLimit(t *testing.T) {
+	limits := newRateLimits(map[string]float64{"acme": 1}, time.Now())
+	limits.take("acme", 1, time.Now())
+	s := newOnDemandSigner(nil, nil, signer.NewMemoryBackend(), nil, nil, limits, 1)
+	if _, _, err := s.sign(context.Background(), "acme", signRequest{Digest: make([]byte, 32)}); err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("sign() over the rate limit: got %v, want a rate limit error", err)
+	}
+}
+
+// TestSignOnDemandRejectsInvalidTenant verifies the tenant of the request
+// subject is validated before anything is loaded or stored.
+func TestSignOnDemandRejectsInvalidTenant(t *testing.T) {
+	s := newOnDemandSigner(nil, nil, signer.NewMemoryBackend(), nil, nil, nil, 1)
+	for _, tenant := range []string{"", "acme.*", "acme>"} {
+		if _, _, err := s.sign(context.Background(), tenant, signRequest{Digest: make([]byte, 32)}); err == nil {
+			t.Errorf("sign() for tenant %q should fail", tenant)
+		}
+	}
+}
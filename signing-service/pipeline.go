@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/record"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/tsa"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+const (
+	// leaseAckWait is how long JetStream waits on a records or key message
+	// before handing it to another replica; messages being worked on are
+	// acked in progress every inProgressInterval.
+	leaseAckWait       = 30 * time.Second
+	inProgressInterval = 10 * time.Second
+
+	batchTimeout           = 10 * time.Second
+	completionPollInterval = 2 * time.Second
+)
+
+// batchPipeline signs the batches its scheduler hands out. Any number of
+// signing-service replicas can run one against the same stream: the durable
+// consumers hand every records and key message to a single replica at a
+// time, and a batch redelivered after another replica committed it is only
+// acknowledged.
+type batchPipeline struct {
+	db          *database.Client
+	js          publisher
+	backend     signer.Backend
+	tsaClient   *tsa.Client
+	sched       *scheduler
+	leaseKey    leaseKeyFunc
+	signingMode string
+
+	signed atomic.Int64 // records signed by this replica
+	wg     sync.WaitGroup
+}
+
+// run signs batches until ctx is done, then waits for those in flight.
+func (p *batchPipeline) run(ctx context.Context) {
+	go p.sched.holdHeads(ctx)
+	for {
+		// Take the next batch due (blocking until one is)
+		batch, err := p.sched.next(ctx)
+		if err != nil {
+			break
+		}
+		log.Debug("Fetched records", zap.Int("RecordsBatchSize", len(batch.records)), zap.String("tenant", batch.tenant), zap.String("priority", batch.priority))
+		stopProgress := keepInProgress(batch.msg)
+
+		// Lease one free key of the batch's tenant (blocking until available)
+		key, releaseKey, err := p.leaseKey(ctx, batch.tenant)
+		if err != nil {
+			log.Error("Error leasing key", zap.String("tenant", batch.tenant), zap.Error(err))
+			stopProgress()
+			batch.msg.Nak()
+			continue
+		}
+		log.Debug("Fetched free key", zap.Int("keyID", key.ID), zap.String("tenant", batch.tenant))
+
+		keySigner, err := p.backend.Signer(ctx, key)
+		if err != nil {
+			log.Error("Error loading signer for key", zap.Int("keyID", key.ID), zap.Error(err))
+			releaseKey()
+			stopProgress()
+			batch.msg.Nak()
+			continue
+		}
+
+		// Sign and insert in the background while the next batch is leased.
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			defer stopProgress()
+			defer releaseKey()
+			p.process(batch, keySigner)
+		}()
+	}
+	p.wg.Wait()
+}
+
+// process signs the batch's unsigned records, commits them and acks the
+// batch. On failure the batch is not acked, so it is redelivered.
+func (p *batchPipeline) process(batch *recordsBatch, keySigner signer.Signer) {
+	ctx, cancel := context.WithTimeout(context.Background(), batchTimeout)
+	defer cancel()
+
+	records, err := unsignedRecords(ctx, p.db, batch.records)
+	if err != nil {
+		log.Error("Error checking records being signed", zap.Error(err))
+		return
+	}
+	if len(records) > 0 {
+		// Sign each record concurrently using the key, or the whole batch at once.
+		signStart := time.Now()
+		var signatures []types.Signature
+		if p.signingMode == signingModeMerkle {
+			signatures, err = signBatch(ctx, records, keySigner)
+		} else {
+			signatures, err = signRecords(ctx, records, keySigner)
+		}
+		if err != nil {
+			log.Error("Error signing records", zap.Error(err))
+			return // Do not ack; message will be re-delivered.
+		}
+
+		if p.tsaClient != nil {
+			if err := timestampSignatures(ctx, p.tsaClient, signatures); err != nil {
+				log.Error("Error timestamping signatures", zap.Error(err))
+				return
+			}
+		}
+
+		// Bulk insert signatures into the database. Should another replica
+		// commit some of the records first, the unique record_id fails the
+		// insert, and the redelivered batch signs only what is left.
+		insertStart := time.Now()
+		if err := insertSignatures(ctx, p.db, signatures); err != nil {
+			log.Error("Error inserting signatures", zap.Error(err))
+			return
+		}
+		timings := &types.BatchTimings{Sign: insertStart.Sub(signStart), Insert: time.Since(insertStart)}
+
+		// The batch is committed either way; a lost event is only logged.
+		if err := publishSignatures(ctx, p.js, signatures, batch.records[0].ID, timings); err != nil {
+			log.Error("Error publishing signatures event", zap.Error(err))
+		}
+	}
+
+	if err := batch.msg.Ack(); err != nil {
+		log.Error("Error acknowledging records being signed", zap.Error(err))
+	}
+	// A stored batch left behind expires with the claim check bucket's TTL.
+	if err := recordbatch.Release(ctx, batch.msg.Headers(), p.sched.claims); err != nil {
+		log.Warn("Error releasing claim checked records", zap.String("subject", batch.msg.Subject()), zap.Error(err))
+	}
+
+	signed := p.signed.Add(int64(len(records)))
+	log.Info("Batch processed", zap.Int64("totalRecordsSigned", signed), zap.Int("alreadySigned", len(batch.records)-len(records)))
+}
+
+// unsignedRecords returns the records that have no signature yet, in order.
+func unsignedRecords(ctx context.Context, db *database.Client, records []types.Record) ([]types.Record, error) {
+	ids := make([]int, len(records))
+	for i, r := range records {
+		ids[i] = r.ID
+	}
+	signedIDs, err := db.Signature.Query().
+		Where(signature.RecordIDIn(ids...)).
+		Select(signature.FieldRecordID).
+		Ints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading signatures of %d records: %w", len(records), err)
+	}
+	signed := make(map[int]bool, len(signedIDs))
+	for _, id := range signedIDs {
+		signed[id] = true
+	}
+	unsigned := make([]types.Record, 0, len(records))
+	for _, r := range records {
+		if !signed[r.ID] {
+			unsigned = append(unsigned, r)
+		}
+	}
+	return unsigned, nil
+}
+
+// keepInProgress acks msg in progress every inProgressInterval until stop is
+// called, so JetStream does not hand it to another replica meanwhile.
+func keepInProgress(msg jetstream.Msg) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(inProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := msg.InProgress(); err != nil {
+					log.Warn("Error extending message lease", zap.String("subject", msg.Subject()), zap.Error(err))
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
+
+// waitAllSigned returns once at least total records exist and every one of
+// them is signed, by whichever replica, polling the database every interval.
+// It only fails when ctx is done.
+func waitAllSigned(ctx context.Context, db *database.Client, total int, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done, err := allSigned(ctx, db, total)
+		if err != nil {
+			log.Error("Error checking signing progress", zap.Error(err))
+		} else if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func allSigned(ctx context.Context, db *database.Client, total int) (bool, error) {
+	count, err := db.Record.Query().Count(ctx)
+	if err != nil || count < total {
+		return false, err
+	}
+	pending, err := db.Record.Query().Where(record.Not(record.HasSignature())).Exist(ctx)
+	return err == nil && !pending, err
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/signature"
+	"github.com/jurshsmith/vaultstream/ledger"
+	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/signer"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// TestReplicasSignEveryRecordOnce runs several pipelines, each with its own
+// NATS connection, scheduler and key pools, against one stream and checks
+// every record is signed exactly once.
+func TestReplicasSignEveryRecordOnce(t *testing.T) {
+	if os.Getenv("VAULTSTREAM_NATS_URL") == "" {
+		t.Skip("Skipping integration test: VAULTSTREAM_NATS_URL not set")
+	}
+	dbClient, ctx := setupDB(t)
+	defer dbClient.Close()
+	if _, err := dbClient.Record.Delete().Exec(ctx); err != nil {
+		t.Fatalf("failed cleaning records: %v", err)
+	}
+
+	const (
+		replicas  = 3
+		batches   = 12
+		batchSize = 5
+		keys      = 2
+		total     = batches * batchSize
+	)
+	// A fresh tenant gets fresh durable consumers on the shared stream.
+	tenant := fmt.Sprintf("replicas%d", time.Now().UnixNano())
+	js, conn, err := nats.Connect("signing-service")
+	if err != nil {
+		t.Fatalf("Failed connecting to NATS: %v", err)
+	}
+	defer conn.Close()
+	t.Cleanup(func() { cleanupTenant(t, js, tenant) })
+
+	backend := signer.NewMemoryBackend()
+	for id := 1; id <= keys; id++ {
+		key, err := backend.GenerateKey(ctx, id, signer.ES256)
+		if err != nil {
+			t.Fatalf("failed generating key %d: %v", id, err)
+		}
+		key.TenantID = tenant
+		data, _ := json.Marshal(key)
+		if _, err := js.Publish(ctx, fmt.Sprintf("keys.%s.%d", tenant, id), data); err != nil {
+			t.Fatalf("failed publishing key %d: %v", id, err)
+		}
+	}
+
+	insertedAt := time.Now().Truncate(time.Microsecond)
+	for batchID := 1; batchID <= batches; batchID++ {
+		dbRecords, err := dbClient.Record.MapCreateBulk(make([]struct{}, batchSize), func(c *database.RecordCreate, _ int) {
+			c.SetTenantID(tenant).SetInsertedAt(insertedAt)
+		}).Save(ctx)
+		if err != nil {
+			t.Fatalf("failed inserting records of batch %d: %v", batchID, err)
+		}
+		records := make([]types.Record, len(dbRecords))
+		for i, r := range dbRecords {
+			records[i] = types.Record{ID: r.ID, TenantID: r.TenantID, InsertedAt: r.InsertedAt}
+		}
+		data, _ := json.Marshal(records)
+		priority := types.Priorities[batchID%len(types.Priorities)]
+		if _, err := js.Publish(ctx, fmt.Sprintf("records.%s.%s.%d", tenant, priority, batchID), data); err != nil {
+			t.Fatalf("failed publishing batch %d: %v", batchID, err)
+		}
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+	var wg sync.WaitGroup
+	pipelines := make([]*batchPipeline, replicas)
+	for i := range pipelines {
+		replicaJS, replicaConn, err := nats.Connect("signing-service")
+		if err != nil {
+			t.Fatalf("replica %d: %v", i, err)
+		}
+		defer replicaConn.Close()
+		lanes, err := recordsLanes(runCtx, replicaJS, []string{tenant}, nil)
+		if err != nil {
+			t.Fatalf("replica %d: %v", i, err)
+		}
+		pipelines[i] = &batchPipeline{
+			db:          dbClient,
+			js:          replicaJS,
+			backend:     backend,
+			sched:       newScheduler(lanes, nil, 1),
+			leaseKey:    newKeyPools(replicaJS).leaser(batchKeyLeaseWait),
+			signingMode: signingModeRecord,
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pipelines[i].run(runCtx)
+		}()
+	}
+
+	if err := waitAllSigned(runCtx, dbClient, total, 50*time.Millisecond); err != nil {
+		t.Fatalf("records were not all signed: %v", err)
+	}
+	cancel()
+	wg.Wait()
+
+	var signed int64
+	for _, p := range pipelines {
+		signed += p.signed.Load()
+	}
+	if signed != total {
+		t.Errorf("replicas signed %d records, want %d", signed, total)
+	}
+	if n, err := dbClient.Signature.Query().Where(signature.TenantID(tenant)).Count(ctx); err != nil || n != total {
+		t.Errorf("signatures of tenant = %d, %v; want %d", n, err, total)
+	}
+	if _, err := ledger.Verify(ctx, dbClient); err != nil {
+		t.Errorf("ledger.Verify() after concurrent replicas: %v", err)
+	}
+}
+
+// cleanupTenant removes a test tenant's consumers and messages from the streams.
+func cleanupTenant(t *testing.T, js jetstream.JetStream, tenant string) {
+	ctx := context.Background()
+	consumers := map[string]string{"signing-keys-consumer-" + tenant: config.KeysStreamName()}
+	for _, priority := range types.Priorities {
+		consumers[fmt.Sprintf("signing-records-consumer-%s-%s", tenant, priority)] = config.RecordsStreamName()
+	}
+	for name, stream := range consumers {
+		if err := js.DeleteConsumer(ctx, stream, name); err != nil {
+			t.Logf("failed deleting consumer %s: %v", name, err)
+		}
+	}
+	subjects := map[string]string{
+		config.RecordsStreamName(): "records." + tenant + ".>",
+		config.KeysStreamName():    "keys." + tenant + ".>",
+		config.EventsStreamName():  "signatures." + tenant + ".>",
+	}
+	for name, subject := range subjects {
+		stream, err := js.Stream(ctx, name)
+		if err != nil {
+			t.Logf("failed loading stream %s: %v", name, err)
+			continue
+		}
+		if err := stream.Purge(ctx, jetstream.WithPurgeSubject(subject)); err != nil {
+			t.Logf("failed purging %s: %v", subject, err)
+		}
+	}
+}
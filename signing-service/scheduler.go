@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/metrics"
+	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+const (
+	schedulerPollInterval = 100 * time.Millisecond
+	// maxIdlePollInterval caps how far polling an idle tenant queue backs off,
+	// and so how long its first batch may wait to be seen.
+	maxIdlePollInterval = time.Second
+)
+
+var (
+	queueWait        = metrics.NewHistogramVec("signing_queue_wait_seconds", metrics.DefaultBuckets...)
+	priorityWait     = metrics.NewHistogramVec("signing_priority_wait_seconds", metrics.DefaultBuckets...)
+	scheduledRecords = expvar.NewMap("signing_records_scheduled")
+)
+
+// recordsSource is the part of jetstream.Consumer the scheduler polls.
+type recordsSource interface {
+	FetchNoWait(batch int) (jetstream.MessageBatch, error)
+}
+
+// recordsBatch is a records message of one tenant and priority.
+type recordsBatch struct {
+	tenant   string
+	priority string
+	msg      jetstream.Msg
+	records  []types.Record
+}
+
+// tenantQueue is a tenant's records consumer of one priority with its
+// scheduling state.
+type tenantQueue struct {
+	tenant string
+	source recordsSource
+	weight float64
+	// pass is the virtual time of the tenant's next batch; it advances by the
+	// batch size over the weight whenever a batch is scheduled.
+	pass float64
+	head *recordsBatch // polled but not scheduled yet
+	// idle is how long the queue waits between polls while it comes up
+	// empty, doubling up to maxIdlePollInterval; nextPoll is when it is
+	// polled again.
+	idle     time.Duration
+	nextPoll time.Time
+	polling  bool // a fetch is in flight, outside the scheduler's lock
+}
+
+// lane holds the tenant queues of one priority.
+type lane struct {
+	priority string
+	queues   []*tenantQueue
+	vtime    float64 // pass of the lane's last scheduled batch
+	skipped  int     // batches taken from other lanes in a row while this one waited
+}
+
+// scheduler hands out records batches by priority, then fairly across
+// tenants. The highest lane with a batch ready goes first, unless a lane has
+// been passed over starvationLimit times in a row, which then gets a turn.
+// Within a lane, weighted fair queuing picks the tenant with the lowest pass
+// among those with a batch waiting and within their rate limit, so over time
+// every backlogged tenant gets signatures in proportion to its weight,
+// however much the others publish.
+//
+// Polled batches wait in their queue's head until scheduled, acked in
+// progress by holdHeads meanwhile, which may run alongside next. Queues are
+// fetched from without holding the lock, so a slow fetch holds up no one
+// else.
+type scheduler struct {
+	mu              sync.Mutex // guards the lanes and their queues
+	lanes           []*lane    // highest priority first
+	limits          *rateLimits
+	starvationLimit int
+	claims          recordbatch.ObjectStore // nil when claim checks are off
+	pollInterval    time.Duration
+	now             func() time.Time
+}
+
+func newScheduler(lanes []*lane, limits *rateLimits, starvationLimit int) *scheduler {
+	return &scheduler{
+		lanes:           lanes,
+		limits:          limits,
+		starvationLimit: starvationLimit,
+		pollInterval:    schedulerPollInterval,
+		now:             time.Now,
+	}
+}
+
+// recordsLanes creates a records consumer per priority and tenant, filtered
+// on records.<tenant>.<priority>.>, weighted by weights.
+func recordsLanes(ctx context.Context, js consumerCreator, tenants []string, weights map[string]float64) ([]*lane, error) {
+	lanes := make([]*lane, len(types.Priorities))
+	for i, priority := range types.Priorities {
+		l := &lane{priority: priority, queues: make([]*tenantQueue, len(tenants))}
+		for j, tenant := range tenants {
+			consumer, err := nats.NewDurable(ctx, js, config.RecordsStreamName(), jetstream.ConsumerConfig{
+				Durable:       fmt.Sprintf("signing-records-consumer-%s-%s", tenant, priority),
+				AckPolicy:     jetstream.AckExplicitPolicy,
+				FilterSubject: fmt.Sprintf("records.%s.%s.>", tenant, priority),
+				DeliverPolicy: jetstream.DeliverAllPolicy,
+				AckWait:       leaseAckWait,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed creating %s priority records consumer of tenant %s: %w", priority, tenant, err)
+			}
+			weight := weights[tenant]
+			if weight == 0 {
+				weight = 1
+			}
+			l.queues[j] = &tenantQueue{tenant: tenant, source: consumer, weight: weight}
+		}
+		lanes[i] = l
+	}
+	return lanes, nil
+}
+
+// next blocks until a batch may be signed and returns it.
+func (s *scheduler) next(ctx context.Context) (*recordsBatch, error) {
+	for {
+		batch, wait := s.due(ctx)
+		if batch != nil {
+			return batch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// due polls the queues and schedules the batch due, if any; otherwise it
+// returns how long to wait before looking again.
+func (s *scheduler) due(ctx context.Context) (*recordsBatch, time.Duration) {
+	s.mu.Lock()
+	polls := s.pollable(s.now())
+	s.mu.Unlock()
+	batches := make([]*recordsBatch, len(polls))
+	for i, p := range polls {
+		batches[i] = s.fetch(ctx, p.lane, p.queue)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for i, p := range polls {
+		s.polled(p.lane, p.queue, batches[i], now)
+	}
+	wait := s.pollInterval
+	heads := make([]*tenantQueue, len(s.lanes))
+	picked := -1
+	for i, l := range s.lanes {
+		q, delay := s.head(l, now)
+		if delay > 0 {
+			wait = min(wait, delay)
+		}
+		if q == nil {
+			continue
+		}
+		heads[i] = q
+		if picked < 0 || l.skipped >= s.starvationLimit && s.lanes[picked].skipped < s.starvationLimit {
+			picked = i
+		}
+	}
+	if picked < 0 {
+		return nil, wait
+	}
+	for i, q := range heads {
+		if q != nil && i != picked {
+			s.lanes[i].skipped++
+		}
+	}
+	s.lanes[picked].skipped = 0
+	return s.schedule(s.lanes[picked], heads[picked], now), 0
+}
+
+// queuePoll is a queue due to be fetched from.
+type queuePoll struct {
+	lane  *lane
+	queue *tenantQueue
+}
+
+// pollable marks the queues without a head whose next poll is due as polling
+// and returns them.
+func (s *scheduler) pollable(now time.Time) []queuePoll {
+	var polls []queuePoll
+	for _, l := range s.lanes {
+		for _, q := range l.queues {
+			if q.head == nil && !q.polling && !now.Before(q.nextPoll) {
+				q.polling = true
+				polls = append(polls, queuePoll{lane: l, queue: q})
+			}
+		}
+	}
+	return polls
+}
+
+// polled merges the outcome of a poll into q: the fetched batch becomes its
+// head, while an empty poll backs the next one off. A tenant that was idle
+// rejoins at the lane's current virtual time rather than with the credit of
+// its idle period.
+func (s *scheduler) polled(l *lane, q *tenantQueue, batch *recordsBatch, now time.Time) {
+	q.polling = false
+	if batch == nil {
+		q.idle = min(max(2*q.idle, s.pollInterval), maxIdlePollInterval)
+		q.nextPoll = now.Add(q.idle)
+		return
+	}
+	q.head = batch
+	q.pass = max(q.pass, l.vtime)
+	q.idle = 0
+}
+
+// head returns the queue of the lane whose batch is due, if any, and the
+// shortest wait of the lane's rate-limited tenants.
+func (s *scheduler) head(l *lane, now time.Time) (*tenantQueue, time.Duration) {
+	var picked *tenantQueue
+	var wait time.Duration
+	for _, q := range l.queues {
+		if q.head == nil {
+			continue
+		}
+		if delay := s.limits.delay(q.tenant, now); delay > 0 {
+			if wait == 0 || delay < wait {
+				wait = delay
+			}
+			continue
+		}
+		if picked == nil || q.pass < picked.pass {
+			picked = q
+		}
+	}
+	return picked, wait
+}
+
+func (s *scheduler) schedule(l *lane, q *tenantQueue, now time.Time) *recordsBatch {
+	batch := q.head
+	q.head = nil
+	l.vtime = q.pass
+	// Empty batches still cost a turn, so they cannot jump the queue.
+	q.pass += float64(max(len(batch.records), 1)) / q.weight
+	s.limits.take(q.tenant, len(batch.records), now)
+
+	scheduledRecords.Add(q.tenant, int64(len(batch.records)))
+	if meta, err := batch.msg.Metadata(); err == nil {
+		waited := now.Sub(meta.Timestamp).Seconds()
+		queueWait.With(q.tenant).Observe(waited)
+		priorityWait.With(l.priority).Observe(waited)
+	}
+	return batch
+}
+
+// fetch fetches and decodes the next batch of a tenant without waiting, nil
+// if there is none. It only reads q's immutable fields, so it runs without
+// the lock.
+func (s *scheduler) fetch(ctx context.Context, l *lane, q *tenantQueue) *recordsBatch {
+	msgs, err := q.source.FetchNoWait(1)
+	if err != nil {
+		log.Error("Error fetching records message", zap.String("tenant", q.tenant), zap.String("priority", l.priority), zap.Error(err))
+		return nil
+	}
+	var batch *recordsBatch
+	for msg := range msgs.Messages() {
+		records, err := recordbatch.Decode(ctx, msg.Headers(), msg.Data(), s.claims)
+		if errors.Is(err, jetstream.ErrObjectNotFound) {
+			// The claim checked batch expired or was signed and released.
+			log.Error("Error loading claim checked records", zap.String("subject", msg.Subject()), zap.Error(err))
+			msg.Term()
+			continue
+		}
+		if err != nil {
+			log.Error("Error decoding records", zap.Error(err))
+			msg.Nak() // Negative acknowledgment so it can be retried
+			continue
+		}
+		if _, err := batchTenant(msg.Subject(), records); err != nil {
+			// Redelivery cannot fix a batch published for the wrong tenant.
+			log.Error("Error resolving records tenant", zap.String("subject", msg.Subject()), zap.Error(err))
+			msg.Term()
+			continue
+		}
+		batch = &recordsBatch{tenant: q.tenant, priority: l.priority, msg: msg, records: records}
+	}
+	if err := msgs.Error(); err != nil {
+		log.Error("Error fetching records message", zap.String("tenant", q.tenant), zap.String("priority", l.priority), zap.Error(err))
+	}
+	return batch
+}
+
+// holdHeads acks every polled batch not scheduled yet in progress each
+// inProgressInterval until ctx is done, so that none is redelivered while it
+// waits, whether on its rate limit, behind other tenants or lanes, or for a
+// free worker.
+func (s *scheduler) holdHeads(ctx context.Context) {
+	ticker := time.NewTicker(inProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.hold()
+		}
+	}
+}
+
+// hold acks every polled batch not scheduled yet in progress.
+func (s *scheduler) hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, l := range s.lanes {
+		for _, q := range l.queues {
+			if q.head == nil {
+				continue
+			}
+			if err := q.head.msg.InProgress(); err != nil {
+				log.Error("Error extending records message", zap.String("tenant", q.tenant), zap.String("priority", l.priority), zap.Error(err))
+			}
+		}
+	}
+}
+
+// rateLimits keeps a token bucket of signatures per rate-limited tenant,
+// holding up to a second's worth. A batch is let through once a whole token
+// is available and may then push the bucket into debt, so batches larger
+// than a second's worth still pass and the tenant waits the debt off.
+type rateLimits struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimits(perSecond map[string]float64, now time.Time) *rateLimits {
+	buckets := make(map[string]*tokenBucket, len(perSecond))
+	for tenant, rate := range perSecond {
+		burst := max(rate, 1)
+		buckets[tenant] = &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+	}
+	return &rateLimits{buckets: buckets}
+}
+
+// delay returns how long the tenant must wait before signing again; a nil
+// rateLimits limits no one.
+func (l *rateLimits) delay(tenant string, now time.Time) time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[tenant]
+	if !ok {
+		return 0
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// take spends n signatures of the tenant's budget.
+func (l *rateLimits) take(tenant string, n int, now time.Time) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[tenant]; ok {
+		b.refill(now)
+		b.tokens -= float64(n)
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
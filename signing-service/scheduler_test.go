@@ -0,0 +1,412 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/recordbatch"
+	"github.com/jurshsmith/vaultstream/types"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop()
+}
+
+// fakeMsg is a records message that remembers how it was acknowledged.
+type fakeMsg struct {
+	jetstream.Msg
+	subject   string
+	header    nats.Header
+	data      []byte
+	published time.Time
+	acked     string
+}
+
+func (m *fakeMsg) Subject() string { return m.subject }
+func (m *fakeMsg) Data() []byte    { return m.data }
+func (m *fakeMsg) Headers() nats.Header {
+	return m.header
+}
+func (m *fakeMsg) Nak() error  { m.acked = "nak"; return nil }
+func (m *fakeMsg) Term() error { m.acked = "term"; return nil }
+func (m *fakeMsg) InProgress() error {
+	m.acked = "in progress"
+	return nil
+}
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{Timestamp: m.published}, nil
+}
+
+// fakeBatch is a fetched batch of at most one message.
+type fakeBatch struct {
+	msgs chan jetstream.Msg
+}
+
+func (b fakeBatch) Messages() <-chan jetstream.Msg { return b.msgs }
+func (b fakeBatch) Error() error                   { return nil }
+
+// fakeSource hands out its messages one fetch at a time.
+type fakeSource struct {
+	msgs    []*fakeMsg
+	fetches int
+}
+
+func (s *fakeSource) FetchNoWait(int) (jetstream.MessageBatch, error) {
+	s.fetches++
+	batch := fakeBatch{msgs: make(chan jetstream.Msg, 1)}
+	if len(s.msgs) > 0 {
+		batch.msgs <- s.msgs[0]
+		s.msgs = s.msgs[1:]
+	}
+	close(batch.msgs)
+	return batch, nil
+}
+
+// recordsMsg is a normal priority batch of n records of tenant, published at
+// published.
+func recordsMsg(t *testing.T, tenant string, n int, published time.Time) *fakeMsg {
+	t.Helper()
+	records := make([]types.Record, n)
+	for i := range records {
+		records[i] = types.Record{ID: i + 1, TenantID: tenant}
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		t.Fatalf("failed marshaling records: %v", err)
+	}
+	return &fakeMsg{subject: fmt.Sprintf("records.%s.normal.1", tenant), data: data, published: published}
+}
+
+// testQueue is a queue of batches of one record each.
+func testQueue(t *testing.T, tenant string, weight float64, batches int) *tenantQueue {
+	t.Helper()
+	source := &fakeSource{}
+	for range batches {
+		source.msgs = append(source.msgs, recordsMsg(t, tenant, 1, time.Now()))
+	}
+	return &tenantQueue{tenant: tenant, source: source, weight: weight}
+}
+
+// normalLane is the normal priority lane of queues.
+func normalLane(queues ...*tenantQueue) []*lane {
+	return []*lane{{priority: types.PriorityNormal, queues: queues}}
+}
+
+// schedule returns the tenants of the next n batches as a string of their
+// initials.
+func schedule(t *testing.T, s *scheduler, n int) string {
+	t.Helper()
+	var order strings.Builder
+	for range n {
+		batch, err := s.next(context.Background())
+		if err != nil {
+			t.Fatalf("next() returned an unexpected error: %v", err)
+		}
+		order.WriteByte(batch.tenant[0])
+	}
+	return order.String()
+}
+
+// TestSchedulerWeights verifies backlogged tenants are served in proportion
+// to their weights.
+func TestSchedulerWeights(t *testing.T) {
+	s := newScheduler(normalLane(testQueue(t, "acme", 3, 20), testQueue(t, "globex", 1, 20)), nil, 1)
+	order := schedule(t, s, 16)
+	if a, g := strings.Count(order, "a"), strings.Count(order, "g"); a != 12 || g != 4 {
+		t.Errorf("schedule = %s, want 12 acme and 4 globex batches", order)
+	}
+	// globex is never kept waiting for more than its share.
+	if strings.Contains(order, "aaaa") {
+		t.Errorf("schedule = %s, want at most 3 acme batches in a row", order)
+	}
+}
+
+// TestSchedulerIdleTenant verifies a tenant that was idle takes turns with
+// the busy one rather than catching up on the turns it did not need.
+func TestSchedulerIdleTenant(t *testing.T) {
+	acme, globex := testQueue(t, "acme", 1, 20), testQueue(t, "globex", 1, 0)
+	now := time.Now()
+	s := newScheduler(normalLane(acme, globex), nil, 1)
+	s.now = func() time.Time { return now }
+	if order := schedule(t, s, 5); order != "aaaaa" {
+		t.Fatalf("schedule = %s, want only acme batches", order)
+	}
+	source := globex.source.(*fakeSource)
+	for range 5 {
+		source.msgs = append(source.msgs, recordsMsg(t, "globex", 1, time.Now()))
+	}
+	now = now.Add(maxIdlePollInterval)
+	if order := schedule(t, s, 6); order != "gagaga" {
+		t.Errorf("schedule = %s, want gagaga", order)
+	}
+}
+
+// TestSchedulerBacksOffIdleQueues verifies a queue that comes up empty is
+// polled less and less often, and again as usual once it has a batch.
+func TestSchedulerBacksOffIdleQueues(t *testing.T) {
+	acme := testQueue(t, "acme", 1, 0)
+	now := time.Now()
+	s := newScheduler(normalLane(acme), nil, 1)
+	s.now = func() time.Time { return now }
+	s.pollInterval = time.Millisecond
+	source := acme.source.(*fakeSource)
+
+	for _, want := range []int{1, 2, 2, 3} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		if _, err := s.next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("next() of an empty queue = %v, want deadline exceeded", err)
+		}
+		cancel()
+		if source.fetches != want {
+			t.Fatalf("fetches = %d, want %d", source.fetches, want)
+		}
+		// The backoff doubles from the poll interval: 1ms, 2ms, 4ms.
+		now = now.Add(time.Millisecond)
+	}
+	if acme.idle != 4*time.Millisecond {
+		t.Errorf("idle poll interval = %s, want 4ms", acme.idle)
+	}
+
+	source.msgs = append(source.msgs, recordsMsg(t, "acme", 1, now))
+	now = now.Add(maxIdlePollInterval)
+	if order := schedule(t, s, 1); order != "a" || acme.idle != 0 {
+		t.Errorf("schedule = %s with idle poll interval %s, want a and 0", order, acme.idle)
+	}
+}
+
+// TestSchedulerHoldsHeads verifies every polled batch waiting to be
+// scheduled is acked in progress, whichever lane or tenant it waits behind.
+func TestSchedulerHoldsHeads(t *testing.T) {
+	acme, globex := testQueue(t, "acme", 1, 2), testQueue(t, "globex", 1, 2)
+	lanes := []*lane{
+		{priority: types.PriorityHigh, queues: []*tenantQueue{testQueue(t, "acme", 1, 2)}},
+		{priority: types.PriorityNormal, queues: []*tenantQueue{acme, globex}},
+	}
+	s := newScheduler(lanes, nil, 3)
+	if batch, err := s.next(context.Background()); err != nil || batch.priority != types.PriorityHigh {
+		t.Fatalf("next() = %+v, %v; want a high priority batch", batch, err)
+	}
+	s.hold()
+	for _, q := range []*tenantQueue{acme, globex} {
+		if q.head == nil || q.head.msg.(*fakeMsg).acked != "in progress" {
+			t.Errorf("%s head = %+v, want acked in progress", q.tenant, q.head)
+		}
+	}
+}
+
+// blockingSource is an empty source whose fetches block until release is
+// closed, signalling fetching as they start.
+type blockingSource struct {
+	fetching chan struct{}
+	release  chan struct{}
+}
+
+func (s *blockingSource) FetchNoWait(int) (jetstream.MessageBatch, error) {
+	s.fetching <- struct{}{}
+	<-s.release
+	batch := fakeBatch{msgs: make(chan jetstream.Msg)}
+	close(batch.msgs)
+	return batch, nil
+}
+
+// TestSchedulerFetchesWithoutLock verifies a slow fetch holds up neither
+// holding heads nor other calls, which do not fetch from the same queue.
+func TestSchedulerFetchesWithoutLock(t *testing.T) {
+	slow := &blockingSource{fetching: make(chan struct{}, 2), release: make(chan struct{})}
+	globex := testQueue(t, "globex", 1, 1)
+	s := newScheduler(normalLane(&tenantQueue{tenant: "acme", source: slow, weight: 1}, globex), nil, 1)
+
+	done := make(chan *recordsBatch)
+	go func() {
+		batch, _ := s.due(context.Background())
+		done <- batch
+	}()
+	<-slow.fetching
+
+	unblocked := make(chan struct{})
+	go func() {
+		s.hold()
+		if batch, _ := s.due(context.Background()); batch != nil {
+			t.Errorf("due() during a fetch = %+v, want nothing to schedule", batch)
+		}
+		close(unblocked)
+	}()
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("hold() and due() waited for another call's fetch")
+	}
+	if len(slow.fetching) != 0 {
+		t.Errorf("queue fetched from again while its fetch was in flight")
+	}
+
+	close(slow.release)
+	if batch := <-done; batch == nil || batch.tenant != "globex" {
+		t.Errorf("due() = %+v, want the globex batch", batch)
+	}
+}
+
+// TestSchedulerBatchSize verifies tenants are charged per record, not per batch.
+func TestSchedulerBatchSize(t *testing.T) {
+	acme := &tenantQueue{tenant: "acme", weight: 1, source: &fakeSource{}}
+	for range 3 {
+		acme.source.(*fakeSource).msgs = append(acme.source.(*fakeSource).msgs, recordsMsg(t, "acme", 4, time.Now()))
+	}
+	s := newScheduler(normalLane(acme, testQueue(t, "globex", 1, 20)), nil, 1)
+	if order := schedule(t, s, 6); order != "agggga" {
+		t.Errorf("schedule = %s, want agggga", order)
+	}
+}
+
+// TestSchedulerRateLimit verifies a tenant over its rate limit is skipped
+// until its budget refills, without holding up the others.
+func TestSchedulerRateLimit(t *testing.T) {
+	now := time.Now()
+	s := newScheduler(normalLane(testQueue(t, "acme", 1, 5), testQueue(t, "globex", 1, 3)),
+		newRateLimits(map[string]float64{"acme": 1}, now), 1)
+	s.now = func() time.Time { return now }
+	s.pollInterval = time.Millisecond
+
+	if order := schedule(t, s, 4); order != "aggg" {
+		t.Fatalf("schedule = %s, want aggg", order)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := s.next(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("next() with acme over its limit = %v, want deadline exceeded", err)
+	}
+
+	now = now.Add(time.Second)
+	if order := schedule(t, s, 1); order != "a" {
+		t.Errorf("schedule after a second = %s, want a", order)
+	}
+}
+
+// TestSchedulerRejectsBatches verifies malformed batches are retried and
+// batches of another tenant dropped.
+func TestSchedulerRejectsBatches(t *testing.T) {
+	malformed := &fakeMsg{subject: "records.acme.normal.1", data: []byte("{")}
+	foreign := recordsMsg(t, "globex", 1, time.Now())
+	foreign.subject = "records.acme.normal.2"
+	valid := recordsMsg(t, "acme", 2, time.Now().Add(-time.Second))
+	s := newScheduler(normalLane(&tenantQueue{tenant: "acme", weight: 1, source: &fakeSource{msgs: []*fakeMsg{malformed, foreign, valid}}}), nil, 1)
+
+	var batch *recordsBatch
+	for range 3 {
+		var err error
+		if batch, err = s.next(context.Background()); err != nil {
+			t.Fatalf("next() returned an unexpected error: %v", err)
+		}
+		if batch.msg == valid {
+			break
+		}
+	}
+	if batch.msg != valid || len(batch.records) != 2 {
+		t.Errorf("next() = %+v, want the valid batch", batch)
+	}
+	if malformed.acked != "nak" || foreign.acked != "term" {
+		t.Errorf("malformed batch %s, foreign batch %s; want nak and term", malformed.acked, foreign.acked)
+	}
+	if got := queueWait.With("acme").Snapshot(); got.Count == 0 || got.Sum < 1 {
+		t.Errorf("acme queue wait = %+v, want the valid batch's wait observed", got)
+	}
+}
+
+// expiredClaims is a claim check bucket whose objects have all expired.
+type expiredClaims struct{ recordbatch.ObjectStore }
+
+func (expiredClaims) GetBytes(context.Context, string, ...jetstream.GetObjectOpt) ([]byte, error) {
+	return nil, jetstream.ErrObjectNotFound
+}
+
+// TestSchedulerDecodesBatches verifies compressed batches are decoded and
+// claim checks whose batch is gone are dropped.
+func TestSchedulerDecodesBatches(t *testing.T) {
+	expired := &fakeMsg{subject: "records.acme.normal.1", header: nats.Header{}}
+	expired.header.Set(recordbatch.ClaimHeader, "records.acme.normal.1")
+	data, err := recordbatch.Encode([]types.Record{{ID: 2, TenantID: "acme"}, {ID: 3, TenantID: "acme"}}, recordbatch.Zstd)
+	if err != nil {
+		t.Fatalf("failed encoding records: %v", err)
+	}
+	compressed := &fakeMsg{subject: "records.acme.normal.2", header: nats.Header{}, data: data}
+	compressed.header.Set(recordbatch.EncodingHeader, string(recordbatch.Zstd))
+	s := newScheduler(normalLane(&tenantQueue{tenant: "acme", weight: 1, source: &fakeSource{msgs: []*fakeMsg{expired, compressed}}}), nil, 1)
+	s.claims = expiredClaims{}
+
+	batch, err := s.next(context.Background())
+	if err != nil {
+		t.Fatalf("next() returned an unexpected error: %v", err)
+	}
+	if batch.msg != compressed || len(batch.records) != 2 || batch.records[1].ID != 3 {
+		t.Errorf("next() = %+v, want the compressed batch decoded", batch)
+	}
+	if expired.acked != "term" {
+		t.Errorf("expired claim check %s, want term", expired.acked)
+	}
+}
+
+// TestSchedulerPriorities verifies higher lanes go first while a lower lane
+// still gets a turn after starvationLimit batches of the others.
+func TestSchedulerPriorities(t *testing.T) {
+	lanes := []*lane{
+		{priority: types.PriorityHigh, queues: []*tenantQueue{testQueue(t, "acme", 1, 7)}},
+		{priority: types.PriorityNormal, queues: []*tenantQueue{testQueue(t, "acme", 1, 20)}},
+		{priority: types.PriorityLow, queues: []*tenantQueue{testQueue(t, "acme", 1, 20)}},
+	}
+	s := newScheduler(lanes, nil, 3)
+	var order strings.Builder
+	for range 14 {
+		batch, err := s.next(context.Background())
+		if err != nil {
+			t.Fatalf("next() returned an unexpected error: %v", err)
+		}
+		order.WriteByte(batch.priority[0])
+	}
+	// normal and low each get a turn after waiting behind three batches; once
+	// high is drained, low still gets one after three of normal at the latest.
+	if got, want := order.String(), "hhhnlhhnlhhnln"; got != want {
+		t.Errorf("priorities = %s, want %s", got, want)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	start := time.Now()
+	limits := newRateLimits(map[string]float64{"acme": 2, "globex": 0.5}, start)
+	tests := []struct {
+		name   string
+		tenant string
+		take   int
+		after  time.Duration
+		want   time.Duration
+	}{
+		{name: "full bucket", tenant: "acme", want: 0},
+		{name: "into debt", tenant: "acme", take: 5, want: 2 * time.Second},
+		{name: "paying off", tenant: "acme", after: time.Second, want: time.Second},
+		{name: "paid off", tenant: "acme", after: 2 * time.Second, want: 0},
+		{name: "below one per second", tenant: "globex", take: 1, want: 2 * time.Second},
+		{name: "unlimited", tenant: "initech", take: 100, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start.Add(tt.after)
+			limits.take(tt.tenant, tt.take, now)
+			if got := limits.delay(tt.tenant, now); got != tt.want {
+				t.Errorf("delay(%s) = %s, want %s", tt.tenant, got, tt.want)
+			}
+		})
+	}
+
+	var unlimited *rateLimits
+	unlimited.take("acme", 100, start)
+	if got := unlimited.delay("acme", start); got != 0 {
+		t.Errorf("nil rateLimits delay = %s, want 0", got)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/nats"
+	"go.uber.org/zap"
+)
+
+var log *zap.Logger
+
+// main reconciles the records, keys and events streams with their
+// <KIND>_STREAM_* configuration and prints the plan. With -dry-run it only
+// prints what would change. Changes that may delete messages are only
+// applied with -allow-lossy. Services only check the streams on startup.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "print the plan without applying it")
+	allowLossy := flag.Bool("allow-lossy", false, "apply changes tightening MAX_AGE or MAX_BYTES, deleting the messages over the new limit")
+	flag.Parse()
+
+	log = logger.New()
+	defer log.Sync()
+
+	config.Setup()
+
+	js, natsConn, err := nats.Dial("streams")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
+	defer natsConn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	desired, err := nats.Streams()
+	if err != nil {
+		log.Fatal("Invalid stream configuration", zap.Error(err))
+	}
+	plan, err := nats.PlanStreams(ctx, js, desired)
+	if err != nil {
+		log.Fatal("Error planning streams", zap.Error(err))
+	}
+	fmt.Print(plan)
+
+	if err := plan.Err(); err != nil {
+		log.Fatal("Streams cannot be reconciled", zap.Error(err))
+	}
+	if *dryRun || !plan.Changed() {
+		return
+	}
+	if plan.Lossy() && !*allowLossy {
+		log.Fatal("Streams not reconciled: the plan may delete messages, rerun with -allow-lossy to apply it")
+	}
+	if err := plan.Apply(ctx, js, *allowLossy); err != nil {
+		log.Fatal("Error reconciling streams", zap.Error(err))
+	}
+	log.Info("Streams reconciled")
+}
@@ -0,0 +1,149 @@
+package tsa
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/digitorus/pkcs7"
+	"github.com/digitorus/timestamp"
+)
+
+const (
+	queryContentType = "application/timestamp-query"
+	replyContentType = "application/timestamp-reply"
+
+	// maxReplySize bounds how much of a TSA reply is read; real tokens are a few KB.
+	maxReplySize = 1 << 20
+)
+
+// Client requests RFC 3161 time-stamp tokens from a TSA over HTTP.
+type Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewClient(url string) *Client {
+	return &Client{url: url, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Timestamp obtains a time-stamp token over the SHA-256 digest of data and
+// returns it in DER form. The TSA is asked to include its certificate, so the
+// token can be verified on its own later.
+func (c *Client) Timestamp(ctx context.Context, data []byte) ([]byte, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	query, err := timestamp.CreateRequest(bytes.NewReader(data), &timestamp.RequestOptions{
+		Hash:         crypto.SHA256,
+		Certificates: true,
+		Nonce:        nonce,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating time-stamp request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", queryContentType)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed requesting time-stamp: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("TSA replied with HTTP %d", resp.StatusCode)
+	}
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, maxReplySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed reading time-stamp reply: %w", err)
+	}
+
+	ts, err := timestamp.ParseResponse(reply)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time-stamp reply: %w", err)
+	}
+	if err := checkImprint(ts, data); err != nil {
+		return nil, err
+	}
+	if ts.Nonce == nil || ts.Nonce.Cmp(nonce) != 0 {
+		return nil, errors.New("time-stamp reply does not echo the request nonce")
+	}
+	return ts.RawToken, nil
+}
+
+// Verify parses a DER time-stamp token, checks that it covers data and that
+// its signer holds a time-stamping certificate chaining up to roots. It
+// returns the parsed token, whose Time is the attested time.
+func Verify(token, data []byte, roots *x509.CertPool) (*timestamp.Timestamp, error) {
+	// Parse also checks the token signature against the embedded certificate.
+	ts, err := timestamp.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time-stamp token: %w", err)
+	}
+	if err := checkImprint(ts, data); err != nil {
+		return nil, err
+	}
+	tsaCert, err := signerCertificate(token)
+	if err != nil {
+		return nil, err
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range ts.Certificates {
+		if cert != tsaCert {
+			intermediates.AddCert(cert)
+		}
+	}
+	if _, err := tsaCert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   ts.Time,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+	}); err != nil {
+		return nil, fmt.Errorf("TSA certificate: %w", err)
+	}
+	return ts, nil
+}
+
+// signerCertificate returns the certificate the token's only SignerInfo names
+// by issuer and serial number, the one its signature was checked with; other
+// embedded certificates prove nothing about who signed.
+func signerCertificate(token []byte) (*x509.Certificate, error) {
+	p7, err := pkcs7.Parse(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time-stamp token: %w", err)
+	}
+	if len(p7.Signers) != 1 {
+		return nil, fmt.Errorf("time-stamp token has %d signers, want 1", len(p7.Signers))
+	}
+	cert := p7.GetOnlySigner()
+	if cert == nil {
+		return nil, errors.New("time-stamp token carries no TSA certificate")
+	}
+	return cert, nil
+}
+
+// checkImprint makes sure the token was issued for data and not something else.
+func checkImprint(ts *timestamp.Timestamp, data []byte) error {
+	if !ts.HashAlgorithm.Available() {
+		return fmt.Errorf("time-stamp uses unsupported hash %v", ts.HashAlgorithm)
+	}
+	h := ts.HashAlgorithm.New()
+	h.Write(data)
+	if !bytes.Equal(h.Sum(nil), ts.HashedMessage) {
+		return errors.New("time-stamp does not cover the given data")
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+module github.com/jurshsmith/vaultstream/tsa
+
+go 1.24.1
+
+require (
+	github.com/digitorus/pkcs7 v0.0.0-20230713084857-e76b763bdc49
+	github.com/digitorus/timestamp v0.0.0-20250524132541-c45532741eea
+)
@@ -0,0 +1,137 @@
+package tsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/digitorus/timestamp"
+)
+
+// LocalPolicy is the TSA policy stamped by the bundled local TSA. It sits under
+// the enterprise number reserved for documentation (RFC 5612): local tokens
+// are for development and tests only.
+var LocalPolicy = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 32473, 1}
+
+var (
+	oidExtKeyUsage    = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidKPTimeStamping = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}
+)
+
+const (
+	localTSAValidity = 365 * 24 * time.Hour
+	maxQuerySize     = 1 << 16
+)
+
+// Server is a minimal RFC 3161 TSA speaking the HTTP transport of RFC 3161
+// section 3.4. It signs every well-formed request with the current time.
+type Server struct {
+	cert   *x509.Certificate
+	key    crypto.Signer
+	policy asn1.ObjectIdentifier
+}
+
+func NewServer(cert *x509.Certificate, key crypto.Signer, policy asn1.ObjectIdentifier) *Server {
+	return &Server{cert: cert, key: key, policy: policy}
+}
+
+// NewLocalServer creates a TSA with a fresh self-signed ECDSA P-256
+// certificate, for development and tests.
+func NewLocalServer() (*Server, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	// RFC 3161 requires the extended key usage to be critical and to hold only
+	// id-kp-timeStamping, which x509.Certificate.ExtKeyUsage cannot express.
+	extKeyUsage, err := asn1.Marshal([]asn1.ObjectIdentifier{oidKPTimeStamping})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"VaultStream"}, CommonName: "VaultStream Local TSA"},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(localTSAValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtraExtensions:       []pkix.Extension{{Id: oidExtKeyUsage, Critical: true, Value: extKeyUsage}},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating local TSA certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(cert, key, LocalPolicy), nil
+}
+
+// Certificate returns the TSA certificate; for a local server it is also the
+// root verifiers have to trust.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Content-Type") != queryContentType {
+		http.Error(w, "expected "+queryContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+	query, err := io.ReadAll(io.LimitReader(r.Body, maxQuerySize))
+	if err != nil {
+		http.Error(w, "failed reading request", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := s.reply(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", replyContentType)
+	w.Write(reply)
+}
+
+// reply answers a DER time-stamp query. Malformed queries get a rejection
+// reply rather than an HTTP error, as RFC 3161 expects.
+func (s *Server) reply(query []byte) ([]byte, error) {
+	req, err := timestamp.ParseRequest(query)
+	if err != nil {
+		return timestamp.CreateErrorResponse(timestamp.Rejection, timestamp.BadDataFormat)
+	}
+	if req.TSAPolicyOID != nil && !req.TSAPolicyOID.Equal(s.policy) {
+		return timestamp.CreateErrorResponse(timestamp.Rejection, timestamp.UnacceptedPolicy)
+	}
+
+	ts := timestamp.Timestamp{
+		HashAlgorithm:     req.HashAlgorithm,
+		HashedMessage:     req.HashedMessage,
+		Time:              time.Now().UTC(),
+		Accuracy:          time.Second,
+		Policy:            s.policy,
+		Nonce:             req.Nonce,
+		AddTSACertificate: req.Certificates,
+	}
+	return ts.CreateResponseWithOpts(s.cert, s.key, crypto.SHA256)
+}
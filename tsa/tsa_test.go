@@ -0,0 +1,230 @@
+package tsa
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/digitorus/pkcs7"
+	"github.com/digitorus/timestamp"
+)
+
+// newLocalTSA starts the bundled TSA on a test HTTP server.
+func newLocalTSA(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+	server, err := NewLocalServer()
+	if err != nil {
+		t.Fatalf("NewLocalServer() unexpected error: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	return server, httpServer
+}
+
+func rootsOf(server *Server) *x509.CertPool {
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	return roots
+}
+
+func TestTimestampAndVerify(t *testing.T) {
+	server, httpServer := newLocalTSA(t)
+	data := []byte("signature bytes")
+
+	before := time.Now().Add(-time.Second)
+	token, err := NewClient(httpServer.URL).Timestamp(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Timestamp() unexpected error: %v", err)
+	}
+
+	ts, err := Verify(token, data, rootsOf(server))
+	if err != nil {
+		t.Fatalf("Verify() unexpected error: %v", err)
+	}
+	if ts.Time.Before(before.Truncate(time.Second)) || ts.Time.After(time.Now().Add(time.Second)) {
+		t.Errorf("time-stamp time %v is not around now", ts.Time)
+	}
+	if !ts.Policy.Equal(LocalPolicy) {
+		t.Errorf("time-stamp policy = %v, want %v", ts.Policy, LocalPolicy)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	server, httpServer := newLocalTSA(t)
+	otherServer, _ := newLocalTSA(t)
+	data := []byte("signature bytes")
+
+	token, err := NewClient(httpServer.URL).Timestamp(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Timestamp() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token []byte
+		data  []byte
+		roots *x509.CertPool
+	}{
+		{name: "different data", token: token, data: []byte("other bytes"), roots: rootsOf(server)},
+		{name: "untrusted TSA", token: token, data: data, roots: rootsOf(otherServer)},
+		{name: "corrupted token", token: token[:len(token)-10], data: data, roots: rootsOf(server)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.token, tt.data, tt.roots); err == nil {
+				t.Errorf("Verify() should fail")
+			}
+		})
+	}
+}
+
+// TestVerifyRejectsForeignSigner re-signs a genuine token's TSTInfo with a
+// foreign key and embeds the genuine TSA certificate next to the foreign
+// signer's, which must not pass for the TSA's signature.
+func TestVerifyRejectsForeignSigner(t *testing.T) {
+	server, httpServer := newLocalTSA(t)
+	data := []byte("signature bytes")
+	token, err := NewClient(httpServer.URL).Timestamp(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Timestamp() unexpected error: %v", err)
+	}
+	genuine, err := pkcs7.Parse(token)
+	if err != nil {
+		t.Fatalf("pkcs7.Parse() unexpected error: %v", err)
+	}
+
+	foreignKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() unexpected error: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Foreign Signer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, foreignKey.Public(), foreignKey)
+	if err != nil {
+		t.Fatalf("CreateCertificate() unexpected error: %v", err)
+	}
+	foreignCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() unexpected error: %v", err)
+	}
+
+	signedData, err := pkcs7.NewSignedData(genuine.Content)
+	if err != nil {
+		t.Fatalf("NewSignedData() unexpected error: %v", err)
+	}
+	signedData.SetContentType(asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4})
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	signedData.AddCertificate(server.Certificate())
+	if err := signedData.AddSigner(foreignCert, foreignKey, pkcs7.SignerInfoConfig{}); err != nil {
+		t.Fatalf("AddSigner() unexpected error: %v", err)
+	}
+	forged, err := signedData.Finish()
+	if err != nil {
+		t.Fatalf("Finish() unexpected error: %v", err)
+	}
+
+	if _, err := timestamp.Parse(forged); err != nil {
+		t.Fatalf("forged token should parse, with a valid signature by the foreign key: %v", err)
+	}
+	if _, err := Verify(forged, data, rootsOf(server)); err == nil {
+		t.Errorf("Verify() accepted a token signed by a foreign key")
+	}
+}
+
+func TestTimestampRejectsBadReplies(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "HTTP error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+		},
+		{
+			name: "garbage reply",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("not DER"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpServer := httptest.NewServer(tt.handler)
+			defer httpServer.Close()
+			if _, err := NewClient(httpServer.URL).Timestamp(context.Background(), []byte("data")); err == nil {
+				t.Errorf("Timestamp() should fail")
+			}
+		})
+	}
+}
+
+func TestServerRejectsMalformedQuery(t *testing.T) {
+	_, httpServer := newLocalTSA(t)
+
+	resp, err := http.Post(httpServer.URL, queryContentType, bytes.NewReader([]byte("not a query")))
+	if err != nil {
+		t.Fatalf("http.Post() unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d with a rejection reply", resp.StatusCode, http.StatusOK)
+	}
+	if resp.Header.Get("Content-Type") != replyContentType {
+		t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), replyContentType)
+	}
+}
+
+// TestTokenVerifiesWithOpenSSL checks the local TSA against OpenSSL when it is installed.
+func TestTokenVerifiesWithOpenSSL(t *testing.T) {
+	openssl, err := exec.LookPath("openssl")
+	if err != nil {
+		t.Skip("Skipping OpenSSL interop test: openssl not found")
+	}
+	server, httpServer := newLocalTSA(t)
+	data := []byte("signature bytes")
+	token, err := NewClient(httpServer.URL).Timestamp(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Timestamp() unexpected error: %v", err)
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"token.der": token,
+		"data":      data,
+		"tsa.pem":   pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(openssl, "ts", "-verify", "-token_in",
+		"-in", filepath.Join(dir, "token.der"),
+		"-data", filepath.Join(dir, "data"),
+		"-CAfile", filepath.Join(dir, "tsa.pem"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("openssl ts -verify failed: %v\n%s", err, out)
+	}
+}
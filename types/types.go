@@ -1,23 +1,144 @@
 package types
 
-import "time"
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTenant owns everything created without an explicit tenant, including
+// all rows from before tenants existed.
+const DefaultTenant = "default"
+
+// maxTenantLength bounds tenant IDs, which appear in subjects and key labels.
+const maxTenantLength = 64
+
+// ValidateTenant checks that a tenant ID can be used as a single NATS subject
+// token: 1 to 64 characters out of letters, digits, '-' and '_'.
+func ValidateTenant(tenantID string) error {
+	if tenantID == "" || len(tenantID) > maxTenantLength {
+		return fmt.Errorf("tenant ID must be between 1 and %d characters", maxTenantLength)
+	}
+	if i := strings.IndexFunc(tenantID, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}); i >= 0 {
+		return fmt.Errorf("tenant ID %q has an invalid character at %d", tenantID, i)
+	}
+	return nil
+}
+
+// Priorities of record batches, highest first. Each is its own lane of
+// records subjects, records.<tenant>.<priority>.>, drained highest first.
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// Priorities lists the priorities highest first.
+var Priorities = []string{PriorityHigh, PriorityNormal, PriorityLow}
+
+// ValidatePriority checks that priority is one of Priorities.
+func ValidatePriority(priority string) error {
+	if !slices.Contains(Priorities, priority) {
+		return fmt.Errorf("priority %q is not one of %s", priority, strings.Join(Priorities, ", "))
+	}
+	return nil
+}
 
 type Record struct {
-	ID         int       `json:"id"`
+	ID int `json:"id"`
+	// TenantID owns the record; only keys of the same tenant sign it.
+	TenantID   string    `json:"tenant_id,omitempty"`
 	InsertedAt time.Time `json:"inserted_at"`
+	// Data is the record content, when it was submitted with one.
+	Data []byte `json:"data,omitempty"`
 }
 
 type Key struct {
-	ID         int       `json:"id"`
-	Value      string    `json:"value"`
-	IsInUse    bool      `json:"is_in_use"`
-	LastUsedAt time.Time `json:"last_used_at"`
+	ID       int    `json:"id"`
+	TenantID string `json:"tenant_id"`
+	// Algorithm is the JOSE name of the key's signature scheme, e.g. "ES256".
+	Algorithm string `json:"algorithm"`
+	// Value is the base64 DER private key. It is only set by the memory signer
+	// backend; keystore and PKCS#11 keys never leave their backend.
+	Value     string `json:"value,omitempty"`
+	PublicKey string `json:"public_key"`
+	// Certificate is the base64 DER X.509 certificate for PublicKey.
+	Certificate string `json:"certificate,omitempty"`
+	// CertificateChain holds the base64 DER certificates from Certificate's
+	// issuer up to the VaultStream root CA.
+	CertificateChain []string  `json:"certificate_chain,omitempty"`
+	IsInUse          bool      `json:"is_in_use"`
+	LastUsedAt       time.Time `json:"last_used_at"`
 }
 
 type Signature struct {
-	ID         int       `json:"id"`
-	RecordID   int       `json:"record_id"`
-	KeyID      int       `json:"key_id"`
-	Value      string    `json:"value"`
+	ID        int    `json:"id"`
+	TenantID  string `json:"tenant_id"`
+	RecordID  int    `json:"record_id"`
+	KeyID     int    `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+	// TimestampToken is the base64 DER RFC 3161 token over the raw signature
+	// value, when signing-service is configured with a TSA.
+	TimestampToken string `json:"timestamp_token,omitempty"`
+	// MerkleProof is set when the signature covers a batch Merkle root rather
+	// than the record itself; Value is then shared by the whole batch.
+	MerkleProof *MerkleProof `json:"merkle_proof,omitempty"`
+	// LedgerSeq is the ledger entry that committed to the signature.
+	LedgerSeq  int       `json:"ledger_seq,omitempty"`
 	InsertedAt time.Time `json:"inserted_at"`
 }
+
+// MerkleProof places a record's leaf in the batch tree whose root was signed.
+type MerkleProof struct {
+	// Root is the base64 tree head that was signed.
+	Root      string `json:"root"`
+	LeafIndex int    `json:"leaf_index"`
+	TreeSize  int    `json:"tree_size"`
+	// Path holds the base64 sibling hashes from the leaf up to the root.
+	Path []string `json:"path"`
+}
+
+// SignaturesEvent is published on signatures.<tenant>.<ledger seq> once a batch of
+// signatures is committed, so downstream systems need not poll the table.
+type SignaturesEvent struct {
+	TenantID   string      `json:"tenant_id"`
+	LedgerSeq  int         `json:"ledger_seq"`
+	Signatures []Signature `json:"signatures"`
+	// CommittedAt is when the batch was stored, the InsertedAt of its signatures.
+	CommittedAt time.Time `json:"committed_at"`
+	// Timings is how long the batch took to sign and commit, when it came
+	// from a records batch; producers size their batches from it.
+	Timings *BatchTimings `json:"timings,omitempty"`
+	// BatchFirstRecordID is the ID of the first record of that records
+	// batch, which the signatures leave out when it was signed before.
+	BatchFirstRecordID int `json:"batch_first_record_id,omitempty"`
+}
+
+// BatchTimings are the durations of signing-service's stages for one batch.
+type BatchTimings struct {
+	Sign   time.Duration `json:"sign_ns"`
+	Insert time.Duration `json:"insert_ns"`
+}
+
+// ScalingRecommendation is published by controller-service on the control
+// subject after every sample of the signing consumers. Rates are in records
+// batches per second.
+type ScalingRecommendation struct {
+	// Backlog counts records batches not delivered yet or not acked yet.
+	Backlog     uint64  `json:"backlog"`
+	ArrivalRate float64 `json:"arrival_rate"`
+	// Throughput is the rate records batches were committed, redeliveries
+	// aside.
+	Throughput float64 `json:"throughput"`
+	// Redelivered counts deliveries currently being retried.
+	Redelivered     int `json:"redelivered"`
+	CurrentReplicas int `json:"current_replicas"`
+	DesiredReplicas int `json:"desired_replicas"`
+	// DesiredKeys is the recommended key pool size of each tenant.
+	DesiredKeys map[string]int `json:"desired_keys"`
+	SampledAt   time.Time      `json:"sampled_at"`
+}
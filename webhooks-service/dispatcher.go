@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/webhook"
+	"github.com/jurshsmith/vaultstream/database/webhookdelivery"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Headers sent with every delivery. The signature header is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)); receivers
+// should recompute it and reject stale timestamps to stop replays. Delivery is
+// at least once: the delivery header, the event's ledger sequence, identifies
+// repeats.
+const (
+	eventHeader     = "X-VaultStream-Event"
+	deliveryHeader  = "X-VaultStream-Delivery"
+	timestampHeader = "X-VaultStream-Timestamp"
+	signatureHeader = "X-VaultStream-Signature"
+
+	signaturesEvent = "signatures.completed"
+
+	maxBackoff      = time.Minute
+	deliveryTimeout = 10 * time.Second
+	maxParallel     = 8 // concurrent deliveries per event
+)
+
+// attempt is the outcome of one POST to a webhook.
+type attempt struct {
+	number     int
+	statusCode int // 0 when no response was received
+	err        error
+	at         time.Time
+}
+
+// Dispatcher POSTs signatures events to every active webhook and records each
+// attempt. Failed deliveries are not retried inline: Dispatch says when the
+// event is due again, and the recorded attempts tell the next pass which
+// webhooks still need it.
+type Dispatcher struct {
+	db             *database.Client
+	httpClient     *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+func NewDispatcher(db *database.Client, maxAttempts int, initialBackoff time.Duration) *Dispatcher {
+	return &Dispatcher{
+		db:             db,
+		httpClient:     &http.Client{Timeout: deliveryTimeout},
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+	}
+}
+
+// Dispatch makes the next attempt at delivering the event body of ledger
+// entry seq to every active webhook of the event's tenant that is due one,
+// and returns how long until the next attempt falls due, 0 once every webhook
+// accepted the event or was given up on after maxAttempts. Only database
+// errors are returned, once every attempt of the pass has been made.
+func (d *Dispatcher) Dispatch(ctx context.Context, tenant string, seq int, body []byte) (time.Duration, error) {
+	hooks, err := d.db.Webhook.Query().
+		Where(webhook.TenantID(tenant), webhook.Active(true)).
+		All(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed loading webhooks: %w", err)
+	}
+	if len(hooks) == 0 {
+		return 0, nil
+	}
+	last, err := d.lastAttempts(ctx, hooks, seq)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		mu      sync.Mutex
+		retryIn time.Duration
+		eg      errgroup.Group
+	)
+	retryAfter := func(delay time.Duration) {
+		delay = max(delay, time.Millisecond) // 0 would say the event is settled
+		mu.Lock()
+		defer mu.Unlock()
+		if retryIn == 0 || delay < retryIn {
+			retryIn = delay
+		}
+	}
+	eg.SetLimit(maxParallel)
+	now := time.Now()
+	for _, hook := range hooks {
+		delay, pending := d.next(last[hook.ID], now)
+		switch {
+		case !pending:
+			continue
+		case delay > 0:
+			retryAfter(delay)
+			continue
+		}
+		// Every webhook's attempt is made and recorded even if another's
+		// fails to be, so that a redelivery repeats none of them.
+		eg.Go(func() error {
+			a := d.deliver(ctx, hook.URL, hook.Secret, seq, body, last[hook.ID].number+1)
+			if delay, pending := d.next(a, a.at); pending {
+				retryAfter(delay)
+			} else if a.err != nil {
+				log.Warn("Giving up on webhook delivery", zap.Int("webhookID", hook.ID), zap.Int("ledgerSeq", seq), zap.Int("attempts", a.number), zap.Error(a.err))
+			}
+			return d.saveAttempt(ctx, hook.ID, seq, a)
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+	return retryIn, nil
+}
+
+// lastAttempts returns the latest recorded attempt at delivering the event of
+// ledger entry seq to each of hooks, by webhook ID.
+func (d *Dispatcher) lastAttempts(ctx context.Context, hooks []*database.Webhook, seq int) (map[int]attempt, error) {
+	ids := make([]int, len(hooks))
+	for i, hook := range hooks {
+		ids[i] = hook.ID
+	}
+	deliveries, err := d.db.WebhookDelivery.Query().
+		Where(webhookdelivery.LedgerSeq(seq), webhookdelivery.WebhookIDIn(ids...)).
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading deliveries: %w", err)
+	}
+	last := make(map[int]attempt, len(hooks))
+	for _, delivery := range deliveries {
+		if delivery.Attempt <= last[delivery.WebhookID].number {
+			continue
+		}
+		a := attempt{number: delivery.Attempt, at: delivery.InsertedAt}
+		if delivery.StatusCode != nil {
+			a.statusCode = *delivery.StatusCode
+		}
+		if !delivery.Succeeded {
+			a.err = errors.New(delivery.Error)
+		}
+		last[delivery.WebhookID] = a
+	}
+	return last, nil
+}
+
+// next returns how long after now a webhook whose latest attempt is last is
+// due another one, and false if it needs none: it accepted the event, or
+// failed maxAttempts times or in a way not worth repeating. Client errors
+// other than 408 and 429 are not retried: repeating the same request will not
+// fix them.
+func (d *Dispatcher) next(last attempt, now time.Time) (time.Duration, bool) {
+	switch {
+	case last.number == 0:
+		return 0, true
+	case last.err == nil, last.number >= d.maxAttempts, !retryable(last.statusCode):
+		return 0, false
+	}
+	return max(0, last.at.Add(d.backoff(last.number)).Sub(now)), true
+}
+
+// backoff is the delay after the given attempt: initialBackoff, doubling with
+// every attempt up to maxBackoff.
+func (d *Dispatcher) backoff(number int) time.Duration {
+	backoff := d.initialBackoff
+	for i := 1; i < number && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxBackoff)
+}
+
+// deliver POSTs body to url once, as the given attempt.
+func (d *Dispatcher) deliver(ctx context.Context, url, secret string, seq int, body []byte, number int) attempt {
+	statusCode, err := d.post(ctx, url, secret, seq, body)
+	return attempt{number: number, statusCode: statusCode, err: err, at: time.Now()}
+}
+
+func (d *Dispatcher) post(ctx context.Context, url, secret string, seq int, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(eventHeader, signaturesEvent)
+	req.Header.Set(deliveryHeader, strconv.Itoa(seq))
+	req.Header.Set(timestampHeader, timestamp)
+	req.Header.Set(signatureHeader, "sha256="+Sign(secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16)) // lets the connection be reused.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook replied with HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) saveAttempt(ctx context.Context, webhookID, seq int, a attempt) error {
+	c := d.db.WebhookDelivery.Create().
+		SetWebhookID(webhookID).
+		SetLedgerSeq(seq).
+		SetAttempt(a.number).
+		SetSucceeded(a.err == nil).
+		SetInsertedAt(a.at)
+	if a.statusCode != 0 {
+		c.SetStatusCode(a.statusCode)
+	}
+	if a.err != nil {
+		c.SetError(a.err.Error())
+	}
+	if err := c.Exec(ctx); err != nil {
+		return fmt.Errorf("failed recording delivery %d to webhook %d: %w", a.number, webhookID, err)
+	}
+	return nil
+}
+
+// retryable reports whether a failed attempt is worth repeating.
+func retryable(statusCode int) bool {
+	switch {
+	case statusCode == 0, statusCode >= 500:
+		return true
+	case statusCode == http.StatusRequestTimeout, statusCode == http.StatusTooManyRequests:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of timestamp + "." + body under secret.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/database/webhookdelivery"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop()
+}
+
+const testSecret = "partner-secret"
+
+// newPartner starts a webhook receiver that checks every delivery's headers
+// and HMAC, then answers with the next of statuses (repeating the last one).
+func newPartner(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(eventHeader) != signaturesEvent || r.Header.Get(deliveryHeader) != "7" {
+			t.Errorf("delivery headers = %v", r.Header)
+		}
+		want := "sha256=" + Sign(testSecret, r.Header.Get(timestampHeader), body)
+		if !hmac.Equal([]byte(r.Header.Get(signatureHeader)), []byte(want)) {
+			t.Errorf("delivery signature = %q, want %q", r.Header.Get(signatureHeader), want)
+		}
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestDeliver(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantSuccess bool
+	}{
+		{name: "accepted", status: http.StatusNoContent, wantSuccess: true},
+		{name: "server error", status: http.StatusBadGateway},
+		{name: "rejected", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partner, calls := newPartner(t, tt.status)
+			d := NewDispatcher(nil, 3, time.Millisecond)
+
+			a := d.deliver(context.Background(), partner.URL, testSecret, 7, []byte(`{"ledger_seq":7}`), 2)
+			if calls.Load() != 1 {
+				t.Fatalf("deliver() made %d calls, want 1", calls.Load())
+			}
+			if a.number != 2 || a.statusCode != tt.status || (a.err == nil) != tt.wantSuccess || a.at.IsZero() {
+				t.Errorf("deliver() = %+v, want attempt 2 with status %d and success %v", a, tt.status, tt.wantSuccess)
+			}
+		})
+	}
+}
+
+func TestDeliverUnreachable(t *testing.T) {
+	partner, _ := newPartner(t, http.StatusOK)
+	partner.Close()
+
+	a := NewDispatcher(nil, 2, time.Millisecond).deliver(context.Background(), partner.URL, testSecret, 7, nil, 1)
+	if a.err == nil || a.statusCode != 0 {
+		t.Errorf("deliver() to a closed server = %+v, want a failed attempt without status", a)
+	}
+}
+
+func TestNext(t *testing.T) {
+	now := time.Now()
+	failed := errors.New("failed")
+	tests := []struct {
+		name        string
+		last        attempt
+		wantDelay   time.Duration
+		wantPending bool
+	}{
+		{name: "never attempted", last: attempt{}, wantPending: true},
+		{name: "accepted", last: attempt{number: 1, statusCode: http.StatusOK, at: now}},
+		{name: "rejected", last: attempt{number: 1, statusCode: http.StatusBadRequest, err: failed, at: now}},
+		{name: "out of attempts", last: attempt{number: 4, statusCode: http.StatusBadGateway, err: failed, at: now}},
+		{name: "backing off", last: attempt{number: 1, statusCode: http.StatusBadGateway, err: failed, at: now}, wantDelay: time.Second, wantPending: true},
+		{name: "backing off longer", last: attempt{number: 3, err: failed, at: now.Add(-time.Second)}, wantDelay: 3 * time.Second, wantPending: true},
+		{name: "rate limited and due", last: attempt{number: 2, statusCode: http.StatusTooManyRequests, err: failed, at: now.Add(-time.Hour)}, wantPending: true},
+	}
+	d := NewDispatcher(nil, 4, time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, pending := d.next(tt.last, now)
+			if delay != tt.wantDelay || pending != tt.wantPending {
+				t.Errorf("next() = %v, %v; want %v, %v", delay, pending, tt.wantDelay, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	d := NewDispatcher(nil, 20, 10*time.Second)
+	want := []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second, maxBackoff, maxBackoff}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
+
+// TestDispatch delivers to webhooks stored in the database and checks the
+// recorded attempts. It needs DATABASE_URL.
+func TestDispatch(t *testing.T) {
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Skip("Skipping integration test: DATABASE_URL not set")
+	}
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		t.Skipf("Skipping integration test: %v", err)
+	}
+	defer dbClient.Close()
+	ctx := context.Background()
+	for _, table := range []string{"webhook_deliveries", "webhooks"} {
+		if _, err := dbClient.Exec(ctx, "DELETE FROM "+table); err != nil {
+			t.Skipf("Skipping integration test: unable to clean %s table: %v", table, err)
+		}
+	}
+
+	healthy, healthyCalls := newPartner(t, http.StatusOK)
+	flaky, _ := newPartner(t, http.StatusServiceUnavailable, http.StatusOK)
+	inactive, inactiveCalls := newPartner(t, http.StatusOK)
+	otherTenant, otherTenantCalls := newPartner(t, http.StatusOK)
+	hooks := map[string]int{}
+	for name, url := range map[string]string{"healthy": healthy.URL, "flaky": flaky.URL, "inactive": inactive.URL, "other tenant": otherTenant.URL} {
+		tenant := "acme"
+		if name == "other tenant" {
+			tenant = "globex"
+		}
+		hook, err := dbClient.Webhook.Create().SetTenantID(tenant).SetURL(url).SetSecret(testSecret).SetActive(name != "inactive").Save(ctx)
+		if err != nil {
+			t.Fatalf("failed creating webhook: %v", err)
+		}
+		hooks[name] = hook.ID
+	}
+
+	// The flaky webhook fails the first pass and is due again after the
+	// backoff; the second pass leaves the healthy one alone.
+	d := NewDispatcher(dbClient, 3, time.Millisecond)
+	body := []byte(`{"tenant_id":"acme","ledger_seq":7}`)
+	retryIn, err := d.Dispatch(ctx, "acme", 7, body)
+	if err != nil {
+		t.Fatalf("Dispatch() unexpected error: %v", err)
+	}
+	if retryIn <= 0 {
+		t.Fatalf("Dispatch() = %v after a failed delivery, want a retry", retryIn)
+	}
+	time.Sleep(retryIn)
+	if retryIn, err = d.Dispatch(ctx, "acme", 7, body); err != nil || retryIn != 0 {
+		t.Fatalf("second Dispatch() = %v, %v; want 0, nil", retryIn, err)
+	}
+	if healthyCalls.Load() != 1 {
+		t.Errorf("healthy webhook was called %d times, want 1", healthyCalls.Load())
+	}
+	if inactiveCalls.Load() != 0 {
+		t.Errorf("inactive webhook was called %d times", inactiveCalls.Load())
+	}
+	if otherTenantCalls.Load() != 0 {
+		t.Errorf("webhook of another tenant was called %d times", otherTenantCalls.Load())
+	}
+
+	tests := []struct {
+		name          string
+		wantAttempts  int
+		wantSucceeded []bool
+	}{
+		{name: "healthy", wantAttempts: 1, wantSucceeded: []bool{true}},
+		{name: "flaky", wantAttempts: 2, wantSucceeded: []bool{false, true}},
+		{name: "inactive", wantAttempts: 0},
+		{name: "other tenant", wantAttempts: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deliveries, err := dbClient.WebhookDelivery.Query().
+				Where(webhookdelivery.WebhookID(hooks[tt.name])).
+				Order(database.Asc(webhookdelivery.FieldAttempt)).
+				All(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(deliveries) != tt.wantAttempts {
+				t.Fatalf("recorded %d deliveries, want %d", len(deliveries), tt.wantAttempts)
+			}
+			for i, delivery := range deliveries {
+				if delivery.LedgerSeq != 7 || delivery.Succeeded != tt.wantSucceeded[i] || delivery.StatusCode == nil {
+					t.Errorf("delivery %d = %+v (status %s)", i+1, delivery, strconv.Quote(delivery.Error))
+				}
+			}
+		})
+	}
+}
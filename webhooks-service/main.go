@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jurshsmith/vaultstream/config"
+	"github.com/jurshsmith/vaultstream/database"
+	"github.com/jurshsmith/vaultstream/logger"
+	"github.com/jurshsmith/vaultstream/nats"
+	"github.com/jurshsmith/vaultstream/types"
+	natsio "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+var log *zap.Logger
+
+const (
+	// ackWait is how long JetStream waits on an event before redelivering
+	// it. A dispatch makes a single attempt per webhook, each within
+	// deliveryTimeout, and keeps the event in progress every
+	// inProgressInterval; backoffs between attempts are spent with the event
+	// Nak'd, so neither WEBHOOK_MAX_ATTEMPTS nor WEBHOOK_INITIAL_BACKOFF
+	// bear on it.
+	ackWait            = 2 * deliveryTimeout
+	inProgressInterval = deliveryTimeout / 2
+
+	maxEvents = 16 // events dispatched concurrently
+)
+
+func main() {
+	log = logger.New()
+	defer log.Sync()
+
+	log.Info("Webhooks Service running...")
+
+	config.Setup()
+
+	dbClient, err := database.Connect(0)
+	if err != nil {
+		log.Fatal("Failed connecting to the database", zap.Error(err))
+	}
+	defer dbClient.Close()
+	log.Debug("Database connection established")
+
+	jetstreamClient, natsConn, err := nats.Connect("webhooks-service")
+	if err != nil {
+		log.Fatal("Failed connecting to NATS", zap.Error(err))
+	}
+	defer natsConn.Close()
+	log.Debug("NATS JetStream connection established")
+
+	ctx := context.Background()
+
+	signaturesConsumer, err := nats.NewDurable(ctx, jetstreamClient, config.EventsStreamName(), jetstream.ConsumerConfig{
+		Durable:       "webhooks-signatures-consumer",
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		FilterSubject: "signatures.>",
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckWait:       ackWait,
+	})
+	if err != nil {
+		log.Fatal("Error creating/updating signatures consumer", zap.Error(err))
+	}
+
+	maxAttempts := config.WebhookMaxAttempts()
+	if maxAttempts < 1 {
+		log.Fatal("Invalid WEBHOOK_MAX_ATTEMPTS", zap.Int("maxAttempts", maxAttempts))
+	}
+	dispatcher := NewDispatcher(dbClient, maxAttempts, config.WebhookInitialBackoff())
+
+	// Events are dispatched concurrently, so that a slow webhook holds up
+	// its own events only.
+	semaphoreQueue := make(chan struct{}, maxEvents)
+	for {
+		msg, err := signaturesConsumer.Next()
+		switch {
+		case errors.Is(err, natsio.ErrTimeout):
+			continue // no event within the wait
+		case errors.Is(err, natsio.ErrConnectionClosed):
+			log.Fatal("NATS connection closed", zap.Error(err))
+		case err != nil:
+			// Disconnected, or the consumer was lost and Next created it
+			// again; either way the next fetch may succeed.
+			log.Error("Error fetching signatures event", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		semaphoreQueue <- struct{}{} // acquire
+		go func() {
+			defer func() { <-semaphoreQueue }() // release
+			handle(ctx, dispatcher, msg)
+		}()
+	}
+}
+
+// handle dispatches a signatures event and acks it once every webhook has
+// accepted it or been given up on. Until then it is Nak'd to be redelivered
+// when the next attempt falls due, so that retries never hold the event, nor
+// any other.
+func handle(ctx context.Context, dispatcher *Dispatcher, msg jetstream.Msg) {
+	var event types.SignaturesEvent
+	if err := json.Unmarshal(msg.Data(), &event); err != nil {
+		// A malformed event will never parse; drop it rather than redeliver it forever.
+		log.Error("Error unmarshaling signatures event", zap.String("subject", msg.Subject()), zap.Error(err))
+		msg.Term()
+		return
+	}
+
+	// Events from before tenants existed carry none.
+	if event.TenantID == "" {
+		event.TenantID = types.DefaultTenant
+	}
+	stop := keepInProgress(msg)
+	retryIn, err := dispatcher.Dispatch(ctx, event.TenantID, event.LedgerSeq, msg.Data())
+	stop()
+	switch {
+	case err != nil:
+		log.Error("Error dispatching signatures event", zap.Int("ledgerSeq", event.LedgerSeq), zap.Error(err))
+		msg.NakWithDelay(10 * time.Second)
+	case retryIn > 0:
+		log.Debug("Signatures event due for another delivery", zap.Int("ledgerSeq", event.LedgerSeq), zap.Duration("retryIn", retryIn))
+		msg.NakWithDelay(retryIn)
+	default:
+		if err := msg.Ack(); err != nil {
+			log.Error("Error acknowledging signatures event", zap.Error(err))
+		}
+		log.Debug("Signatures event dispatched", zap.Int("ledgerSeq", event.LedgerSeq))
+	}
+}
+
+// keepInProgress acks msg in progress every inProgressInterval until stop is
+// called, however many webhooks the event goes to.
+func keepInProgress(msg jetstream.Msg) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(inProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := msg.InProgress(); err != nil {
+					log.Warn("Error extending message lease", zap.String("subject", msg.Subject()), zap.Error(err))
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}